@@ -5,9 +5,13 @@ import (
 	"os"
 	"os/signal"
 	"sync/atomic"
+	"time"
 
 	"iperf-tool/internal/cli"
+	"iperf-tool/internal/export"
+	"iperf-tool/internal/format"
 	"iperf-tool/internal/iperf"
+	"iperf-tool/internal/model"
 )
 
 func main() {
@@ -20,23 +24,97 @@ func main() {
 		os.Exit(1)
 	}
 	if err := runCLI(cfg); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if cfg.ErrorJSON {
+			cli.WriteErrorJSON(os.Stdout, *cfg, err, time.Now())
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
 		os.Exit(1)
 	}
 }
 
 func runCLI(cfg *cli.RunnerConfig) error {
+	format.Units = cfg.UnitSystem()
+	if len(cfg.Compare) == 2 {
+		out, err := cli.CompareFiles(cfg.Compare[0], cfg.Compare[1])
+		if err != nil {
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	}
+	if cfg.Doctor {
+		report, ready := cli.Doctor(*cfg)
+		fmt.Print(report)
+		if !ready {
+			return fmt.Errorf("environment not ready")
+		}
+		return nil
+	}
+	if cfg.Import != "" {
+		result, err := cli.ImportResult(*cfg)
+		if err != nil {
+			return err
+		}
+		cli.PrintResult(result, *cfg)
+		return nil
+	}
+	if cfg.MTUCheck > 0 {
+		return cli.MTUCheck(*cfg, os.Stdout)
+	}
+	if cfg.DryRun {
+		for _, sc := range cfg.PerServer() {
+			if err := cli.DryRun(sc, os.Stdout); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if cfg.Listen {
+		return cli.RunLocalServer(*cfg)
+	}
 	if cfg.SSHHost != "" {
 		return runRemoteServer(cfg)
 	}
+	if len(cfg.Sweep) > 0 {
+		results, err := cli.BitrateSweep(*cfg, cfg.Sweep, cfg.LossThreshold)
+		fmt.Fprintln(cfg.Stdout())
+		fmt.Fprint(cfg.Stdout(), cli.SweepSummary(cfg.Sweep, results, cfg.LossThreshold))
+		return err
+	}
 	if cfg.Repeat {
 		return runCLIRepeat(cfg)
 	}
-	result, err := cli.LocalTestRunner(*cfg)
-	if err != nil {
+	if cfg.Every > 0 {
+		return cli.RunScheduled(*cfg, cfg.Every, cfg.ScheduleDeadline(time.Now()))
+	}
+	if cfg.Concurrent {
+		_, err := cli.RunConcurrent(cfg.PerServer())
 		return err
 	}
-	cli.PrintResult(result)
+	servers := cfg.PerServer()
+	failed := 0
+	for i, sc := range servers {
+		if len(servers) > 1 {
+			if i > 0 {
+				fmt.Fprintln(cfg.Stdout())
+			}
+			fmt.Fprintln(cfg.Stdout(), cli.ServerHeader(i+1, len(servers), sc.ServerAddr))
+		}
+		result, err := runTest(sc)
+		if err != nil {
+			if len(servers) == 1 {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Server %s error: %v\n", sc.ServerAddr, err)
+			failed++
+			continue
+		}
+		cli.PrintResult(result, *cfg)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d server(s) failed", failed, len(servers))
+	}
 	return nil
 }
 
@@ -69,6 +147,11 @@ func runRemoteServer(cfg *cli.RunnerConfig) error {
 	if cfg.ServerAddr != "" {
 		cfg.SSHClient = runner.Client()
 		cfg.IsWindows = runner.IsWindows()
+		if v, err := runner.CheckRemoteVersion(); err == nil {
+			cfg.RemoteIperfVersion = v
+		} else if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Remote iperf2 version check: %v\n", err)
+		}
 		if (cfg.Reverse || cfg.Bidir) && cfg.SSHClient != nil {
 			if lap, ok := cfg.SSHClient.(iperf.LocalAddrProvider); ok {
 				cfg.LocalAddr = lap.LocalAddr()
@@ -78,18 +161,31 @@ func runRemoteServer(cfg *cli.RunnerConfig) error {
 		if cfg.Repeat {
 			return runCLIRepeat(cfg)
 		}
+		if cfg.Every > 0 {
+			return cli.RunScheduled(*cfg, cfg.Every, cfg.ScheduleDeadline(time.Now()))
+		}
 
-		result, err := cli.LocalTestRunner(*cfg)
+		result, err := runTest(*cfg)
 		if err != nil {
 			return err
 		}
-		cli.PrintResult(result)
+		cli.PrintResult(result, *cfg)
 	}
 
 	return nil
 }
 
+func runTest(cfg cli.RunnerConfig) (*model.TestResult, error) {
+	if cfg.Average > 1 {
+		return cli.RunAveraged(cfg, cfg.Average)
+	}
+	return cli.LocalTestRunner(cfg)
+}
+
 func runCLIRepeat(cfg *cli.RunnerConfig) error {
+	// One interval CSV for the whole session, even across midnight
+	cfg.PinIntervalLog(time.Now())
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt)
 
@@ -97,11 +193,14 @@ func runCLIRepeat(cfg *cli.RunnerConfig) error {
 
 	go func() {
 		<-sigCh
-		fmt.Println("\nStop requested — finishing current measurement...")
+		fmt.Fprintln(cfg.Stdout(), "\nStop requested — finishing current measurement...")
 		atomic.StoreInt32(&stopped, 1)
 	}()
 
 	totalRuns := 0
+	errored := 0
+	var totals cli.RunTotals
+	var results []model.TestResult
 	for runNum := 1; ; runNum++ {
 		if atomic.LoadInt32(&stopped) == 1 {
 			break
@@ -111,22 +210,44 @@ func runCLIRepeat(cfg *cli.RunnerConfig) error {
 		}
 
 		if runNum > 1 {
-			fmt.Printf("\n--- Repeat run %d", runNum)
+			fmt.Fprintf(cfg.Stdout(), "\n--- Repeat run %d", runNum)
 			if cfg.RepeatCount > 0 {
-				fmt.Printf(" of %d", cfg.RepeatCount)
+				fmt.Fprintf(cfg.Stdout(), " of %d", cfg.RepeatCount)
 			}
-			fmt.Println(" ---")
+			fmt.Fprintln(cfg.Stdout(), " ---")
 		}
 
-		result, err := cli.LocalTestRunner(*cfg)
-		totalRuns++
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Run %d error: %v\n", runNum, err)
-			continue
+		servers := cfg.PerServer()
+		for i, sc := range servers {
+			if i > 0 && atomic.LoadInt32(&stopped) == 1 {
+				break
+			}
+			if len(servers) > 1 {
+				fmt.Fprintln(cfg.Stdout(), cli.ServerHeader(i+1, len(servers), sc.ServerAddr))
+			}
+
+			result, err := cli.LocalTestRunner(sc)
+			totalRuns++
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Run %d error: %v\n", runNum, err)
+				errored++
+				continue
+			}
+			cli.PrintResult(result, *cfg)
+			results = append(results, *result)
+			totals.Add(result)
+			if sc.PromPath != "" {
+				if err := export.WritePromMetrics(sc.PromPath, result); err != nil {
+					fmt.Fprintf(os.Stderr, "Write metrics error: %v\n", err)
+				}
+			}
 		}
-		cli.PrintResult(result)
 	}
 
-	fmt.Printf("\nCompleted %d run(s).\n", totalRuns)
+	fmt.Fprintf(cfg.Stdout(), "\n%s\n", totals.Completed(totalRuns, "run(s)"))
+	if totalRuns > 1 {
+		fmt.Fprintln(cfg.Stdout())
+		fmt.Fprint(cfg.Stdout(), cli.SummarizeRuns(results, errored))
+	}
 	return nil
 }
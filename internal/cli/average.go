@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"iperf-tool/internal/model"
+)
+
+// RunAveraged runs n tests back to back and returns one synthetic result
+// whose summary figures are the means of the successful runs. Failed runs
+// are reported and left out; an error is returned only when every run fails.
+// Only the averaged result is saved.
+func RunAveraged(cfg RunnerConfig, n int) (*model.TestResult, error) {
+	each := cfg
+	each.OutputCSV, each.InfluxPath, each.InfluxURL, each.DBPath = "", "", "", ""
+
+	var results []model.TestResult
+	var lastErr error
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(cfg.Stdout(), "--- Sample %d of %d ---\n", i, n)
+		result, err := runLocalTest(each)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Sample %d error: %v\n", i, err)
+			lastErr = err
+			continue
+		}
+		results = append(results, *result)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("all %d samples failed: %w", n, lastErr)
+	}
+
+	avg := averageResults(results)
+	saveResults(avg, cfg)
+	return avg, nil
+}
+
+// averageResults folds results into one whose throughput, loss, jitter,
+// retransmit and byte counts are the per-run means. Test parameters, pings
+// and the measurement ID come from the first run; streams and intervals are
+// dropped since they do not average meaningfully.
+func averageResults(results []model.TestResult) *model.TestResult {
+	avg := results[0]
+	avg.Streams, avg.Intervals, avg.ReverseIntervals = nil, nil, nil
+	avg.FinishedAt = results[len(results)-1].FinishedAt
+	avg.SampleCount = len(results)
+
+	n := float64(len(results))
+	meanF := func(get func(r *model.TestResult) float64) float64 {
+		var sum float64
+		for i := range results {
+			sum += get(&results[i])
+		}
+		return sum / n
+	}
+	meanI := func(get func(r *model.TestResult) int64) int64 {
+		return int64(math.Round(meanF(func(r *model.TestResult) float64 { return float64(get(r)) })))
+	}
+
+	avg.SentBps = meanF(func(r *model.TestResult) float64 { return r.SentBps })
+	avg.ReceivedBps = meanF(func(r *model.TestResult) float64 { return r.ReceivedBps })
+	avg.FwdReceivedBps = meanF(func(r *model.TestResult) float64 { return r.FwdReceivedBps })
+	avg.ReverseSentBps = meanF(func(r *model.TestResult) float64 { return r.ReverseSentBps })
+	avg.ReverseReceivedBps = meanF(func(r *model.TestResult) float64 { return r.ReverseReceivedBps })
+	avg.JitterMs = meanF(func(r *model.TestResult) float64 { return r.JitterMs })
+	avg.FwdJitterMs = meanF(func(r *model.TestResult) float64 { return r.FwdJitterMs })
+	avg.ReverseJitterMs = meanF(func(r *model.TestResult) float64 { return r.ReverseJitterMs })
+	avg.LostPercent = meanF(func(r *model.TestResult) float64 { return r.LostPercent })
+	avg.FwdLostPercent = meanF(func(r *model.TestResult) float64 { return r.FwdLostPercent })
+	avg.ReverseLostPercent = meanF(func(r *model.TestResult) float64 { return r.ReverseLostPercent })
+	avg.ActualDuration = meanF(func(r *model.TestResult) float64 { return r.ActualDuration })
+	avg.ReverseActualDuration = meanF(func(r *model.TestResult) float64 { return r.ReverseActualDuration })
+
+	avg.BytesSent = meanI(func(r *model.TestResult) int64 { return r.BytesSent })
+	avg.BytesReceived = meanI(func(r *model.TestResult) int64 { return r.BytesReceived })
+	avg.ReverseBytesSent = meanI(func(r *model.TestResult) int64 { return r.ReverseBytesSent })
+	avg.ReverseBytesReceived = meanI(func(r *model.TestResult) int64 { return r.ReverseBytesReceived })
+	avg.Retransmits = int(meanI(func(r *model.TestResult) int64 { return int64(r.Retransmits) }))
+	avg.ReverseRetransmits = int(meanI(func(r *model.TestResult) int64 { return int64(r.ReverseRetransmits) }))
+	avg.LostPackets = int(meanI(func(r *model.TestResult) int64 { return int64(r.LostPackets) }))
+	avg.FwdLostPackets = int(meanI(func(r *model.TestResult) int64 { return int64(r.FwdLostPackets) }))
+	avg.ReverseLostPackets = int(meanI(func(r *model.TestResult) int64 { return int64(r.ReverseLostPackets) }))
+	avg.Packets = int(meanI(func(r *model.TestResult) int64 { return int64(r.Packets) }))
+	avg.FwdPackets = int(meanI(func(r *model.TestResult) int64 { return int64(r.FwdPackets) }))
+	avg.ReversePackets = int(meanI(func(r *model.TestResult) int64 { return int64(r.ReversePackets) }))
+
+	// Sample standard deviation of the headline forward throughput
+	if len(results) > 1 {
+		mean := meanF(func(r *model.TestResult) float64 { return r.FwdActualMbps() })
+		var sq float64
+		for i := range results {
+			d := results[i].FwdActualMbps() - mean
+			sq += d * d
+		}
+		avg.StdDevMbps = math.Sqrt(sq / (n - 1))
+	}
+	return &avg
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"iperf-tool/internal/model"
+)
+
+func TestRunAveraged(t *testing.T) {
+	orig := runLocalTest
+	t.Cleanup(func() { runLocalTest = orig })
+
+	// 100, 200 and 300 Mbps received, with one failed run in between
+	recv := []float64{100e6, 200e6, 0, 300e6}
+	calls := 0
+	runLocalTest = func(cfg RunnerConfig) (*model.TestResult, error) {
+		if cfg.OutputCSV != "" || cfg.DBPath != "" {
+			t.Errorf("sample run should not save, got OutputCSV %q, DBPath %q", cfg.OutputCSV, cfg.DBPath)
+		}
+		bps := recv[calls]
+		calls++
+		if bps == 0 {
+			return nil, errors.New("connect failed: Connection refused")
+		}
+		return &model.TestResult{
+			Protocol:      "TCP",
+			SentBps:       bps + 10e6,
+			ReceivedBps:   bps,
+			Retransmits:   int(bps / 1e6),
+			BytesReceived: int64(bps / 8 * 10),
+			Intervals:     []model.IntervalResult{{TimeEnd: 1}},
+		}, nil
+	}
+
+	avg, err := RunAveraged(RunnerConfig{OutputCSV: filepath.Join(t.TempDir(), "avg"), Quiet: true}, 4)
+	if err != nil {
+		t.Fatalf("RunAveraged() error = %v", err)
+	}
+	if calls != 4 {
+		t.Errorf("ran %d samples, want 4", calls)
+	}
+	if avg.SampleCount != 3 {
+		t.Errorf("SampleCount = %d, want 3 (failed run left out)", avg.SampleCount)
+	}
+	if got := avg.ReceivedMbps(); got != 200 {
+		t.Errorf("ReceivedMbps = %v, want 200", got)
+	}
+	if got := avg.SentMbps(); got != 210 {
+		t.Errorf("SentMbps = %v, want 210", got)
+	}
+	if avg.Retransmits != 200 || avg.BytesReceived != 250_000_000 {
+		t.Errorf("Retransmits = %d, BytesReceived = %d, want 200 and 250000000", avg.Retransmits, avg.BytesReceived)
+	}
+	if math.Abs(avg.StdDevMbps-100) > 1e-9 {
+		t.Errorf("StdDevMbps = %v, want 100", avg.StdDevMbps)
+	}
+	if avg.Intervals != nil {
+		t.Error("averaged result should carry no intervals")
+	}
+}
+
+func TestRunAveraged_AllFail(t *testing.T) {
+	orig := runLocalTest
+	t.Cleanup(func() { runLocalTest = orig })
+	runLocalTest = func(RunnerConfig) (*model.TestResult, error) {
+		return nil, errors.New("connect failed: Connection refused")
+	}
+
+	if _, err := RunAveraged(RunnerConfig{Quiet: true}, 2); err == nil {
+		t.Error("expected an error when every sample fails")
+	}
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"iperf-tool/internal/export"
+	"iperf-tool/internal/format"
+	"iperf-tool/internal/model"
+)
+
+// LoadCompareResult reads the last result from a saved .json results file
+// or a _log.csv summary log.
+func LoadCompareResult(path string) (*model.TestResult, error) {
+	var results []model.TestResult
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		results, err = export.ReadCSVLog(path)
+	} else {
+		results, err = export.ReadJSON(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compare: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("compare: %s holds no results", path)
+	}
+	return &results[len(results)-1], nil
+}
+
+// CompareFiles loads the last result of each file and returns
+// CompareResults of the two.
+func CompareFiles(pathA, pathB string) (string, error) {
+	a, err := LoadCompareResult(pathA)
+	if err != nil {
+		return "", err
+	}
+	b, err := LoadCompareResult(pathB)
+	if err != nil {
+		return "", err
+	}
+	return CompareResults(*a, *b), nil
+}
+
+// compareRow is one metric of a before/after comparison. ok is false when
+// neither side measured it.
+type compareRow struct {
+	label string
+	a, b  float64
+	ok    bool
+}
+
+// CompareResults formats a side-by-side table of a (before) and b (after)
+// with the signed delta and percent change of each metric. Metrics neither
+// result measured are left out.
+func CompareResults(a, b model.TestResult) string {
+	rows := []compareRow{
+		{"Fwd " + format.Units.MbpsUnit(), format.Units.Scale(a.FwdActualMbps()), format.Units.Scale(b.FwdActualMbps()), true},
+		{"Rev " + format.Units.MbpsUnit(), format.Units.Scale(a.ReverseActualMbps()), format.Units.Scale(b.ReverseActualMbps()), hasReverse(a) || hasReverse(b)},
+		{"Fwd Retransmits", float64(a.Retransmits), float64(b.Retransmits), isTCP(a) || isTCP(b)},
+		{"Rev Retransmits", float64(a.ReverseRetransmits), float64(b.ReverseRetransmits), (hasReverse(a) || hasReverse(b)) && (isTCP(a) || isTCP(b))},
+		{"Jitter ms", a.ActualJitterMs(), b.ActualJitterMs(), !isTCP(a) || !isTCP(b)},
+		{"Loss %", fwdLostPercentOf(a), fwdLostPercentOf(b), !isTCP(a) || !isTCP(b)},
+		{"Baseline ms", pingAvg(a.PingBaseline), pingAvg(b.PingBaseline), a.PingBaseline != nil || b.PingBaseline != nil},
+		{"Loaded ms", pingAvg(a.PingLoaded), pingAvg(b.PingLoaded), a.PingLoaded != nil || b.PingLoaded != nil},
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("=== Compare: A = %s, B = %s ===\n", compareLabel(a), compareLabel(b)))
+	sb.WriteString(fmt.Sprintf("%-16s %12s %12s %12s %10s\n", "", "A", "B", "Delta", "Change"))
+	for _, row := range rows {
+		if !row.ok {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%-16s %12.2f %12.2f %+12.2f %10s\n",
+			row.label, row.a, row.b, row.b-row.a, percentChange(row.a, row.b)))
+	}
+	return sb.String()
+}
+
+// percentChange returns the signed change from a to b, or "n/a" when a is
+// zero and the ratio is undefined.
+func percentChange(a, b float64) string {
+	if a == 0 {
+		if b == 0 {
+			return "+0.0%"
+		}
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", (b-a)/a*100)
+}
+
+func compareLabel(r model.TestResult) string {
+	label := r.MeasurementID
+	if label == "" {
+		label = r.Timestamp.Format("2006-01-02 15:04:05")
+	}
+	return fmt.Sprintf("%s (%s)", label, r.ServerAddr)
+}
+
+func hasReverse(r model.TestResult) bool {
+	return r.Direction == "Bidirectional" || r.ReverseSentBps > 0 || r.ReverseReceivedBps > 0
+}
+
+func isTCP(r model.TestResult) bool {
+	return !strings.EqualFold(r.Protocol, "udp")
+}
+
+// fwdLostPercentOf prefers the server-measured bidir loss, as the CSV does.
+func fwdLostPercentOf(r model.TestResult) float64 {
+	if r.Direction == "Bidirectional" && r.FwdPackets > 0 {
+		return r.FwdLostPercent
+	}
+	return r.LostPercent
+}
+
+func pingAvg(p *model.PingResult) float64 {
+	if p == nil {
+		return 0
+	}
+	return p.AvgMs
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"iperf-tool/internal/export"
+	"iperf-tool/internal/model"
+)
+
+// compareLine returns the table row for label, or fails the test.
+func compareLine(t *testing.T, out, label string) string {
+	t.Helper()
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, label+" ") {
+			return line
+		}
+	}
+	t.Fatalf("no %q row in:\n%s", label, out)
+	return ""
+}
+
+func TestCompareResults(t *testing.T) {
+	a := model.TestResult{
+		MeasurementID:  "20260301-001",
+		ServerAddr:     "10.0.0.1",
+		Protocol:       "TCP",
+		Direction:      "Bidirectional",
+		SentBps:        800_000_000,
+		Retransmits:    40,
+		ReverseSentBps: 500_000_000,
+		PingBaseline:   &model.PingResult{AvgMs: 2},
+		PingLoaded:     &model.PingResult{AvgMs: 40},
+	}
+	b := a
+	b.MeasurementID = "20260302-001"
+	b.SentBps = 920_000_000
+	b.Retransmits = 10
+	b.ReverseSentBps = 450_000_000
+	b.PingLoaded = &model.PingResult{AvgMs: 10}
+
+	out := CompareResults(a, b)
+
+	tests := []struct{ label, delta, change string }{
+		{"Fwd Mbps", "+120.00", "+15.0%"},
+		{"Rev Mbps", "-50.00", "-10.0%"},
+		{"Fwd Retransmits", "-30.00", "-75.0%"},
+		{"Baseline ms", "+0.00", "+0.0%"},
+		{"Loaded ms", "-30.00", "-75.0%"},
+	}
+	for _, tt := range tests {
+		f := strings.Fields(compareLine(t, out, tt.label))
+		if delta, change := f[len(f)-2], f[len(f)-1]; delta != tt.delta || change != tt.change {
+			t.Errorf("%s delta/change = %s/%s, want %s/%s", tt.label, delta, change, tt.delta, tt.change)
+		}
+	}
+	// TCP results carry no jitter or loss rows
+	if strings.Contains(out, "Jitter ms") || strings.Contains(out, "Loss %") {
+		t.Errorf("TCP comparison should omit UDP rows:\n%s", out)
+	}
+	if !strings.Contains(out, "A = 20260301-001 (10.0.0.1), B = 20260302-001 (10.0.0.1)") {
+		t.Errorf("missing result labels in header:\n%s", out)
+	}
+}
+
+func TestCompareResults_UDPFromZero(t *testing.T) {
+	a := model.TestResult{Protocol: "UDP", SentBps: 100_000_000, JitterMs: 0.5}
+	b := model.TestResult{Protocol: "UDP", SentBps: 100_000_000, JitterMs: 0.25, LostPercent: 2}
+
+	out := CompareResults(a, b)
+	if line := compareLine(t, out, "Jitter ms"); !strings.HasSuffix(line, "-50.0%") {
+		t.Errorf("Jitter row = %q, want -50.0%%", line)
+	}
+	if line := compareLine(t, out, "Loss %"); !strings.HasSuffix(line, "n/a") {
+		t.Errorf("Loss row = %q, want n/a for a change from zero", line)
+	}
+	if strings.Contains(out, "Retransmits") || strings.Contains(out, "Rev Mbps") {
+		t.Errorf("UDP forward comparison should omit TCP and reverse rows:\n%s", out)
+	}
+}
+
+func TestCompareFiles(t *testing.T) {
+	dir := t.TempDir()
+	before := filepath.Join(dir, "before.json")
+	after := filepath.Join(dir, "after_log.csv")
+	if err := export.WriteJSON(before, []model.TestResult{
+		{ServerAddr: "10.0.0.1", Protocol: "TCP", SentBps: 100_000_000},
+		{ServerAddr: "10.0.0.1", Protocol: "TCP", SentBps: 400_000_000},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := export.WriteCSV(after, []model.TestResult{
+		{ServerAddr: "10.0.0.1", Protocol: "TCP", SentBps: 500_000_000},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := CompareFiles(before, after)
+	if err != nil {
+		t.Fatalf("CompareFiles() error: %v", err)
+	}
+	// The last JSON result (400) is compared against the CSV row (500).
+	if line := compareLine(t, out, "Fwd Mbps"); !strings.HasSuffix(line, "+25.0%") {
+		t.Errorf("Fwd Mbps row = %q, want +25.0%%", line)
+	}
+
+	if _, err := CompareFiles(before, filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+
+	"iperf-tool/internal/model"
+)
+
+// RunConcurrent runs one test per config at the same time, each with its own
+// iperf runner, and waits for all of them. Progress output is buffered per
+// run and printed after all have finished, in config order, followed by the
+// result. results[i] belongs to cfgs[i] and is nil when that run failed.
+//
+// The runs share the local NIC; results show each path under that
+// contention, not its standalone capacity.
+func RunConcurrent(cfgs []RunnerConfig) ([]*model.TestResult, error) {
+	results := make([]*model.TestResult, len(cfgs))
+	errs := make([]error, len(cfgs))
+	bufs := make([]bytes.Buffer, len(cfgs))
+
+	var wg sync.WaitGroup
+	for i := range cfgs {
+		c := cfgs[i]
+		c.Progress = &bufs[i]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = runLocalTest(c)
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, c := range cfgs {
+		if len(cfgs) > 1 {
+			if i > 0 {
+				fmt.Fprintln(c.Stdout())
+			}
+			fmt.Fprintln(c.Stdout(), ServerHeader(i+1, len(cfgs), c.ServerAddr))
+		}
+		os.Stdout.Write(bufs[i].Bytes())
+		if errs[i] != nil {
+			fmt.Fprintf(os.Stderr, "Server %s error: %v\n", c.ServerAddr, errs[i])
+			failed++
+			continue
+		}
+		PrintResult(results[i], c)
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d concurrent test(s) failed", failed, len(cfgs))
+	}
+	return results, nil
+}
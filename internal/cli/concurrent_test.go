@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+func TestRunConcurrent_RunsAtOnce(t *testing.T) {
+	orig := runLocalTest
+	t.Cleanup(func() { runLocalTest = orig })
+
+	// Each stub waits until both have started, so a sequential
+	// implementation would time out.
+	var started sync.WaitGroup
+	started.Add(2)
+	runLocalTest = func(cfg RunnerConfig) (*model.TestResult, error) {
+		fmt.Fprintf(cfg.Stdout(), "testing %s\n", cfg.ServerAddr)
+		started.Done()
+		done := make(chan struct{})
+		go func() { started.Wait(); close(done) }()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			return nil, errors.New("runs did not overlap")
+		}
+		if cfg.ServerAddr == "10.0.1.1" {
+			return nil, errors.New("connection refused")
+		}
+		return &model.TestResult{ServerAddr: cfg.ServerAddr, Protocol: "TCP", SentBps: 1e6}, nil
+	}
+
+	cfg := RunnerConfig{ServerAddrs: []string{"10.0.0.1", "10.0.1.1"}, Color: "never"}
+	results, err := RunConcurrent(cfg.PerServer())
+	if err == nil {
+		t.Error("expected error when one server fails")
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if results[0] == nil || results[0].ServerAddr != "10.0.0.1" {
+		t.Errorf("results[0] = %+v, want 10.0.0.1", results[0])
+	}
+	if results[1] != nil {
+		t.Errorf("results[1] = %+v, want nil for the failed run", results[1])
+	}
+}
+
+func TestRunConcurrent_BuffersProgress(t *testing.T) {
+	orig := runLocalTest
+	t.Cleanup(func() { runLocalTest = orig })
+
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	runLocalTest = func(cfg RunnerConfig) (*model.TestResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		// Progress must go to a per-run buffer, never straight to stdout.
+		seen[cfg.ServerAddr] = cfg.Progress != nil && cfg.Stdout() == cfg.Progress
+		return &model.TestResult{ServerAddr: cfg.ServerAddr, Protocol: "TCP", SentBps: 1e6}, nil
+	}
+
+	cfg := RunnerConfig{ServerAddrs: []string{"a", "b"}, Color: "never"}
+	if _, err := RunConcurrent(cfg.PerServer()); err != nil {
+		t.Fatalf("RunConcurrent() error: %v", err)
+	}
+	if !seen["a"] || !seen["b"] {
+		t.Errorf("progress not buffered per run: %v", seen)
+	}
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadConfigFile reads a JSON config file whose keys are RunnerConfig field
+// names (e.g. "ServerAddr", "Parallel", "OutputCSV"). Keys absent from the
+// file keep their built-in defaults; unknown keys are rejected so typos do
+// not go unnoticed.
+func LoadConfigFile(path string) (*RunnerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+
+	cfg := defaultRunnerConfig()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("decode config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// findConfigPath returns the value of -config/--config in args, if present.
+// It runs before flag parsing so the file can supply the flag defaults.
+func findConfigPath(args []string) string {
+	for i, a := range args {
+		if a == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(a, "-"), "=")
+		if !strings.HasPrefix(a, "-") || name != "config" {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
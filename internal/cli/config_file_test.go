@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "iperf-tool.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigFile_Partial(t *testing.T) {
+	path := writeConfigFile(t, `{"ServerAddr": "10.0.0.5", "Parallel": 4, "OutputCSV": "perf/nightly"}`)
+
+	cfg, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error: %v", err)
+	}
+	if cfg.ServerAddr != "10.0.0.5" {
+		t.Errorf("ServerAddr = %q, want 10.0.0.5", cfg.ServerAddr)
+	}
+	if cfg.Parallel != 4 {
+		t.Errorf("Parallel = %d, want 4", cfg.Parallel)
+	}
+	if cfg.OutputCSV != "perf/nightly" {
+		t.Errorf("OutputCSV = %q, want perf/nightly", cfg.OutputCSV)
+	}
+	// Keys absent from the file keep the built-in defaults
+	if cfg.Port != 5201 || cfg.Duration != 10 || cfg.Protocol != "tcp" || cfg.SSHPort != 22 {
+		t.Errorf("defaults not preserved: port=%d duration=%d protocol=%q ssh-port=%d",
+			cfg.Port, cfg.Duration, cfg.Protocol, cfg.SSHPort)
+	}
+}
+
+func TestLoadConfigFile_Errors(t *testing.T) {
+	if _, err := LoadConfigFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+	if _, err := LoadConfigFile(writeConfigFile(t, `{"Paralel": 4}`)); err == nil {
+		t.Error("expected error for unknown key")
+	}
+	if _, err := LoadConfigFile(writeConfigFile(t, `{"Parallel": "four"}`)); err == nil {
+		t.Error("expected error for wrong value type")
+	}
+}
+
+func TestParseFlags_ConfigFilePrecedence(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	path := writeConfigFile(t, `{"ServerAddr": "10.0.0.5", "Parallel": 4, "Duration": 60, "Reverse": true}`)
+	os.Args = []string{"iperf-tool", "-P", "8", "-config", path, "-t", "30"}
+
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	// Explicit flags win regardless of position relative to -config
+	if cfg.Parallel != 8 {
+		t.Errorf("Parallel = %d, want 8 (flag)", cfg.Parallel)
+	}
+	if cfg.Duration != 30 {
+		t.Errorf("Duration = %d, want 30 (flag)", cfg.Duration)
+	}
+	// Unset flags take the file value
+	if cfg.ServerAddr != "10.0.0.5" {
+		t.Errorf("ServerAddr = %q, want 10.0.0.5 (file)", cfg.ServerAddr)
+	}
+	if !cfg.Reverse {
+		t.Error("Reverse should come from the file")
+	}
+	if cfg.Port != 5201 {
+		t.Errorf("Port = %d, want default 5201", cfg.Port)
+	}
+}
+
+func TestParseFlags_ConfigFileEqualsSyntax(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	path := writeConfigFile(t, `{"ServerAddr": "10.0.0.5", "Protocol": "udp"}`)
+	os.Args = []string{"iperf-tool", "--config=" + path}
+
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.ServerAddr != "10.0.0.5" || cfg.Protocol != "udp" {
+		t.Errorf("got server=%q protocol=%q", cfg.ServerAddr, cfg.Protocol)
+	}
+}
+
+func TestParseFlags_ConfigFileInvalid(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-config", writeConfigFile(t, `{not json`)}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("ParseFlags() with invalid config file should return error")
+	}
+}
+
+func TestFindConfigPath(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-s", "host"}, ""},
+		{[]string{"-config", "a.json"}, "a.json"},
+		{[]string{"--config", "b.json", "-s", "host"}, "b.json"},
+		{[]string{"-config=c.json"}, "c.json"},
+		{[]string{"--", "-config", "d.json"}, ""},
+	}
+	for _, tt := range tests {
+		if got := findConfigPath(tt.args); got != tt.want {
+			t.Errorf("findConfigPath(%v) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"iperf-tool/internal/iperf"
+	"iperf-tool/internal/ping"
+)
+
+// Replaced in tests.
+var (
+	doctorCongestion = iperf.SupportsCongestionControl
+	doctorListen     = func(port int) error {
+		ln, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+		if err != nil {
+			return err
+		}
+		return ln.Close()
+	}
+)
+
+// doctorCheck is one line of the -doctor report.
+type doctorCheck struct {
+	status string // "OK", "WARN" or "FAIL"
+	msg    string
+}
+
+// Doctor checks whether this machine can run tests — iperf2 present and
+// recent enough, -Z congestion control, ICMP ping and a free listen port —
+// and returns a readiness report. ready is false when any check failed;
+// warnings only disable optional features.
+func Doctor(cfg RunnerConfig) (report string, ready bool) {
+	var checks []doctorCheck
+	add := func(status, format string, args ...any) {
+		checks = append(checks, doctorCheck{status, fmt.Sprintf(format, args...)})
+	}
+
+	version, err := localVersion(cfg.BinaryPath)
+	switch {
+	case err != nil:
+		add("FAIL", "iperf2 (%s): %v", cfg.BinaryPath, err)
+	case iperf.CompareVersions(version, iperf.MinVersion) < 0:
+		add("WARN", "iperf2 %s is older than %s; results may be incomplete", version, iperf.MinVersion)
+	default:
+		add("OK", "iperf2 %s (%s)", version, cfg.BinaryPath)
+	}
+
+	if err == nil {
+		if doctorCongestion(cfg.BinaryPath) {
+			add("OK", "TCP congestion control (-Z) supported")
+		} else {
+			add("WARN", "TCP congestion control (-Z) not supported by this iperf2")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if r, err := pingRun(ctx, "127.0.0.1", 1); ping.Blocked(r, err) {
+		add("WARN", "ICMP ping unavailable; latency falls back to TCP connect timing")
+	} else {
+		add("OK", "ICMP ping available")
+	}
+
+	if err := doctorListen(cfg.Port); err != nil {
+		add("WARN", "cannot listen on TCP port %d: %v", cfg.Port, err)
+	} else {
+		add("OK", "TCP port %d free for --listen and reverse tests", cfg.Port)
+	}
+
+	var b strings.Builder
+	failed := 0
+	for _, c := range checks {
+		fmt.Fprintf(&b, "[%-4s] %s\n", c.status, c.msg)
+		if c.status == "FAIL" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Fprintf(&b, "Ready: no (%d problem(s))\n", failed)
+		return b.String(), false
+	}
+	b.WriteString("Ready: yes\n")
+	return b.String(), true
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"iperf-tool/internal/ping"
+)
+
+func stubDoctor(t *testing.T, version string, versionErr error, congestion bool, pingRes *ping.Result, listenErr error) {
+	t.Helper()
+	origVersion, origCongestion, origPing, origListen := localVersion, doctorCongestion, pingRun, doctorListen
+	t.Cleanup(func() {
+		localVersion, doctorCongestion, pingRun, doctorListen = origVersion, origCongestion, origPing, origListen
+	})
+	localVersion = func(string) (string, error) { return version, versionErr }
+	doctorCongestion = func(string) bool { return congestion }
+	pingRun = func(context.Context, string, int) (*ping.Result, error) { return pingRes, nil }
+	doctorListen = func(int) error { return listenErr }
+}
+
+func TestDoctor_AllOK(t *testing.T) {
+	stubDoctor(t, "2.1.9", nil, true, &ping.Result{PacketsSent: 1, PacketsRecv: 1}, nil)
+
+	report, ready := Doctor(RunnerConfig{BinaryPath: "iperf", Port: 5201})
+	if !ready {
+		t.Errorf("ready = false, want true\n%s", report)
+	}
+	for _, want := range []string{
+		"[OK  ] iperf2 2.1.9 (iperf)",
+		"[OK  ] TCP congestion control (-Z) supported",
+		"[OK  ] ICMP ping available",
+		"[OK  ] TCP port 5201 free",
+		"Ready: yes",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestDoctor_Warnings(t *testing.T) {
+	stubDoctor(t, "2.0.5", nil, false, &ping.Result{PacketsSent: 1}, errors.New("address already in use"))
+
+	report, ready := Doctor(RunnerConfig{BinaryPath: "iperf", Port: 5201})
+	if !ready {
+		t.Errorf("warnings alone should not make the machine unready:\n%s", report)
+	}
+	for _, want := range []string{
+		"[WARN] iperf2 2.0.5 is older than 2.0.10",
+		"[WARN] TCP congestion control (-Z) not supported",
+		"[WARN] ICMP ping unavailable",
+		"[WARN] cannot listen on TCP port 5201: address already in use",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestDoctor_MissingIperf(t *testing.T) {
+	stubDoctor(t, "", errors.New("executable file not found"), true, &ping.Result{PacketsSent: 1, PacketsRecv: 1}, nil)
+
+	report, ready := Doctor(RunnerConfig{BinaryPath: "iperf", Port: 5201})
+	if ready {
+		t.Errorf("ready = true without iperf2:\n%s", report)
+	}
+	if !strings.Contains(report, "[FAIL] iperf2 (iperf): executable file not found") {
+		t.Errorf("missing FAIL line:\n%s", report)
+	}
+	if strings.Contains(report, "congestion") {
+		t.Errorf("congestion check should be skipped without iperf2:\n%s", report)
+	}
+	if !strings.HasSuffix(report, "Ready: no (1 problem(s))\n") {
+		t.Errorf("report should end with the not-ready verdict:\n%s", report)
+	}
+}
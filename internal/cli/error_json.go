@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+// errorReport is the -error-json document written when a CLI run fails.
+type errorReport struct {
+	Error     string `json:"error"`
+	Kind      string `json:"kind"`
+	Server    string `json:"server,omitempty"`
+	Timestamp string `json:"timestamp"`
+}
+
+// WriteErrorJSON writes err as one JSON object for scripts, with the kind
+// from model.ClassifyErrorString in snake case ("connection_refused").
+func WriteErrorJSON(w io.Writer, cfg RunnerConfig, err error, now time.Time) error {
+	server := cfg.ServerAddr
+	if server == "" {
+		server = cfg.SSHHost
+	}
+	kind := model.ClassifyErrorString(err.Error())
+	return json.NewEncoder(w).Encode(errorReport{
+		Error:     err.Error(),
+		Kind:      strings.ReplaceAll(kind.String(), " ", "_"),
+		Server:    server,
+		Timestamp: now.Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWriteErrorJSON(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := RunnerConfig{ServerAddr: "10.0.0.1"}
+	err := errors.New("dial tcp 10.0.0.1:5201: connect: connection refused")
+	if werr := WriteErrorJSON(&buf, cfg, err, now); werr != nil {
+		t.Fatalf("WriteErrorJSON() error = %v", werr)
+	}
+
+	var got map[string]string
+	if jerr := json.Unmarshal(buf.Bytes(), &got); jerr != nil {
+		t.Fatalf("output is not JSON: %v\n%s", jerr, buf.String())
+	}
+	want := map[string]string{
+		"error":     err.Error(),
+		"kind":      "connection_refused",
+		"server":    "10.0.0.1",
+		"timestamp": "2026-03-01T12:00:00Z",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+
+	buf.Reset()
+	_ = WriteErrorJSON(&buf, RunnerConfig{SSHHost: "bastion"}, errors.New("something odd"), now)
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil || got["kind"] != "unknown" || got["server"] != "bastion" {
+		t.Errorf("unknown error = %v (%v)", got, err)
+	}
+}
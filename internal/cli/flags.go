@@ -6,7 +6,11 @@ import (
 	"os"
 	"os/user"
 	"runtime"
+	"strings"
+	"time"
 
+	"iperf-tool/internal/export"
+	"iperf-tool/internal/format"
 	"iperf-tool/internal/iperf"
 )
 
@@ -30,6 +34,76 @@ func defaultBinaryPath() string {
 	return "iperf"
 }
 
+// defaultRunnerConfig returns the configuration used when neither a config
+// file nor a flag sets a value.
+func defaultRunnerConfig() *RunnerConfig {
+	return &RunnerConfig{
+		Port:       5201,
+		Parallel:   1,
+		Duration:   10,
+		Interval:   1,
+		Protocol:   "tcp",
+		BinaryPath: defaultBinaryPath(),
+		SSHUser:    defaultUsername(),
+		SSHPort:    22,
+		PingCount:  4,
+		Color:      "auto",
+		Units:      "decimal",
+
+		LossThreshold: DefaultLossThreshold,
+	}
+}
+
+// argList is a flag.Value for -x: every use appends one argument. The first
+// use replaces any config-file ExtraArgs.
+type argList struct {
+	args *[]string
+	set  bool
+}
+
+func (l *argList) String() string {
+	if l.args == nil {
+		return ""
+	}
+	return strings.Join(*l.args, " ")
+}
+
+func (l *argList) Set(v string) error {
+	if !l.set {
+		*l.args = nil
+		l.set = true
+	}
+	*l.args = append(*l.args, v)
+	return nil
+}
+
+// serverList is a flag.Value for -s/--server: it accepts a comma-separated
+// list and may be repeated. The first use replaces any config-file servers.
+type serverList struct {
+	addrs *[]string
+	set   bool
+}
+
+func (l *serverList) String() string {
+	if l.addrs == nil {
+		return ""
+	}
+	return strings.Join(*l.addrs, ",")
+}
+
+func (l *serverList) Set(v string) error {
+	if !l.set {
+		*l.addrs = nil
+		l.set = true
+	}
+	for _, a := range strings.Split(v, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			*l.addrs = append(*l.addrs, a)
+		}
+	}
+	return nil
+}
+
 // ParseFlags parses command-line arguments and returns a RunnerConfig.
 // Returns nil config and prints help if no arguments or --help is provided.
 func ParseFlags() (*RunnerConfig, error) {
@@ -42,79 +116,368 @@ func ParseFlags() (*RunnerConfig, error) {
 		return nil, nil
 	}
 
-	cfg := &RunnerConfig{
-		Port:       5201,
-		Parallel:   1,
-		Duration:   10,
-		Interval:   1,
-		Protocol:   "tcp",
-		BinaryPath: defaultBinaryPath(),
-		SSHPort:    22,
+	cfg := defaultRunnerConfig()
+
+	// A config file supplies the defaults; flags given on the command line
+	// are parsed afterwards and win. Every flag below therefore takes its
+	// default from cfg rather than a literal.
+	configPath := findConfigPath(os.Args[1:])
+	if configPath != "" {
+		fileCfg, err := LoadConfigFile(configPath)
+		if err != nil {
+			return nil, err
+		}
+		cfg = fileCfg
 	}
 
 	fs := flag.NewFlagSet("iperf-tool", flag.ContinueOnError)
+	fs.StringVar(&configPath, "config", configPath, "Load defaults from a JSON config file")
 
 	// Local test flags
-	fs.StringVar(&cfg.ServerAddr, "s", "", "Server address (required for local test)")
-	fs.StringVar(&cfg.ServerAddr, "server", "", "Server address (required for local test)")
+	if len(cfg.ServerAddrs) == 0 && cfg.ServerAddr != "" {
+		cfg.ServerAddrs = []string{cfg.ServerAddr}
+	}
+	servers := &serverList{addrs: &cfg.ServerAddrs}
+	fs.Var(servers, "s", "Server address, comma-separated or repeated for several servers (required for local test)")
+	fs.Var(servers, "server", "Server address, comma-separated or repeated for several servers (required for local test)")
+	fs.BoolVar(&cfg.Concurrent, "concurrent", cfg.Concurrent, "Test all -s servers at the same time instead of in turn")
 	fs.IntVar(&cfg.Port, "p", cfg.Port, "Server port")
 	fs.IntVar(&cfg.Port, "port", cfg.Port, "Server port")
 	fs.IntVar(&cfg.Parallel, "P", cfg.Parallel, "Parallel streams")
 	fs.IntVar(&cfg.Parallel, "parallel", cfg.Parallel, "Parallel streams")
 	fs.IntVar(&cfg.Duration, "t", cfg.Duration, "Test duration in seconds")
 	fs.IntVar(&cfg.Duration, "time", cfg.Duration, "Test duration in seconds")
-	fs.IntVar(&cfg.Interval, "i", cfg.Interval, "Reporting interval in seconds")
-	fs.IntVar(&cfg.Interval, "interval", cfg.Interval, "Reporting interval in seconds")
+	fs.StringVar(&cfg.BytesLimit, "n", cfg.BytesLimit, "Stop after this much data (e.g. 500M, 1G) instead of a duration")
+	fs.StringVar(&cfg.BytesLimit, "num", cfg.BytesLimit, "Stop after this much data (e.g. 500M, 1G) instead of a duration")
+	fs.IntVar(&cfg.BlocksLimit, "k", cfg.BlocksLimit, "Stop after this many blocks/datagrams instead of a duration")
+	fs.IntVar(&cfg.BlocksLimit, "blockcount", cfg.BlocksLimit, "Stop after this many blocks/datagrams instead of a duration")
+	fs.Float64Var(&cfg.Interval, "i", cfg.Interval, "Reporting interval in seconds (fractional allowed, e.g. 0.5)")
+	fs.Float64Var(&cfg.Interval, "interval", cfg.Interval, "Reporting interval in seconds (fractional allowed, e.g. 0.5)")
 	var udpFlag bool
 	fs.BoolVar(&udpFlag, "u", false, "UDP mode (shorthand for --protocol udp)")
 	fs.StringVar(&cfg.Protocol, "protocol", cfg.Protocol, "Protocol (tcp or udp, default 'tcp')")
-	fs.IntVar(&cfg.BlockSize, "l", 0, "Block size (buffer/datagram size in bytes)")
-	fs.IntVar(&cfg.BlockSize, "block-size", 0, "Block size (buffer/datagram size in bytes)")
-	fs.BoolVar(&cfg.MeasurePing, "ping", false, "Measure latency before and during test")
-	fs.BoolVar(&cfg.Reverse, "R", false, "Reverse mode (server sends, client receives)")
-	fs.BoolVar(&cfg.Reverse, "reverse", false, "Reverse mode (server sends, client receives)")
-	fs.BoolVar(&cfg.Bidir, "bidir", false, "Bidirectional mode (simultaneous both directions)")
-	fs.StringVar(&cfg.Bandwidth, "b", "", "Target bandwidth (e.g. 100M, 1G)")
-	fs.StringVar(&cfg.Bandwidth, "bandwidth", "", "Target bandwidth (e.g. 100M, 1G)")
+	fs.IntVar(&cfg.BlockSize, "l", cfg.BlockSize, "Block size (buffer/datagram size in bytes)")
+	fs.IntVar(&cfg.BlockSize, "block-size", cfg.BlockSize, "Block size (buffer/datagram size in bytes)")
+	fs.BoolVar(&cfg.MeasurePing, "ping", cfg.MeasurePing, "Measure latency before and during test")
+	fs.IntVar(&cfg.PingCount, "ping-count", cfg.PingCount, "Baseline ping packets (or TCP connect samples)")
+	fs.BoolVar(&cfg.TCPPing, "tcp-ping", cfg.TCPPing, "Measure baseline latency as TCP connect time to the iperf port (works when ICMP is blocked)")
+	fs.BoolVar(&cfg.Reverse, "R", cfg.Reverse, "Reverse mode (server sends, client receives)")
+	fs.BoolVar(&cfg.Reverse, "reverse", cfg.Reverse, "Reverse mode (server sends, client receives)")
+	fs.BoolVar(&cfg.Bidir, "bidir", cfg.Bidir, "Bidirectional mode (simultaneous both directions)")
+	fs.StringVar(&cfg.BidirMode, "mode", cfg.BidirMode, "Bidirectional run mode: auto, dualtest (-d, one local process) or split (reverse client over SSH)")
+	fs.StringVar(&cfg.Bandwidth, "b", cfg.Bandwidth, "Target bandwidth (e.g. 100M, 1G)")
+	fs.StringVar(&cfg.Bandwidth, "bandwidth", cfg.Bandwidth, "Target bandwidth (e.g. 100M, 1G)")
 	fs.StringVar(&cfg.BinaryPath, "binary", cfg.BinaryPath, "Path to iperf2 binary")
-	fs.BoolVar(&cfg.IPv6, "V", false, "Use IPv6 (iperf2 -V flag)")
-	fs.BoolVar(&cfg.IPv6, "ipv6", false, "Use IPv6 (iperf2 -V flag)")
+	fs.BoolVar(&cfg.RequireVersion, "require-version", cfg.RequireVersion, "Fail instead of running when the local iperf2 is older than "+iperf.MinVersion)
+	sweep := strings.Join(cfg.Sweep, ",")
+	fs.StringVar(&sweep, "sweep", sweep, "UDP bitrate sweep: comma-separated -b targets run in order (e.g. 10M,50M,100M)")
+	fs.Float64Var(&cfg.LossThreshold, "loss-threshold", cfg.LossThreshold, "Loss percentage that ends a -sweep (default 2)")
+	fs.BoolVar(&cfg.IPv6, "V", cfg.IPv6, "Use IPv6 (iperf2 -V flag)")
+	fs.BoolVar(&cfg.IPv6, "ipv6", cfg.IPv6, "Use IPv6 (iperf2 -V flag)")
+	var ipv4Only, ipv6Only bool
+	fs.BoolVar(&ipv4Only, "4", false, "Force IPv4")
+	fs.BoolVar(&ipv6Only, "6", false, "Force IPv6")
+	fs.IntVar(&cfg.OmitSeconds, "O", cfg.OmitSeconds, "Omit the first N seconds (warm-up) from interval data")
+	fs.IntVar(&cfg.OmitSeconds, "omit", cfg.OmitSeconds, "Omit the first N seconds (warm-up) from interval data")
+	fs.StringVar(&cfg.WindowSize, "w", cfg.WindowSize, "Socket buffer / TCP window size (e.g. 256K, 2M)")
+	fs.StringVar(&cfg.WindowSize, "window", cfg.WindowSize, "Socket buffer / TCP window size (e.g. 256K, 2M)")
+	fs.StringVar(&cfg.DSCP, "S", cfg.DSCP, "Mark packets with a ToS byte (0-255) or DSCP keyword (ef, af41, cs5)")
+	fs.StringVar(&cfg.DSCP, "dscp", cfg.DSCP, "Mark packets with a ToS byte (0-255) or DSCP keyword (ef, af41, cs5)")
+	fs.StringVar(&cfg.BindAddr, "B", cfg.BindAddr, "Bind the client to this local IP (multi-homed hosts)")
+	fs.StringVar(&cfg.BindAddr, "bind", cfg.BindAddr, "Bind the client to this local IP (multi-homed hosts)")
+	fs.BoolVar(&cfg.NoDelay, "N", cfg.NoDelay, "Disable Nagle's algorithm on TCP senders (TCP_NODELAY)")
+	fs.BoolVar(&cfg.NoDelay, "no-delay", cfg.NoDelay, "Disable Nagle's algorithm on TCP senders (TCP_NODELAY)")
+	fs.IntVar(&cfg.MSS, "M", cfg.MSS, "Clamp the TCP MSS to N bytes (88-9000)")
+	fs.IntVar(&cfg.MSS, "mss", cfg.MSS, "Clamp the TCP MSS to N bytes (88-9000)")
+	fs.IntVar(&cfg.ConnTimeout, "connect-timeout", cfg.ConnTimeout, "Give up a TCP connect after N ms (0 = iperf2 default)")
+	fs.StringVar(&cfg.Label, "label", cfg.Label, "Free-text label stored with each result (e.g. office-wifi)")
+	fs.Var(&argList{args: &cfg.ExtraArgs}, "x", "Extra iperf2 client flag passed through verbatim; repeat for several (e.g. -x=--trip-times)")
+	fs.IntVar(&cfg.SteadyTrim, "steady-trim", cfg.SteadyTrim, "Also show throughput averaged without the first and last N intervals")
+	fs.IntVar(&cfg.LinkMbps, "link-mbps", cfg.LinkMbps, "Local link rate in Mbps for the clock-skew check (default: probed interface speed)")
 
 	// Remote server flags
-	fs.StringVar(&cfg.SSHHost, "ssh", "", "SSH host for remote server")
-	fs.StringVar(&cfg.SSHUser, "user", defaultUsername(), "SSH username")
-	fs.StringVar(&cfg.SSHKeyPath, "key", "", "SSH private key path")
-	fs.StringVar(&cfg.SSHPassword, "password", "", "SSH password (insecure, use key instead)")
-	fs.IntVar(&cfg.SSHPort, "ssh-port", 22, "SSH port")
-	fs.BoolVar(&cfg.StartServer, "start-server", false, "Start remote iperf2 server")
-	fs.BoolVar(&cfg.StopServer, "stop-server", false, "Stop remote iperf2 server")
-	fs.BoolVar(&cfg.InstallIperf, "install", false, "Install iperf2 on remote host")
+	fs.StringVar(&cfg.SSHHost, "ssh", cfg.SSHHost, "SSH host for remote server")
+	fs.StringVar(&cfg.SSHUser, "user", cfg.SSHUser, "SSH username")
+	fs.StringVar(&cfg.SSHKeyPath, "key", cfg.SSHKeyPath, "SSH private key path")
+	fs.StringVar(&cfg.SSHPassword, "password", cfg.SSHPassword, "SSH password (insecure, use key instead)")
+	fs.IntVar(&cfg.SSHPort, "ssh-port", cfg.SSHPort, "SSH port")
+	fs.StringVar(&cfg.SSHJump, "jump", cfg.SSHJump, "SSH jump host (ProxyJump) [user@]host[:port]")
+	fs.StringVar(&cfg.RemoteBinary, "remote-binary", cfg.RemoteBinary, "Path to iperf2 on the remote host (default: iperf on PATH)")
+	fs.BoolVar(&cfg.StartServer, "start-server", cfg.StartServer, "Start remote iperf2 server")
+	fs.BoolVar(&cfg.StopServer, "stop-server", cfg.StopServer, "Stop remote iperf2 server")
+	fs.BoolVar(&cfg.InstallIperf, "install", cfg.InstallIperf, "Install iperf2 on remote host")
+	fs.BoolVar(&cfg.NoServerOutput, "no-server-output", cfg.NoServerOutput, "Don't log the SSH-started server to a file; receiver data comes from the Server Report only")
+
+	// Listen mode flags
+	fs.BoolVar(&cfg.Listen, "listen", cfg.Listen, "Run a local iperf2 server on -p (listen mode)")
+	fs.BoolVar(&cfg.Daemon, "D", cfg.Daemon, "With --listen: run the server as a daemon (iperf2 -D)")
+	fs.BoolVar(&cfg.Daemon, "daemon", cfg.Daemon, "With --listen: run the server as a daemon (iperf2 -D)")
 
 	// Repeat flags
-	fs.BoolVar(&cfg.Repeat, "repeat", false, "Repeat measurements in a loop until Ctrl-C (or --repeat-count reached)")
-	fs.IntVar(&cfg.RepeatCount, "repeat-count", 0, "Number of repeat iterations (0 = infinite)")
+	fs.BoolVar(&cfg.Repeat, "repeat", cfg.Repeat, "Repeat measurements in a loop until Ctrl-C (or --repeat-count reached)")
+	fs.IntVar(&cfg.RepeatCount, "repeat-count", cfg.RepeatCount, "Number of repeat iterations (0 = infinite)")
+	fs.IntVar(&cfg.Average, "average", cfg.Average, "Run N tests back to back and report their mean as one result")
+
+	// Schedule flags
+	fs.DurationVar(&cfg.Every, "every", cfg.Every, "Run a test at wall-clock ticks of this period (e.g. 15m) until Ctrl-C")
+	fs.DurationVar(&cfg.EveryFor, "for", cfg.EveryFor, "With -every: stop scheduling after this long (e.g. 24h)")
+	var untilFlag string
+	fs.StringVar(&untilFlag, "until", "", "With -every: stop scheduling after this time (15:04, 2006-01-02 15:04 or RFC 3339)")
+
+	// Retry flags
+	fs.IntVar(&cfg.MaxRetries, "retries", cfg.MaxRetries, "Retry a run up to N times on connection refused / server busy")
+	fs.DurationVar(&cfg.RetryBackoff, "retry-delay", cfg.RetryBackoff, "First retry delay, doubled on each retry (default 1s)")
 
 	// Output flags
-	fs.StringVar(&cfg.OutputCSV, "o", "", "Output base path (default: results/results); date suffix added automatically")
-	fs.StringVar(&cfg.OutputCSV, "output", "", "Output base path (default: results/results); date suffix added automatically")
-	fs.BoolVar(&cfg.Verbose, "v", false, "Verbose output")
-	fs.BoolVar(&cfg.Verbose, "verbose", false, "Verbose output")
-	fs.BoolVar(&cfg.Debug, "debug", false, "Log raw iperf2 output to "+iperf.DebugLogPath)
+	fs.StringVar(&cfg.OutputCSV, "o", cfg.OutputCSV, "Output base path (default: results/results); date suffix added automatically")
+	fs.StringVar(&cfg.OutputCSV, "output", cfg.OutputCSV, "Output base path (default: results/results); date suffix added automatically")
+	fs.BoolVar(&cfg.SaveJSON, "json", cfg.SaveJSON, "Also save results as a JSON array (requires -o)")
+	fs.BoolVar(&cfg.SaveMD, "md", cfg.SaveMD, "Also save results as a Markdown report (requires -o)")
+	fs.BoolVar(&cfg.SaveHTML, "html", cfg.SaveHTML, "Also save an HTML report with a throughput chart per measurement (requires -o)")
+	saveTXT, saveIntervals, saveSummary := !cfg.NoSaveTXT, !cfg.NoSaveIntervals, !cfg.NoSaveSummary
+	fs.BoolVar(&saveTXT, "save-txt", saveTXT, "Write the dated TXT report (with -o); -save-txt=false skips it")
+	fs.BoolVar(&saveIntervals, "save-intervals", saveIntervals, "Write the per-interval CSV (with -o); -save-intervals=false skips it")
+	fs.BoolVar(&saveSummary, "save-summary", saveSummary, "Append to the _log.csv summary (with -o); -save-summary=false skips it")
+	fs.BoolVar(&cfg.SkipOmitted, "skip-omitted", cfg.SkipOmitted, "Leave omitted (-O) warm-up intervals out of the interval CSV")
+	fs.IntVar(&cfg.IntervalCap, "interval-cap", cfg.IntervalCap, "Merge intervals into at most this many rows per direction in memory; the interval CSV is written as the test runs")
+	fs.BoolVar(&cfg.Detailed, "detailed", cfg.Detailed, "Add Retr/s and cumulative retransmit columns to the TXT report")
+	fs.BoolVar(&cfg.ErrorJSON, "error-json", cfg.ErrorJSON, "On failure print the error as JSON on stdout instead of text on stderr")
+	fs.BoolVar(&cfg.IncludeServerOutput, "include-server-output", cfg.IncludeServerOutput, "Append the raw server-side iperf2 output to the TXT report")
+	fs.StringVar(&cfg.CSVSep, "csv-sep", cfg.CSVSep, "CSV field separator: ',', ';' or 'tab' (default ';')")
+	fs.StringVar(&cfg.PromPath, "prom", cfg.PromPath, "Write latest result as Prometheus textfile metrics (repeat mode)")
+	fs.StringVar(&cfg.InfluxPath, "influx", cfg.InfluxPath, "Append each result to a file as InfluxDB line protocol")
+	fs.StringVar(&cfg.InfluxURL, "influx-url", cfg.InfluxURL, "POST each result as InfluxDB line protocol to this write URL")
+	fs.StringVar(&cfg.DBPath, "db", cfg.DBPath, "Insert each result and its intervals into a SQLite database")
+	fs.BoolVar(&cfg.Verbose, "v", cfg.Verbose, "Verbose output")
+	fs.BoolVar(&cfg.Verbose, "verbose", cfg.Verbose, "Verbose output")
+	fs.BoolVar(&cfg.Quiet, "q", cfg.Quiet, "Quiet: print only a one-line summary per test")
+	fs.BoolVar(&cfg.Quiet, "quiet", cfg.Quiet, "Quiet: print only a one-line summary per test")
+	fs.StringVar(&cfg.Color, "color", cfg.Color, "Color the result summary: auto (terminal only), always or never")
+	fs.StringVar(&cfg.Units, "units", cfg.Units, "Display units: decimal (MB, Mbps) or binary (MiB, Mibps)")
+	fs.BoolVar(&cfg.DryRun, "dry-run", cfg.DryRun, "Print the iperf2 commands that would run, then exit")
+	fs.IntVar(&cfg.MTUCheck, "mtu-check", 0, "Print how UDP datagrams (-l) fragment on a path with this MTU, then exit")
+	fs.BoolVar(&cfg.Doctor, "doctor", false, "Check iperf2, ping and port readiness on this machine, then exit")
+	fs.StringVar(&cfg.Import, "import", cfg.Import, "Format a saved iperf2 output file instead of running a test")
+	var compareFlag bool
+	fs.BoolVar(&compareFlag, "compare", false, "Compare two saved result files (.json or _log.csv) given as arguments")
+	fs.StringVar(&cfg.StreamJSON, "stream-json", cfg.StreamJSON, "Write each interval as a JSON line to this file ('-' = stdout) while the test runs")
+	fs.BoolVar(&cfg.Debug, "debug", cfg.Debug, "Log raw iperf2 output to "+iperf.DebugLogPath)
+	fs.StringVar(&cfg.DebugLog, "debug-log", cfg.DebugLog, "Log raw iperf2 output to this file (implies -debug)")
 
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		return nil, err
 	}
+	cfg.NoSaveTXT, cfg.NoSaveIntervals, cfg.NoSaveSummary = !saveTXT, !saveIntervals, !saveSummary
+
+	// -n, -k and -t each bound the test; only one may be given
+	bounds := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "t", "time":
+			bounds["-t"] = true
+		case "n", "num":
+			bounds["-n"] = true
+		case "k", "blockcount":
+			bounds["-k"] = true
+		}
+	})
+	if len(bounds) > 1 {
+		return nil, fmt.Errorf("-t, -n and -k are mutually exclusive; give only one test bound")
+	}
+	cfg.BytesLimit = strings.ToUpper(cfg.BytesLimit)
+
+	// Display units are applied by the caller (cfg.UnitSystem), for every
+	// output including -compare
+	if _, err := format.ParseUnitSystem(cfg.Units); err != nil {
+		return nil, fmt.Errorf("-units must be decimal or binary, got %q", cfg.Units)
+	}
+
+	// Compare: diff two saved result files, nothing is run
+	if compareFlag {
+		if fs.NArg() != 2 {
+			return nil, fmt.Errorf("-compare needs two result files (.json or _log.csv), got %d", fs.NArg())
+		}
+		cfg.Compare = fs.Args()
+		return cfg, nil
+	}
+
+	// Doctor: check the local environment, nothing is run
+	if cfg.Doctor {
+		return cfg, nil
+	}
 
-	// Normalize protocol: -u flag takes precedence over --protocol
-	if udpFlag || cfg.Protocol == "udp" || cfg.Protocol == "u" {
+	// Normalize protocol: -u flag takes precedence over --protocol.
+	// iperf2 has no SCTP mode, so anything other than tcp/udp is rejected
+	// rather than silently run as TCP.
+	switch p := strings.ToLower(cfg.Protocol); {
+	case udpFlag || p == "udp" || p == "u":
 		cfg.Protocol = "udp"
-	} else {
+	case p == "" || p == "tcp" || p == "t":
 		cfg.Protocol = "tcp"
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q: iperf2 supports tcp and udp only", cfg.Protocol)
+	}
+
+	if cfg.PingCount < 1 {
+		return nil, fmt.Errorf("-ping-count must be >= 1, got %d", cfg.PingCount)
+	}
+
+	switch cfg.BidirMode = strings.ToLower(cfg.BidirMode); cfg.BidirMode {
+	case "", iperf.BidirAuto:
+	case iperf.BidirDualtest, iperf.BidirSplit:
+		if !cfg.Bidir {
+			return nil, fmt.Errorf("-mode %s only applies to bidirectional tests; add --bidir", cfg.BidirMode)
+		}
+	default:
+		return nil, fmt.Errorf("-mode must be auto, dualtest or split, got %q", cfg.BidirMode)
+	}
+
+	cfg.Sweep = nil
+	for _, rate := range strings.Split(sweep, ",") {
+		if rate = strings.TrimSpace(rate); rate != "" {
+			cfg.Sweep = append(cfg.Sweep, rate)
+		}
+	}
+	if len(cfg.Sweep) > 0 {
+		if cfg.Protocol != "udp" {
+			return nil, fmt.Errorf("-sweep needs a UDP test (-u)")
+		}
+		if cfg.Repeat || cfg.SSHHost != "" || len(cfg.ServerAddrs) > 1 {
+			return nil, fmt.Errorf("-sweep cannot be combined with --repeat, -ssh or multiple servers")
+		}
+	}
+	if cfg.LossThreshold < 0 {
+		return nil, fmt.Errorf("-loss-threshold must be >= 0, got %g", cfg.LossThreshold)
+	}
+
+	if cfg.TCPPing && cfg.Protocol == "udp" {
+		return nil, fmt.Errorf("-tcp-ping needs a TCP test (the iperf2 UDP server accepts no TCP connections)")
+	}
+
+	if cfg.DebugLog != "" {
+		cfg.Debug = true
+	}
+
+	if cfg.MaxRetries < 0 {
+		return nil, fmt.Errorf("-retries must be >= 0, got %d", cfg.MaxRetries)
+	}
+
+	if cfg.DSCP != "" {
+		if _, err := iperf.ParseTOS(cfg.DSCP); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.LinkMbps < 0 {
+		return nil, fmt.Errorf("-link-mbps must be >= 0, got %d", cfg.LinkMbps)
+	}
+	if cfg.ConnTimeout < 0 {
+		return nil, fmt.Errorf("-connect-timeout must be >= 0 ms, got %d", cfg.ConnTimeout)
+	}
+	if cfg.MSS != 0 && (cfg.MSS < iperf.MinMSS || cfg.MSS > iperf.MaxMSS) {
+		return nil, fmt.Errorf("-M must be between %d and %d bytes, got %d", iperf.MinMSS, iperf.MaxMSS, cfg.MSS)
+	}
+
+	if cfg.CSVSep != "" {
+		if _, err := export.ParseDelimiter(cfg.CSVSep); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.IntervalCap < 0 {
+		return nil, fmt.Errorf("-interval-cap must be >= 0, got %d", cfg.IntervalCap)
+	}
+
+	switch cfg.Color {
+	case "auto", "always", "never":
+	default:
+		return nil, fmt.Errorf("-color must be auto, always or never, got %q", cfg.Color)
+	}
+
+	// A single server collapses to a one-element list; ServerAddr always
+	// mirrors the first entry so single-server code paths are unchanged.
+	cfg.ServerAddr = ""
+	if len(cfg.ServerAddrs) > 0 {
+		cfg.ServerAddr = cfg.ServerAddrs[0]
+	}
+	if len(cfg.ServerAddrs) > 1 && cfg.SSHHost != "" {
+		return nil, fmt.Errorf("multiple servers are not supported with -ssh")
+	}
+	if cfg.MTUCheck != 0 && (cfg.MTUCheck < 68 || cfg.MTUCheck > 65535) {
+		return nil, fmt.Errorf("-mtu-check must be between 68 and 65535, got %d", cfg.MTUCheck)
+	}
+	if cfg.SteadyTrim < 0 {
+		return nil, fmt.Errorf("-steady-trim must be >= 0, got %d", cfg.SteadyTrim)
+	}
+	if untilFlag != "" {
+		t, err := ParseUntil(untilFlag, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("-until: %w", err)
+		}
+		cfg.EveryUntil = t
+	}
+	if cfg.Every < 0 || cfg.EveryFor < 0 {
+		return nil, fmt.Errorf("-every and -for must not be negative")
+	}
+	if cfg.Every == 0 && (cfg.EveryFor > 0 || untilFlag != "") {
+		return nil, fmt.Errorf("-for and -until need -every")
+	}
+	if cfg.EveryFor > 0 && untilFlag != "" {
+		return nil, fmt.Errorf("-for and -until are mutually exclusive")
+	}
+	if cfg.Every > 0 {
+		if cfg.Every <= time.Duration(cfg.Duration)*time.Second {
+			return nil, fmt.Errorf("-every (%s) must be longer than the test duration (%ds)", cfg.Every, cfg.Duration)
+		}
+		if cfg.Repeat || len(cfg.Sweep) > 0 || cfg.Concurrent {
+			return nil, fmt.Errorf("-every cannot be combined with --repeat, -sweep or -concurrent")
+		}
+	}
+	if cfg.Average < 0 || cfg.Average == 1 {
+		return nil, fmt.Errorf("-average must be at least 2, got %d", cfg.Average)
+	}
+	if cfg.Average > 1 && (cfg.Repeat || len(cfg.Sweep) > 0 || cfg.Every > 0 || cfg.Concurrent) {
+		return nil, fmt.Errorf("-average cannot be combined with --repeat, -sweep, -every or -concurrent")
+	}
+	if cfg.Concurrent {
+		if len(cfg.ServerAddrs) < 2 {
+			return nil, fmt.Errorf("-concurrent needs at least two servers (-s)")
+		}
+		if cfg.Repeat || len(cfg.Sweep) > 0 {
+			return nil, fmt.Errorf("-concurrent cannot be combined with --repeat or -sweep")
+		}
+	}
+
+	if cfg.InfluxURL != "" && !strings.HasPrefix(cfg.InfluxURL, "http://") && !strings.HasPrefix(cfg.InfluxURL, "https://") {
+		return nil, fmt.Errorf("-influx-url must be an http:// or https:// URL, got %q", cfg.InfluxURL)
 	}
 
-	// Validate: must have either server address or SSH host
-	if cfg.ServerAddr == "" && cfg.SSHHost == "" {
-		fmt.Fprintf(os.Stderr, "Error: must provide -s <server> for local test or -ssh <host> for remote server\n\n")
+	if cfg.Quiet && cfg.Verbose {
+		return nil, fmt.Errorf("-q and -v are mutually exclusive")
+	}
+
+	if ipv4Only && ipv6Only {
+		return nil, fmt.Errorf("-4 and -6 are mutually exclusive")
+	}
+	if ipv4Only {
+		cfg.AddrFamily = "4"
+	} else if ipv6Only {
+		cfg.AddrFamily = "6"
+	}
+
+	if cfg.Daemon && !cfg.Listen {
+		return nil, fmt.Errorf("-daemon requires --listen")
+	}
+	if cfg.Listen && (cfg.ServerAddr != "" || cfg.SSHHost != "") {
+		return nil, fmt.Errorf("--listen cannot be combined with -s or -ssh")
+	}
+
+	if cfg.Import != "" && (cfg.SSHHost != "" || cfg.Listen) {
+		return nil, fmt.Errorf("-import cannot be combined with -ssh or --listen")
+	}
+
+	// Validate: must have a server address, an SSH host, listen mode or an import file
+	if cfg.ServerAddr == "" && cfg.SSHHost == "" && !cfg.Listen && cfg.Import == "" {
+		fmt.Fprintf(os.Stderr, "Error: must provide -s <server> for local test, -ssh <host> for remote server, --listen or -import <file>\n\n")
 		PrintUsage()
 		return nil, fmt.Errorf("missing required flags")
 	}
@@ -130,20 +493,46 @@ Usage: iperf-tool [flags]
        iperf-tool help    (show this message)
 
 LOCAL TEST MODE:
-  -s, --server <addr>      Server address to test (required for local test)
+  -s, --server <addr>      Server address to test (required for local test);
+                           comma-separated or repeated to test several servers in turn
+  --concurrent             Test all -s servers at the same time (they share the local NIC)
   -p, --port <num>         Server port (default: 5201)
   -P, --parallel <num>     Parallel streams (default: 1)
   -t, --time <sec>         Test duration in seconds (default: 10)
-  -i, --interval <sec>     Reporting interval (default: 1)
+  -n, --num <size>         Stop after this much data, e.g. 500M, 1G (replaces -t)
+  -k, --blockcount <n>     Stop after this many blocks/datagrams (replaces -t)
+  -i, --interval <sec>     Reporting interval, fractional allowed e.g. 0.5 (default: 1)
   -u                       UDP mode (shorthand for --protocol udp)
       --protocol <tcp|udp> Protocol (default: tcp)
   -l, --block-size <bytes> Block size / buffer length (default: iperf2 default)
   -R, --reverse            Reverse mode (server sends, client receives)
   --bidir                  Bidirectional mode (simultaneous both directions)
+  --mode <m>               Bidir run mode: auto, dualtest or split (default: auto)
   -b, --bandwidth <rate>   Target bandwidth (e.g. 100M, 1G; empty = unlimited)
+  --sweep <rates>          UDP bitrate sweep, e.g. 10M,50M,100M,500M,1G (one test per rate)
+  --loss-threshold <pct>   Loss percentage that ends a --sweep (default: 2)
   -V, --ipv6               Use IPv6
+  -4, -6                   Force IPv4 or IPv6 regardless of what the hostname resolves to
+  -O, --omit <sec>         Flag the first N seconds as warm-up (omitted intervals)
+  -w, --window <size>      Socket buffer / TCP window size (e.g. 256K, 2M; default: OS)
+  -B, --bind <ip>          Local IP the client binds to (multi-homed hosts)
+  -N, --no-delay           Disable Nagle's algorithm on TCP senders (TCP_NODELAY)
+  -M, --mss <bytes>        Clamp the TCP MSS (88-9000) for path-MTU experiments
+  --connect-timeout <ms>   Give up a TCP connect after this many ms (default: iperf2's)
+  --link-mbps <N>          Local link rate for the clock-skew check (default: probed)
+  --label <text>           Tag results with a free-text label (CSV, TXT and summary)
+  -x <flag>                Pass an extra flag to the iperf2 client; repeatable
+  --steady-trim <n>        Also show throughput without the first and last n intervals
+  -S, --dscp <tos|class>   Mark packets: ToS byte (0-255) or DSCP class (ef, af41, cs5)
   --ping                   Measure latency before and during test
+  --ping-count <n>         Baseline ping packets / TCP connect samples (default: 4)
+  --tcp-ping               Baseline latency as TCP connect time to the iperf port (TCP only)
   --binary <path>          Path to iperf2 binary (default: iperf)
+  --require-version        Fail instead of running when the local iperf2 is older than 2.0.10
+
+LOCAL SERVER MODE:
+  --listen                 Run an iperf2 server on this machine (port from -p, protocol from -u)
+  -D, --daemon             With --listen: detach the server (iperf2 -D)
 
 REMOTE SERVER MODE:
   --ssh <host>             SSH host to manage remote iperf2 server
@@ -151,18 +540,59 @@ REMOTE SERVER MODE:
   --key <path>             SSH private key path
   --password <pwd>         SSH password (insecure, prefer --key)
   --ssh-port <num>         SSH port (default: 22)
+  --jump <[user@]host>     Connect through an SSH jump host / bastion (like ssh -J)
+  --remote-binary <path>   iperf2 path on the remote host (default: iperf on PATH)
   --install                Install iperf2 on remote host
+  --no-server-output       Don't log the remote server to a file (Server Report only)
   --start-server           Start remote iperf2 server
   --stop-server            Stop remote iperf2 server
 
 REPEAT:
   --repeat                 Repeat measurements in a loop until Ctrl-C (or --repeat-count reached)
   --repeat-count <N>       Run exactly N iterations (0 = infinite, default: 0)
+  --average <N>            Run N tests back to back and report their mean as one result
+  --every <dur>            Run at wall-clock ticks (e.g. 15m runs at :00, :15, :30, :45) until Ctrl-C
+  --for <dur>              With --every: stop after this long (e.g. 24h)
+  --until <time>           With --every: stop after this time (15:04, 2006-01-02 15:04, RFC 3339)
+
+RETRY:
+  --retries <N>            Retry a run up to N times on connection refused / server busy (default: 0)
+  --retry-delay <dur>      First retry delay, doubled on each retry (e.g. 500ms, 2s; default: 1s)
+
+CONFIG FILE:
+  --config <path>          Load defaults from a JSON file (keys = RunnerConfig field names);
+                           flags given on the command line override file values
 
 OUTPUT:
   -o, --output <path>      Output base path (default: results/results); date appended automatically
+  --json                   Also save results as a dated JSON array (with -o)
+  --md                     Also append results to a dated Markdown report (with -o)
+  --html                   Also write a self-contained HTML report per measurement (with -o)
+  --save-txt=false         Don't write the dated TXT report (with -o)
+  --save-intervals=false   Don't write the per-interval CSV (with -o)
+  --save-summary=false     Don't append to the _log.csv summary (with -o)
+  --csv-sep <sep>          CSV field separator: , ; or tab (default: ;)
+  --skip-omitted           Leave omitted warm-up intervals out of the interval CSV
+  --interval-cap <n>       Merge intervals into at most n rows per direction; the interval CSV is written live
+  --detailed               Add Retr/s and cumulative retransmits to the TXT report
+  --error-json             On failure print {"error","kind","server","timestamp"} JSON
+  --include-server-output  Append the raw server-side iperf2 output to the TXT report
+  --prom <path>            Rewrite Prometheus textfile metrics after each --repeat run
+  --influx <path>          Append each result to a file as InfluxDB line protocol
+  --influx-url <url>       POST each result as line protocol, e.g. http://host:8086/write?db=iperf
+  --db <path>              Insert each result and its intervals into a SQLite database
   -v, --verbose            Verbose output
+  -q, --quiet              Print only a one-line summary per test (errors go to stderr)
+  --color <mode>           Color the result summary: auto, always or never (default: auto)
+  --units <system>         Display units: decimal (MB, Mbps) or binary (MiB, Mibps) (default: decimal)
+  --dry-run                Print the iperf2 commands that would run, then exit
+  --mtu-check <mtu>        Print how UDP datagrams (-l) fragment at this path MTU, then exit
+  --doctor                 Check iperf2, -Z support, ping and the port, print a readiness report
+  --import <file>          Format a saved iperf2 output file (prints it, writes -o outputs)
+  --compare <a> <b>        Diff the last result of two .json or _log.csv files side by side
+  --stream-json <path|->   Append one JSON line per interval while the test runs ('-' = stdout)
   --debug                  Log raw iperf2 output to temp dir (GUI: set IPERF_DEBUG=1)
+  --debug-log <path>       Log raw iperf2 output to <path> instead (implies --debug)
 
 EXAMPLES:
   # Run local test to server
@@ -171,6 +601,9 @@ EXAMPLES:
   # Run test with verbose output
   iperf-tool -s server.example.com -t 60 -v
 
+  # Cron-friendly run: one summary line on stdout
+  iperf-tool -s 10.0.0.1 -q -o results/nightly
+
   # Test via UDP
   iperf-tool -s 10.0.0.1 -u -t 20
 
@@ -183,6 +616,9 @@ EXAMPLES:
   # Repeat exactly 5 times
   iperf-tool -s 192.168.1.1 -t 10 --repeat --repeat-count 5
 
+  # Test every 15 minutes for a day, logging to results/
+  iperf-tool -s 192.168.1.1 --every 15m --for 24h -o results/link
+
   # Install iperf2 on remote server and start it
   iperf-tool --ssh remote.host --user ubuntu --key ~/.ssh/id_rsa --install --start-server
 
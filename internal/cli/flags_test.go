@@ -2,7 +2,12 @@ package cli
 
 import (
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"iperf-tool/internal/format"
 )
 
 func TestParseFlags_NoArgs(t *testing.T) {
@@ -176,6 +181,388 @@ func TestParseFlags_BandwidthFlag(t *testing.T) {
 	}
 }
 
+func TestParseFlags_OmitFlag(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-server", "10.0.0.1", "-O", "2"}
+
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.OmitSeconds != 2 {
+		t.Errorf("OmitSeconds = %d, want 2", cfg.OmitSeconds)
+	}
+}
+
+func TestParseFlags_AddrFamily(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-server", "example.com", "-6"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.AddrFamily != "6" {
+		t.Errorf("AddrFamily = %q, want 6", cfg.AddrFamily)
+	}
+
+	os.Args = []string{"iperf-tool", "-server", "example.com", "-4"}
+	cfg, err = ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.AddrFamily != "4" {
+		t.Errorf("AddrFamily = %q, want 4", cfg.AddrFamily)
+	}
+
+	os.Args = []string{"iperf-tool", "-server", "example.com", "-4", "-6"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("ParseFlags() with -4 and -6 should return error")
+	}
+}
+
+func TestParseFlags_JSONFlag(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-server", "10.0.0.1", "-o", "out/results", "-json"}
+
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !cfg.SaveJSON {
+		t.Error("SaveJSON should be true")
+	}
+}
+
+func TestParseFlags_MarkdownFlag(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-server", "10.0.0.1", "-o", "out/results", "-md"}
+
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !cfg.SaveMD {
+		t.Error("SaveMD should be true")
+	}
+}
+
+func TestParseFlags_HTMLFlag(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-server", "10.0.0.1", "-o", "out/results", "-html"}
+
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !cfg.SaveHTML {
+		t.Error("SaveHTML should be true")
+	}
+}
+
+func TestParseFlags_SkipOmittedFlag(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-server", "10.0.0.1", "-O", "2", "-skip-omitted"}
+
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !cfg.SkipOmitted {
+		t.Error("SkipOmitted should be true")
+	}
+}
+
+func TestParseFlags_Quiet(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-server", "10.0.0.1", "-q"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !cfg.Quiet {
+		t.Error("Quiet should be true")
+	}
+
+	os.Args = []string{"iperf-tool", "-server", "10.0.0.1", "-quiet", "-v"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for -quiet with -v")
+	}
+}
+
+func TestParseFlags_Color(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-server", "10.0.0.1"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.Color != "auto" {
+		t.Errorf("Color = %q, want auto", cfg.Color)
+	}
+
+	os.Args = []string{"iperf-tool", "-server", "10.0.0.1", "-color", "sometimes"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for -color sometimes")
+	}
+}
+
+func TestParseFlags_MSS(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-M", "1400"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.MSS != 1400 {
+		t.Errorf("MSS = %d, want 1400", cfg.MSS)
+	}
+	if got := cfg.iperfConfig().MSS; got != 1400 {
+		t.Errorf("iperfConfig().MSS = %d, want 1400", got)
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-mss", "40"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for -mss 40")
+	}
+}
+
+func TestParseFlags_ConnectTimeout(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-connect-timeout", "3000"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if got := cfg.iperfConfig().ConnectTimeoutMs; got != 3000 {
+		t.Errorf("iperfConfig().ConnectTimeoutMs = %d, want 3000", got)
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-connect-timeout", "-5"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for -connect-timeout -5")
+	}
+}
+
+func TestParseFlags_Average(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-average", "5"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.Average != 5 {
+		t.Errorf("Average = %d, want 5", cfg.Average)
+	}
+
+	for _, args := range [][]string{
+		{"-average", "1"},
+		{"-average", "3", "--repeat"},
+	} {
+		os.Args = append([]string{"iperf-tool", "-s", "10.0.0.1"}, args...)
+		if _, err := ParseFlags(); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestParseFlags_Compare(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-compare", "a.json", "b.json"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if len(cfg.Compare) != 2 || cfg.Compare[0] != "a.json" || cfg.Compare[1] != "b.json" {
+		t.Errorf("Compare = %v, want [a.json b.json]", cfg.Compare)
+	}
+
+	os.Args = []string{"iperf-tool", "-compare", "a.json"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for -compare with one file")
+	}
+}
+
+func TestParseFlags_Sweep(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-u", "-sweep", "10M, 50M,100M", "-loss-threshold", "1.5"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if strings.Join(cfg.Sweep, ",") != "10M,50M,100M" || cfg.LossThreshold != 1.5 {
+		t.Errorf("Sweep/LossThreshold = %v/%g, want [10M 50M 100M]/1.5", cfg.Sweep, cfg.LossThreshold)
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-sweep", "10M"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for -sweep without -u")
+	}
+}
+
+func TestParseFlags_PromFlag(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-server", "10.0.0.1", "-repeat", "-prom", "/var/lib/node_exporter/iperf.prom"}
+
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.PromPath != "/var/lib/node_exporter/iperf.prom" {
+		t.Errorf("PromPath = %q", cfg.PromPath)
+	}
+}
+
+func TestParseFlags_CSVSep(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-server", "10.0.0.1", "-csv-sep", ","}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.CSVSep != "," {
+		t.Errorf("CSVSep = %q, want ,", cfg.CSVSep)
+	}
+
+	os.Args = []string{"iperf-tool", "-server", "10.0.0.1", "-csv-sep", "|"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("ParseFlags() with invalid -csv-sep should return error")
+	}
+}
+
+func TestParseFlags_Retry(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-server", "10.0.0.1", "-retries", "3", "-retry-delay", "500ms"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.MaxRetries != 3 {
+		t.Errorf("MaxRetries = %d, want 3", cfg.MaxRetries)
+	}
+	if cfg.RetryBackoff != 500*time.Millisecond {
+		t.Errorf("RetryBackoff = %v, want 500ms", cfg.RetryBackoff)
+	}
+
+	os.Args = []string{"iperf-tool", "-server", "10.0.0.1", "-retries", "-1"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("ParseFlags() with negative -retries should return error")
+	}
+}
+
+func TestParseFlags_MultipleServers(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"single", []string{"-s", "10.0.0.1"}, []string{"10.0.0.1"}},
+		{"comma-separated", []string{"-s", "10.0.0.1, 10.0.0.2,10.0.0.3"}, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}},
+		{"repeated", []string{"-s", "10.0.0.1", "--server", "10.0.0.2"}, []string{"10.0.0.1", "10.0.0.2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"iperf-tool"}, tt.args...)
+			cfg, err := ParseFlags()
+			if err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+			if !reflect.DeepEqual(cfg.ServerAddrs, tt.want) {
+				t.Errorf("ServerAddrs = %v, want %v", cfg.ServerAddrs, tt.want)
+			}
+			if cfg.ServerAddr != tt.want[0] {
+				t.Errorf("ServerAddr = %q, want %q", cfg.ServerAddr, tt.want[0])
+			}
+		})
+	}
+}
+
+func TestParseFlags_MultipleServersWithSSH(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-ssh", "host", "-s", "10.0.0.1,10.0.0.2"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for multiple servers with -ssh")
+	}
+}
+
+func TestParseFlags_DSCP(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-dscp", "af41", "-w", "512K", "-B", "10.0.0.2", "-N"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.DSCP != "af41" {
+		t.Errorf("DSCP = %q, want af41", cfg.DSCP)
+	}
+	if cfg.WindowSize != "512K" {
+		t.Errorf("WindowSize = %q, want 512K", cfg.WindowSize)
+	}
+	if cfg.BindAddr != "10.0.0.2" {
+		t.Errorf("BindAddr = %q, want 10.0.0.2", cfg.BindAddr)
+	}
+	if !cfg.NoDelay {
+		t.Error("NoDelay = false, want true")
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-S", "999"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for out-of-range ToS byte")
+	}
+}
+
+func TestParseFlags_FractionalInterval(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-i", "0.5"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.Interval != 0.5 {
+		t.Errorf("Interval = %v, want 0.5", cfg.Interval)
+	}
+}
+
 func TestParseFlags_MissingRequired(t *testing.T) {
 	origArgs := os.Args
 	defer func() { os.Args = origArgs }()
@@ -191,3 +578,474 @@ func TestParseFlags_MissingRequired(t *testing.T) {
 		t.Errorf("ParseFlags() with error should return nil config, got %v", cfg)
 	}
 }
+
+func TestParseFlags_Listen(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "--listen", "-p", "5301", "-D"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !cfg.Listen || !cfg.Daemon || cfg.Port != 5301 {
+		t.Errorf("Listen=%v Daemon=%v Port=%d", cfg.Listen, cfg.Daemon, cfg.Port)
+	}
+
+	os.Args = []string{"iperf-tool", "--listen", "-s", "10.0.0.1"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for --listen with -s")
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-daemon"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for -daemon without --listen")
+	}
+}
+
+func TestParseFlags_DebugLog(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-debug-log", "/var/tmp/raw.log"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !cfg.Debug || cfg.DebugLog != "/var/tmp/raw.log" {
+		t.Errorf("Debug=%v DebugLog=%q", cfg.Debug, cfg.DebugLog)
+	}
+}
+
+func TestParseFlags_UnsupportedProtocol(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "--protocol", "sctp"}
+	if _, err := ParseFlags(); err == nil || !strings.Contains(err.Error(), "sctp") {
+		t.Errorf("ParseFlags() error = %v, want unsupported sctp", err)
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "--protocol", "UDP"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.Protocol != "udp" {
+		t.Errorf("Protocol = %q, want udp", cfg.Protocol)
+	}
+}
+
+func TestParseFlags_TCPPing(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-tcp-ping"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !cfg.TCPPing {
+		t.Error("TCPPing = false, want true")
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-u", "-tcp-ping"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for -tcp-ping with UDP")
+	}
+}
+
+func TestParseFlags_PingCount(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.PingCount != 4 {
+		t.Errorf("default PingCount = %d, want 4", cfg.PingCount)
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-ping-count", "10"}
+	cfg, err = ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.PingCount != 10 {
+		t.Errorf("PingCount = %d, want 10", cfg.PingCount)
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-ping-count", "0"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for -ping-count 0")
+	}
+}
+
+func TestParseFlags_Jump(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-ssh", "10.0.0.5", "-jump", "admin@bastion:2222"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.SSHJump != "admin@bastion:2222" {
+		t.Errorf("SSHJump = %q, want admin@bastion:2222", cfg.SSHJump)
+	}
+}
+
+func TestParseFlags_Concurrent(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-s", "10.0.1.1", "-concurrent"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !cfg.Concurrent || len(cfg.ServerAddrs) != 2 {
+		t.Errorf("Concurrent = %v, ServerAddrs = %v", cfg.Concurrent, cfg.ServerAddrs)
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-concurrent"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for -concurrent with one server")
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1,10.0.1.1", "-concurrent", "--repeat"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for -concurrent with --repeat")
+	}
+}
+
+func TestParseFlags_DB(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-db", "results/history.db"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.DBPath != "results/history.db" {
+		t.Errorf("DBPath = %q, want results/history.db", cfg.DBPath)
+	}
+}
+
+func TestParseFlags_Influx(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-influx", "/tmp/iperf.lp", "-influx-url", "http://tsdb:8086/write?db=iperf"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.InfluxPath != "/tmp/iperf.lp" || cfg.InfluxURL != "http://tsdb:8086/write?db=iperf" {
+		t.Errorf("InfluxPath = %q, InfluxURL = %q", cfg.InfluxPath, cfg.InfluxURL)
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-influx-url", "tsdb:8086"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for -influx-url without a scheme")
+	}
+}
+
+func TestParseFlags_SteadyTrim(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-steady-trim", "2"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.SteadyTrim != 2 {
+		t.Errorf("SteadyTrim = %d, want 2", cfg.SteadyTrim)
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-steady-trim", "-1"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for negative -steady-trim")
+	}
+}
+
+func TestParseFlags_NoServerOutput(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-ssh", "10.0.0.5", "-s", "10.0.0.5", "-no-server-output"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !cfg.NoServerOutput || !cfg.iperfConfig().NoServerOutput {
+		t.Error("NoServerOutput not set or not passed to the iperf config")
+	}
+}
+
+func TestParseFlags_IncludeServerOutput(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.iperfConfig().KeepServerOutput {
+		t.Error("server output should not be kept by default")
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-include-server-output"}
+	cfg, err = ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !cfg.IncludeServerOutput || !cfg.iperfConfig().KeepServerOutput {
+		t.Error("-include-server-output not passed to the iperf config")
+	}
+}
+
+func TestParseFlags_SaveSelection(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-o", "out"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.NoSaveTXT || cfg.NoSaveIntervals || cfg.NoSaveSummary {
+		t.Errorf("all outputs should be saved by default: %+v", cfg)
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-o", "out", "-save-txt=false", "-save-intervals=false"}
+	cfg, err = ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !cfg.NoSaveTXT || !cfg.NoSaveIntervals || cfg.NoSaveSummary {
+		t.Errorf("NoSaveTXT = %v, NoSaveIntervals = %v, NoSaveSummary = %v", cfg.NoSaveTXT, cfg.NoSaveIntervals, cfg.NoSaveSummary)
+	}
+}
+
+func TestParseFlags_MTUCheck(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-u", "-l", "8000", "-mtu-check", "1500"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.MTUCheck != 1500 {
+		t.Errorf("MTUCheck = %d, want 1500", cfg.MTUCheck)
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-mtu-check", "40"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for an MTU below 68")
+	}
+}
+
+func TestParseFlags_IntervalCap(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-t", "21600", "-interval-cap", "1000"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.IntervalCap != 1000 {
+		t.Errorf("IntervalCap = %d, want 1000", cfg.IntervalCap)
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-interval-cap", "-1"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for a negative -interval-cap")
+	}
+}
+
+func TestParseFlags_RequireVersion(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-require-version"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !cfg.RequireVersion || !cfg.iperfConfig().RequireVersion {
+		t.Errorf("RequireVersion = %v, iperfConfig().RequireVersion = %v; want true", cfg.RequireVersion, cfg.iperfConfig().RequireVersion)
+	}
+}
+
+func TestParseFlags_Doctor(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	// No server is needed: -doctor only inspects the local machine
+	os.Args = []string{"iperf-tool", "-doctor", "-p", "5001"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !cfg.Doctor || cfg.Port != 5001 {
+		t.Errorf("Doctor = %v, Port = %d; want true, 5001", cfg.Doctor, cfg.Port)
+	}
+}
+
+func TestParseFlags_Every(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-every", "15m", "-for", "24h"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.Every != 15*time.Minute || cfg.EveryFor != 24*time.Hour {
+		t.Errorf("Every = %s, EveryFor = %s", cfg.Every, cfg.EveryFor)
+	}
+	start := time.Now()
+	if got := cfg.ScheduleDeadline(start); !got.Equal(start.Add(24 * time.Hour)) {
+		t.Errorf("ScheduleDeadline = %v, want start + 24h", got)
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-every", "1h", "-until", "23:59"}
+	cfg, err = ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.EveryUntil.IsZero() || cfg.EveryUntil.Minute() != 59 {
+		t.Errorf("EveryUntil = %v", cfg.EveryUntil)
+	}
+
+	for _, args := range [][]string{
+		{"-for", "1h"},               // needs -every
+		{"-every", "5s", "-t", "10"}, // shorter than the test
+		{"-every", "1h", "-for", "2h", "-until", "23:00"},
+		{"-every", "1h", "--repeat"},
+		{"-every", "1h", "-until", "noon"},
+	} {
+		os.Args = append([]string{"iperf-tool", "-s", "10.0.0.1"}, args...)
+		if _, err := ParseFlags(); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestParseFlags_DataLimit(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-n", "500m"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.BytesLimit != "500M" {
+		t.Errorf("BytesLimit = %q, want 500M", cfg.BytesLimit)
+	}
+	if c := cfg.iperfConfig(); c.BytesLimit != "500M" {
+		t.Errorf("iperfConfig().BytesLimit = %q", c.BytesLimit)
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "--blockcount", "2000"}
+	cfg, err = ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.BlocksLimit != 2000 {
+		t.Errorf("BlocksLimit = %d, want 2000", cfg.BlocksLimit)
+	}
+
+	for _, args := range [][]string{
+		{"-n", "1G", "-t", "30"},
+		{"-n", "1G", "-k", "100"},
+		{"-k", "100", "--time", "5"},
+	} {
+		os.Args = append([]string{"iperf-tool", "-s", "10.0.0.1"}, args...)
+		if _, err := ParseFlags(); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestParseFlags_ExtraArgs(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-x=--trip-times", "-x", "-Z", "-x", "bbr"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if got := strings.Join(cfg.ExtraArgs, " "); got != "--trip-times -Z bbr" {
+		t.Errorf("ExtraArgs = %q", got)
+	}
+	if c := cfg.iperfConfig(); len(c.ExtraArgs) != 3 {
+		t.Errorf("iperfConfig().ExtraArgs = %v", c.ExtraArgs)
+	}
+}
+
+func TestParseFlags_BidirMode(t *testing.T) {
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "--bidir", "-mode", "Split"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.BidirMode != "split" || cfg.iperfConfig().BidirMode != "split" {
+		t.Errorf("BidirMode = %q, want split", cfg.BidirMode)
+	}
+
+	for _, args := range [][]string{
+		{"-mode", "dualtest"}, // needs --bidir
+		{"--bidir", "-mode", "stream"},
+	} {
+		os.Args = append([]string{"iperf-tool", "-s", "10.0.0.1"}, args...)
+		if _, err := ParseFlags(); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestParseFlags_Units(t *testing.T) {
+	origArgs, origUnits := os.Args, format.Units
+	defer func() { os.Args, format.Units = origArgs, origUnits }()
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.Units != "decimal" || cfg.UnitSystem() != format.Decimal {
+		t.Errorf("Units = %q (%v), want decimal", cfg.Units, cfg.UnitSystem())
+	}
+
+	format.Units = format.Decimal
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-units", "binary"}
+	cfg, err = ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if cfg.UnitSystem() != format.Binary {
+		t.Errorf("UnitSystem() = %v, want binary", cfg.UnitSystem())
+	}
+	if format.Units != format.Decimal {
+		t.Error("ParseFlags changed format.Units; the caller applies the units")
+	}
+
+	os.Args = []string{"iperf-tool", "-s", "10.0.0.1", "-units", "metric"}
+	if _, err := ParseFlags(); err == nil {
+		t.Error("expected error for -units metric")
+	}
+}
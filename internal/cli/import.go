@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"iperf-tool/internal/export"
+	"iperf-tool/internal/iperf"
+	"iperf-tool/internal/model"
+)
+
+// ImportResult parses a saved iperf2 text output file named by cfg.Import
+// and writes it through the usual -o outputs. Neither iperf nor ping is run.
+func ImportResult(cfg RunnerConfig) (*model.TestResult, error) {
+	data, err := os.ReadFile(cfg.Import)
+	if err != nil {
+		return nil, fmt.Errorf("import: %w", err)
+	}
+
+	result, err := parseImported(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("import %s: %w", cfg.Import, err)
+	}
+
+	// The file's modification time is the best guess at when the test ran.
+	if info, err := os.Stat(cfg.Import); err == nil {
+		result.Timestamp = info.ModTime()
+	}
+	result.Mode = "CLI"
+	result.MeasurementID = export.NextMeasurementID(result.Timestamp)
+
+	saveResults(result, cfg)
+	return result, nil
+}
+
+// parseImported picks the parser from the iperf2 banner: a dualtest client
+// prints both banners, server-only output carries jitter and loss.
+func parseImported(text string) (*model.TestResult, error) {
+	client := strings.Contains(text, "Client connecting to")
+	server := strings.Contains(text, "Server listening on")
+	if client && server {
+		return iperf.ParseDualtestOutput(text)
+	}
+	return iperf.ParseOutput(text, server)
+}
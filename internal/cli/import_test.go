@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const importTCPOutput = `------------------------------------------------------------
+Client connecting to 10.0.0.1, TCP port 5001
+TCP window size: 85.0 KByte (default)
+------------------------------------------------------------
+[  1] local 10.0.0.2 port 52800 connected with 10.0.0.1 port 5001
+[  1]  0.00-1.00 sec  1.12 MBytes  9.44 Mbits/sec
+[  1]  1.00-2.00 sec  1.10 MBytes  9.23 Mbits/sec
+[  1]  0.00-2.00 sec  2.22 MBytes  9.33 Mbits/sec
+`
+
+func TestImportResult_WritesOutputs(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "client.txt")
+	if err := os.WriteFile(in, []byte(importTCPOutput), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := RunnerConfig{Import: in, OutputCSV: filepath.Join(dir, "out", "results")}
+	result, err := ImportResult(cfg)
+	if err != nil {
+		t.Fatalf("ImportResult() error = %v", err)
+	}
+	if result.ServerAddr != "10.0.0.1" || result.Port != 5001 || result.Protocol != "TCP" {
+		t.Errorf("header = %s:%d %s", result.ServerAddr, result.Port, result.Protocol)
+	}
+	if result.MeasurementID == "" {
+		t.Error("MeasurementID not set")
+	}
+	if len(result.Intervals) != 2 {
+		t.Errorf("Intervals = %d, want 2", len(result.Intervals))
+	}
+
+	log, err := os.ReadFile(filepath.Join(dir, "out", "results_log.csv"))
+	if err != nil {
+		t.Fatalf("CSV log not written: %v", err)
+	}
+	if !strings.Contains(string(log), "10.0.0.1") {
+		t.Errorf("CSV log missing server address:\n%s", log)
+	}
+	txts, _ := filepath.Glob(filepath.Join(dir, "out", "results*.txt"))
+	if len(txts) != 1 {
+		t.Fatalf("TXT files = %v, want one", txts)
+	}
+}
+
+const importDualtestOutput = `------------------------------------------------------------
+Client connecting to 10.0.0.1, TCP port 5001
+TCP window size: 85.0 KByte (default)
+------------------------------------------------------------
+[  1] local 10.0.0.2 port 52800 connected with 10.0.0.1 port 5001
+------------------------------------------------------------
+Server listening on TCP port 5001
+------------------------------------------------------------
+[  2] local 10.0.0.2 port 5001 connected with 10.0.0.1 port 52801
+[  1]  0.00-1.00 sec  11.2 MBytes  94.37 Mbits/sec
+[  2]  0.00-1.00 sec  10.5 MBytes  88.08 Mbits/sec
+[  1]  1.00-2.00 sec  11.5 MBytes  96.47 Mbits/sec
+[  2]  1.00-2.00 sec  10.8 MBytes  90.58 Mbits/sec
+[  1]  0.00-2.00 sec  22.7 MBytes  95.23 Mbits/sec
+[  2]  0.00-2.00 sec  21.3 MBytes  89.33 Mbits/sec
+`
+
+// An imported dualtest file must keep both directions' per-interval data so
+// the interval log matches a live bidir run.
+func TestImportResult_DualtestIntervals(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "dualtest.txt")
+	if err := os.WriteFile(in, []byte(importDualtestOutput), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ImportResult(RunnerConfig{Import: in, OutputCSV: filepath.Join(dir, "out", "results")})
+	if err != nil {
+		t.Fatalf("ImportResult() error = %v", err)
+	}
+	if len(result.Intervals) != 2 || len(result.ReverseIntervals) != 2 {
+		t.Fatalf("Intervals/ReverseIntervals = %d/%d, want 2/2", len(result.Intervals), len(result.ReverseIntervals))
+	}
+
+	logs, _ := filepath.Glob(filepath.Join(dir, "out", "results_*.csv"))
+	var intervalLog string
+	for _, l := range logs {
+		if !strings.HasSuffix(l, "_log.csv") {
+			intervalLog = l
+		}
+	}
+	data, err := os.ReadFile(intervalLog)
+	if err != nil {
+		t.Fatalf("interval log not written: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("interval log has %d lines, want header + 2:\n%s", len(lines), data)
+	}
+	header := strings.Split(lines[0], ";")
+	row := strings.Split(lines[1], ";")
+	for _, col := range []string{"fwd_bandwidth_mbps", "rev_bandwidth_mbps"} {
+		idx := -1
+		for i, h := range header {
+			if h == col {
+				idx = i
+			}
+		}
+		if idx < 0 || idx >= len(row) || row[idx] == "" {
+			t.Errorf("first interval row missing %s: %s", col, lines[1])
+		}
+	}
+}
+
+func TestImportResult_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := ImportResult(RunnerConfig{Import: filepath.Join(dir, "missing.txt")}); err == nil {
+		t.Error("expected error for missing file")
+	}
+
+	junk := filepath.Join(dir, "junk.txt")
+	os.WriteFile(junk, []byte("not iperf output\n"), 0644)
+	if _, err := ImportResult(RunnerConfig{Import: junk}); err == nil {
+		t.Error("expected error for unparseable file")
+	}
+}
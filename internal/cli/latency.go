@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"iperf-tool/internal/format"
+	"iperf-tool/internal/model"
+	"iperf-tool/internal/netutil"
+	"iperf-tool/internal/ping"
+)
+
+// Replaced in tests.
+var (
+	pingRun        = ping.Run
+	tcpConnectTime = netutil.TCPConnectTime
+)
+
+// baselineLatency is the latency measured before the iperf run starts.
+type baselineLatency struct {
+	icmp    *ping.Result      // ICMP baseline; nil when not run or blocked
+	tcp     *model.PingResult // TCP connect baseline; nil unless measured
+	skipped bool              // ICMP got no replies, so the loaded ping is skipped
+	method  string            // model.LatencyICMP, model.LatencyTCPConnect or ""
+}
+
+// pingCount returns the baseline packet count, defaulting to ping.DefaultCount.
+func (cfg RunnerConfig) pingCount() int {
+	if cfg.PingCount < 1 {
+		return ping.DefaultCount
+	}
+	return cfg.PingCount
+}
+
+// measureBaseline runs the baseline ping, or TCP connect timing when asked
+// for or when ICMP is filtered. No reply at all means ICMP is blocked, so the
+// loaded ping would only fail the same way. Only TCP servers accept the
+// handshake, so UDP tests get no TCP fallback.
+func measureBaseline(ctx context.Context, cfg RunnerConfig) baselineLatency {
+	var bl baselineLatency
+	n := cfg.pingCount()
+	out := cfg.Stdout()
+	if cfg.MeasurePing && !cfg.TCPPing {
+		fmt.Fprintf(out, "Running baseline ping (%d packets)...\n", n)
+		res, err := pingRun(ctx, cfg.ServerAddr, n)
+		if ping.Blocked(res, err) {
+			fmt.Fprintln(out, ping.BlockedNotice)
+			bl.skipped = true
+		} else {
+			bl.icmp = res
+			bl.method = model.LatencyICMP
+			fmt.Fprintf(out, "Baseline latency: %s\n", format.PingStats(res.ToModel()))
+		}
+	}
+
+	if (cfg.TCPPing || bl.skipped) && !strings.EqualFold(cfg.Protocol, "udp") {
+		fmt.Fprintf(out, "Measuring TCP connect time to port %d (%d samples)...\n", cfg.Port, n)
+		res, err := tcpConnectTime(cfg.ServerAddr, cfg.Port, n)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "TCP connect time failed: %v\n", err)
+		} else {
+			bl.tcp = res
+			bl.method = model.LatencyTCPConnect
+			fmt.Fprintf(out, "TCP connect time: min/avg/max = %.2f / %.2f / %.2f ms\n",
+				res.MinMs, res.AvgMs, res.MaxMs)
+		}
+	}
+	return bl
+}
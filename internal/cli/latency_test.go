@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"iperf-tool/internal/model"
+	"iperf-tool/internal/ping"
+)
+
+func stubLatency(t *testing.T, pingRes *ping.Result, pingErr error) (pings, samples *int) {
+	t.Helper()
+	origPing, origTCP := pingRun, tcpConnectTime
+	t.Cleanup(func() { pingRun, tcpConnectTime = origPing, origTCP })
+
+	pings, samples = new(int), new(int)
+	pingRun = func(_ context.Context, _ string, count int) (*ping.Result, error) {
+		*pings = count
+		return pingRes, pingErr
+	}
+	tcpConnectTime = func(_ string, _ int, n int) (*model.PingResult, error) {
+		*samples = n
+		return &model.PingResult{PacketsSent: n, PacketsRecv: n, AvgMs: 1}, nil
+	}
+	return pings, samples
+}
+
+func TestMeasureBaseline_PingCount(t *testing.T) {
+	pings, samples := stubLatency(t, &ping.Result{PacketsSent: 10, PacketsRecv: 10, AvgMs: 2}, nil)
+
+	cfg := RunnerConfig{ServerAddr: "10.0.0.1", Port: 5201, Protocol: "tcp", MeasurePing: true, PingCount: 10}
+	bl := measureBaseline(context.Background(), cfg)
+	if *pings != 10 {
+		t.Errorf("ping.Run count = %d, want 10", *pings)
+	}
+	if *samples != 0 {
+		t.Errorf("TCP connect ran with %d samples, want not run", *samples)
+	}
+	if bl.icmp == nil || bl.method != model.LatencyICMP {
+		t.Errorf("baseline = %+v, want ICMP result", bl)
+	}
+}
+
+func TestMeasureBaseline_DefaultCount(t *testing.T) {
+	pings, _ := stubLatency(t, &ping.Result{PacketsSent: 4, PacketsRecv: 4}, nil)
+
+	measureBaseline(context.Background(), RunnerConfig{ServerAddr: "10.0.0.1", MeasurePing: true})
+	if *pings != ping.DefaultCount {
+		t.Errorf("ping.Run count = %d, want %d", *pings, ping.DefaultCount)
+	}
+}
+
+func TestMeasureBaseline_BlockedFallsBackWithCount(t *testing.T) {
+	_, samples := stubLatency(t, nil, errors.New("timeout"))
+
+	cfg := RunnerConfig{ServerAddr: "10.0.0.1", Port: 5201, Protocol: "tcp", MeasurePing: true, PingCount: 6}
+	bl := measureBaseline(context.Background(), cfg)
+	if !bl.skipped {
+		t.Error("skipped = false, want true")
+	}
+	if *samples != 6 {
+		t.Errorf("TCP connect samples = %d, want 6", *samples)
+	}
+	if bl.tcp == nil || bl.method != model.LatencyTCPConnect {
+		t.Errorf("baseline = %+v, want TCP connect result", bl)
+	}
+}
+
+func TestMeasureBaseline_BlockedUDPNoFallback(t *testing.T) {
+	_, samples := stubLatency(t, &ping.Result{PacketsSent: 4}, nil)
+
+	cfg := RunnerConfig{ServerAddr: "10.0.0.1", Protocol: "udp", MeasurePing: true, PingCount: 4}
+	bl := measureBaseline(context.Background(), cfg)
+	if !bl.skipped || bl.tcp != nil || bl.method != "" {
+		t.Errorf("baseline = %+v, want skipped without fallback", bl)
+	}
+	if *samples != 0 {
+		t.Errorf("TCP connect ran for UDP test")
+	}
+}
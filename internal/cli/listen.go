@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+// RunLocalServer runs an iperf2 server on this machine (listen mode) and
+// streams its output until Ctrl-C. With Daemon set, iperf2 detaches (-D) and
+// this returns once it has started.
+func RunLocalServer(cfg RunnerConfig) error {
+	iperfCfg := cfg.iperfConfig()
+	if err := iperfCfg.ValidateListen(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	runner := cfg.newRunner()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Printf("Starting local server: %s\n", iperfCfg.ListenCommand(cfg.Daemon))
+	if !cfg.Daemon {
+		fmt.Println("Press Ctrl-C to stop.")
+	}
+	err := runner.RunServer(ctx, iperfCfg, cfg.Daemon, func(line string) {
+		fmt.Println(line)
+	})
+	if err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		fmt.Println("\nLocal server stopped.")
+	}
+	return nil
+}
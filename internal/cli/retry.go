@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+// defaultRetryBackoff is the first retry delay when MaxRetries is set but
+// RetryBackoff is not.
+const defaultRetryBackoff = time.Second
+
+// retrySleep waits for d or until ctx is done. Replaced in tests.
+var retrySleep = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// isRetryable reports whether err is worth retrying: the server refused the
+// connection (not up yet) or is busy with another client.
+func isRetryable(err error) bool {
+	switch model.ClassifyErrorString(err.Error()) {
+	case model.ErrorConnectionRefused, model.ErrorServerBusy:
+		return true
+	}
+	return false
+}
+
+// runWithRetry calls run until it succeeds, fails with a non-retryable error,
+// or maxRetries retries are used up. The delay doubles after every failed
+// attempt, starting at backoff. Returns the number of attempts made.
+func runWithRetry(ctx context.Context, maxRetries int, backoff time.Duration, run func() (*model.TestResult, error)) (*model.TestResult, int, error) {
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	delay := backoff
+	for attempt := 1; ; attempt++ {
+		result, err := run()
+		if err == nil {
+			return result, attempt, nil
+		}
+		if attempt > maxRetries || !isRetryable(err) {
+			return nil, attempt, err
+		}
+		fmt.Fprintf(os.Stderr, "Attempt %d failed (%s): %v — retrying in %v\n",
+			attempt, model.ClassifyErrorString(err.Error()), err, delay)
+		if serr := retrySleep(ctx, delay); serr != nil {
+			return nil, attempt, err
+		}
+		delay *= 2
+	}
+}
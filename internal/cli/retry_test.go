@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+// stubRun returns a run func that fails with failErr for the first failures
+// calls and succeeds afterwards.
+func stubRun(failures int, failErr error) (func() (*model.TestResult, error), *int) {
+	calls := 0
+	return func() (*model.TestResult, error) {
+		calls++
+		if calls <= failures {
+			return nil, failErr
+		}
+		return &model.TestResult{ServerAddr: "10.0.0.1"}, nil
+	}, &calls
+}
+
+// recordSleeps replaces retrySleep for the duration of the test.
+func recordSleeps(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var sleeps []time.Duration
+	orig := retrySleep
+	retrySleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	t.Cleanup(func() { retrySleep = orig })
+	return &sleeps
+}
+
+func TestRunWithRetry_SucceedsAfterFailures(t *testing.T) {
+	sleeps := recordSleeps(t)
+	run, calls := stubRun(2, errors.New("iperf failed: connect failed: Connection refused"))
+
+	result, attempts, err := runWithRetry(context.Background(), 3, 100*time.Millisecond, run)
+	if err != nil {
+		t.Fatalf("runWithRetry() error: %v", err)
+	}
+	if result == nil || attempts != 3 || *calls != 3 {
+		t.Errorf("attempts = %d, calls = %d, want 3", attempts, *calls)
+	}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}
+	if len(*sleeps) != len(want) || (*sleeps)[0] != want[0] || (*sleeps)[1] != want[1] {
+		t.Errorf("backoff delays = %v, want %v", *sleeps, want)
+	}
+}
+
+func TestRunWithRetry_GivesUp(t *testing.T) {
+	recordSleeps(t)
+	run, calls := stubRun(10, errors.New("the server is busy running a test"))
+
+	_, attempts, err := runWithRetry(context.Background(), 2, time.Millisecond, run)
+	if err == nil {
+		t.Fatal("expected error after retries exhausted")
+	}
+	if attempts != 3 || *calls != 3 {
+		t.Errorf("attempts = %d, calls = %d, want 3 (1 + 2 retries)", attempts, *calls)
+	}
+}
+
+func TestRunWithRetry_NonRetryable(t *testing.T) {
+	sleeps := recordSleeps(t)
+	run, calls := stubRun(5, errors.New("parse output: no summary line"))
+
+	_, attempts, err := runWithRetry(context.Background(), 3, time.Millisecond, run)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 1 || *calls != 1 || len(*sleeps) != 0 {
+		t.Errorf("non-retryable error should not retry: attempts=%d sleeps=%v", attempts, *sleeps)
+	}
+}
+
+func TestRunWithRetry_NoRetriesConfigured(t *testing.T) {
+	recordSleeps(t)
+	run, calls := stubRun(1, errors.New("Connection refused"))
+
+	if _, attempts, err := runWithRetry(context.Background(), 0, 0, run); err == nil || attempts != 1 || *calls != 1 {
+		t.Errorf("MaxRetries=0 should make a single attempt: attempts=%d err=%v", attempts, err)
+	}
+}
+
+func TestRunWithRetry_ContextCancelledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	run, calls := stubRun(5, errors.New("Connection refused"))
+
+	_, _, err := runWithRetry(ctx, 5, time.Hour, run)
+	if err == nil || *calls != 1 {
+		t.Errorf("cancelled context should stop retrying: calls=%d err=%v", *calls, err)
+	}
+}
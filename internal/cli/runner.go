@@ -3,10 +3,14 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
+	"golang.org/x/term"
+
 	"iperf-tool/internal/export"
 	"iperf-tool/internal/format"
 	"iperf-tool/internal/iperf"
@@ -20,76 +24,405 @@ import (
 type RunnerConfig struct {
 	// Local test
 	ServerAddr  string
+	ServerAddrs []string // all servers to test in turn; ServerAddr is the first
+	Concurrent  bool     // test all ServerAddrs at the same time instead of in turn
 	Port        int
 	Parallel    int
 	Duration    int
-	Interval    int
+	BytesLimit  string
+	BlocksLimit int
+	Interval    float64 // reporting interval in seconds; fractional values such as 0.5 are allowed
 	Protocol    string
 	BinaryPath  string
 	BlockSize   int
 	MeasurePing bool
+	TCPPing     bool // measure baseline latency as TCP connect time instead of ICMP
+	PingCount   int  // baseline ping packets / TCP connect samples (default 4)
 	Reverse     bool
 	Bidir       bool
+	BidirMode   string // "auto", "dualtest" or "split" (-mode); empty = auto
 	Bandwidth   string
 	IPv6        bool
+	AddrFamily  string // "", "4" or "6"
+	OmitSeconds int
+	DSCP        string // ToS byte (0-255) or DSCP keyword ("ef", "af41")
+	WindowSize  string // -w socket buffer / TCP window (e.g. "256K")
+	BindAddr    string // -B local IP the client binds to
+	NoDelay     bool   // -N TCP_NODELAY on the sender
+	MSS         int    // -M TCP MSS clamp in bytes; 0 = OS default
+	ConnTimeout int    // --connect-timeout in ms for TCP clients; 0 = iperf2 default
+	Label       string // free-text tag stored on each result (-label)
+	SteadyTrim  int    // intervals dropped from each end for the steady-state figure
+	LinkMbps    int    // link rate hint for the clock-skew check; 0 = use the probed interface speed
+	ExtraArgs   []string
+
+	// RequireVersion — fail instead of running with a local iperf2 older
+	// than iperf.MinVersion (-require-version)
+	RequireVersion bool
+	// IntervalCap — interval rows kept per direction on the result, merged
+	// from neighbours as they arrive so a run of any length holds no more;
+	// with -o the interval CSV is then written while the test runs
+	// (-interval-cap)
+	IntervalCap int
 
 	// Remote server (optional)
-	SSHHost      string
-	SSHUser      string
-	SSHKeyPath   string
-	SSHPassword  string
-	SSHPort      int
-	StartServer  bool
-	StopServer   bool
-	InstallIperf bool
+	SSHHost        string
+	SSHUser        string
+	SSHKeyPath     string
+	SSHPassword    string
+	SSHPort        int
+	SSHJump        string // ProxyJump spec "[user@]bastion[:port]"; empty = ~/.ssh/config
+	RemoteBinary   string // iperf2 path on the remote host; empty = "iperf" on PATH
+	StartServer    bool
+	StopServer     bool
+	InstallIperf   bool
+	NoServerOutput bool // don't log the SSH-started server to a file; use the Server Report only
+
+	// Local server (listen mode)
+	Listen bool // run an iperf2 server on this machine instead of a test
+	Daemon bool // with Listen: detach the server (iperf2 -D)
 
 	// Repeat
 	Repeat      bool // loop until Ctrl-C or RepeatCount exhausted
 	RepeatCount int  // 0 = infinite; N > 0 = run exactly N times
+	Average     int  // report the mean of N back-to-back runs as one result (-average); 0 = off
+
+	// Schedule
+	Every    time.Duration // run at wall-clock ticks of this period (-every); 0 = off
+	EveryFor time.Duration // stop scheduling this long after start (-for); 0 = no limit
+
+	// Bitrate sweep
+	Sweep         []string // UDP target rates to step through (-sweep)
+	LossThreshold float64  // sweep stops once loss exceeds this percentage
+
+	// Retry
+	MaxRetries   int           // retries on connection-refused/server-busy; 0 = none
+	RetryBackoff time.Duration // first retry delay, doubled each retry (default 1s)
 
 	// Output
-	OutputCSV string
-	Verbose   bool
-	Debug     bool
+	OutputCSV   string
+	SaveJSON    bool   // also write a dated .json file next to the CSV/TXT output
+	SaveMD      bool   // also append to a dated Markdown report next to the CSV/TXT output
+	SaveHTML    bool   // also write a self-contained HTML report per measurement
+	PromPath    string // Prometheus textfile-collector output, rewritten after each repeat run
+	InfluxPath  string // InfluxDB line-protocol file, one point appended per result
+	InfluxURL   string // InfluxDB write endpoint each result is POSTed to
+	DBPath      string // SQLite history database each result is inserted into
+	CSVSep      string // CSV field separator: ",", ";" or "tab" (default ";")
+	SkipOmitted bool   // leave omitted warm-up intervals out of the interval log
+	Detailed    bool   // add Retr/s and cumulative retransmits to the TXT report
+	ErrorJSON   bool   // report a failed run as one JSON object on stdout (-error-json)
+	Verbose     bool
+	Quiet       bool   // only the one-line summary on stdout (-q)
+	Color       string // result coloring: "auto" (TTY only), "always" or "never"
+	Units       string // display units: "decimal" (MB, Mbps) or "binary" (MiB, Mibps)
+	Debug       bool
+	DebugLog    string // raw iperf2 output log path; empty = iperf.DebugLogPath
+	DryRun      bool   // print the iperf2 command lines instead of running them
+	Import      string // format an existing iperf2 output file instead of testing
+	StreamJSON  string // JSON Lines interval stream: file path or "-" for stdout
+
+	// IncludeServerOutput appends the raw server-side output to the TXT report.
+	IncludeServerOutput bool
+
+	// NoSaveTXT, NoSaveIntervals and NoSaveSummary drop the dated TXT report,
+	// the per-interval CSV and the _log.csv summary row from -o output.
+	NoSaveTXT       bool
+	NoSaveIntervals bool
+	NoSaveSummary   bool
 
 	// SSH client — set after Connect(), used by Reverse/Bidir/Forward with SSH
-	SSHClient iperf.SSHClient
+	SSHClient iperf.SSHClient `json:"-"`
 	// IsWindows — set after Connect() if remote is Windows
-	IsWindows bool
+	IsWindows bool `json:"-"`
+	// RemoteIperfVersion — set after Connect() from CheckRemoteVersion
+	RemoteIperfVersion string `json:"-"`
 	// LocalAddr — local IP for reverse/bidir (remote client connects back here)
-	LocalAddr string
+	LocalAddr string `json:"-"`
+	// Compare — two saved result files to diff instead of testing (-compare)
+	Compare []string `json:"-"`
+	// IntervalLog — fixed interval CSV path shared by repeat runs; empty = dated per run
+	IntervalLog string `json:"-"`
+	// EveryUntil — stop scheduling after this time (-until); zero = no limit
+	EveryUntil time.Time `json:"-"`
+	// MTUCheck — path MTU to report UDP fragmentation for instead of testing (-mtu-check)
+	MTUCheck int `json:"-"`
+	// Doctor — print an environment readiness report instead of testing (-doctor)
+	Doctor bool `json:"-"`
+	// Progress — where progress output goes; nil = stdout. RunConcurrent
+	// buffers it per server.
+	Progress io.Writer `json:"-"`
+}
+
+// PinIntervalLog makes every following run append its intervals to the
+// interval CSV dated by start, so a repeat session spanning midnight still
+// writes one continuous log. No-op without -o.
+func (c *RunnerConfig) PinIntervalLog(start time.Time) {
+	if c.OutputCSV == "" {
+		return
+	}
+	c.IntervalLog = export.BuildPath(strings.TrimSuffix(c.OutputCSV, ".csv"), "", ".csv", start)
+}
+
+// runGracePeriod is added to the test duration to form the hard deadline for
+// one run: it covers pings, SSH server restarts, probes and the final report.
+const runGracePeriod = 60 * time.Second
+
+// runDeadline returns the hard time limit for a test of the given duration.
+func runDeadline(durationSec int) time.Duration {
+	return time.Duration(durationSec)*time.Second + runGracePeriod
+}
+
+// openIntervalStream opens the -stream-json destination: "-" is stdout, a
+// path is appended to so repeat runs accumulate. Empty path returns a nil
+// stream. The returned close func is always safe to call.
+func openIntervalStream(path string) (*export.IntervalStream, func(), error) {
+	switch path {
+	case "":
+		return nil, func() {}, nil
+	case "-":
+		return export.NewIntervalStream(os.Stdout), func() {}, nil
+	}
+	if err := export.EnsureDir(path); err != nil {
+		return nil, nil, fmt.Errorf("stream json: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("stream json: %w", err)
+	}
+	return export.NewIntervalStream(f), func() { f.Close() }, nil
+}
+
+// openLiveIntervalLog opens the interval CSV for appending rows while the
+// test runs when -interval-cap is set with -o, so the full-resolution log
+// does not depend on the capped result. Returns a nil writer otherwise.
+func openLiveIntervalLog(cfg RunnerConfig, iperfCfg iperf.Config, start time.Time) (*export.IntervalLogWriter, string, error) {
+	if cfg.IntervalCap <= 0 || cfg.OutputCSV == "" || cfg.NoSaveIntervals {
+		return nil, "", nil
+	}
+	delim, err := cfg.csvDelimiter()
+	if err != nil {
+		return nil, "", err
+	}
+	path := cfg.intervalLogPath(start)
+	if err := export.EnsureDir(path); err != nil {
+		return nil, "", fmt.Errorf("interval log: %w", err)
+	}
+	head := model.TestResult{Timestamp: start, MeasurementID: export.NextMeasurementID(start)}
+	iperfCfg.ApplyToResult(&head, "CLI")
+	w, err := export.OpenIntervalLogWriter(path, head, export.IntervalLogOptions{Delimiter: delim, SkipOmitted: cfg.SkipOmitted})
+	if err != nil {
+		return nil, "", err
+	}
+	return w, head.MeasurementID, nil
+}
+
+// csvDelimiter returns the -csv-sep field separator, DefaultDelimiter when unset.
+func (cfg RunnerConfig) csvDelimiter() (rune, error) {
+	if cfg.CSVSep == "" {
+		return export.DefaultDelimiter, nil
+	}
+	return export.ParseDelimiter(cfg.CSVSep)
+}
+
+// intervalLogPath returns the interval CSV for a run on date: the pinned
+// IntervalLog of a repeat session, or the -o path dated by the run.
+func (cfg RunnerConfig) intervalLogPath(date time.Time) string {
+	if cfg.IntervalLog != "" {
+		return cfg.IntervalLog
+	}
+	return export.BuildPath(strings.TrimSuffix(cfg.OutputCSV, ".csv"), "", ".csv", date)
+}
+
+// newRunner returns a runner that logs raw output to DebugLog when Debug is set.
+func (cfg RunnerConfig) newRunner() *iperf.Runner {
+	if cfg.Debug {
+		return iperf.NewDebugRunnerWithPath(cfg.DebugLog)
+	}
+	return iperf.NewRunner()
+}
+
+// UnitSystem returns the display units selected by -units; an unset or
+// unknown value is Decimal.
+func (cfg RunnerConfig) UnitSystem() format.UnitSystem {
+	units, err := format.ParseUnitSystem(cfg.Units)
+	if err != nil {
+		return format.Decimal
+	}
+	return units
+}
+
+// iperfConfig maps the CLI options onto the iperf runner configuration.
+func (cfg RunnerConfig) iperfConfig() iperf.Config {
+	return iperf.Config{
+		BinaryPath:  cfg.BinaryPath,
+		ServerAddr:  cfg.ServerAddr,
+		Port:        cfg.Port,
+		Parallel:    cfg.Parallel,
+		Duration:    cfg.Duration,
+		BytesLimit:  cfg.BytesLimit,
+		BlocksLimit: cfg.BlocksLimit,
+		IntervalSec: cfg.Interval,
+		Protocol:    cfg.Protocol,
+		BlockSize:   cfg.BlockSize,
+		Reverse:     cfg.Reverse,
+		Bidir:       cfg.Bidir,
+		BidirMode:   cfg.BidirMode,
+		Bandwidth:   cfg.Bandwidth,
+		IPv6:        cfg.IPv6,
+		AddrFamily:  cfg.AddrFamily,
+		IsWindows:   cfg.IsWindows,
+		LocalAddr:   cfg.LocalAddr,
+		Enhanced:    true,
+		OmitSeconds: cfg.OmitSeconds,
+		DSCP:        cfg.DSCP,
+		WindowSize:  cfg.WindowSize,
+		BindAddr:    cfg.BindAddr,
+		NoDelay:     cfg.NoDelay,
+		MSS:         cfg.MSS,
+		Label:       cfg.Label,
+		ExtraArgs:   cfg.ExtraArgs,
+		PingCount:   cfg.PingCount,
+
+		ConnectTimeoutMs: cfg.ConnTimeout,
+		NoServerOutput:   cfg.NoServerOutput,
+		KeepServerOutput: cfg.IncludeServerOutput,
+		RequireVersion:   cfg.RequireVersion,
+		StreamIntervals:  cfg.IntervalCap > 0,
+	}
+}
+
+// DryRun validates cfg and writes the iperf2 command lines a run would start
+// to w, one per line, without executing them.
+func DryRun(cfg RunnerConfig, w io.Writer) error {
+	iperfCfg := cfg.iperfConfig()
+	if cfg.Listen {
+		if err := iperfCfg.ValidateListen(); err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+		fmt.Fprintln(w, "local: "+iperfCfg.ListenCommand(cfg.Daemon))
+		return nil
+	}
+	if cfg.SSHHost != "" && iperfCfg.LocalAddr == "" {
+		iperfCfg.LocalAddr = "<local-addr>" // learned from the SSH connection at run time
+	}
+	cmds, err := iperf.NewRunner().BuildCommands(iperfCfg, cfg.SSHHost != "")
+	if err != nil {
+		return err
+	}
+	for _, c := range cmds {
+		fmt.Fprintln(w, c.String())
+	}
+	return nil
+}
+
+// ScheduleDeadline returns when an -every schedule started at start ends:
+// start + EveryFor, EveryUntil, or zero for no end.
+func (c RunnerConfig) ScheduleDeadline(start time.Time) time.Time {
+	if c.EveryFor > 0 {
+		return start.Add(c.EveryFor)
+	}
+	return c.EveryUntil
+}
+
+// MTUCheck writes how the configured UDP datagram size (-l) is split into
+// IP packets on a path with cfg.MTUCheck bytes of MTU, without running
+// anything.
+func MTUCheck(cfg RunnerConfig, w io.Writer) error {
+	iperfCfg, mtu := cfg.iperfConfig(), cfg.MTUCheck
+	if err := iperfCfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	if iperfCfg.Protocol != "udp" {
+		fmt.Fprintln(w, "TCP: iperf2 segments the stream to the MSS, so -l never causes IP fragmentation")
+		return nil
+	}
+	size, note := iperfCfg.BlockSize, ""
+	if size == 0 {
+		size, note = iperf.DefaultUDPBlockSize, " (iperf2 default)"
+	}
+	ipv6 := iperfCfg.IPv6 || iperfCfg.AddrFamily == "6"
+	family := "IPv4"
+	if ipv6 {
+		family = "IPv6"
+	}
+	fmt.Fprintf(w, "Datagram size:     %d bytes%s\n", size, note)
+	fmt.Fprintf(w, "Path MTU:          %d bytes (%s)\n", mtu, family)
+	fmt.Fprintf(w, "Max unfragmented:  %d bytes\n", iperf.MaxUnfragmentedDatagram(mtu, ipv6))
+	if n := iperf.UDPFragments(size, mtu, ipv6); n > 1 {
+		fmt.Fprintf(w, "Fragmentation:     %d IP packets per datagram; losing any one drops the datagram\n", n)
+	} else {
+		fmt.Fprintln(w, "Fragmentation:     none")
+	}
+	return nil
 }
 
+// probeTimeout bounds the preflight dial to the iperf2 server.
+const probeTimeout = 2 * time.Second
+
+// Stdout is where progress output goes; quiet mode discards it.
+func (cfg RunnerConfig) Stdout() io.Writer {
+	if cfg.Quiet {
+		return io.Discard
+	}
+	if cfg.Progress != nil {
+		return cfg.Progress
+	}
+	return os.Stdout
+}
+
+// localVersion looks up the local iperf2 version; replaced in tests.
+var localVersion = iperf.CheckVersion
+
+// probePort is the preflight dial to the iperf2 server; replaced in tests.
+var probePort = netutil.ProbePort
+
+// adviseParallelOnce limits the CPU-limit warning to once per process, so
+// repeat runs don't print it every time.
+var adviseParallelOnce sync.Once
+
+// adviseDatagramOnce does the same for the UDP fragmentation warning.
+var adviseDatagramOnce sync.Once
+
 // LocalTestRunner runs a single iperf2 test locally and optionally saves results.
 func LocalTestRunner(cfg RunnerConfig) (*model.TestResult, error) {
-	iperfCfg := iperf.Config{
-		BinaryPath: cfg.BinaryPath,
-		ServerAddr: cfg.ServerAddr,
-		Port:       cfg.Port,
-		Parallel:   cfg.Parallel,
-		Duration:   cfg.Duration,
-		Interval:   cfg.Interval,
-		Protocol:   cfg.Protocol,
-		BlockSize:  cfg.BlockSize,
-		Reverse:    cfg.Reverse,
-		Bidir:      cfg.Bidir,
-		Bandwidth:  cfg.Bandwidth,
-		IPv6:       cfg.IPv6,
-		IsWindows:  cfg.IsWindows,
-		LocalAddr:  cfg.LocalAddr,
-		Enhanced:   true,
-	}
+	iperfCfg := cfg.iperfConfig()
+	out := cfg.Stdout()
 
 	if err := iperfCfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
+	dualtest, err := iperfCfg.UseDualtest(cfg.SSHClient != nil)
+	if iperfCfg.Bidir && err != nil {
+		return nil, err
+	}
+	version, err := localVersion(iperfCfg.BinaryPath)
+	if iperfCfg.RequireVersion {
+		if err != nil {
+			return nil, fmt.Errorf("-require-version: %w", err)
+		}
+		if err := iperf.RequireMinVersion(version); err != nil {
+			return nil, err
+		}
+	}
 
-	var runner *iperf.Runner
-	if cfg.Debug {
-		runner = iperf.NewDebugRunner()
-	} else {
-		runner = iperf.NewRunner()
+	stream, closeStream, err := openIntervalStream(cfg.StreamJSON)
+	if err != nil {
+		return nil, err
 	}
+	defer closeStream()
+
+	ivLog, measurementID, err := openLiveIntervalLog(cfg, iperfCfg, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if ivLog != nil {
+			ivLog.Close()
+		}
+	}()
+
+	runner := cfg.newRunner()
 	ctx := context.Background()
 
 	dirLabel := ""
@@ -98,34 +431,37 @@ func LocalTestRunner(cfg RunnerConfig) (*model.TestResult, error) {
 	} else if cfg.Bidir {
 		dirLabel = ", bidirectional"
 	}
-	fmt.Printf("Starting test: %s:%d (%s, %d parallel, %ds duration%s)\n",
-		cfg.ServerAddr, cfg.Port, strings.ToUpper(cfg.Protocol), cfg.Parallel, cfg.Duration, dirLabel)
+	limit := fmt.Sprintf("%ds duration", cfg.Duration)
+	if cfg.BytesLimit != "" {
+		limit = cfg.BytesLimit + " of data"
+	} else if cfg.BlocksLimit > 0 {
+		limit = fmt.Sprintf("%d blocks", cfg.BlocksLimit)
+	}
+	fmt.Fprintf(out, "Starting test: %s:%d (%s, %d parallel, %s%s)\n",
+		cfg.ServerAddr, cfg.Port, strings.ToUpper(cfg.Protocol), cfg.Parallel, limit, dirLabel)
 
-	// Phase 1: baseline ping (before iperf)
-	var baseline *ping.Result
-	if cfg.MeasurePing {
-		fmt.Println("Running baseline ping (4 packets)...")
-		var err error
-		baseline, err = ping.Run(ctx, cfg.ServerAddr, 4)
-		if err != nil {
-			fmt.Printf("Baseline ping failed: %v\n", err)
-		} else {
-			fmt.Printf("Baseline latency: min/avg/max = %.2f / %.2f / %.2f ms\n",
-				baseline.MinMs, baseline.AvgMs, baseline.MaxMs)
-		}
+	cpuAdvice, cpuLimited := iperf.AdviseParallel(cfg.Parallel)
+	if cpuLimited {
+		adviseParallelOnce.Do(func() { fmt.Fprintln(os.Stderr, cpuAdvice) })
+	}
+	if msg, ok := iperfCfg.AdviseDatagram(); ok {
+		adviseDatagramOnce.Do(func() { fmt.Fprintln(os.Stderr, msg) })
 	}
 
+	// Phase 1: baseline latency (before iperf)
+	bl := measureBaseline(ctx, cfg)
+
 	// Phase 2: start background ping (during iperf)
 	var loadedCh chan *ping.Result
 	var pingCancel context.CancelFunc
-	if cfg.MeasurePing {
+	if cfg.MeasurePing && !cfg.TCPPing && !bl.skipped {
 		var pingCtx context.Context
 		pingCtx, pingCancel = context.WithCancel(ctx)
 		loadedCh = make(chan *ping.Result, 1)
 		go func() {
 			loaded, err := ping.RunUntilCancel(pingCtx, cfg.ServerAddr)
 			if err != nil {
-				fmt.Printf("Under-load ping failed: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Under-load ping failed: %v\n", err)
 				loadedCh <- nil
 			} else {
 				loadedCh <- loaded
@@ -135,31 +471,63 @@ func LocalTestRunner(cfg RunnerConfig) (*model.TestResult, error) {
 
 	// Print interval header
 	isUDP := strings.EqualFold(iperfCfg.Protocol, "udp")
+	tsLayout := format.IntervalTimeLayout(iperfCfg.IntervalSeconds())
+	timeCol := format.IntervalTimeColumn(tsLayout)
 	if iperfCfg.Bidir {
-		header := "Time      " + format.FormatBidirIntervalHeader(isUDP)
-		fmt.Println(header)
-		fmt.Println(strings.Repeat("-", len(header)))
+		header := timeCol + format.FormatBidirIntervalHeader(isUDP)
+		fmt.Fprintln(out, header)
+		fmt.Fprintln(out, strings.Repeat("-", len(header)))
 	} else {
-		header := "Time      " + format.FormatIntervalHeader(isUDP)
-		fmt.Println(header)
-		fmt.Println(strings.Repeat("-", len(header)))
+		header := timeCol + format.FormatIntervalHeader(isUDP)
+		fmt.Fprintln(out, header)
+		fmt.Fprintln(out, strings.Repeat("-", len(header)))
 	}
 
 	testStart := time.Now()
 
+	// With -interval-cap the live intervals feed these keepers and the
+	// runner leaves them out of the client output, so memory stays bounded.
+	var fwdKeep, revKeep *model.IntervalKeeper
+	streamFailed := false
 	emit := func(fwd, rev *model.IntervalResult) {
 		if fwd == nil && rev == nil {
 			return
 		}
+		if fwdKeep != nil {
+			if fwd != nil {
+				fwdKeep.Add(*fwd)
+			}
+			if rev != nil {
+				revKeep.Add(*rev)
+			}
+		}
+		if ivLog != nil {
+			if err := ivLog.Write(fwd, rev); err != nil {
+				fmt.Fprintf(os.Stderr, "Save interval log error: %v\n", err)
+				ivLog.Close()
+				ivLog = nil
+			}
+		}
+		if stream != nil && !streamFailed {
+			// Reverse runs report their only direction in the fwd slot.
+			sf, sr := fwd, rev
+			if iperfCfg.Reverse {
+				sf, sr = nil, fwd
+			}
+			if err := stream.Write(sf, sr); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				streamFailed = true
+			}
+		}
 		ref := fwd
 		if ref == nil {
 			ref = rev
 		}
-		ts := testStart.Add(time.Duration(ref.TimeStart * float64(time.Second))).Format("15:04:05")
+		ts := testStart.Add(time.Duration(ref.TimeStart * float64(time.Second))).Format(tsLayout)
 		if iperfCfg.Bidir {
-			fmt.Println(ts + "  " + format.FormatBidirInterval(fwd, rev, isUDP))
+			fmt.Fprintln(out, ts+"  "+format.FormatBidirInterval(fwd, rev, isUDP))
 		} else if fwd != nil {
-			fmt.Println(ts + "  " + format.FormatInterval(fwd, isUDP))
+			fmt.Fprintln(out, ts+"  "+format.FormatInterval(fwd, isUDP))
 		}
 	}
 
@@ -172,22 +540,45 @@ func LocalTestRunner(cfg RunnerConfig) (*model.TestResult, error) {
 	}
 
 	// Run iperf test — dispatch based on direction
-	var result *model.TestResult
-	var err error
+	runOnce := func() (*model.TestResult, error) {
+		// Hard deadline so a wedged iperf process cannot hang the CLI forever;
+		// on expiry the process is sent SIGTERM and its partial output is kept.
+		// A -n/-k run has no known length, so only Ctrl-C stops it.
+		var runCtx context.Context
+		var cancel context.CancelFunc
+		if iperfCfg.DataLimited() {
+			runCtx, cancel = context.WithCancel(ctx)
+		} else {
+			runCtx, cancel = context.WithTimeout(ctx, runDeadline(cfg.Duration))
+		}
+		defer cancel()
 
-	version, _ := iperf.CheckVersion(iperfCfg.BinaryPath)
+		if cfg.IntervalCap > 0 { // a retry starts over
+			fwdKeep, revKeep = model.NewIntervalKeeper(cfg.IntervalCap), model.NewIntervalKeeper(cfg.IntervalCap)
+		}
 
-	if iperfCfg.Bidir {
-		if cfg.SSHClient == nil {
-			result, err = runner.RunBidirDualtest(ctx, iperfCfg, onInterval)
-		} else {
-			result, err = runner.RunBidir(ctx, iperfCfg, cfg.SSHClient, onInterval)
+		// Fail fast when nothing listens on the server port. UDP servers have
+		// no TCP listener, reverse runs dial back to us and with SSH the
+		// runner starts the server itself, so those skip the probe. A refused
+		// port is retried under -retries like a refused iperf2 connect.
+		if cfg.SSHClient == nil && !isUDP && !cfg.Reverse {
+			if err := probePort(cfg.ServerAddr, cfg.Port, probeTimeout); err != nil {
+				return nil, err
+			}
 		}
-	} else if iperfCfg.Reverse {
-		result, err = runner.RunReverse(ctx, iperfCfg, cfg.SSHClient, onInterval)
-	} else {
-		result, err = runner.RunForward(ctx, iperfCfg, cfg.SSHClient, onInterval)
+
+		if iperfCfg.Bidir {
+			if dualtest {
+				return runner.RunBidirDualtest(runCtx, iperfCfg, onInterval)
+			}
+			return runner.RunBidir(runCtx, iperfCfg, cfg.SSHClient, onInterval)
+		} else if iperfCfg.Reverse {
+			return runner.RunReverse(runCtx, iperfCfg, cfg.SSHClient, onInterval)
+		}
+		return runner.RunForward(runCtx, iperfCfg, cfg.SSHClient, onInterval)
 	}
+	started := time.Now()
+	result, attempts, err := runWithRetry(ctx, cfg.MaxRetries, cfg.RetryBackoff, runOnce)
 
 	if flushIntervals != nil {
 		flushIntervals()
@@ -198,7 +589,7 @@ func LocalTestRunner(cfg RunnerConfig) (*model.TestResult, error) {
 	if cfg.MeasurePing && pingCancel != nil {
 		pingCancel()
 		loaded := <-loadedCh
-		pingBaseline = baseline.ToModel()
+		pingBaseline = bl.icmp.ToModel()
 		pingLoaded = loaded.ToModel()
 	}
 
@@ -208,24 +599,118 @@ func LocalTestRunner(cfg RunnerConfig) (*model.TestResult, error) {
 
 	result.PingBaseline = pingBaseline
 	result.PingLoaded = pingLoaded
+	result.PingSkipped = bl.skipped
+	if bl.tcp != nil {
+		result.PingBaseline = bl.tcp
+	}
+	result.LatencyMethod = bl.method
+	result.Attempts = attempts
+	if attempts > 1 {
+		result.StartedAt = started // wall clock includes the failed attempts
+	}
 
 	// Set config echo fields on the result.
 	iperfCfg.ApplyToResult(result, "CLI")
+	netutil.RecordServer(result, cfg.ServerAddr)
+	result.SteadyTrim = cfg.SteadyTrim
+	result.CPULimitedWarning = cpuLimited
 	result.IperfVersion = version
 	if h, herr := os.Hostname(); herr == nil {
 		result.LocalHostname = h
 	}
 	result.LocalIP = netutil.OutboundIP()
+	if cfg.BindAddr != "" {
+		result.LocalIP = cfg.BindAddr // the test was pinned to this address
+	}
+	netutil.RecordInterface(result)
+	if cfg.LinkMbps > 0 {
+		result.LocalLinkMbps = cfg.LinkMbps
+	}
 	if cfg.SSHHost != "" {
 		result.SSHRemoteHost = cfg.SSHHost
+		result.RemoteIperfVersion = cfg.RemoteIperfVersion
+	}
+	result.MeasurementID = measurementID
+	if measurementID != "" {
+		cfg.NoSaveIntervals = true // the interval rows were written live under this ID
+	} else {
+		result.MeasurementID = export.NextMeasurementID(result.Timestamp)
+	}
+	if cfg.IntervalCap > 0 {
+		result.Intervals = keptIntervals(fwdKeep, result.Intervals, cfg.IntervalCap)
+		result.ReverseIntervals = keptIntervals(revKeep, result.ReverseIntervals, cfg.IntervalCap)
 	}
-	result.MeasurementID = export.NextMeasurementID(result.Timestamp)
 
 	saveResults(result, cfg)
 	return result, nil
 }
 
+// keptIntervals returns the intervals k retained from the live stream, or
+// parsed capped to limit when none were streamed.
+func keptIntervals(k *model.IntervalKeeper, parsed []model.IntervalResult, limit int) []model.IntervalResult {
+	if k != nil && k.Seen() > 0 {
+		return k.Intervals()
+	}
+	return model.CapIntervals(parsed, limit)
+}
+
+// PerServer returns one config per entry in ServerAddrs, each with ServerAddr
+// set to that server. A config with at most one server is returned as is.
+func (c RunnerConfig) PerServer() []RunnerConfig {
+	if len(c.ServerAddrs) <= 1 {
+		return []RunnerConfig{c}
+	}
+	out := make([]RunnerConfig, len(c.ServerAddrs))
+	for i, addr := range c.ServerAddrs {
+		out[i] = c
+		out[i].ServerAddr = addr
+	}
+	return out
+}
+
+// ServerHeader returns the banner printed before testing server i (1-based) of n.
+func ServerHeader(i, n int, addr string) string {
+	return fmt.Sprintf("=== Server %d of %d: %s ===", i, n, addr)
+}
+
+// insertDB adds result to the SQLite history at path.
+func insertDB(path string, result *model.TestResult) error {
+	if err := export.EnsureDir(path); err != nil {
+		return err
+	}
+	db, err := export.OpenDB(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.Insert(result)
+}
+
+// saveMu serializes saveResults so concurrent runs sharing an -o base do
+// not interleave rows.
+var saveMu sync.Mutex
+
 func saveResults(result *model.TestResult, cfg RunnerConfig) {
+	saveMu.Lock()
+	defer saveMu.Unlock()
+
+	if cfg.InfluxPath != "" {
+		if err := export.AppendInflux(cfg.InfluxPath, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Save Influx error: %v\n", err)
+		}
+	}
+	if cfg.InfluxURL != "" {
+		if err := export.PushInflux(cfg.InfluxURL, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Influx push error: %v\n", err)
+		}
+	}
+
+	if cfg.DBPath != "" {
+		if err := insertDB(cfg.DBPath, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Save DB error: %v\n", err)
+		}
+	}
+
 	if cfg.OutputCSV == "" {
 		return // opt-in: only save when -o is specified
 	}
@@ -233,28 +718,63 @@ func saveResults(result *model.TestResult, cfg RunnerConfig) {
 	base := strings.TrimSuffix(cfg.OutputCSV, ".csv")
 
 	if err := export.EnsureDir(base + ".csv"); err != nil {
-		fmt.Printf("Cannot create output directory: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Cannot create output directory: %v\n", err)
+		return
+	}
+
+	delim, err := cfg.csvDelimiter()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Save CSV error: %v\n", err)
 		return
 	}
 
 	date := result.Timestamp
 	logPath := export.BuildLogPath(base, "_log", ".csv")
-	csvPath := export.BuildPath(base, "", ".csv", date)
+	csvPath := cfg.intervalLogPath(date)
 	txtPath := export.BuildPath(base, "", ".txt", date)
 
-	if err := export.WriteCSV(logPath, []model.TestResult{*result}); err != nil {
-		fmt.Printf("Save CSV error: %v\n", err)
-		return
+	var saved []string
+	if !cfg.NoSaveSummary {
+		if err := export.WriteCSVWithDelimiter(logPath, []model.TestResult{*result}, delim); err != nil {
+			fmt.Fprintf(os.Stderr, "Save CSV error: %v\n", err)
+			return
+		}
+		saved = append(saved, logPath)
+	}
+	if !cfg.NoSaveTXT {
+		opts := export.TXTOptions{Detailed: cfg.Detailed}
+		if err := export.WriteTXTWithOptions(txtPath, []model.TestResult{*result}, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Save TXT error: %v\n", err)
+		} else {
+			saved = append(saved, txtPath)
+		}
+	}
+	if cfg.SaveJSON {
+		jsonPath := export.BuildPath(base, "", ".json", date)
+		if err := export.WriteJSON(jsonPath, []model.TestResult{*result}); err != nil {
+			fmt.Fprintf(os.Stderr, "Save JSON error: %v\n", err)
+		}
 	}
-	if err := export.WriteTXT(txtPath, []model.TestResult{*result}); err != nil {
-		fmt.Printf("Save TXT error: %v\n", err)
+	if cfg.SaveMD {
+		mdPath := export.BuildPath(base, "", ".md", date)
+		if err := export.WriteMarkdown(mdPath, []model.TestResult{*result}); err != nil {
+			fmt.Fprintf(os.Stderr, "Save Markdown error: %v\n", err)
+		}
+	}
+	if cfg.SaveHTML {
+		if err := export.WriteHTML(export.HTMLPath(base, result), result); err != nil {
+			fmt.Fprintf(os.Stderr, "Save HTML error: %v\n", err)
+		}
 	}
-	if len(result.Intervals) > 0 {
-		if err := export.WriteIntervalLog(csvPath, result); err != nil {
-			fmt.Printf("Save interval log error: %v\n", err)
+	if len(result.Intervals)+len(result.ReverseIntervals) > 0 && !cfg.NoSaveIntervals {
+		opts := export.IntervalLogOptions{Delimiter: delim, SkipOmitted: cfg.SkipOmitted}
+		if err := export.WriteIntervalLogWithOptions(csvPath, result, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Save interval log error: %v\n", err)
 		}
 	}
-	fmt.Printf("Results saved: %s, %s\n", logPath, txtPath)
+	if len(saved) > 0 {
+		fmt.Fprintf(cfg.Stdout(), "Results saved: %s\n", strings.Join(saved, ", "))
+	}
 }
 
 // RemoteServerRunner manages a remote iperf2 server via SSH.
@@ -266,9 +786,11 @@ type RemoteServerRunner struct {
 
 // NewRemoteServerRunner creates a new remote server manager.
 func NewRemoteServerRunner(cfg RunnerConfig) *RemoteServerRunner {
+	mgr := ssh.NewServerManager()
+	mgr.IperfPath = cfg.RemoteBinary
 	return &RemoteServerRunner{
 		cfg: cfg,
-		mgr: ssh.NewServerManager(),
+		mgr: mgr,
 	}
 }
 
@@ -280,6 +802,9 @@ func (r *RemoteServerRunner) Connect() error {
 		User:     r.cfg.SSHUser,
 		KeyPath:  r.cfg.SSHKeyPath,
 		Password: r.cfg.SSHPassword,
+		JumpHost: r.cfg.SSHJump,
+
+		PassphrasePrompt: promptPassphrase,
 	}
 
 	client, err := ssh.Connect(sshCfg)
@@ -289,12 +814,25 @@ func (r *RemoteServerRunner) Connect() error {
 
 	r.client = client
 	if r.cfg.Verbose {
-		fmt.Printf("Connected to %s@%s\n", r.cfg.SSHUser, r.cfg.SSHHost)
+		fmt.Fprintf(r.cfg.Stdout(), "Connected to %s@%s\n", r.cfg.SSHUser, r.cfg.SSHHost)
 	}
 
 	return nil
 }
 
+// promptPassphrase reads an SSH key passphrase from the terminal without
+// echoing it.
+func promptPassphrase(keyPath string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("stdin is not a terminal")
+	}
+	fmt.Fprintf(os.Stderr, "Enter passphrase for key %s: ", keyPath)
+	b, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	return string(b), err
+}
+
 // Close disconnects from the remote host.
 func (r *RemoteServerRunner) Close() error {
 	if r.client != nil {
@@ -303,6 +841,28 @@ func (r *RemoteServerRunner) Close() error {
 	return nil
 }
 
+// CheckRemoteVersion returns the remote iperf2 version and prints a warning
+// when it is older than iperf.MinVersion or from a different release series
+// than the local client.
+func (r *RemoteServerRunner) CheckRemoteVersion() (string, error) {
+	if r.client == nil {
+		return "", fmt.Errorf("not connected")
+	}
+	return checkRemoteVersion(r.client, r.cfg)
+}
+
+func checkRemoteVersion(client iperf.SSHClient, cfg RunnerConfig) (string, error) {
+	remote, err := iperf.RemoteVersion(client, cfg.RemoteBinary)
+	if err != nil {
+		return "", err
+	}
+	local, _ := iperf.CheckVersion(cfg.BinaryPath)
+	for _, w := range iperf.VersionWarnings(local, remote) {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	return remote, nil
+}
+
 // Install attempts to install iperf2 on the remote host.
 func (r *RemoteServerRunner) Install() error {
 	if r.client == nil {
@@ -310,7 +870,7 @@ func (r *RemoteServerRunner) Install() error {
 	}
 
 	if r.cfg.Verbose {
-		fmt.Println("Checking/installing iperf2 on remote host...")
+		fmt.Fprintln(r.cfg.Stdout(), "Checking/installing iperf2 on remote host...")
 	}
 
 	if err := r.client.InstallIperf(); err != nil {
@@ -318,7 +878,7 @@ func (r *RemoteServerRunner) Install() error {
 	}
 
 	if r.cfg.Verbose {
-		fmt.Println("iperf2 ready on remote host")
+		fmt.Fprintln(r.cfg.Stdout(), "iperf2 ready on remote host")
 	}
 	return nil
 }
@@ -335,21 +895,23 @@ func (r *RemoteServerRunner) Start() error {
 	}
 
 	if r.cfg.Verbose {
-		fmt.Printf("Starting remote iperf2 servers on ports %d, %d...\n", port, port+1)
+		fmt.Fprintf(r.cfg.Stdout(), "Starting remote iperf2 servers on ports %d, %d...\n", port, port+1)
 	}
 
-	if err := r.mgr.StartServer(r.client, port); err != nil {
+	if err := r.client.WithReconnect(func() error { return r.mgr.StartServer(r.client, port) }); err != nil {
 		return fmt.Errorf("start server: %w", err)
 	}
 
 	if r.cfg.Verbose {
-		fmt.Printf("Remote servers started on ports %d, %d\n", port, port+1)
+		fmt.Fprintf(r.cfg.Stdout(), "Remote servers started on ports %d, %d\n", port, port+1)
 	}
 	return nil
 }
 
 // Restart kills any existing iperf2 processes and starts a fresh server.
 // numInstances controls how many server instances to start (0 = default of 2).
+// Like Start, Stop and CheckStatus it reconnects once if the SSH connection
+// has dropped.
 func (r *RemoteServerRunner) Restart(numInstances int) error {
 	if r.client == nil {
 		return fmt.Errorf("not connected")
@@ -358,7 +920,9 @@ func (r *RemoteServerRunner) Restart(numInstances int) error {
 	if port == 0 {
 		port = 5201
 	}
-	return r.mgr.RestartServer(r.client, port, numInstances)
+	return r.client.WithReconnect(func() error {
+		return r.mgr.RestartServer(r.client, port, numInstances)
+	})
 }
 
 // Stop stops the remote iperf2 server.
@@ -368,15 +932,15 @@ func (r *RemoteServerRunner) Stop() error {
 	}
 
 	if r.cfg.Verbose {
-		fmt.Println("Stopping remote iperf2 server...")
+		fmt.Fprintln(r.cfg.Stdout(), "Stopping remote iperf2 server...")
 	}
 
-	if err := r.mgr.StopServer(r.client); err != nil {
+	if err := r.client.WithReconnect(func() error { return r.mgr.StopServer(r.client) }); err != nil {
 		return fmt.Errorf("stop server: %w", err)
 	}
 
 	if r.cfg.Verbose {
-		fmt.Println("Remote server stopped")
+		fmt.Fprintln(r.cfg.Stdout(), "Remote server stopped")
 	}
 	return nil
 }
@@ -386,7 +950,12 @@ func (r *RemoteServerRunner) CheckStatus() (bool, error) {
 	if r.client == nil {
 		return false, fmt.Errorf("not connected")
 	}
-	return r.mgr.CheckStatus(r.client)
+	var running bool
+	err := r.client.WithReconnect(func() (err error) {
+		running, err = r.mgr.CheckStatus(r.client)
+		return err
+	})
+	return running, err
 }
 
 // Client returns the underlying SSH client for use with the iperf2 runner.
@@ -403,8 +972,29 @@ func (r *RemoteServerRunner) IsWindows() bool {
 	return err == nil && os == ssh.OSWindows
 }
 
-// PrintResult formats and prints a test result.
-func PrintResult(result *model.TestResult) {
+// PrintResult formats and prints a test result, colored per cfg.Color;
+// cfg.Quiet prints the one-line compact summary instead.
+func PrintResult(result *model.TestResult, cfg RunnerConfig) {
+	if cfg.Quiet {
+		fmt.Println(format.FormatResultCompact(result))
+		return
+	}
 	fmt.Println()
-	fmt.Println(format.FormatResult(result))
+	fmt.Println(format.FormatResultColored(result, cfg.colorizer(os.Stdout)))
+}
+
+// colorizer resolves cfg.Color for output written to f: "auto" colors only
+// when f is a terminal and NO_COLOR is unset.
+func (cfg RunnerConfig) colorizer(f *os.File) format.Colorizer {
+	switch cfg.Color {
+	case "always":
+		return format.Colorizer{Enabled: true}
+	case "never":
+		return format.Colorizer{}
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return format.Colorizer{}
+	}
+	fi, err := f.Stat()
+	return format.Colorizer{Enabled: err == nil && fi.Mode()&os.ModeCharDevice != 0}
 }
@@ -1,7 +1,17 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
+
+	"iperf-tool/internal/model"
 )
 
 func TestLocalTestRunnerConfig(t *testing.T) {
@@ -47,3 +57,356 @@ func TestPrintResult(t *testing.T) {
 		}
 	}()
 }
+
+func TestRunDeadline(t *testing.T) {
+	if got := runDeadline(10); got != 10*time.Second+runGracePeriod {
+		t.Errorf("runDeadline(10) = %v", got)
+	}
+	if runDeadline(3600) <= time.Hour {
+		t.Error("deadline must exceed the test duration")
+	}
+}
+
+func TestPerServer(t *testing.T) {
+	single := RunnerConfig{ServerAddr: "10.0.0.1", ServerAddrs: []string{"10.0.0.1"}, Port: 5001}
+	if got := single.PerServer(); len(got) != 1 || got[0].ServerAddr != "10.0.0.1" {
+		t.Errorf("PerServer() single = %+v", got)
+	}
+
+	multi := RunnerConfig{ServerAddr: "a", ServerAddrs: []string{"a", "b", "c"}, Port: 5001, OutputCSV: "results/run"}
+	got := multi.PerServer()
+	if len(got) != 3 {
+		t.Fatalf("PerServer() returned %d configs, want 3", len(got))
+	}
+	for i, want := range []string{"a", "b", "c"} {
+		if got[i].ServerAddr != want {
+			t.Errorf("config %d ServerAddr = %q, want %q", i, got[i].ServerAddr, want)
+		}
+		if got[i].Port != 5001 || got[i].OutputCSV != "results/run" {
+			t.Errorf("config %d lost shared settings: %+v", i, got[i])
+		}
+	}
+}
+
+func TestServerHeader(t *testing.T) {
+	if got := ServerHeader(2, 3, "10.0.0.2"); got != "=== Server 2 of 3: 10.0.0.2 ===" {
+		t.Errorf("ServerHeader() = %q", got)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	cfg := *defaultRunnerConfig()
+	cfg.BinaryPath = "iperf"
+	cfg.ServerAddr = "192.168.1.1"
+	cfg.Parallel = 2
+	cfg.Protocol = "udp"
+	cfg.Bandwidth = "50M"
+
+	var buf bytes.Buffer
+	if err := DryRun(cfg, &buf); err != nil {
+		t.Fatalf("DryRun() error: %v", err)
+	}
+	want := "local: iperf -c 192.168.1.1 -u -p 5201-5202 -t 10 -f m -i 1 -b 50M -e\n"
+	if buf.String() != want {
+		t.Errorf("DryRun() printed %q, want %q", buf.String(), want)
+	}
+
+	cfg.SSHHost = "remote.host"
+	cfg.Reverse = true
+	buf.Reset()
+	if err := DryRun(cfg, &buf); err != nil {
+		t.Fatalf("DryRun() reverse error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "remote: iperf -c <local-addr>") {
+		t.Errorf("reverse dry run should show the remote client command:\n%s", buf.String())
+	}
+
+	cfg.Port = 70000
+	if err := DryRun(cfg, &buf); err == nil {
+		t.Error("DryRun() should still validate the config")
+	}
+}
+
+func TestOpenIntervalStream(t *testing.T) {
+	s, closeFn, err := openIntervalStream("")
+	if err != nil || s != nil {
+		t.Fatalf("empty path: stream=%v err=%v", s, err)
+	}
+	closeFn()
+
+	path := filepath.Join(t.TempDir(), "live", "stream.jsonl")
+	for i := 0; i < 2; i++ {
+		s, closeFn, err := openIntervalStream(path)
+		if err != nil {
+			t.Fatalf("openIntervalStream() error = %v", err)
+		}
+		s.Write(&model.IntervalResult{TimeEnd: float64(i + 1), BandwidthBps: 1e6}, nil)
+		closeFn()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "\n"); got != 2 {
+		t.Errorf("stream file has %d lines, want 2 (appended):\n%s", got, data)
+	}
+}
+
+func TestNewRemoteServerRunner_RemoteBinary(t *testing.T) {
+	runner := NewRemoteServerRunner(RunnerConfig{SSHHost: "10.0.0.1", RemoteBinary: "/opt/iperf2/bin/iperf"})
+	if runner.mgr.IperfPath != "/opt/iperf2/bin/iperf" {
+		t.Errorf("IperfPath = %q, want /opt/iperf2/bin/iperf", runner.mgr.IperfPath)
+	}
+}
+
+type fakeVersionClient struct{ cmds []string }
+
+func (f *fakeVersionClient) RunCommand(cmd string) (string, error) {
+	f.cmds = append(f.cmds, cmd)
+	return "iperf version 2.1.9 (14 March 2023) pthreads\n", nil
+}
+
+func TestCheckRemoteVersion(t *testing.T) {
+	f := &fakeVersionClient{}
+	v, err := checkRemoteVersion(f, RunnerConfig{RemoteBinary: "/opt/iperf2/bin/iperf", BinaryPath: "/nonexistent/iperf"})
+	if err != nil {
+		t.Fatalf("checkRemoteVersion() error: %v", err)
+	}
+	if v != "2.1.9" {
+		t.Errorf("version = %q, want 2.1.9", v)
+	}
+	if len(f.cmds) != 1 || f.cmds[0] != "/opt/iperf2/bin/iperf --version" {
+		t.Errorf("commands = %q", f.cmds)
+	}
+
+	if _, err := NewRemoteServerRunner(RunnerConfig{}).CheckRemoteVersion(); err == nil {
+		t.Error("expected error when not connected")
+	}
+}
+
+func TestPinIntervalLog(t *testing.T) {
+	dir := t.TempDir()
+	cfg := RunnerConfig{OutputCSV: filepath.Join(dir, "results"), Quiet: true}
+	cfg.PinIntervalLog(time.Date(2026, 2, 18, 23, 59, 0, 0, time.Local))
+
+	// Two runs either side of midnight land in the same interval CSV.
+	for i, ts := range []time.Time{
+		time.Date(2026, 2, 18, 23, 59, 30, 0, time.Local),
+		time.Date(2026, 2, 19, 0, 0, 30, 0, time.Local),
+	} {
+		saveResults(&model.TestResult{
+			Timestamp:     ts,
+			MeasurementID: "run-" + string(rune('1'+i)),
+			Protocol:      "TCP",
+			Intervals:     []model.IntervalResult{{TimeStart: 0, TimeEnd: 1, BandwidthBps: 1e8}},
+		}, cfg)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "results_18.02.2026.csv"))
+	if err != nil {
+		t.Fatalf("pinned interval log missing: %v", err)
+	}
+	if !strings.Contains(string(data), "run-1;") || !strings.Contains(string(data), "run-2;") {
+		t.Errorf("both runs should be in the pinned log:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "results_19.02.2026.csv")); err == nil {
+		t.Error("second run should not start a new dated interval log")
+	}
+
+	unset := RunnerConfig{}
+	unset.PinIntervalLog(time.Now())
+	if unset.IntervalLog != "" {
+		t.Errorf("IntervalLog = %q without -o, want empty", unset.IntervalLog)
+	}
+}
+
+func TestSaveResults_InfluxWithoutOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "iperf.lp")
+	cfg := RunnerConfig{InfluxPath: path, Quiet: true}
+	saveResults(&model.TestResult{ServerAddr: "10.0.0.1", Protocol: "TCP", SentBps: 1e6}, cfg)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("influx file missing: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "iperf,server=10.0.0.1,protocol=TCP,") {
+		t.Errorf("unexpected influx line: %q", data)
+	}
+}
+
+func TestSaveResults_SelectiveOutputs(t *testing.T) {
+	ts := time.Date(2026, 3, 4, 10, 0, 0, 0, time.Local)
+	result := &model.TestResult{
+		Timestamp: ts,
+		Protocol:  "TCP",
+		SentBps:   1e8,
+		Intervals: []model.IntervalResult{{TimeStart: 0, TimeEnd: 1, BandwidthBps: 1e8}},
+	}
+	tests := []struct {
+		name                   string
+		cfg                    RunnerConfig
+		summary, txt, interval bool
+	}{
+		{"all", RunnerConfig{}, true, true, true},
+		{"no txt", RunnerConfig{NoSaveTXT: true}, true, false, true},
+		{"no intervals", RunnerConfig{NoSaveIntervals: true}, true, true, false},
+		{"summary only", RunnerConfig{NoSaveTXT: true, NoSaveIntervals: true}, true, false, false},
+		{"no summary", RunnerConfig{NoSaveSummary: true}, false, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cfg := tt.cfg
+			cfg.OutputCSV = filepath.Join(dir, "results")
+			cfg.Quiet = true
+			saveResults(result, cfg)
+
+			for path, want := range map[string]bool{
+				"results_log.csv":        tt.summary,
+				"results_04.03.2026.txt": tt.txt,
+				"results_04.03.2026.csv": tt.interval,
+			} {
+				_, err := os.Stat(filepath.Join(dir, path))
+				if got := err == nil; got != want {
+					t.Errorf("%s exists = %v, want %v", path, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalTestRunner_RequireVersion(t *testing.T) {
+	orig := localVersion
+	t.Cleanup(func() { localVersion = orig })
+
+	// Port 1 has no listener, so a run that got past the check would fail
+	// at the port probe instead.
+	cfg := RunnerConfig{ServerAddr: "127.0.0.1", Port: 1, Parallel: 1, Duration: 10, Interval: 1, Protocol: "tcp", BinaryPath: "iperf", RequireVersion: true}
+	localVersion = func(string) (string, error) { return "2.0.5", nil }
+	_, err := LocalTestRunner(cfg)
+	if err == nil || !strings.Contains(err.Error(), "iperf2 2.0.5 is older than the required 2.0.10") {
+		t.Errorf("old iperf2 error = %v, want the found and required versions", err)
+	}
+
+	localVersion = func(string) (string, error) {
+		return "", errors.New("exec: \"iperf\": executable file not found in $PATH")
+	}
+	if _, err := LocalTestRunner(cfg); err == nil || !strings.HasPrefix(err.Error(), "-require-version: ") {
+		t.Errorf("missing iperf2 error = %v, want a -require-version error", err)
+	}
+
+	// Without the flag an old version is only recorded
+	cfg.RequireVersion = false
+	localVersion = func(string) (string, error) { return "2.0.5", nil }
+	if _, err := LocalTestRunner(cfg); err == nil || strings.Contains(err.Error(), "older than") {
+		t.Errorf("error without -require-version = %v, want the port probe failure", err)
+	}
+}
+
+func TestMTUCheck(t *testing.T) {
+	cfg := RunnerConfig{ServerAddr: "10.0.0.1", Port: 5201, Parallel: 1, Duration: 10, Interval: 1, Protocol: "udp", BinaryPath: "iperf", BlockSize: 8000, MTUCheck: 1500}
+	var buf bytes.Buffer
+	if err := MTUCheck(cfg, &buf); err != nil {
+		t.Fatalf("MTUCheck() error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Datagram size:     8000 bytes\n", "Max unfragmented:  1472 bytes\n", "6 IP packets per datagram"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	cfg.BlockSize, cfg.MTUCheck = 0, 9000
+	buf.Reset()
+	if err := MTUCheck(cfg, &buf); err != nil {
+		t.Fatalf("MTUCheck() error: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "1470 bytes (iperf2 default)") || !strings.Contains(out, "Fragmentation:     none") {
+		t.Errorf("default datagram on a jumbo path:\n%s", out)
+	}
+}
+
+type failingSSHClient struct{}
+
+func (failingSSHClient) RunCommand(string) (string, error) {
+	return "", errors.New("ssh session closed")
+}
+
+func TestLocalTestRunner_PortProbeRetried(t *testing.T) {
+	origProbe, origSleep := probePort, retrySleep
+	t.Cleanup(func() { probePort, retrySleep = origProbe, origSleep })
+	probes := 0
+	probePort = func(string, int, time.Duration) error {
+		probes++
+		return errors.New("cannot reach iperf2 server at 127.0.0.1:5999 (is it running?): dial tcp 127.0.0.1:5999: connect: connection refused")
+	}
+	retrySleep = func(context.Context, time.Duration) error { return nil }
+
+	cfg := RunnerConfig{ServerAddr: "127.0.0.1", Port: 5999, Parallel: 1, Duration: 10, Interval: 1, Protocol: "tcp", BinaryPath: "iperf", MaxRetries: 2, Quiet: true}
+	if _, err := LocalTestRunner(cfg); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("error = %v, want the refused probe", err)
+	}
+	if probes != 3 {
+		t.Errorf("probes = %d, want 3 (first attempt + 2 retries)", probes)
+	}
+
+	// With SSH the runner starts the server itself, so there is nothing to probe yet.
+	probes = 0
+	cfg.MaxRetries = 0
+	cfg.SSHClient = failingSSHClient{}
+	if _, err := LocalTestRunner(cfg); err == nil || !strings.Contains(err.Error(), "start remote server") {
+		t.Errorf("error = %v, want the remote server start failure", err)
+	}
+	if probes != 0 {
+		t.Errorf("probes = %d with an SSH client, want 0", probes)
+	}
+}
+
+func TestLocalTestRunner_IntervalCapBoundsLiveIntervals(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake iperf2 is a shell script")
+	}
+	origProbe, origVersion := probePort, localVersion
+	t.Cleanup(func() { probePort, localVersion = origProbe, origVersion })
+	probePort = func(string, int, time.Duration) error { return nil }
+	localVersion = func(string) (string, error) { return "2.1.9", nil }
+
+	// A 2000-interval run, far more than the cap.
+	bin := filepath.Join(t.TempDir(), "iperf")
+	script := `#!/bin/sh
+i=0
+while [ $i -lt 2000 ]; do
+  echo "[  1] $i.00-$((i+1)).00 sec  1.00 MBytes  8.39 Mbits/sec"
+  i=$((i+1))
+done
+echo "[  1] 0.00-2000.00 sec  2000.00 MBytes  8.39 Mbits/sec"
+`
+	if err := os.WriteFile(bin, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var progress bytes.Buffer
+	cfg := RunnerConfig{ServerAddr: "127.0.0.1", Port: 5001, Parallel: 1, Duration: 10, Interval: 1, Protocol: "tcp",
+		BinaryPath: bin, IntervalCap: 16, Progress: &progress}
+	result, err := LocalTestRunner(cfg)
+	if err != nil {
+		t.Fatalf("LocalTestRunner() error: %v", err)
+	}
+
+	if n := len(result.Intervals); n == 0 || n > cfg.IntervalCap {
+		t.Fatalf("kept %d intervals, want 1..%d", n, cfg.IntervalCap)
+	}
+	var bytes int64
+	for _, iv := range result.Intervals {
+		bytes += iv.Bytes
+	}
+	if last := result.Intervals[len(result.Intervals)-1]; last.TimeEnd != 2000 || bytes != 2000*1_000_000 {
+		t.Errorf("kept rows end at %.0fs with %d bytes, want the whole 2000s run and all its bytes", last.TimeEnd, bytes)
+	}
+	if result.ActualDuration != 2000 || result.SentBps != 8.39e6 {
+		t.Errorf("duration = %v, sent = %v; want the 0-2000s summary line", result.ActualDuration, result.SentBps)
+	}
+	if got := strings.Count(progress.String(), "Mbps"); got < 2000 {
+		t.Errorf("printed %d live intervals, want all 2000", got)
+	}
+}
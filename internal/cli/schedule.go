@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"iperf-tool/internal/export"
+	"iperf-tool/internal/model"
+)
+
+// clock is the time source RunScheduled waits on; tests replace it.
+type clock struct {
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+var schedClock = clock{now: time.Now, after: time.After}
+
+// scheduleAnchor returns the instant ticks are counted from. Periods that
+// divide a day evenly (1m, 15m, 1h, ...) are anchored at local midnight, so
+// runs land on round wall-clock times such as :00, :15, :30 and :45; any
+// other period is counted from start.
+func scheduleAnchor(start time.Time, period time.Duration) time.Time {
+	if (24*time.Hour)%period != 0 {
+		return start
+	}
+	y, m, d := start.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, start.Location())
+}
+
+// nextTick returns the first anchor + k*period strictly after now.
+func nextTick(now, anchor time.Time, period time.Duration) time.Time {
+	k := now.Sub(anchor)/period + 1
+	return anchor.Add(k * period)
+}
+
+// ParseUntil parses an -until value: "15:04", "15:04:05", "2006-01-02 15:04"
+// or RFC 3339. A bare time of day means its next occurrence after now.
+func ParseUntil(s string, now time.Time) (time.Time, error) {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			y, m, d := now.Date()
+			at := time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, now.Location())
+			if !at.After(now) {
+				at = at.AddDate(0, 0, 1)
+			}
+			return at, nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use 15:04, 2006-01-02 15:04 or RFC 3339)", s)
+}
+
+// RunScheduled runs the configured test(s) on wall-clock ticks of period
+// until deadline (zero = until Ctrl-C), saving every result like -repeat
+// does. Ctrl-C during a run lets it finish; during the wait it stops at once.
+func RunScheduled(cfg RunnerConfig, period time.Duration, deadline time.Time) error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+
+	stop := make(chan struct{})
+	go func() {
+		if _, ok := <-sigCh; ok {
+			fmt.Fprintln(cfg.Stdout(), "\nStop requested — finishing current measurement...")
+			close(stop)
+		}
+	}()
+	return runScheduled(cfg, period, deadline, schedClock, stop)
+}
+
+func runScheduled(cfg RunnerConfig, period time.Duration, deadline time.Time, clk clock, stop <-chan struct{}) error {
+	start := clk.now()
+	cfg.PinIntervalLog(start)
+	anchor := scheduleAnchor(start, period)
+
+	runs, errored := 0, 0
+	var totals RunTotals
+	var results []model.TestResult
+	for n := 1; ; n++ {
+		tick := nextTick(clk.now(), anchor, period)
+		if !deadline.IsZero() && tick.After(deadline) {
+			break
+		}
+		fmt.Fprintf(cfg.Stdout(), "Next run at %s (in %s)\n", tick.Format("15:04:05"), tick.Sub(clk.now()).Round(time.Second))
+		select {
+		case <-stop:
+			return finishScheduled(cfg.Stdout(), runs, errored, results, totals)
+		case <-clk.after(tick.Sub(clk.now())):
+		}
+
+		fmt.Fprintf(cfg.Stdout(), "\n--- Scheduled run %d at %s ---\n", n, tick.Format("2006-01-02 15:04:05"))
+		servers := cfg.PerServer()
+		for i, sc := range servers {
+			if len(servers) > 1 {
+				fmt.Fprintln(cfg.Stdout(), ServerHeader(i+1, len(servers), sc.ServerAddr))
+			}
+			result, err := runLocalTest(sc)
+			runs++
+			if err != nil {
+				// Keep going: a dashboard wants the next tick even after a failure
+				fmt.Fprintf(os.Stderr, "Scheduled run error: %v\n", err)
+				errored++
+				continue
+			}
+			PrintResult(result, cfg)
+			results = append(results, *result)
+			totals.Add(result)
+			if sc.PromPath != "" {
+				if err := export.WritePromMetrics(sc.PromPath, result); err != nil {
+					fmt.Fprintf(os.Stderr, "Write metrics error: %v\n", err)
+				}
+			}
+		}
+
+		select {
+		case <-stop:
+			return finishScheduled(cfg.Stdout(), runs, errored, results, totals)
+		default:
+		}
+	}
+	return finishScheduled(cfg.Stdout(), runs, errored, results, totals)
+}
+
+func finishScheduled(out io.Writer, runs, errored int, results []model.TestResult, totals RunTotals) error {
+	fmt.Fprintf(out, "\n%s\n", totals.Completed(runs, "scheduled run(s)"))
+	if runs > 1 {
+		fmt.Fprintln(out)
+		fmt.Fprint(out, SummarizeRuns(results, errored))
+	}
+	return nil
+}
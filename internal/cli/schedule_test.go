@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+func TestNextTick_Aligned(t *testing.T) {
+	loc := time.FixedZone("test", 3*3600)
+	start := time.Date(2026, 5, 1, 10, 7, 42, 0, loc)
+	anchor := scheduleAnchor(start, 15*time.Minute)
+	if want := time.Date(2026, 5, 1, 0, 0, 0, 0, loc); !anchor.Equal(want) {
+		t.Fatalf("anchor = %v, want local midnight %v", anchor, want)
+	}
+
+	tests := []struct {
+		now  time.Time
+		want time.Time
+	}{
+		{start, time.Date(2026, 5, 1, 10, 15, 0, 0, loc)},
+		{time.Date(2026, 5, 1, 10, 15, 0, 0, loc), time.Date(2026, 5, 1, 10, 30, 0, 0, loc)}, // exactly on a tick = next one
+		{time.Date(2026, 5, 1, 10, 31, 5, 0, loc), time.Date(2026, 5, 1, 10, 45, 0, 0, loc)}, // overran 10:30, skip it
+		{time.Date(2026, 5, 1, 23, 50, 0, 0, loc), time.Date(2026, 5, 2, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		if got := nextTick(tt.now, anchor, 15*time.Minute); !got.Equal(tt.want) {
+			t.Errorf("nextTick(%s) = %s, want %s", tt.now.Format("15:04:05"), got.Format("15:04:05"), tt.want.Format("15:04:05"))
+		}
+	}
+
+	// One minute: the top of the next minute
+	if got := nextTick(start, scheduleAnchor(start, time.Minute), time.Minute); !got.Equal(time.Date(2026, 5, 1, 10, 8, 0, 0, loc)) {
+		t.Errorf("1m tick = %s, want 10:08:00", got.Format("15:04:05"))
+	}
+}
+
+func TestNextTick_Unaligned(t *testing.T) {
+	start := time.Date(2026, 5, 1, 10, 7, 42, 0, time.UTC)
+	period := 7 * time.Minute
+	anchor := scheduleAnchor(start, period)
+	if !anchor.Equal(start) {
+		t.Fatalf("anchor = %v, want start for a period that does not divide a day", anchor)
+	}
+	if got := nextTick(start.Add(time.Second), anchor, period); !got.Equal(start.Add(period)) {
+		t.Errorf("nextTick = %v, want %v", got, start.Add(period))
+	}
+}
+
+func TestParseUntil(t *testing.T) {
+	now := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"18:30", time.Date(2026, 5, 1, 18, 30, 0, 0, time.UTC)},
+		{"09:00", time.Date(2026, 5, 2, 9, 0, 0, 0, time.UTC)}, // already passed today
+		{"10:00:30", time.Date(2026, 5, 1, 10, 0, 30, 0, time.UTC)},
+		{"2026-05-03 06:00", time.Date(2026, 5, 3, 6, 0, 0, 0, time.UTC)},
+		{"2026-05-03T06:00:00Z", time.Date(2026, 5, 3, 6, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseUntil(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseUntil(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseUntil(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	if _, err := ParseUntil("tomorrow", now); err == nil {
+		t.Error("expected error for an unparseable time")
+	}
+}
+
+func TestRunScheduled_StopsAtDeadline(t *testing.T) {
+	now := time.Date(2026, 5, 1, 10, 7, 0, 0, time.UTC)
+	var waits []time.Duration
+	clk := clock{
+		now: func() time.Time { return now },
+		after: func(d time.Duration) <-chan time.Time {
+			waits = append(waits, d)
+			now = now.Add(d)
+			ch := make(chan time.Time, 1)
+			ch <- now
+			return ch
+		},
+	}
+
+	var ran []time.Time
+	orig := runLocalTest
+	defer func() { runLocalTest = orig }()
+	runLocalTest = func(cfg RunnerConfig) (*model.TestResult, error) {
+		ran = append(ran, now)
+		now = now.Add(10 * time.Second) // the test itself takes time
+		return &model.TestResult{ServerAddr: cfg.ServerAddr, Protocol: "TCP", SentBps: 1e8, ReceivedBps: 1e8}, nil
+	}
+
+	cfg := RunnerConfig{ServerAddr: "10.0.0.1", ServerAddrs: []string{"10.0.0.1"}, Quiet: true}
+	deadline := time.Date(2026, 5, 1, 10, 50, 0, 0, time.UTC)
+	if err := runScheduled(cfg, 15*time.Minute, deadline, clk, make(chan struct{})); err != nil {
+		t.Fatalf("runScheduled() error: %v", err)
+	}
+
+	want := []string{"10:15:00", "10:30:00", "10:45:00"}
+	if len(ran) != len(want) {
+		t.Fatalf("ran %d tests, want %d: %v", len(ran), len(want), ran)
+	}
+	for i, w := range want {
+		if got := ran[i].Format("15:04:05"); got != w {
+			t.Errorf("run %d at %s, want %s", i+1, got, w)
+		}
+	}
+	if waits[0] != 8*time.Minute {
+		t.Errorf("first wait = %s, want 8m to the next quarter hour", waits[0])
+	}
+}
+
+func TestRunScheduled_StopWhileWaiting(t *testing.T) {
+	clk := clock{
+		now:   func() time.Time { return time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC) },
+		after: func(time.Duration) <-chan time.Time { return nil }, // never fires
+	}
+	orig := runLocalTest
+	defer func() { runLocalTest = orig }()
+	runLocalTest = func(RunnerConfig) (*model.TestResult, error) {
+		t.Fatal("no test should run after stop")
+		return nil, nil
+	}
+
+	stop := make(chan struct{})
+	close(stop)
+	if err := runScheduled(RunnerConfig{ServerAddr: "10.0.0.1", Quiet: true}, time.Hour, time.Time{}, clk, stop); err != nil {
+		t.Fatalf("runScheduled() error: %v", err)
+	}
+}
+
+func TestRunScheduled_QuietStdoutOnlySummaries(t *testing.T) {
+	now := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	clk := clock{
+		now: func() time.Time { return now },
+		after: func(d time.Duration) <-chan time.Time {
+			now = now.Add(d)
+			ch := make(chan time.Time, 1)
+			ch <- now
+			return ch
+		},
+	}
+	orig := runLocalTest
+	defer func() { runLocalTest = orig }()
+	runLocalTest = func(cfg RunnerConfig) (*model.TestResult, error) {
+		return &model.TestResult{ServerAddr: cfg.ServerAddr, Protocol: "TCP", SentBps: 1e8, ReceivedBps: 1e8}, nil
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	cfg := RunnerConfig{ServerAddr: "10.0.0.1", ServerAddrs: []string{"10.0.0.1", "10.0.0.2"}, Quiet: true}
+	deadline := now.Add(35 * time.Minute)
+	runErr := runScheduled(cfg, 15*time.Minute, deadline, clk, make(chan struct{}))
+	w.Close()
+	os.Stdout = stdout
+	out, _ := io.ReadAll(r)
+
+	if runErr != nil {
+		t.Fatalf("runScheduled() error: %v", runErr)
+	}
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("quiet stdout has %d lines, want one summary per run (4):\n%s", len(lines), out)
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, " TCP fwd=") {
+			t.Errorf("quiet stdout line %q is not a one-line summary", line)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"iperf-tool/internal/format"
+	"iperf-tool/internal/model"
+)
+
+// RunTotals accumulates the data moved and the test wall time of a repeat
+// or scheduled session.
+type RunTotals struct {
+	Bytes int64
+	Wall  time.Duration
+}
+
+// Add counts one finished run. Each direction contributes the bytes its
+// receiver saw (the sender's count when that is unknown), so a forward
+// test is not counted twice; wall time is Elapsed, else the iperf2 duration.
+func (t *RunTotals) Add(r *model.TestResult) {
+	fwd, rev := r.BytesReceived, r.ReverseBytesReceived
+	if fwd == 0 {
+		fwd = r.BytesSent
+	}
+	if rev == 0 {
+		rev = r.ReverseBytesSent
+	}
+	t.Bytes += fwd + rev
+	if d := r.Elapsed(); d > 0 {
+		t.Wall += d
+	} else {
+		t.Wall += time.Duration(r.ActualDuration * float64(time.Second))
+	}
+}
+
+// Completed returns the session's closing line, e.g.
+// "Completed 42 run(s), 18.3 GB transferred over 2h11m." noun names the runs.
+func (t RunTotals) Completed(runs int, noun string) string {
+	if t.Bytes == 0 && t.Wall == 0 {
+		return fmt.Sprintf("Completed %d %s.", runs, noun)
+	}
+	return fmt.Sprintf("Completed %d %s, %s transferred over %s.",
+		runs, noun, format.HumanBytes(t.Bytes), format.HumanDuration(t.Wall))
+}
+
+// runStats accumulates min/avg/max/stddev for one metric across runs.
+type runStats struct {
+	values []float64
+}
+
+func (s *runStats) add(v float64) {
+	s.values = append(s.values, v)
+}
+
+// summary returns min, mean, max and sample standard deviation.
+func (s *runStats) summary() (minV, mean, maxV, stddev float64) {
+	minV, maxV = math.Inf(1), math.Inf(-1)
+	var sum float64
+	for _, v := range s.values {
+		sum += v
+		minV = math.Min(minV, v)
+		maxV = math.Max(maxV, v)
+	}
+	n := float64(len(s.values))
+	mean = sum / n
+	if len(s.values) > 1 {
+		var sq float64
+		for _, v := range s.values {
+			sq += (v - mean) * (v - mean)
+		}
+		stddev = math.Sqrt(sq / (n - 1))
+	}
+	return minV, mean, maxV, stddev
+}
+
+// SummarizeRuns formats min/avg/max/stddev of forward and reverse Mbps,
+// jitter and loss across repeat runs. Reverse throughput is taken only from
+// runs that measured it, jitter and loss only from UDP runs. errored is the
+// number of runs that failed and are not in results.
+func SummarizeRuns(results []model.TestResult, errored int) string {
+	var fwd, rev, jitter, loss runStats
+	for i := range results {
+		r := &results[i]
+		if r.Direction != "Reverse" {
+			fwd.add(format.Units.Scale(r.FwdActualMbps()))
+		}
+		if r.Direction == "Reverse" || r.Direction == "Bidirectional" {
+			rev.add(format.Units.Scale(r.ReverseActualMbps()))
+		}
+		if r.Protocol == "UDP" {
+			jitter.add(r.ActualJitterMs())
+			lost := r.LostPercent
+			if r.Direction == "Bidirectional" && r.FwdPackets > 0 {
+				lost = r.FwdLostPercent
+			}
+			loss.add(lost)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("=== Summary: %d successful run(s), %d errored ===\n", len(results), errored))
+	if len(results) == 0 {
+		return b.String()
+	}
+	b.WriteString(fmt.Sprintf("%-15s %10s %10s %10s %10s\n", "", "Min", "Avg", "Max", "StdDev"))
+	rows := []struct {
+		label string
+		stats *runStats
+	}{
+		{"Fwd " + format.Units.MbpsUnit(), &fwd},
+		{"Rev " + format.Units.MbpsUnit(), &rev},
+		{"Jitter ms", &jitter},
+		{"Loss %", &loss},
+	}
+	for _, row := range rows {
+		if len(row.stats.values) == 0 {
+			continue
+		}
+		minV, mean, maxV, sd := row.stats.summary()
+		b.WriteString(fmt.Sprintf("%-15s %10.2f %10.2f %10.2f %10.2f\n", row.label, minV, mean, maxV, sd))
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+func TestSummarizeRuns_TCP(t *testing.T) {
+	results := []model.TestResult{
+		{Protocol: "TCP", Direction: "Forward", FwdReceivedBps: 100_000_000},
+		{Protocol: "TCP", Direction: "Forward", FwdReceivedBps: 200_000_000},
+		{Protocol: "TCP", Direction: "Forward", FwdReceivedBps: 300_000_000},
+	}
+
+	out := SummarizeRuns(results, 2)
+
+	if !strings.Contains(out, "3 successful run(s), 2 errored") {
+		t.Errorf("missing run counts in:\n%s", out)
+	}
+	// min 100, avg 200, max 300, sample stddev 100
+	if !strings.Contains(out, "Fwd Mbps            100.00     200.00     300.00     100.00") {
+		t.Errorf("unexpected forward row in:\n%s", out)
+	}
+	if strings.Contains(out, "Rev Mbps") || strings.Contains(out, "Jitter") || strings.Contains(out, "Loss") {
+		t.Errorf("forward TCP runs should only report forward Mbps:\n%s", out)
+	}
+}
+
+func TestSummarizeRuns_UDPBidir(t *testing.T) {
+	results := []model.TestResult{
+		{Protocol: "UDP", Direction: "Bidirectional", SentBps: 10_000_000, ReverseReceivedBps: 9_000_000,
+			FwdJitterMs: 0.5, FwdPackets: 100, FwdLostPercent: 1.0, LostPercent: 50},
+		{Protocol: "UDP", Direction: "Bidirectional", SentBps: 10_000_000, ReverseReceivedBps: 11_000_000,
+			FwdJitterMs: 1.5, FwdPackets: 100, FwdLostPercent: 3.0, LostPercent: 50},
+	}
+
+	out := SummarizeRuns(results, 0)
+
+	for _, want := range []string{
+		"Rev Mbps              9.00      10.00      11.00",
+		"Jitter ms             0.50       1.00       1.50",
+		"Loss %                1.00       2.00       3.00",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestSummarizeRuns_NoResults(t *testing.T) {
+	out := SummarizeRuns(nil, 4)
+	if !strings.Contains(out, "0 successful run(s), 4 errored") {
+		t.Errorf("unexpected summary:\n%s", out)
+	}
+	if strings.Contains(out, "Min") {
+		t.Error("empty summary should not print a stats table")
+	}
+}
+
+func TestSummarizeRuns_SingleRunStdDev(t *testing.T) {
+	out := SummarizeRuns([]model.TestResult{{Protocol: "TCP", SentBps: 50_000_000}}, 0)
+	if !strings.Contains(out, "Fwd Mbps             50.00      50.00      50.00       0.00") {
+		t.Errorf("unexpected single-run summary:\n%s", out)
+	}
+}
+
+func TestRunTotals(t *testing.T) {
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	var totals RunTotals
+	if got := totals.Completed(0, "run(s)"); got != "Completed 0 run(s)." {
+		t.Errorf("empty Completed() = %q", got)
+	}
+
+	// Forward: receiver bytes win over sender bytes
+	totals.Add(&model.TestResult{
+		BytesSent: 9_000_000_000, BytesReceived: 8_000_000_000,
+		StartedAt: start, FinishedAt: start.Add(time.Hour),
+	})
+	// Bidir: both directions count; no wall clock, so ActualDuration is used
+	totals.Add(&model.TestResult{
+		BytesSent: 6_000_000_000, ReverseBytesSent: 4_300_000_000,
+		ActualDuration: 4260,
+	})
+
+	if totals.Bytes != 18_300_000_000 {
+		t.Errorf("Bytes = %d, want 18300000000", totals.Bytes)
+	}
+	if totals.Wall != time.Hour+71*time.Minute {
+		t.Errorf("Wall = %s, want 2h11m", totals.Wall)
+	}
+	want := "Completed 42 run(s), 18.3 GB transferred over 2h11m."
+	if got := totals.Completed(42, "run(s)"); got != want {
+		t.Errorf("Completed() = %q, want %q", got, want)
+	}
+}
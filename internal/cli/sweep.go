@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"iperf-tool/internal/format"
+	"iperf-tool/internal/model"
+)
+
+// DefaultLossThreshold is the UDP loss percentage at which a bitrate sweep
+// stops.
+const DefaultLossThreshold = 2.0
+
+// runLocalTest runs one sweep step; replaced in tests.
+var runLocalTest = LocalTestRunner
+
+// BitrateSweep runs one UDP test per target rate in rates, in order, and
+// stops after the first result whose loss exceeds lossThreshold percent. The
+// results collected so far are returned together with any run error.
+func BitrateSweep(cfg RunnerConfig, rates []string, lossThreshold float64) ([]model.TestResult, error) {
+	var results []model.TestResult
+	for i, rate := range rates {
+		c := cfg
+		c.Bandwidth = rate
+		fmt.Fprintf(c.Stdout(), "=== Sweep %d of %d: -b %s ===\n", i+1, len(rates), rate)
+		result, err := runLocalTest(c)
+		if err != nil {
+			return results, fmt.Errorf("sweep at %s: %w", rate, err)
+		}
+		PrintResult(result, c)
+		results = append(results, *result)
+		if loss := fwdLostPercentOf(*result); loss > lossThreshold {
+			fmt.Fprintf(c.Stdout(), "Loss %.2f%% exceeds %.2f%% at %s, stopping sweep\n", loss, lossThreshold, rate)
+			break
+		}
+	}
+	return results, nil
+}
+
+// SweepSummary formats the per-rate results of BitrateSweep, where
+// results[i] was run at rates[i], and names the saturation point: the first
+// rate whose loss exceeded lossThreshold.
+func SweepSummary(rates []string, results []model.TestResult, lossThreshold float64) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("=== Sweep summary (loss threshold %.2f%%) ===\n", lossThreshold))
+	b.WriteString(fmt.Sprintf("%-10s %12s %10s %10s\n", "Target", "Recv "+format.Units.MbpsUnit(), "Loss %", "Jitter ms"))
+	saturated, lastClean := "", ""
+	for i := range results {
+		r, rate := results[i], ""
+		if i < len(rates) {
+			rate = rates[i]
+		}
+		loss := fwdLostPercentOf(r)
+		mark := ""
+		if loss > lossThreshold {
+			mark = "  <- over threshold"
+			if saturated == "" {
+				saturated = rate
+			}
+		} else if saturated == "" {
+			lastClean = rate
+		}
+		b.WriteString(fmt.Sprintf("%-10s %12.2f %10.2f %10.3f%s\n",
+			rate, format.Units.Scale(r.FwdActualMbps()), loss, r.ActualJitterMs(), mark))
+	}
+	switch {
+	case saturated == "":
+		b.WriteString("Saturation: not reached\n")
+	case lastClean == "":
+		b.WriteString(fmt.Sprintf("Saturation: at %s (no rate stayed under the threshold)\n", saturated))
+	default:
+		b.WriteString(fmt.Sprintf("Saturation: at %s (last clean rate %s)\n", saturated, lastClean))
+	}
+	return b.String()
+}
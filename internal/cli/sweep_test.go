@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"iperf-tool/internal/model"
+)
+
+// stubSweep replaces runLocalTest with a runner that reports loss[rate].
+func stubSweep(t *testing.T, loss map[string]float64) *[]string {
+	t.Helper()
+	orig := runLocalTest
+	t.Cleanup(func() { runLocalTest = orig })
+	var ran []string
+	runLocalTest = func(cfg RunnerConfig) (*model.TestResult, error) {
+		ran = append(ran, cfg.Bandwidth)
+		return &model.TestResult{Protocol: "UDP", SentBps: 1e6, LostPercent: loss[cfg.Bandwidth]}, nil
+	}
+	return &ran
+}
+
+func TestBitrateSweep_StopsAtThreshold(t *testing.T) {
+	ran := stubSweep(t, map[string]float64{"10M": 0, "50M": 0.5, "100M": 3, "500M": 20})
+	rates := []string{"10M", "50M", "100M", "500M"}
+
+	results, err := BitrateSweep(RunnerConfig{Protocol: "udp"}, rates, 2)
+	if err != nil {
+		t.Fatalf("BitrateSweep() error: %v", err)
+	}
+	if len(results) != 3 || strings.Join(*ran, ",") != "10M,50M,100M" {
+		t.Fatalf("ran %v (%d results), want 10M,50M,100M", *ran, len(results))
+	}
+
+	summary := SweepSummary(rates, results, 2)
+	if !strings.Contains(summary, "Saturation: at 100M (last clean rate 50M)") {
+		t.Errorf("summary missing saturation point:\n%s", summary)
+	}
+	if strings.Count(summary, "over threshold") != 1 {
+		t.Errorf("exactly one row should be flagged:\n%s", summary)
+	}
+}
+
+func TestBitrateSweep_NotSaturated(t *testing.T) {
+	ran := stubSweep(t, map[string]float64{"10M": 0, "50M": 1})
+	rates := []string{"10M", "50M"}
+
+	results, err := BitrateSweep(RunnerConfig{Protocol: "udp"}, rates, 2)
+	if err != nil {
+		t.Fatalf("BitrateSweep() error: %v", err)
+	}
+	if len(*ran) != 2 {
+		t.Errorf("ran %v, want every rate", *ran)
+	}
+	if summary := SweepSummary(rates, results, 2); !strings.Contains(summary, "Saturation: not reached") {
+		t.Errorf("summary = %q, want not reached", summary)
+	}
+}
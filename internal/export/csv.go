@@ -15,6 +15,7 @@ var csvHeaders = []string{
 	"date",
 	"time",
 	"measurement_id",
+	"label",
 	"hostname",
 	"local_ip",
 	"server",
@@ -33,28 +34,83 @@ var csvHeaders = []string{
 	"fwd_mb",
 	"rev_mbps",
 	"rev_mb",
+	"download_mbps",
+	"upload_mbps",
+	"efficiency_percent",
+	"fairness_index",
 	"fwd_retransmits",
 	"rev_retransmits",
 	"fwd_jitter_ms",
 	"fwd_lost_packets",
 	"fwd_lost_percent",
 	"fwd_packets",
+	"fwd_pps",
 	"rev_jitter_ms",
 	"rev_lost_packets",
 	"rev_lost_percent",
 	"rev_packets",
+	"rev_pps",
 	"ping_baseline_min_ms",
 	"ping_baseline_avg_ms",
 	"ping_baseline_max_ms",
 	"ping_loaded_min_ms",
 	"ping_loaded_avg_ms",
 	"ping_loaded_max_ms",
+	"ping_baseline_p50_ms",
+	"ping_baseline_p95_ms",
+	"ping_baseline_p99_ms",
+	"ping_loaded_p50_ms",
+	"ping_loaded_p95_ms",
+	"ping_loaded_p99_ms",
+	"ping_baseline_stddev_ms",
+	"ping_loaded_stddev_ms",
+	"bufferbloat_grade",
 	"error",
 }
 
+// DefaultDelimiter is the field separator used by WriteCSV and WriteIntervalLog.
+const DefaultDelimiter = ';'
+
+// ValidateDelimiter reports whether delim is one of the supported CSV
+// separators: comma, semicolon or tab.
+func ValidateDelimiter(delim rune) error {
+	switch delim {
+	case ',', ';', '\t':
+		return nil
+	}
+	return fmt.Errorf("invalid csv delimiter %q (must be ',', ';' or tab)", delim)
+}
+
+// ParseDelimiter converts a user-supplied separator (",", ";", "tab" or a
+// literal/escaped tab) to a rune, rejecting anything else.
+func ParseDelimiter(s string) (rune, error) {
+	switch s {
+	case ",":
+		return ',', nil
+	case ";":
+		return ';', nil
+	case "\t", `\t`, "tab":
+		return '\t', nil
+	}
+	return 0, fmt.Errorf("invalid csv delimiter %q (must be ',', ';' or tab)", s)
+}
+
 // WriteCSV writes test results to a CSV file (semicolon-separated), creating
 // it with headers if it doesn't exist, or appending rows if it does.
 func WriteCSV(path string, results []model.TestResult) error {
+	return WriteCSVWithDelimiter(path, results, DefaultDelimiter)
+}
+
+// WriteCSVWithDelimiter is WriteCSV with a caller-chosen field separator.
+// An existing file whose header differs from the current columns or
+// separator is first moved aside (see rotateStaleCSV) and a fresh log started.
+func WriteCSVWithDelimiter(path string, results []model.TestResult, delim rune) error {
+	if err := ValidateDelimiter(delim); err != nil {
+		return err
+	}
+	if _, err := rotateStaleCSV(path, csvHeaders, delim); err != nil {
+		return err
+	}
 	exists := fileExists(path)
 
 	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
@@ -64,7 +120,7 @@ func WriteCSV(path string, results []model.TestResult) error {
 	defer f.Close()
 
 	w := csv.NewWriter(f)
-	w.Comma = ';'
+	w.Comma = delim
 	defer w.Flush()
 
 	if !exists {
@@ -74,87 +130,127 @@ func WriteCSV(path string, results []model.TestResult) error {
 	}
 
 	for _, r := range results {
-		// Ping fields
-		var baselineMin, baselineAvg, baselineMax string
-		var loadedMin, loadedAvg, loadedMax string
-		if r.PingBaseline != nil {
-			baselineMin = fmt.Sprintf("%.2f", r.PingBaseline.MinMs)
-			baselineAvg = fmt.Sprintf("%.2f", r.PingBaseline.AvgMs)
-			baselineMax = fmt.Sprintf("%.2f", r.PingBaseline.MaxMs)
-		}
-		if r.PingLoaded != nil {
-			loadedMin = fmt.Sprintf("%.2f", r.PingLoaded.MinMs)
-			loadedAvg = fmt.Sprintf("%.2f", r.PingLoaded.AvgMs)
-			loadedMax = fmt.Sprintf("%.2f", r.PingLoaded.MaxMs)
+		if err := w.Write(summaryRow(r)); err != nil {
+			return fmt.Errorf("write csv row: %w", err)
 		}
+	}
 
-		// Actual duration: prefer r.ActualDuration; fall back to last non-omitted interval
-		actualDur := r.ActualDuration
-		if actualDur == 0 && len(r.Intervals) > 0 {
-			for i := len(r.Intervals) - 1; i >= 0; i-- {
-				if !r.Intervals[i].Omitted {
-					actualDur = r.Intervals[i].TimeEnd
-					break
-				}
+	return nil
+}
+
+// summaryRow returns r's fields in csvHeaders order.
+func summaryRow(r model.TestResult) []string {
+	// Ping fields
+	var baselineMin, baselineAvg, baselineMax string
+	var loadedMin, loadedAvg, loadedMax string
+	if r.PingBaseline != nil {
+		baselineMin = fmt.Sprintf("%.2f", r.PingBaseline.MinMs)
+		baselineAvg = fmt.Sprintf("%.2f", r.PingBaseline.AvgMs)
+		baselineMax = fmt.Sprintf("%.2f", r.PingBaseline.MaxMs)
+	}
+	if r.PingLoaded != nil {
+		loadedMin = fmt.Sprintf("%.2f", r.PingLoaded.MinMs)
+		loadedAvg = fmt.Sprintf("%.2f", r.PingLoaded.AvgMs)
+		loadedMax = fmt.Sprintf("%.2f", r.PingLoaded.MaxMs)
+	}
+	baselineP50, baselineP95, baselineP99 := pingPercentilesCSV(r.PingBaseline)
+	loadedP50, loadedP95, loadedP99 := pingPercentilesCSV(r.PingLoaded)
+
+	// Actual duration: prefer r.ActualDuration; fall back to last non-omitted interval
+	actualDur := r.ActualDuration
+	if actualDur == 0 && len(r.Intervals) > 0 {
+		for i := len(r.Intervals) - 1; i >= 0; i-- {
+			if !r.Intervals[i].Omitted {
+				actualDur = r.Intervals[i].TimeEnd
+				break
 			}
 		}
-		actualDurStr := ""
-		if actualDur > 0 {
-			actualDurStr = fmt.Sprintf("%.1f", actualDur)
-		}
+	}
+	actualDurStr := ""
+	if actualDur > 0 {
+		actualDurStr = fmt.Sprintf("%.1f", actualDur)
+	}
 
-		// Block size: empty when 0 (iperf default)
-		blockSize := ""
-		if r.BlockSize > 0 {
-			blockSize = strconv.Itoa(r.BlockSize)
-		}
+	// Block size: empty when 0 (iperf default)
+	blockSize := ""
+	if r.BlockSize > 0 {
+		blockSize = strconv.Itoa(r.BlockSize)
+	}
 
-		row := []string{
-			r.Timestamp.Format("02.01.2006"),
-			r.Timestamp.Format("15:04:05"),
-			r.MeasurementID,
-			r.LocalHostname,
-			r.LocalIP,
-			r.ServerAddr,
-			strconv.Itoa(r.Port),
-			strconv.Itoa(r.Duration),
-			actualDurStr,
-			strconv.Itoa(r.Parallel),
-			r.Protocol,
-			r.Direction,
-			blockSize,
-			r.Bandwidth,
-			r.Congestion,
-			r.Mode,
-			r.IperfVersion,
-			fwdMbpsCSV(r),
-			fwdMbCSV(r),
-			revMbpsCSV(r),
-			fmt.Sprintf("%.2f", r.TotalRevMB()),
-			strconv.Itoa(r.Retransmits),
-			strconv.Itoa(r.ReverseRetransmits),
-			fwdJitter(r),
-			strconv.Itoa(fwdLostPackets(r)),
-			fmt.Sprintf("%.2f", fwdLostPercent(r)),
-			strconv.Itoa(fwdPackets(r)),
-			fmt.Sprintf("%.3f", r.ReverseJitterMs),
-			strconv.Itoa(r.ReverseLostPackets),
-			fmt.Sprintf("%.2f", r.ReverseLostPercent),
-			strconv.Itoa(r.ReversePackets),
-			baselineMin,
-			baselineAvg,
-			baselineMax,
-			loadedMin,
-			loadedAvg,
-			loadedMax,
-			errorField(r),
-		}
-		if err := w.Write(row); err != nil {
-			return fmt.Errorf("write csv row: %w", err)
-		}
+	return []string{
+		r.Timestamp.Format("02.01.2006"),
+		r.Timestamp.Format("15:04:05"),
+		r.MeasurementID,
+		r.Label,
+		r.LocalHostname,
+		r.LocalIP,
+		r.ServerAddr,
+		strconv.Itoa(r.Port),
+		strconv.Itoa(r.Duration),
+		actualDurStr,
+		strconv.Itoa(r.Parallel),
+		r.Protocol,
+		r.Direction,
+		blockSize,
+		r.Bandwidth,
+		r.Congestion,
+		r.Mode,
+		r.IperfVersion,
+		fwdMbpsCSV(r),
+		fwdMbCSV(r),
+		revMbpsCSV(r),
+		fmt.Sprintf("%.2f", r.TotalRevMB()),
+		downloadMbpsCSV(r),
+		uploadMbpsCSV(r),
+		efficiencyCSV(r),
+		fairnessCSV(r),
+		strconv.Itoa(r.Retransmits),
+		strconv.Itoa(r.ReverseRetransmits),
+		fwdJitter(r),
+		strconv.Itoa(fwdLostPackets(r)),
+		fmt.Sprintf("%.2f", fwdLostPercent(r)),
+		strconv.Itoa(fwdPackets(r)),
+		ppsCSV(r, r.AvgPPS()),
+		fmt.Sprintf("%.3f", r.ReverseJitterMs),
+		strconv.Itoa(r.ReverseLostPackets),
+		fmt.Sprintf("%.2f", r.ReverseLostPercent),
+		strconv.Itoa(r.ReversePackets),
+		ppsCSV(r, r.ReverseAvgPPS()),
+		baselineMin,
+		baselineAvg,
+		baselineMax,
+		loadedMin,
+		loadedAvg,
+		loadedMax,
+		baselineP50,
+		baselineP95,
+		baselineP99,
+		loadedP50,
+		loadedP95,
+		loadedP99,
+		pingStdDevCSV(r.PingBaseline),
+		pingStdDevCSV(r.PingLoaded),
+		r.BufferbloatGrade(),
+		errorField(r),
 	}
+}
 
-	return nil
+// pingPercentilesCSV returns the p50/p95/p99 CSV values, empty when ping
+// was not run or no per-reply samples were available.
+func pingPercentilesCSV(p *model.PingResult) (p50, p95, p99 string) {
+	if p == nil || p.P50Ms == 0 {
+		return "", "", ""
+	}
+	return fmt.Sprintf("%.2f", p.P50Ms), fmt.Sprintf("%.2f", p.P95Ms), fmt.Sprintf("%.2f", p.P99Ms)
+}
+
+// pingStdDevCSV returns the stddev CSV value, empty when ping was not run
+// or the summary had no stddev/mdev.
+func pingStdDevCSV(p *model.PingResult) string {
+	if p == nil || p.StdDevMs == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", p.StdDevMs)
 }
 
 // revMbpsCSV returns the rev_mbps CSV value.
@@ -184,6 +280,47 @@ func revMbpsCSV(r model.TestResult) string {
 	return fmt.Sprintf("%.2f", r.ReverseActualMbps())
 }
 
+// downloadMbpsCSV returns the download_mbps CSV value: server→client
+// throughput as seen from the machine running the tool. Reverse tests store
+// it in the forward fields; bidir tests in the reverse ones. Empty for
+// forward-only tests.
+func downloadMbpsCSV(r model.TestResult) string {
+	switch r.Direction {
+	case "Reverse":
+		return fwdMbpsCSV(r)
+	case "Bidirectional":
+		return revMbpsCSV(r)
+	}
+	return ""
+}
+
+// uploadMbpsCSV returns the upload_mbps CSV value: client→server throughput.
+// Empty for reverse-only tests.
+func uploadMbpsCSV(r model.TestResult) string {
+	if r.Direction == "Reverse" {
+		return ""
+	}
+	return fwdMbpsCSV(r)
+}
+
+// efficiencyCSV returns the efficiency_percent CSV value, empty when no
+// bitrate target was set.
+func efficiencyCSV(r model.TestResult) string {
+	if r.TargetTotalMbps <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f", r.EfficiencyPercent())
+}
+
+// fairnessCSV returns the fairness_index CSV value, empty for single-stream
+// tests or when no per-stream data was parsed.
+func fairnessCSV(r model.TestResult) string {
+	if r.Parallel <= 1 || len(r.Streams) <= 1 {
+		return ""
+	}
+	return fmt.Sprintf("%.3f", r.FairnessIndex())
+}
+
 // fwdLostPackets returns the forward lost packets count.
 // In bidir mode, uses FwdLostPackets (server-measured); otherwise LostPackets.
 func fwdLostPackets(r model.TestResult) int {
@@ -211,6 +348,15 @@ func fwdPackets(r model.TestResult) int {
 	return r.Packets
 }
 
+// ppsCSV returns a fwd_pps/rev_pps CSV value, empty for TCP or when the
+// rate is unknown.
+func ppsCSV(r model.TestResult, pps float64) string {
+	if !strings.EqualFold(r.Protocol, "UDP") || pps <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f", pps)
+}
+
 // fwdJitter returns the fwd_jitter_ms CSV value.
 // For interrupted UDP bidir tests where server output was not received, returns "N/A".
 func fwdJitter(r model.TestResult) string {
@@ -254,48 +400,199 @@ var intervalHeaders = []string{
 	"rev_lost_packets",
 	"rev_lost_percent",
 	"rev_jitter_ms",
+	"rev_omitted",
 }
 
-// WriteIntervalLog writes interval measurements to a CSV file (semicolon-separated).
-// Each row is stamped with test parameters from result. The file is created (or
-// truncated) on each call — it holds the intervals for a single test run.
+// WriteIntervalLog appends interval measurements to a CSV file (semicolon-separated).
+// Each row is stamped with test parameters from result, including its
+// measurement_id, so several runs can share one file. The header is written
+// only when the file is new or empty.
 // In bidirectional mode result.ReverseIntervals should be populated; pass an empty
 // slice for normal/UDP mode.
 func WriteIntervalLog(path string, result *model.TestResult) error {
-	exists := fileExists(path)
+	return WriteIntervalLogWithDelimiter(path, result, DefaultDelimiter)
+}
 
+// WriteIntervalLogWithDelimiter is WriteIntervalLog with a caller-chosen
+// field separator.
+func WriteIntervalLogWithDelimiter(path string, result *model.TestResult, delim rune) error {
+	return WriteIntervalLogWithOptions(path, result, IntervalLogOptions{Delimiter: delim})
+}
+
+// IntervalLogOptions controls optional WriteIntervalLog behaviour.
+type IntervalLogOptions struct {
+	Delimiter   rune // field separator; 0 = DefaultDelimiter
+	SkipOmitted bool // leave out warm-up rows where every direction is omitted
+}
+
+// WriteIntervalLogWithOptions is WriteIntervalLog with the given options.
+func WriteIntervalLogWithOptions(path string, result *model.TestResult, opts IntervalLogOptions) error {
+	delim := opts.Delimiter
+	if delim == 0 {
+		delim = DefaultDelimiter
+	}
+	if err := ValidateDelimiter(delim); err != nil {
+		return err
+	}
+	if _, err := rotateStaleCSV(path, intervalHeaders, delim); err != nil {
+		return err
+	}
 	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		return fmt.Errorf("open interval log: %w", err)
 	}
 	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat interval log: %w", err)
+	}
 
 	w := csv.NewWriter(f)
-	w.Comma = ';'
+	w.Comma = delim
 	defer w.Flush()
 
-	if !exists {
+	if info.Size() == 0 {
 		if err := w.Write(intervalHeaders); err != nil {
 			return fmt.Errorf("write interval headers: %w", err)
 		}
 	}
 
+	for _, row := range intervalRows(result, opts.SkipOmitted) {
+		if err := w.Write(row); err != nil {
+			return fmt.Errorf("write interval row: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// IntervalLogWriter appends interval rows to an interval log while a test
+// runs, so a long test does not have to keep every interval for the
+// end-of-run write. Rows carry the test metadata of the head result given
+// to OpenIntervalLogWriter.
+type IntervalLogWriter struct {
+	f    *os.File
+	w    *csv.Writer
+	head model.TestResult
+	opts IntervalLogOptions
+}
+
+// OpenIntervalLogWriter opens path for appending like
+// WriteIntervalLogWithOptions, writing the header row to a new file.
+func OpenIntervalLogWriter(path string, head model.TestResult, opts IntervalLogOptions) (*IntervalLogWriter, error) {
+	if opts.Delimiter == 0 {
+		opts.Delimiter = DefaultDelimiter
+	}
+	if err := ValidateDelimiter(opts.Delimiter); err != nil {
+		return nil, err
+	}
+	if _, err := rotateStaleCSV(path, intervalHeaders, opts.Delimiter); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open interval log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("stat interval log: %w", err)
+	}
+
+	w := csv.NewWriter(f)
+	w.Comma = opts.Delimiter
+	if info.Size() == 0 {
+		if err := w.Write(intervalHeaders); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("write interval headers: %w", err)
+		}
+	}
+	head.Intervals, head.ReverseIntervals = nil, nil
+	return &IntervalLogWriter{f: f, w: w, head: head, opts: opts}, nil
+}
+
+// Write appends the row for one paired forward/reverse interval and flushes
+// it to disk. Either side may be nil. Intervals inside the head's warm-up
+// (OmitSeconds) are flagged as omitted, since live intervals have not been
+// marked yet.
+func (l *IntervalLogWriter) Write(fwd, rev *model.IntervalResult) error {
+	omit := float64(l.head.OmitSeconds)
+	live := func(iv *model.IntervalResult) []model.IntervalResult {
+		if iv == nil {
+			return nil
+		}
+		c := *iv
+		if omit > 0 && c.TimeEnd <= omit+0.005 {
+			c.Omitted = true
+		}
+		return []model.IntervalResult{c}
+	}
+	r := l.head
+	r.Intervals, r.ReverseIntervals = live(fwd), live(rev)
+	for _, row := range intervalRows(&r, l.opts.SkipOmitted) {
+		if err := l.w.Write(row); err != nil {
+			return fmt.Errorf("write interval row: %w", err)
+		}
+	}
+	l.w.Flush()
+	return l.w.Error()
+}
+
+// Close flushes and closes the log.
+func (l *IntervalLogWriter) Close() error {
+	l.w.Flush()
+	if err := l.w.Error(); err != nil {
+		l.f.Close()
+		return fmt.Errorf("write interval log: %w", err)
+	}
+	return l.f.Close()
+}
+
+// intervalRows returns one row per interval of result in intervalHeaders
+// order, pairing each forward interval with its reverse counterpart.
+func intervalRows(result *model.TestResult, skipOmitted bool) [][]string {
 	blockSize := ""
 	if result.BlockSize > 0 {
 		blockSize = strconv.Itoa(result.BlockSize)
 	}
 
 	wallTime := result.Timestamp
+	tsLayout := "2006-01-02T15:04:05"
+	if model.HasSubSecondIntervals(result.Intervals) || model.HasSubSecondIntervals(result.ReverseIntervals) {
+		tsLayout = "2006-01-02T15:04:05.000"
+	}
 
-	for i, iv := range result.Intervals {
-		omitted := "0"
-		if iv.Omitted {
-			omitted = "1"
+	// A bidir direction that stalled or ended early has fewer intervals; the
+	// rows run to the longer side and leave the shorter side's columns empty.
+	n := max(len(result.Intervals), len(result.ReverseIntervals))
+	var rows [][]string
+	for i := 0; i < n; i++ {
+		var fwd, rev *model.IntervalResult
+		if i < len(result.Intervals) {
+			fwd = &result.Intervals[i]
 		}
-
-		revBw, revMB, revRtr, revPkts, revLost, revLostPct, revJitter := "", "", "", "", "", "", ""
 		if i < len(result.ReverseIntervals) {
-			rev := result.ReverseIntervals[i]
+			rev = &result.ReverseIntervals[i]
+		}
+		if skipOmitted && (fwd == nil || fwd.Omitted) && (rev == nil || rev.Omitted) {
+			continue
+		}
+
+		start := 0.0
+		fwdBw, fwdMB, fwdRtr, fwdPkts, fwdOmitted := "", "", "", "", ""
+		if fwd != nil {
+			start = fwd.TimeStart
+			fwdBw = fmt.Sprintf("%.2f", fwd.BandwidthMbps())
+			fwdMB = fmt.Sprintf("%.2f", fwd.TransferMB())
+			fwdRtr = strconv.Itoa(fwd.Retransmits)
+			fwdPkts = strconv.Itoa(fwd.Packets)
+			fwdOmitted = omittedFlag(fwd.Omitted)
+		}
+		revBw, revMB, revRtr, revPkts, revLost, revLostPct, revJitter, revOmitted := "", "", "", "", "", "", "", ""
+		if rev != nil {
+			if fwd == nil {
+				start = rev.TimeStart
+			}
 			revBw = fmt.Sprintf("%.2f", rev.BandwidthMbps())
 			revMB = fmt.Sprintf("%.2f", rev.TransferMB())
 			revRtr = strconv.Itoa(rev.Retransmits)
@@ -303,11 +600,12 @@ func WriteIntervalLog(path string, result *model.TestResult) error {
 			revLost = strconv.Itoa(rev.LostPackets)
 			revLostPct = fmt.Sprintf("%.2f", rev.LostPercent)
 			revJitter = fmt.Sprintf("%.3f", rev.JitterMs)
+			revOmitted = omittedFlag(rev.Omitted)
 		}
 
-		row := []string{
+		rows = append(rows, []string{
 			result.MeasurementID,
-			wallTime.Add(time.Duration(iv.TimeStart * float64(time.Second))).Format("2006-01-02T15:04:05"),
+			wallTime.Add(time.Duration(start * float64(time.Second))).Format(tsLayout),
 			result.Protocol,
 			strconv.Itoa(result.Parallel),
 			result.Direction,
@@ -315,17 +613,17 @@ func WriteIntervalLog(path string, result *model.TestResult) error {
 			result.Bandwidth,
 			result.ServerAddr,
 			strconv.Itoa(result.Port),
-			fmt.Sprintf("%.2f", iv.BandwidthMbps()),
-			fmt.Sprintf("%.2f", iv.TransferMB()),
-			strconv.Itoa(iv.Retransmits),
-			strconv.Itoa(iv.Packets),
-			omitted,
-			revBw, revMB, revRtr, revPkts, revLost, revLostPct, revJitter,
-		}
-		if err := w.Write(row); err != nil {
-			return fmt.Errorf("write interval row: %w", err)
-		}
+			fwdBw, fwdMB, fwdRtr, fwdPkts, fwdOmitted,
+			revBw, revMB, revRtr, revPkts, revLost, revLostPct, revJitter, revOmitted,
+		})
 	}
+	return rows
+}
 
-	return nil
+// omittedFlag returns the 0/1 value of an interval's omitted column.
+func omittedFlag(omitted bool) string {
+	if omitted {
+		return "1"
+	}
+	return "0"
 }
@@ -0,0 +1,64 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CSVHeaderMatches reports whether the summary log at path starts with the
+// current column set, whatever its separator. A missing or empty file
+// matches, since writing to it starts a fresh header.
+func CSVHeaderMatches(path string) (bool, error) {
+	line, err := firstLine(path)
+	if err != nil || line == "" {
+		return err == nil, err
+	}
+	r := csv.NewReader(strings.NewReader(line))
+	r.Comma = sniffDelimiter(line)
+	fields, err := r.Read()
+	if err != nil {
+		return false, fmt.Errorf("parse csv header: %w", err)
+	}
+	return strings.Join(fields, "\x00") == strings.Join(csvHeaders, "\x00"), nil
+}
+
+// firstLine returns the first line of path without its line ending; "" for
+// a missing or empty file.
+func firstLine(path string) (string, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("open csv file: %w", err)
+	}
+	defer f.Close()
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && line == "" {
+		return "", nil
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// rotateStaleCSV moves an existing CSV at path aside when its header line is
+// not headers joined by delim, so new rows never land under an older column
+// layout or separator. The old file is kept as <name>_pre-<mtime>.csv and
+// its new path returned; "" when nothing was moved.
+func rotateStaleCSV(path string, headers []string, delim rune) (string, error) {
+	line, err := firstLine(path)
+	if err != nil || line == "" || line == strings.Join(headers, string(delim)) {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("stat csv file: %w", err)
+	}
+	stale := strings.TrimSuffix(path, ".csv") + "_pre-" + info.ModTime().Format("20060102-150405") + ".csv"
+	if err := os.Rename(path, stale); err != nil {
+		return "", fmt.Errorf("move stale csv aside: %w", err)
+	}
+	return stale, nil
+}
@@ -0,0 +1,84 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+func TestCSVHeaderMatches(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"missing", filepath.Join(dir, "none.csv"), true},
+		{"empty", write("empty.csv", ""), true},
+		{"current", write("current.csv", strings.Join(csvHeaders, ";")+"\n"), true},
+		{"current comma", write("comma.csv", strings.Join(csvHeaders, ",")+"\r\n"), true},
+		{"stale", write("stale.csv", "date;time;server;fwd_mbps\n01.03.2026;09:00:00;10.0.0.1;940.00\n"), false},
+	}
+	for _, tt := range tests {
+		got, err := CSVHeaderMatches(tt.path)
+		if err != nil {
+			t.Errorf("%s: CSVHeaderMatches() error: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: CSVHeaderMatches() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCSV_RotatesStaleHeader(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results_log.csv")
+	old := "date;time;server;fwd_mbps\n01.03.2026;09:00:00;10.0.0.1;940.00\n"
+	if err := os.WriteFile(path, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2026, 3, 1, 9, 0, 5, 0, time.Local)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	r := model.TestResult{Timestamp: mtime, ServerAddr: "10.0.0.1", Port: 5201, Protocol: "TCP", SentBps: 940e6}
+	if err := WriteCSV(path, []model.TestResult{r}); err != nil {
+		t.Fatalf("WriteCSV() error: %v", err)
+	}
+
+	if ok, err := CSVHeaderMatches(path); !ok || err != nil {
+		t.Errorf("new log header does not match (err %v)", err)
+	}
+	data, _ := os.ReadFile(path)
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 {
+		t.Errorf("new log has %d lines, want header + 1 row", len(lines))
+	}
+	kept, err := os.ReadFile(filepath.Join(dir, "results_log_pre-20260301-090005.csv"))
+	if err != nil {
+		t.Fatalf("stale log was not kept: %v", err)
+	}
+	if string(kept) != old {
+		t.Errorf("stale log changed:\n%s", kept)
+	}
+
+	// A second write appends to the fresh log instead of rotating again
+	if err := WriteCSV(path, []model.TestResult{r}); err != nil {
+		t.Fatalf("second WriteCSV() error: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 3 {
+		t.Errorf("log has %d lines after second write, want 3", len(lines))
+	}
+}
@@ -0,0 +1,154 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+// ReadCSVLog parses an append log written by WriteCSV back into results.
+// Columns are matched by header name, so logs written before columns were
+// added still load; the separator is detected from the header line.
+//
+// Only summary fields are restored. Throughput lands in the "best available"
+// fields (FwdReceivedBps, ReverseReceivedBps, BytesReceived,
+// ReverseBytesReceived) so the model accessors return the logged values.
+// "N/A" cells are read as zero.
+func ReadCSVLog(path string) ([]model.TestResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open csv log: %w", err)
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	first, err := br.Peek(4096)
+	if err != nil && len(first) == 0 {
+		return nil, fmt.Errorf("read csv log: empty file")
+	}
+
+	r := csv.NewReader(br)
+	r.Comma = sniffDelimiter(string(first))
+	r.FieldsPerRecord = -1 // tolerate rows from older/newer column sets
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse csv log: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	col := make(map[string]int, len(records[0]))
+	for i, h := range records[0] {
+		col[strings.TrimSpace(h)] = i
+	}
+	if _, ok := col["server"]; !ok {
+		return nil, fmt.Errorf("parse csv log: missing server column, not a results log")
+	}
+
+	results := make([]model.TestResult, 0, len(records)-1)
+	for _, rec := range records[1:] {
+		results = append(results, resultFromRecord(rec, col))
+	}
+	return results, nil
+}
+
+// sniffDelimiter picks the separator used in the header line.
+func sniffDelimiter(data string) rune {
+	header, _, _ := strings.Cut(data, "\n")
+	best, bestN := DefaultDelimiter, 0
+	for _, d := range []rune{';', ',', '\t'} {
+		if n := strings.Count(header, string(d)); n > bestN {
+			best, bestN = d, n
+		}
+	}
+	return best
+}
+
+func resultFromRecord(rec []string, col map[string]int) model.TestResult {
+	get := func(name string) string {
+		if i, ok := col[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+	num := func(name string) float64 {
+		v, _ := strconv.ParseFloat(get(name), 64)
+		return v
+	}
+	integer := func(name string) int {
+		v, _ := strconv.Atoi(get(name))
+		return v
+	}
+
+	r := model.TestResult{
+		MeasurementID:        get("measurement_id"),
+		Label:                get("label"),
+		LocalHostname:        get("hostname"),
+		LocalIP:              get("local_ip"),
+		ServerAddr:           get("server"),
+		Port:                 integer("port"),
+		Duration:             integer("test_duration"),
+		ActualDuration:       num("actual_duration"),
+		Parallel:             integer("streams"),
+		Protocol:             get("protocol"),
+		Direction:            get("direction"),
+		BlockSize:            integer("block_size"),
+		Bandwidth:            get("stream_bandwidth"),
+		Congestion:           get("congestion"),
+		Mode:                 get("mode"),
+		IperfVersion:         get("iperf_version"),
+		FwdReceivedBps:       num("fwd_mbps") * 1_000_000,
+		BytesReceived:        int64(num("fwd_mb") * 1_000_000),
+		ReverseReceivedBps:   num("rev_mbps") * 1_000_000,
+		ReverseBytesReceived: int64(num("rev_mb") * 1_000_000),
+		Retransmits:          integer("fwd_retransmits"),
+		ReverseRetransmits:   integer("rev_retransmits"),
+		JitterMs:             num("fwd_jitter_ms"),
+		LostPackets:          integer("fwd_lost_packets"),
+		LostPercent:          num("fwd_lost_percent"),
+		Packets:              integer("fwd_packets"),
+		ReverseJitterMs:      num("rev_jitter_ms"),
+		ReverseLostPackets:   integer("rev_lost_packets"),
+		ReverseLostPercent:   num("rev_lost_percent"),
+		ReversePackets:       integer("rev_packets"),
+	}
+
+	if ts, err := time.ParseInLocation("02.01.2006 15:04:05", get("date")+" "+get("time"), time.Local); err == nil {
+		r.Timestamp = ts
+	}
+
+	if e := get("error"); e == "Interrupted" {
+		r.Interrupted = true
+	} else {
+		r.Error = e
+	}
+
+	r.PingBaseline = pingFromRecord(get, num, "ping_baseline_")
+	r.PingLoaded = pingFromRecord(get, num, "ping_loaded_")
+	return r
+}
+
+// pingFromRecord rebuilds a PingResult from the prefixed ping columns, or
+// returns nil when the row has no ping data.
+func pingFromRecord(get func(string) string, num func(string) float64, prefix string) *model.PingResult {
+	if get(prefix+"avg_ms") == "" {
+		return nil
+	}
+	return &model.PingResult{
+		MinMs:    num(prefix + "min_ms"),
+		AvgMs:    num(prefix + "avg_ms"),
+		MaxMs:    num(prefix + "max_ms"),
+		P50Ms:    num(prefix + "p50_ms"),
+		P95Ms:    num(prefix + "p95_ms"),
+		P99Ms:    num(prefix + "p99_ms"),
+		StdDevMs: num(prefix + "stddev_ms"),
+	}
+}
@@ -0,0 +1,122 @@
+package export
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+func TestReadCSVLog_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results_log.csv")
+
+	written := []model.TestResult{
+		{
+			Timestamp:          time.Date(2026, 3, 1, 9, 30, 15, 0, time.Local),
+			MeasurementID:      "20260301-001",
+			ServerAddr:         "192.168.1.1",
+			Port:               5201,
+			Duration:           10,
+			ActualDuration:     10.0,
+			Parallel:           4,
+			Protocol:           "TCP",
+			Direction:          "Bidirectional",
+			BlockSize:          131072,
+			FwdReceivedBps:     941_500_000,
+			ReverseReceivedBps: 512_250_000,
+			Retransmits:        12,
+			ReverseRetransmits: 3,
+			PingBaseline:       &model.PingResult{MinMs: 0.5, AvgMs: 0.75, MaxMs: 1.25},
+			PingLoaded:         &model.PingResult{MinMs: 4, AvgMs: 9.5, MaxMs: 30, P50Ms: 8, P95Ms: 25, P99Ms: 29.5},
+		},
+		{
+			Timestamp:  time.Date(2026, 3, 1, 9, 31, 0, 0, time.Local),
+			ServerAddr: "192.168.1.1",
+			Port:       5201,
+			Protocol:   "UDP",
+			Direction:  "Forward",
+			Error:      "connect failed: Connection refused",
+		},
+	}
+	if err := WriteCSV(path, written[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteCSV(path, written[1:]); err != nil { // append path
+		t.Fatal(err)
+	}
+
+	got, err := ReadCSVLog(path)
+	if err != nil {
+		t.Fatalf("ReadCSVLog() error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(got))
+	}
+
+	r := got[0]
+	if !r.Timestamp.Equal(written[0].Timestamp) {
+		t.Errorf("Timestamp = %v, want %v", r.Timestamp, written[0].Timestamp)
+	}
+	if r.MeasurementID != "20260301-001" || r.ServerAddr != "192.168.1.1" || r.Port != 5201 {
+		t.Errorf("identity fields = %q %q %d", r.MeasurementID, r.ServerAddr, r.Port)
+	}
+	if r.Parallel != 4 || r.Protocol != "TCP" || r.Direction != "Bidirectional" || r.BlockSize != 131072 {
+		t.Errorf("config fields = %d %q %q %d", r.Parallel, r.Protocol, r.Direction, r.BlockSize)
+	}
+	if math.Abs(r.FwdActualMbps()-941.5) > 0.01 {
+		t.Errorf("FwdActualMbps() = %f, want 941.5", r.FwdActualMbps())
+	}
+	if math.Abs(r.ReverseActualMbps()-512.25) > 0.01 {
+		t.Errorf("ReverseActualMbps() = %f, want 512.25", r.ReverseActualMbps())
+	}
+	if r.Retransmits != 12 || r.ReverseRetransmits != 3 {
+		t.Errorf("retransmits = %d/%d, want 12/3", r.Retransmits, r.ReverseRetransmits)
+	}
+	if r.PingBaseline == nil || r.PingBaseline.AvgMs != 0.75 {
+		t.Errorf("PingBaseline = %+v", r.PingBaseline)
+	}
+	if r.PingLoaded == nil || r.PingLoaded.P95Ms != 25 {
+		t.Errorf("PingLoaded = %+v", r.PingLoaded)
+	}
+
+	if got[1].Error != "connect failed: Connection refused" {
+		t.Errorf("Error = %q", got[1].Error)
+	}
+	if got[1].PingBaseline != nil {
+		t.Error("row without ping data should have nil PingBaseline")
+	}
+	if got[1].FwdActualMbps() != 0 {
+		t.Errorf("N/A fwd_mbps should read as 0, got %f", got[1].FwdActualMbps())
+	}
+}
+
+func TestReadCSVLog_CommaDelimiter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results_log.csv")
+	in := []model.TestResult{{ServerAddr: "10.0.0.1", Protocol: "TCP", FwdReceivedBps: 100_000_000}}
+	if err := WriteCSVWithDelimiter(path, in, ','); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadCSVLog(path)
+	if err != nil {
+		t.Fatalf("ReadCSVLog() error: %v", err)
+	}
+	if len(got) != 1 || got[0].ServerAddr != "10.0.0.1" || got[0].FwdActualMbps() != 100 {
+		t.Errorf("unexpected result: %+v", got)
+	}
+}
+
+func TestReadCSVLog_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := ReadCSVLog(filepath.Join(dir, "missing.csv")); err == nil {
+		t.Error("expected error for missing file")
+	}
+
+	notLog := filepath.Join(dir, "other.csv")
+	os.WriteFile(notLog, []byte("a;b;c\n1;2;3\n"), 0644)
+	if _, err := ReadCSVLog(notLog); err == nil {
+		t.Error("expected error for a CSV without the results columns")
+	}
+}
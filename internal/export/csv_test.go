@@ -1,6 +1,7 @@
 package export
 
 import (
+	"encoding/csv"
 	"os"
 	"path/filepath"
 	"strings"
@@ -230,6 +231,72 @@ func TestWriteIntervalLog(t *testing.T) {
 	}
 }
 
+// Repeat runs share one interval log: rows accumulate, the header appears
+// once, and measurement_id tells the runs apart.
+func TestWriteIntervalLog_AppendsRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "intervals.csv")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err) // an empty pre-existing file still gets the header
+	}
+	for i, id := range []string{"20260218-143200-01", "20260218-143300-01"} {
+		result := &model.TestResult{
+			Timestamp:     time.Date(2026, 2, 18, 14, 32+i, 0, 0, time.UTC),
+			MeasurementID: id,
+			Protocol:      "TCP",
+			Intervals: []model.IntervalResult{
+				{TimeStart: 0, TimeEnd: 1, BandwidthBps: 940_000_000},
+				{TimeStart: 1, TimeEnd: 2, BandwidthBps: 920_000_000},
+			},
+		}
+		if err := WriteIntervalLog(path, result); err != nil {
+			t.Fatalf("WriteIntervalLog() run %d error: %v", i+1, err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected header + 4 rows, got %d lines:\n%s", len(lines), data)
+	}
+	if strings.Count(string(data), "measurement_id;") != 1 {
+		t.Errorf("header should appear once:\n%s", data)
+	}
+	for i, want := range []string{"20260218-143200-01", "20260218-143200-01", "20260218-143300-01", "20260218-143300-01"} {
+		if !strings.HasPrefix(lines[i+1], want+";") {
+			t.Errorf("row %d = %s, want measurement_id %s", i+1, lines[i+1], want)
+		}
+	}
+}
+
+func TestWriteIntervalLog_SubSecond(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "intervals.csv")
+
+	result := &model.TestResult{
+		Timestamp:  time.Date(2026, 2, 18, 14, 32, 0, 0, time.UTC),
+		ServerAddr: "192.168.1.1",
+		Protocol:   "TCP",
+		Intervals: []model.IntervalResult{
+			{TimeStart: 0, TimeEnd: 0.5, BandwidthBps: 900_000_000},
+			{TimeStart: 0.5, TimeEnd: 1.0, BandwidthBps: 910_000_000},
+		},
+	}
+	if err := WriteIntervalLog(path, result); err != nil {
+		t.Fatalf("WriteIntervalLog() error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if !strings.Contains(lines[1], "2026-02-18T14:32:00.000") {
+		t.Errorf("row 1 should carry millisecond wall_time: %s", lines[1])
+	}
+	if !strings.Contains(lines[2], "2026-02-18T14:32:00.500") {
+		t.Errorf("row 2 should start half a second later: %s", lines[2])
+	}
+}
+
 func TestWriteIntervalLog_Bidir(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "intervals_bidir.csv")
@@ -275,6 +342,75 @@ func TestWriteIntervalLog_Bidir(t *testing.T) {
 	}
 }
 
+func TestWriteIntervalLog_Omitted(t *testing.T) {
+	result := &model.TestResult{
+		Timestamp:  time.Date(2026, 2, 18, 14, 32, 0, 0, time.UTC),
+		ServerAddr: "192.168.1.1",
+		Port:       5201,
+		Protocol:   "TCP",
+		Direction:  "Bidirectional",
+		Intervals: []model.IntervalResult{
+			{TimeStart: 0, TimeEnd: 1, BandwidthBps: 100_000_000, Omitted: true},
+			{TimeStart: 1, TimeEnd: 2, BandwidthBps: 940_000_000},
+		},
+		ReverseIntervals: []model.IntervalResult{
+			{TimeStart: 0, TimeEnd: 1, BandwidthBps: 50_000_000, Omitted: true},
+			{TimeStart: 1, TimeEnd: 2, BandwidthBps: 400_000_000},
+		},
+	}
+
+	readRows := func(t *testing.T, path string) []map[string]string {
+		t.Helper()
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		r := csv.NewReader(f)
+		r.Comma = DefaultDelimiter
+		records, err := r.ReadAll()
+		if err != nil {
+			t.Fatalf("re-read: %v", err)
+		}
+		var rows []map[string]string
+		for _, rec := range records[1:] {
+			row := map[string]string{}
+			for i, h := range records[0] {
+				row[h] = rec[i]
+			}
+			rows = append(rows, row)
+		}
+		return rows
+	}
+
+	path := filepath.Join(t.TempDir(), "intervals.csv")
+	if err := WriteIntervalLog(path, result); err != nil {
+		t.Fatalf("WriteIntervalLog() error: %v", err)
+	}
+	rows := readRows(t, path)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	if rows[0]["fwd_omitted"] != "1" || rows[0]["rev_omitted"] != "1" {
+		t.Errorf("first row omitted flags = %q/%q, want 1/1", rows[0]["fwd_omitted"], rows[0]["rev_omitted"])
+	}
+	if rows[1]["fwd_omitted"] != "0" || rows[1]["rev_omitted"] != "0" {
+		t.Errorf("second row omitted flags = %q/%q, want 0/0", rows[1]["fwd_omitted"], rows[1]["rev_omitted"])
+	}
+
+	path = filepath.Join(t.TempDir(), "intervals_skip.csv")
+	if err := WriteIntervalLogWithOptions(path, result, IntervalLogOptions{SkipOmitted: true}); err != nil {
+		t.Fatalf("WriteIntervalLogWithOptions() error: %v", err)
+	}
+	rows = readRows(t, path)
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows with SkipOmitted, want 1", len(rows))
+	}
+	if rows[0]["fwd_bandwidth_mbps"] != "940.00" {
+		t.Errorf("kept row fwd_bandwidth_mbps = %q, want 940.00", rows[0]["fwd_bandwidth_mbps"])
+	}
+}
+
 func TestWriteIntervalLog_UDP(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "intervals_udp.csv")
@@ -376,6 +512,37 @@ func TestWriteCSV_UDP(t *testing.T) {
 	}
 }
 
+func TestWriteCSV_Efficiency(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.csv")
+	results := []model.TestResult{
+		{ServerAddr: "10.0.0.1", Protocol: "UDP", FwdReceivedBps: 47_000_000, TargetTotalMbps: 50},
+		{ServerAddr: "10.0.0.1", Protocol: "TCP", SentBps: 940_000_000},
+	}
+	if err := WriteCSV(path, results); err != nil {
+		t.Fatalf("WriteCSV() error: %v", err)
+	}
+	f, _ := os.Open(path)
+	r := csv.NewReader(f)
+	r.Comma = DefaultDelimiter
+	records, err := r.ReadAll()
+	f.Close()
+	if err != nil || len(records) != 3 {
+		t.Fatalf("re-read: %v (%d rows)", err, len(records))
+	}
+	col := -1
+	for i, h := range records[0] {
+		if h == "efficiency_percent" {
+			col = i
+		}
+	}
+	if col < 0 {
+		t.Fatal("missing efficiency_percent header")
+	}
+	if records[1][col] != "94.0" || records[2][col] != "" {
+		t.Errorf("efficiency_percent = %q/%q, want 94.0 and empty", records[1][col], records[2][col])
+	}
+}
+
 func TestWriteCSV_WithPing(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "results.csv")
@@ -405,7 +572,7 @@ func TestWriteCSV_WithPing(t *testing.T) {
 
 	content := string(data)
 
-	for _, h := range []string{"ping_baseline_min_ms", "ping_baseline_avg_ms", "ping_baseline_max_ms", "ping_loaded_min_ms", "ping_loaded_avg_ms", "ping_loaded_max_ms"} {
+	for _, h := range []string{"ping_baseline_min_ms", "ping_baseline_avg_ms", "ping_baseline_max_ms", "ping_loaded_min_ms", "ping_loaded_avg_ms", "ping_loaded_max_ms", "bufferbloat_grade"} {
 		if !strings.Contains(content, h) {
 			t.Errorf("CSV should contain %s header", h)
 		}
@@ -435,6 +602,56 @@ func TestWriteCSV_WithPing(t *testing.T) {
 	if !strings.Contains(lines[1], "50.00") {
 		t.Errorf("row should contain loaded max: %s", lines[1])
 	}
+	// Bufferbloat: +8 ms average increase = B
+	if !strings.Contains(lines[1], ";B;") {
+		t.Errorf("row should contain bufferbloat grade B: %s", lines[1])
+	}
+}
+
+func TestWriteCSV_PingPercentiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.csv")
+
+	results := []model.TestResult{{
+		ServerAddr:   "192.168.1.1",
+		Protocol:     "TCP",
+		PingBaseline: &model.PingResult{AvgMs: 2.0},
+		PingLoaded:   &model.PingResult{AvgMs: 10.0, StdDevMs: 4.5, P50Ms: 9.5, P95Ms: 30.25, P99Ms: 48.0},
+	}}
+	if err := WriteCSV(path, results); err != nil {
+		t.Fatalf("WriteCSV() error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	header := strings.Split(lines[0], ";")
+	row := strings.Split(lines[1], ";")
+	col := func(name string) string {
+		for i, h := range header {
+			if h == name {
+				return row[i]
+			}
+		}
+		t.Fatalf("missing column %s", name)
+		return ""
+	}
+
+	if got := col("ping_loaded_p95_ms"); got != "30.25" {
+		t.Errorf("ping_loaded_p95_ms = %q, want 30.25", got)
+	}
+	if got := col("ping_loaded_p99_ms"); got != "48.00" {
+		t.Errorf("ping_loaded_p99_ms = %q, want 48.00", got)
+	}
+	// Summary-only baseline leaves percentiles blank
+	if got := col("ping_baseline_p50_ms"); got != "" {
+		t.Errorf("ping_baseline_p50_ms = %q, want empty", got)
+	}
+	if got := col("ping_loaded_stddev_ms"); got != "4.50" {
+		t.Errorf("ping_loaded_stddev_ms = %q, want 4.50", got)
+	}
+	if got := col("ping_baseline_stddev_ms"); got != "" {
+		t.Errorf("ping_baseline_stddev_ms = %q, want empty", got)
+	}
 }
 
 func TestWriteCSV_NewColumns(t *testing.T) {
@@ -568,6 +785,114 @@ func TestWriteCSV_Bidir(t *testing.T) {
 	}
 }
 
+func TestWriteCSV_Label(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.csv")
+	results := []model.TestResult{{ServerAddr: "10.0.0.1", Protocol: "TCP", Label: "office-wifi"}}
+	if err := WriteCSV(path, results); err != nil {
+		t.Fatalf("WriteCSV() error: %v", err)
+	}
+	back, err := ReadCSVLog(path)
+	if err != nil || len(back) != 1 {
+		t.Fatalf("ReadCSVLog() = %d results, err %v", len(back), err)
+	}
+	if back[0].Label != "office-wifi" {
+		t.Errorf("label round-trip = %q, want office-wifi", back[0].Label)
+	}
+}
+
+func TestWriteCSV_PPS(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.csv")
+	results := []model.TestResult{
+		{Protocol: "UDP", Direction: "Bidirectional", FwdPackets: 8930, ReversePackets: 4470, ActualDuration: 10},
+		{Protocol: "TCP", Packets: 100, ActualDuration: 10},
+	}
+	if err := WriteCSV(path, results); err != nil {
+		t.Fatalf("WriteCSV() error: %v", err)
+	}
+	f, _ := os.Open(path)
+	r := csv.NewReader(f)
+	r.Comma = DefaultDelimiter
+	records, err := r.ReadAll()
+	f.Close()
+	if err != nil || len(records) != 3 {
+		t.Fatalf("re-read: %v (%d rows)", err, len(records))
+	}
+	fwdCol, revCol := -1, -1
+	for i, h := range records[0] {
+		switch h {
+		case "fwd_pps":
+			fwdCol = i
+		case "rev_pps":
+			revCol = i
+		}
+	}
+	if fwdCol < 0 || revCol < 0 {
+		t.Fatal("missing fwd_pps/rev_pps headers")
+	}
+	if records[1][fwdCol] != "893" || records[1][revCol] != "447" {
+		t.Errorf("UDP pps = %q/%q, want 893/447", records[1][fwdCol], records[1][revCol])
+	}
+	if records[2][fwdCol] != "" || records[2][revCol] != "" {
+		t.Errorf("TCP pps = %q/%q, want empty", records[2][fwdCol], records[2][revCol])
+	}
+}
+
+func TestWriteCSV_DownloadUpload(t *testing.T) {
+	tests := []struct {
+		name         string
+		r            model.TestResult
+		wantDownload string
+		wantUpload   string
+	}{
+		{
+			name:       "forward",
+			r:          model.TestResult{Protocol: "TCP", Direction: "Forward", SentBps: 940_000_000},
+			wantUpload: "940.00",
+		},
+		{
+			name:         "reverse",
+			r:            model.TestResult{Protocol: "TCP", Direction: "Reverse", SentBps: 610_000_000, FwdReceivedBps: 600_000_000},
+			wantDownload: "600.00",
+		},
+		{
+			name: "bidir",
+			r: model.TestResult{Protocol: "TCP", Direction: "Bidirectional",
+				SentBps: 400_000_000, ReverseSentBps: 480_000_000},
+			wantDownload: "480.00",
+			wantUpload:   "400.00",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "results.csv")
+			tt.r.Timestamp = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+			tt.r.ServerAddr = "192.168.1.1"
+			if err := WriteCSV(path, []model.TestResult{tt.r}); err != nil {
+				t.Fatalf("WriteCSV() error: %v", err)
+			}
+
+			f, _ := os.Open(path)
+			r := csv.NewReader(f)
+			r.Comma = DefaultDelimiter
+			records, err := r.ReadAll()
+			f.Close()
+			if err != nil || len(records) != 2 {
+				t.Fatalf("re-read: %v (%d rows)", err, len(records))
+			}
+			col := map[string]string{}
+			for i, h := range records[0] {
+				col[h] = records[1][i]
+			}
+			if got := col["download_mbps"]; got != tt.wantDownload {
+				t.Errorf("download_mbps = %q, want %q", got, tt.wantDownload)
+			}
+			if got := col["upload_mbps"]; got != tt.wantUpload {
+				t.Errorf("upload_mbps = %q, want %q", got, tt.wantUpload)
+			}
+		})
+	}
+}
+
 func TestWriteCSV_WithError(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "results.csv")
@@ -588,3 +913,241 @@ func TestWriteCSV_WithError(t *testing.T) {
 		t.Error("CSV should contain error message")
 	}
 }
+
+func TestWriteCSVWithDelimiter(t *testing.T) {
+	for _, delim := range []rune{',', '\t'} {
+		path := filepath.Join(t.TempDir(), "results.csv")
+		results := []model.TestResult{{
+			Timestamp:  time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			ServerAddr: "192.168.1.1",
+			Port:       5201,
+			Protocol:   "TCP",
+			SentBps:    940_000_000,
+		}}
+		if err := WriteCSVWithDelimiter(path, results, delim); err != nil {
+			t.Fatalf("WriteCSVWithDelimiter(%q) error: %v", delim, err)
+		}
+
+		f, _ := os.Open(path)
+		r := csv.NewReader(f)
+		r.Comma = delim
+		records, err := r.ReadAll()
+		f.Close()
+		if err != nil {
+			t.Fatalf("re-read with %q: %v", delim, err)
+		}
+		if len(records) != 2 || len(records[0]) != len(csvHeaders) || len(records[1]) != len(csvHeaders) {
+			t.Errorf("delimiter %q: unexpected shape %d rows", delim, len(records))
+		}
+		if strings.Contains(records[0][0], ";") {
+			t.Errorf("delimiter %q: header still semicolon-separated", delim)
+		}
+	}
+}
+
+func TestWriteCSVWithDelimiter_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.csv")
+	if err := WriteCSVWithDelimiter(path, nil, '|'); err == nil {
+		t.Error("expected error for unsupported delimiter")
+	}
+	if fileExists(path) {
+		t.Error("file should not be created for an invalid delimiter")
+	}
+	if err := WriteIntervalLogWithDelimiter(path, &model.TestResult{}, 'x'); err == nil {
+		t.Error("expected error for unsupported interval log delimiter")
+	}
+}
+
+func TestWriteIntervalLogWithDelimiter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "intervals.csv")
+	result := &model.TestResult{
+		Protocol:  "TCP",
+		Intervals: []model.IntervalResult{{TimeStart: 0, TimeEnd: 1, Bytes: 1_000_000, BandwidthBps: 8_000_000}},
+	}
+	if err := WriteIntervalLogWithDelimiter(path, result, ','); err != nil {
+		t.Fatalf("WriteIntervalLogWithDelimiter() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	header := strings.SplitN(string(data), "\n", 2)[0]
+	if strings.Contains(header, ";") || !strings.Contains(header, ",") {
+		t.Errorf("header should be comma-separated: %s", header)
+	}
+}
+
+func TestParseDelimiter(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    rune
+		wantErr bool
+	}{
+		{",", ',', false},
+		{";", ';', false},
+		{"tab", '\t', false},
+		{`\t`, '\t', false},
+		{"\t", '\t', false},
+		{"|", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDelimiter(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseDelimiter(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestWriteCSV_FairnessIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.csv")
+	results := []model.TestResult{
+		{Protocol: "TCP", Parallel: 2, Streams: []model.StreamResult{{ID: 1, SentBps: 75e6}, {ID: 2, SentBps: 25e6}}},
+		{Protocol: "TCP", Parallel: 1, Streams: []model.StreamResult{{ID: 1, SentBps: 90e6}}},
+	}
+	if err := WriteCSV(path, results); err != nil {
+		t.Fatalf("WriteCSV() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	col := -1
+	for i, h := range strings.Split(lines[0], ";") {
+		if h == "fairness_index" {
+			col = i
+		}
+	}
+	if col < 0 {
+		t.Fatal("missing fairness_index header")
+	}
+	if got := strings.Split(lines[1], ";")[col]; got != "0.800" {
+		t.Errorf("fairness_index = %q, want 0.800", got)
+	}
+	if got := strings.Split(lines[2], ";")[col]; got != "" {
+		t.Errorf("single-stream fairness_index = %q, want empty", got)
+	}
+}
+
+func TestWriteIntervalLog_BidirUnevenLengths(t *testing.T) {
+	iv := func(start float64) model.IntervalResult {
+		return model.IntervalResult{TimeStart: start, TimeEnd: start + 1, Bytes: 1_000_000, BandwidthBps: 8_000_000}
+	}
+	read := func(result *model.TestResult) [][]string {
+		path := filepath.Join(t.TempDir(), "intervals.csv")
+		if err := WriteIntervalLog(path, result); err != nil {
+			t.Fatalf("WriteIntervalLog() error: %v", err)
+		}
+		f, _ := os.Open(path)
+		defer f.Close()
+		r := csv.NewReader(f)
+		r.Comma = DefaultDelimiter
+		records, err := r.ReadAll()
+		if err != nil {
+			t.Fatalf("read interval log: %v", err)
+		}
+		return records[1:]
+	}
+	col := func(name string) int {
+		for i, h := range intervalHeaders {
+			if h == name {
+				return i
+			}
+		}
+		t.Fatalf("no %s column", name)
+		return -1
+	}
+
+	rows := read(&model.TestResult{
+		Timestamp:        time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		Protocol:         "TCP",
+		Direction:        "Bidirectional",
+		Intervals:        []model.IntervalResult{iv(0), iv(1), iv(2)},
+		ReverseIntervals: []model.IntervalResult{iv(0), iv(1)},
+	})
+	if len(rows) != 3 {
+		t.Fatalf("got %d data rows, want 3", len(rows))
+	}
+	if rows[2][col("fwd_bandwidth_mbps")] != "8.00" {
+		t.Errorf("row 3 fwd_bandwidth_mbps = %q, want 8.00", rows[2][col("fwd_bandwidth_mbps")])
+	}
+	for _, name := range []string{"rev_bandwidth_mbps", "rev_transfer_mb", "rev_jitter_ms", "rev_omitted"} {
+		if got := rows[2][col(name)]; got != "" {
+			t.Errorf("row 3 %s = %q, want empty", name, got)
+		}
+	}
+
+	// The reverse side outlasting the forward side keeps its rows too
+	rows = read(&model.TestResult{
+		Timestamp:        time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		Protocol:         "TCP",
+		Direction:        "Bidirectional",
+		Intervals:        []model.IntervalResult{iv(0)},
+		ReverseIntervals: []model.IntervalResult{iv(0), iv(1)},
+	})
+	if len(rows) != 2 {
+		t.Fatalf("got %d data rows, want 2", len(rows))
+	}
+	if rows[1][col("fwd_bandwidth_mbps")] != "" || rows[1][col("rev_bandwidth_mbps")] != "8.00" {
+		t.Errorf("row 2 = %v, want empty fwd and 8.00 rev", rows[1])
+	}
+	if rows[1][col("wall_time")] != "2026-03-01T09:00:01" {
+		t.Errorf("row 2 wall_time = %q, want the reverse interval's start", rows[1][col("wall_time")])
+	}
+}
+
+func TestIntervalLogWriter_MatchesEndOfRunLog(t *testing.T) {
+	iv := func(start float64) model.IntervalResult {
+		return model.IntervalResult{TimeStart: start, TimeEnd: start + 1, Bytes: 1_000_000, BandwidthBps: 8_000_000}
+	}
+	result := &model.TestResult{
+		MeasurementID:    "20260301-090000-01",
+		Timestamp:        time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		Protocol:         "TCP",
+		Parallel:         1,
+		Direction:        "Bidirectional",
+		ServerAddr:       "10.0.0.1",
+		Port:             5201,
+		OmitSeconds:      1,
+		Intervals:        []model.IntervalResult{iv(0), iv(1), iv(2)},
+		ReverseIntervals: []model.IntervalResult{iv(0), iv(1)},
+	}
+	dir := t.TempDir()
+
+	// The end-of-run writer sees intervals already marked by ApplyToResult
+	marked := *result
+	marked.Intervals = append([]model.IntervalResult(nil), result.Intervals...)
+	marked.ReverseIntervals = append([]model.IntervalResult(nil), result.ReverseIntervals...)
+	marked.Intervals[0].Omitted, marked.ReverseIntervals[0].Omitted = true, true
+	endPath := filepath.Join(dir, "end.csv")
+	if err := WriteIntervalLog(endPath, &marked); err != nil {
+		t.Fatalf("WriteIntervalLog() error: %v", err)
+	}
+
+	livePath := filepath.Join(dir, "live.csv")
+	w, err := OpenIntervalLogWriter(livePath, *result, IntervalLogOptions{})
+	if err != nil {
+		t.Fatalf("OpenIntervalLogWriter() error: %v", err)
+	}
+	for i := range result.Intervals {
+		var rev *model.IntervalResult
+		if i < len(result.ReverseIntervals) {
+			rev = &result.ReverseIntervals[i]
+		}
+		if err := w.Write(&result.Intervals[i], rev); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+		// Each row is on disk as soon as it is written
+		data, _ := os.ReadFile(livePath)
+		if lines := strings.Count(string(data), "\n"); lines != i+2 {
+			t.Errorf("after %d writes the log has %d lines, want %d", i+1, lines, i+2)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	want, _ := os.ReadFile(endPath)
+	got, _ := os.ReadFile(livePath)
+	if string(got) != string(want) {
+		t.Errorf("live log differs from the end-of-run log:\n got:\n%s\nwant:\n%s", got, want)
+	}
+	if result.Intervals[0].Omitted {
+		t.Error("Write() modified the caller's interval")
+	}
+}
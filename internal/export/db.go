@@ -0,0 +1,153 @@
+package export
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // cgo-free "sqlite" database/sql driver
+
+	"iperf-tool/internal/model"
+)
+
+// textColumns are the CSV columns stored as TEXT; every other column gets
+// NUMERIC affinity so it can be compared and aggregated in queries.
+var textColumns = map[string]bool{
+	"timestamp": true, "date": true, "time": true, "measurement_id": true,
+	"label": true, "hostname": true, "local_ip": true, "server": true,
+	"protocol": true, "direction": true, "test_direction": true,
+	"stream_bandwidth": true, "congestion": true, "mode": true,
+	"iperf_version": true, "bufferbloat_grade": true, "error": true,
+	"wall_time": true,
+}
+
+// DB is a SQLite results history: a measurements table with the summary
+// CSV columns and an intervals table linked to it by measurement_id.
+type DB struct {
+	db *sql.DB
+}
+
+// OpenDB opens or creates the SQLite database at path. Columns added to the
+// CSV since the file was created are added to the existing tables.
+func OpenDB(path string) (*DB, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	d := &DB{db: db}
+	if err := d.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// Close closes the database.
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+func (d *DB) migrate() error {
+	measurements := append([]string{"timestamp"}, csvHeaders...)
+	if err := d.ensureTable("measurements", measurements, "PRIMARY KEY (measurement_id)"); err != nil {
+		return err
+	}
+	if err := d.ensureTable("intervals", intervalHeaders,
+		"FOREIGN KEY (measurement_id) REFERENCES measurements(measurement_id) ON DELETE CASCADE"); err != nil {
+		return err
+	}
+	if _, err := d.db.Exec(`CREATE INDEX IF NOT EXISTS intervals_measurement_id ON intervals(measurement_id)`); err != nil {
+		return fmt.Errorf("create intervals index: %w", err)
+	}
+	return nil
+}
+
+// ensureTable creates table with columns, or adds the columns an older
+// version of the table lacks.
+func (d *DB) ensureTable(table string, columns []string, constraint string) error {
+	defs := make([]string, len(columns))
+	for i, c := range columns {
+		defs[i] = columnDef(c)
+	}
+	create := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s, %s)", table, strings.Join(defs, ", "), constraint)
+	if _, err := d.db.Exec(create); err != nil {
+		return fmt.Errorf("create %s table: %w", table, err)
+	}
+
+	rows, err := d.db.Query(fmt.Sprintf("SELECT name FROM pragma_table_info('%s')", table))
+	if err != nil {
+		return fmt.Errorf("read %s columns: %w", table, err)
+	}
+	have := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return fmt.Errorf("read %s columns: %w", table, err)
+		}
+		have[name] = true
+	}
+	rows.Close()
+	for _, c := range columns {
+		if have[c] {
+			continue
+		}
+		if _, err := d.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", table, columnDef(c))); err != nil {
+			return fmt.Errorf("add %s.%s: %w", table, c, err)
+		}
+	}
+	return nil
+}
+
+func columnDef(name string) string {
+	if textColumns[name] {
+		return `"` + name + `" TEXT`
+	}
+	return `"` + name + `" NUMERIC`
+}
+
+// Insert stores r and its intervals in one transaction. Values match the
+// CSV exports; empty CSV fields are stored as NULL.
+func (d *DB) Insert(r *model.TestResult) error {
+	if r.MeasurementID == "" {
+		return fmt.Errorf("insert result: no measurement ID")
+	}
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin insert: %w", err)
+	}
+	defer tx.Rollback()
+
+	row := append([]string{r.Timestamp.Format(time.RFC3339)}, summaryRow(*r)...)
+	if err := insertRow(tx, "measurements", append([]string{"timestamp"}, csvHeaders...), row); err != nil {
+		return err
+	}
+	for _, iv := range intervalRows(r, false) {
+		if err := insertRow(tx, "intervals", intervalHeaders, iv); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit insert: %w", err)
+	}
+	return nil
+}
+
+func insertRow(tx *sql.Tx, table string, columns, values []string) error {
+	names := make([]string, len(columns))
+	marks := make([]string, len(columns))
+	args := make([]any, len(columns))
+	for i, c := range columns {
+		names[i] = `"` + c + `"`
+		marks[i] = "?"
+		if values[i] != "" {
+			args[i] = values[i]
+		}
+	}
+	q := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(names, ", "), strings.Join(marks, ", "))
+	if _, err := tx.Exec(q, args...); err != nil {
+		return fmt.Errorf("insert into %s: %w", table, err)
+	}
+	return nil
+}
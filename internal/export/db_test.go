@@ -0,0 +1,92 @@
+package export
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+func TestDB_InsertAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	db, err := OpenDB(path)
+	if err != nil {
+		t.Fatalf("OpenDB() error: %v", err)
+	}
+	defer db.Close()
+
+	ts := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	results := []model.TestResult{
+		{
+			Timestamp: ts, MeasurementID: "20260301-093000-01", ServerAddr: "10.0.0.1", Port: 5201,
+			Protocol: "TCP", Parallel: 1, Duration: 2, SentBps: 900e6, ReceivedBps: 880e6, Retransmits: 3,
+			Intervals: []model.IntervalResult{
+				{TimeStart: 0, TimeEnd: 1, Bytes: 110_000_000, BandwidthBps: 880e6},
+				{TimeStart: 1, TimeEnd: 2, Bytes: 110_000_000, BandwidthBps: 880e6},
+			},
+		},
+		{
+			Timestamp: ts.Add(time.Minute), MeasurementID: "20260301-093100-01", ServerAddr: "10.0.0.1", Port: 5201,
+			Protocol: "TCP", Parallel: 1, Duration: 1, SentBps: 500e6, ReceivedBps: 480e6,
+			Intervals: []model.IntervalResult{{TimeStart: 0, TimeEnd: 1, Bytes: 60_000_000, BandwidthBps: 480e6}},
+		},
+	}
+	for i := range results {
+		if err := db.Insert(&results[i]); err != nil {
+			t.Fatalf("Insert(%d) error: %v", i, err)
+		}
+	}
+	db.Close()
+
+	// Reopen to check the schema survives and nothing needs migrating
+	db, err = OpenDB(path)
+	if err != nil {
+		t.Fatalf("reopen error: %v", err)
+	}
+	defer db.Close()
+
+	rows, err := db.db.Query(`SELECT m.measurement_id, m.fwd_mbps, m.fwd_retransmits, COUNT(i.rowid), SUM(i.fwd_bandwidth_mbps)
+		FROM measurements m JOIN intervals i ON i.measurement_id = m.measurement_id
+		GROUP BY m.measurement_id ORDER BY m.timestamp`)
+	if err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+	defer rows.Close()
+
+	type row struct {
+		id        string
+		fwdMbps   float64
+		retr      int
+		intervals int
+		sumMbps   float64
+	}
+	var got []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.fwdMbps, &r.retr, &r.intervals, &r.sumMbps); err != nil {
+			t.Fatalf("scan error: %v", err)
+		}
+		got = append(got, r)
+	}
+	want := []row{
+		{"20260301-093000-01", 900, 3, 2, 1760},
+		{"20260301-093100-01", 500, 0, 1, 480},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d measurements, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// Intervals must reference an existing measurement
+	if _, err := db.db.Exec(`INSERT INTO intervals (measurement_id) VALUES ('missing')`); err == nil {
+		t.Error("expected a foreign key error for an orphan interval")
+	}
+	if err := db.Insert(&results[0]); err == nil {
+		t.Error("expected an error inserting a duplicate measurement ID")
+	}
+}
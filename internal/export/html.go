@@ -0,0 +1,285 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+
+	"iperf-tool/internal/format"
+	"iperf-tool/internal/model"
+)
+
+// Chart geometry for the inline SVG, in user units.
+const (
+	chartWidth  = 720
+	chartHeight = 240
+	chartPad    = 40
+)
+
+type htmlRow struct {
+	Label string
+	Value string
+}
+
+type htmlStream struct {
+	ID                       int
+	Dir                      string
+	Sent, Recv, Jitter, Loss string
+	Retr                     int
+}
+
+type htmlSeries struct {
+	Name   string
+	Color  string
+	Points string // SVG polyline points
+}
+
+type htmlChart struct {
+	Width, Height, Pad int
+	Right, Bottom      int
+	MaxMbps, MaxTime   string
+	Series             []htmlSeries
+}
+
+type htmlReport struct {
+	Title    string
+	Error    string
+	Params   []htmlRow
+	Summary  []htmlRow
+	Streams  []htmlStream
+	Chart    *htmlChart
+	Latency  []htmlRow
+	Method   string
+	Footnote string
+	RateUnit string // "Mbps" or "Mibps", per format.Units
+}
+
+var htmlTmpl = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.4em; }
+h2 { font-size: 1.1em; margin-top: 1.6em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+.error { color: #b00020; font-weight: bold; }
+.legend span { margin-right: 1.5em; }
+footer { margin-top: 2em; color: #888; font-size: 0.85em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<h2>Parameters</h2>
+<table>
+{{range .Params}}<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{if .Error}}<p class="error">Error: {{.Error}}</p>
+{{else}}<h2>Summary</h2>
+<table>
+{{range .Summary}}<tr><th>{{.Label}}</th><td class="num">{{.Value}}</td></tr>
+{{end}}</table>
+{{with .Chart}}<h2>Throughput</h2>
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}" role="img" aria-label="Throughput over time">
+<rect x="0" y="0" width="{{.Width}}" height="{{.Height}}" fill="#fff"/>
+<line x1="{{.Pad}}" y1="{{.Bottom}}" x2="{{.Right}}" y2="{{.Bottom}}" stroke="#999"/>
+<line x1="{{.Pad}}" y1="{{.Pad}}" x2="{{.Pad}}" y2="{{.Bottom}}" stroke="#999"/>
+<text x="{{.Pad}}" y="{{.Pad}}" dx="-4" text-anchor="end" font-size="11" fill="#555">{{.MaxMbps}}</text>
+<text x="{{.Pad}}" y="{{.Bottom}}" dx="-4" text-anchor="end" font-size="11" fill="#555">0</text>
+<text x="{{.Right}}" y="{{.Bottom}}" dy="14" text-anchor="end" font-size="11" fill="#555">{{.MaxTime}}</text>
+{{range .Series}}<polyline fill="none" stroke="{{.Color}}" stroke-width="2" points="{{.Points}}"/>
+{{end}}</svg>
+<p class="legend">{{range .Series}}<span style="color: {{.Color}}">&#9632; {{.Name}}</span>{{end}}</p>
+{{end}}{{if .Streams}}<h2>Per-Stream</h2>
+<table>
+<tr><th>Stream</th><th>Direction</th><th>Sent ({{.RateUnit}})</th><th>Received ({{.RateUnit}})</th><th>Retr</th><th>Jitter (ms)</th><th>Loss (%)</th></tr>
+{{range .Streams}}<tr><td>{{.ID}}</td><td>{{.Dir}}</td><td class="num">{{.Sent}}</td><td class="num">{{.Recv}}</td><td class="num">{{.Retr}}</td><td class="num">{{.Jitter}}</td><td class="num">{{.Loss}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Latency}}<h2>Latency ({{.Method}})</h2>
+<table>
+{{range .Latency}}<tr><th>{{.Label}}</th><td class="num">{{.Value}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}<footer>{{.Footnote}}</footer>
+</body>
+</html>
+`))
+
+// WriteHTML renders r as a self-contained HTML report with an inline SVG
+// throughput chart. Styles are embedded so the file opens offline. Unlike
+// the CSV/TXT writers the file is overwritten: one report per measurement.
+func WriteHTML(path string, r *model.TestResult) error {
+	var b strings.Builder
+	if err := htmlTmpl.Execute(&b, buildHTMLReport(r)); err != nil {
+		return fmt.Errorf("render html report: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("write html file: %w", err)
+	}
+	return nil
+}
+
+// HTMLPath returns the report path for r: base + "_" + MeasurementID + ".html",
+// falling back to the dated form when the result has no ID.
+func HTMLPath(base string, r *model.TestResult) string {
+	if r.MeasurementID != "" {
+		return fmt.Sprintf("%s_%s.html", base, r.MeasurementID)
+	}
+	return BuildPath(base, "", ".html", r.Timestamp)
+}
+
+func buildHTMLReport(r *model.TestResult) htmlReport {
+	rep := htmlReport{
+		Title:    fmt.Sprintf("iperf2 %s:%d — %s", r.ServerAddr, r.Port, r.Timestamp.Local().Format("2006-01-02 15:04:05")),
+		Error:    r.Error,
+		RateUnit: format.Units.MbpsUnit(),
+	}
+	if r.MeasurementID != "" {
+		rep.Footnote = "Measurement ID " + r.MeasurementID
+	}
+
+	dir := r.Direction
+	if dir == "" {
+		dir = "Normal"
+	}
+	rep.Params = []htmlRow{
+		{"Server", fmt.Sprintf("%s:%d", r.ServerAddr, r.Port)},
+		{"Protocol", r.Protocol},
+		{"Direction", dir},
+		{"Parallel", fmt.Sprintf("%d streams", r.Parallel)},
+		{"Duration", fmt.Sprintf("%d s", r.Duration)},
+	}
+	for _, opt := range []htmlRow{
+		{"Bandwidth limit", format.BandwidthTarget(r)},
+		{"Window", r.WindowSize},
+		{"DSCP/ToS", r.DSCP},
+		{"Congestion", r.Congestion},
+		{"Local IP", r.LocalIP},
+		{"iperf version", r.IperfVersion},
+	} {
+		if opt.Value != "" {
+			rep.Params = append(rep.Params, opt)
+		}
+	}
+	if r.Error != "" {
+		return rep
+	}
+
+	isUDP := r.Protocol == "UDP"
+	if r.Direction == "Bidirectional" {
+		rep.Summary = append(rep.Summary,
+			htmlRow{"Forward", format.Units.Mbps(r.FwdActualMbps())},
+			htmlRow{"Reverse", format.Units.Mbps(r.ReverseActualMbps())},
+			htmlRow{"Transferred", fmt.Sprintf("%s fwd / %s rev", format.Units.MB(r.TotalFwdMB()), format.Units.MB(r.TotalRevMB()))},
+		)
+		if isUDP {
+			rep.Summary = append(rep.Summary,
+				htmlRow{"Jitter", fmt.Sprintf("%.3f ms fwd / %.3f ms rev", r.ActualJitterMs(), r.ReverseJitterMs)},
+				htmlRow{"Loss", fmt.Sprintf("%.2f%% fwd / %.2f%% rev", fwdLostPercent(*r), r.ReverseLostPercent)},
+			)
+		} else {
+			rep.Summary = append(rep.Summary,
+				htmlRow{"Retransmits", fmt.Sprintf("%d fwd / %d rev", r.Retransmits, r.ReverseRetransmits)})
+		}
+	} else {
+		rep.Summary = append(rep.Summary,
+			htmlRow{"Sent", format.Units.Mbps(r.SentMbps())},
+			htmlRow{"Received", format.Units.Mbps(r.ReceivedMbps())},
+			htmlRow{"Transferred", fmt.Sprintf("%s sent / %s received", format.Units.MB(r.SentMB()), format.Units.MB(r.ReceivedMB()))},
+		)
+		if isUDP {
+			rep.Summary = append(rep.Summary,
+				htmlRow{"Jitter", fmt.Sprintf("%.3f ms", r.JitterMs)},
+				htmlRow{"Loss", fmt.Sprintf("%.2f%% (%d/%d)", r.LostPercent, r.LostPackets, r.Packets)},
+			)
+		} else {
+			rep.Summary = append(rep.Summary, htmlRow{"Retransmits", fmt.Sprintf("%d", r.Retransmits)})
+		}
+	}
+
+	for _, s := range r.Streams {
+		sd := "Forward"
+		if r.Direction == "Bidirectional" && !s.Sender {
+			sd = "Reverse"
+		} else if r.Direction == "Reverse" {
+			sd = "Reverse"
+		}
+		st := htmlStream{ID: s.ID, Dir: sd, Sent: fmt.Sprintf("%.2f", format.Units.Scale(s.SentMbps())), Recv: fmt.Sprintf("%.2f", format.Units.Scale(s.ReceivedMbps())),
+			Retr: s.Retransmits, Jitter: "—", Loss: "—"}
+		if isUDP {
+			st.Jitter = fmt.Sprintf("%.3f", s.JitterMs)
+			st.Loss = fmt.Sprintf("%.2f", s.LostPercent)
+		}
+		rep.Streams = append(rep.Streams, st)
+	}
+
+	rep.Chart = buildHTMLChart(r)
+
+	if r.PingBaseline != nil || r.PingLoaded != nil {
+		rep.Method = r.LatencyMethod
+		if rep.Method == "" {
+			rep.Method = model.LatencyICMP
+		}
+		if p := r.PingBaseline; p != nil {
+			rep.Latency = append(rep.Latency, htmlRow{"Baseline", fmt.Sprintf("%.2f / %.2f / %.2f ms", p.MinMs, p.AvgMs, p.MaxMs)})
+		}
+		if p := r.PingLoaded; p != nil {
+			rep.Latency = append(rep.Latency, htmlRow{"Under load", fmt.Sprintf("%.2f / %.2f / %.2f ms", p.MinMs, p.AvgMs, p.MaxMs)})
+		}
+	}
+	return rep
+}
+
+// buildHTMLChart scales the forward and reverse interval series into the
+// SVG plot area, or returns nil when there are no intervals to draw.
+func buildHTMLChart(r *model.TestResult) *htmlChart {
+	fwdName := "Forward"
+	if r.Direction == "Reverse" {
+		fwdName = "Reverse"
+	}
+	series := []struct {
+		name, color string
+		ivs         []model.IntervalResult
+	}{
+		{fwdName, "#1f77b4", r.Intervals},
+		{"Reverse", "#ff7f0e", r.ReverseIntervals},
+	}
+
+	var maxMbps, maxTime float64
+	for _, s := range series {
+		for i := range s.ivs {
+			maxMbps = max(maxMbps, s.ivs[i].BandwidthMbps())
+			maxTime = max(maxTime, s.ivs[i].TimeEnd)
+		}
+	}
+	if maxTime == 0 {
+		return nil
+	}
+	if maxMbps == 0 {
+		maxMbps = 1
+	}
+
+	c := &htmlChart{
+		Width: chartWidth, Height: chartHeight, Pad: chartPad,
+		Right: chartWidth - chartPad/2, Bottom: chartHeight - chartPad,
+		MaxMbps: fmt.Sprintf("%.0f %s", format.Units.Scale(maxMbps), format.Units.MbpsUnit()), MaxTime: fmt.Sprintf("%.0f s", maxTime),
+	}
+	plotW := float64(c.Right - c.Pad)
+	plotH := float64(c.Bottom - c.Pad)
+	for _, s := range series {
+		if len(s.ivs) == 0 {
+			continue
+		}
+		pts := make([]string, len(s.ivs))
+		for i := range s.ivs {
+			x := float64(c.Pad) + s.ivs[i].TimeEnd/maxTime*plotW
+			y := float64(c.Bottom) - s.ivs[i].BandwidthMbps()/maxMbps*plotH
+			pts[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+		}
+		c.Series = append(c.Series, htmlSeries{Name: s.name, Color: s.color, Points: strings.Join(pts, " ")})
+	}
+	return c
+}
@@ -0,0 +1,95 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"iperf-tool/internal/model"
+)
+
+func TestWriteHTML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.html")
+	r := &model.TestResult{
+		Timestamp:     baseTXTTime,
+		ServerAddr:    "192.168.1.1",
+		Port:          5201,
+		Protocol:      "TCP",
+		Parallel:      2,
+		Duration:      2,
+		SentBps:       100_000_000,
+		ReceivedBps:   95_000_000,
+		BytesSent:     25_000_000,
+		BytesReceived: 23_750_000,
+		Retransmits:   3,
+		Streams: []model.StreamResult{
+			{ID: 3, SentBps: 50_000_000, ReceivedBps: 47_000_000, Retransmits: 1, Sender: true},
+			{ID: 4, SentBps: 50_000_000, ReceivedBps: 48_000_000, Retransmits: 2, Sender: true},
+		},
+		Intervals: []model.IntervalResult{
+			{TimeStart: 0, TimeEnd: 1, BandwidthBps: 90_000_000},
+			{TimeStart: 1, TimeEnd: 2, BandwidthBps: 100_000_000},
+		},
+		PingBaseline: &model.PingResult{MinMs: 1, AvgMs: 1.5, MaxMs: 2},
+	}
+	if err := WriteHTML(path, r); err != nil {
+		t.Fatalf("WriteHTML() error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"192.168.1.1:5201",
+		"<svg",
+		"<polyline",
+		"100 Mbps",
+		"100.00 Mbps", // sent
+		"95.00 Mbps",  // received
+		"25.00 MB sent / 23.75 MB received",
+		"<th>Retransmits</th><td class=\"num\">3</td>",
+		"<td>3</td><td>Forward</td><td class=\"num\">50.00</td><td class=\"num\">47.00</td>",
+		"Latency (ICMP ping)",
+		"1.00 / 1.50 / 2.00 ms",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("html missing %q", want)
+		}
+	}
+	for _, ext := range []string{"<script", "<link", "src=\"http"} {
+		if strings.Contains(content, ext) {
+			t.Errorf("html references external resource %q", ext)
+		}
+	}
+}
+
+func TestWriteHTML_ErrorNoChart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.html")
+	r := &model.TestResult{Timestamp: baseTXTTime, ServerAddr: "10.0.0.1", Port: 5201, Protocol: "TCP",
+		Error: "connection refused <host>"}
+	if err := WriteHTML(path, r); err != nil {
+		t.Fatalf("WriteHTML() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if !strings.Contains(content, "Error: connection refused &lt;host&gt;") {
+		t.Error("error message missing or not escaped")
+	}
+	if strings.Contains(content, "<svg") {
+		t.Error("chart rendered for a failed run")
+	}
+}
+
+func TestHTMLPath(t *testing.T) {
+	r := &model.TestResult{Timestamp: baseTXTTime, MeasurementID: "20260218-143207-01"}
+	if got := HTMLPath("out/iperf", r); got != "out/iperf_20260218-143207-01.html" {
+		t.Errorf("HTMLPath() = %q", got)
+	}
+	r.MeasurementID = ""
+	if got := HTMLPath("out/iperf", r); got != "out/iperf_18.02.2026.html" {
+		t.Errorf("HTMLPath() without ID = %q", got)
+	}
+}
@@ -0,0 +1,138 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+// influxField is one numeric field of the line-protocol point.
+type influxField struct {
+	key   string
+	value string
+}
+
+// WriteInflux writes r as one InfluxDB line-protocol point:
+//
+//	iperf,server=...,protocol=...,direction=...,label=... fwd_mbps=940,... <ns>
+//
+// Tags with an empty value are left out, as line protocol forbids them. The
+// timestamp is omitted when r.Timestamp is zero so the server assigns one.
+func WriteInflux(w io.Writer, r *model.TestResult) error {
+	var b strings.Builder
+	b.WriteString("iperf")
+	for _, tag := range [][2]string{
+		{"server", r.ServerAddr},
+		{"protocol", r.Protocol},
+		{"direction", influxDirection(r.Direction)},
+		{"label", r.Label},
+	} {
+		if tag[1] != "" {
+			fmt.Fprintf(&b, ",%s=%s", tag[0], influxEscape(tag[1]))
+		}
+	}
+
+	fields := []influxField{{"fwd_mbps", influxFloat(r.FwdActualMbps())}}
+	if r.Direction == "Bidirectional" {
+		fields = append(fields, influxField{"rev_mbps", influxFloat(r.ReverseActualMbps())})
+	}
+	if strings.EqualFold(r.Protocol, "UDP") {
+		fields = append(fields,
+			influxField{"jitter_ms", influxFloat(r.ActualJitterMs())},
+			influxField{"lost_percent", influxFloat(fwdLostPercent(*r))},
+		)
+	} else {
+		fields = append(fields, influxField{"retransmits", strconv.Itoa(r.Retransmits) + "i"})
+	}
+	if r.PingBaseline != nil {
+		fields = append(fields, influxField{"ping_baseline_avg_ms", influxFloat(r.PingBaseline.AvgMs)})
+	}
+	if r.PingLoaded != nil {
+		fields = append(fields, influxField{"ping_loaded_avg_ms", influxFloat(r.PingLoaded.AvgMs)})
+	}
+	for i, f := range fields {
+		sep := ","
+		if i == 0 {
+			sep = " "
+		}
+		fmt.Fprintf(&b, "%s%s=%s", sep, f.key, f.value)
+	}
+
+	if !r.Timestamp.IsZero() {
+		fmt.Fprintf(&b, " %d", r.Timestamp.UnixNano())
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// AppendInflux appends the line-protocol point for r to the file at path.
+func AppendInflux(path string, r *model.TestResult) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open influx file: %w", err)
+	}
+	defer f.Close()
+	if err := WriteInflux(f, r); err != nil {
+		return fmt.Errorf("write influx file: %w", err)
+	}
+	return nil
+}
+
+// influxPushTimeout bounds one HTTP write to the InfluxDB server.
+const influxPushTimeout = 10 * time.Second
+
+// PushInflux POSTs the line-protocol point for r to url, a full write
+// endpoint such as http://host:8086/write?db=iperf or the v2
+// /api/v2/write?org=..&bucket=.. form. Any non-2xx reply is an error.
+func PushInflux(url string, r *model.TestResult) error {
+	var body bytes.Buffer
+	if err := WriteInflux(&body, r); err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: influxPushTimeout}
+	resp, err := client.Post(url, "text/plain; charset=utf-8", &body)
+	if err != nil {
+		return fmt.Errorf("influx push: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("influx push: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// influxDirection maps a result direction to a short tag value.
+func influxDirection(dir string) string {
+	switch dir {
+	case "Reverse":
+		return "reverse"
+	case "Bidirectional":
+		return "bidir"
+	}
+	return "forward"
+}
+
+// influxEscape escapes a tag value per the line protocol: commas, equals
+// signs and spaces are backslash-escaped.
+func influxEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, ",", `\,`)
+	s = strings.ReplaceAll(s, "=", `\=`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return strings.ReplaceAll(s, " ", `\ `)
+}
+
+// influxFloat formats a float field value without exponent notation.
+func influxFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
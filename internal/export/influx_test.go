@@ -0,0 +1,109 @@
+package export
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+func TestWriteInflux(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	r := &model.TestResult{
+		Timestamp:      ts,
+		ServerAddr:     "10.0.0.1",
+		Protocol:       "TCP",
+		Label:          "office wifi, 2nd floor",
+		SentBps:        940_000_000,
+		FwdReceivedBps: 930_000_000,
+		Retransmits:    7,
+		PingBaseline:   &model.PingResult{AvgMs: 1.5},
+	}
+	var b strings.Builder
+	if err := WriteInflux(&b, r); err != nil {
+		t.Fatalf("WriteInflux() error: %v", err)
+	}
+	want := `iperf,server=10.0.0.1,protocol=TCP,direction=forward,label=office\ wifi\,\ 2nd\ floor ` +
+		"fwd_mbps=930,retransmits=7i,ping_baseline_avg_ms=1.5 1700000000000000000\n"
+	if b.String() != want {
+		t.Errorf("WriteInflux() =\n%q\nwant\n%q", b.String(), want)
+	}
+}
+
+func TestWriteInflux_BidirUDP(t *testing.T) {
+	r := &model.TestResult{
+		ServerAddr:         "a=b",
+		Protocol:           "UDP",
+		Direction:          "Bidirectional",
+		FwdReceivedBps:     100_000_000,
+		ReverseReceivedBps: 50_000_000,
+		FwdJitterMs:        0.25,
+		FwdPackets:         1000,
+		FwdLostPercent:     1.5,
+	}
+	var b strings.Builder
+	if err := WriteInflux(&b, r); err != nil {
+		t.Fatalf("WriteInflux() error: %v", err)
+	}
+	line := b.String()
+	if !strings.HasPrefix(line, `iperf,server=a\=b,protocol=UDP,direction=bidir `) {
+		t.Errorf("bad tag set: %q", line)
+	}
+	for _, want := range []string{"rev_mbps=50", "lost_percent=1.5"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("missing %q in %q", want, line)
+		}
+	}
+	if strings.Contains(line, "retransmits") {
+		t.Errorf("UDP point should not carry retransmits: %q", line)
+	}
+	// No timestamp when the result has none.
+	if fields := strings.Fields(line); len(fields) != 2 {
+		t.Errorf("want measurement+tags and fields only, got %q", line)
+	}
+}
+
+func TestAppendInflux(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "iperf.lp")
+	r := &model.TestResult{ServerAddr: "10.0.0.1", Protocol: "TCP", SentBps: 1e6}
+	for i := 0; i < 2; i++ {
+		if err := AppendInflux(path, r); err != nil {
+			t.Fatalf("AppendInflux() error: %v", err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	if n := strings.Count(string(data), "\n"); n != 2 {
+		t.Errorf("got %d lines, want 2:\n%s", n, data)
+	}
+}
+
+func TestPushInflux(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		got = string(body)
+		if req.URL.Query().Get("db") == "bad" {
+			http.Error(w, "database not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	r := &model.TestResult{ServerAddr: "10.0.0.1", Protocol: "TCP", SentBps: 1e6}
+	if err := PushInflux(srv.URL+"/write?db=iperf", r); err != nil {
+		t.Fatalf("PushInflux() error: %v", err)
+	}
+	if !strings.HasPrefix(got, "iperf,server=10.0.0.1,") {
+		t.Errorf("server got %q", got)
+	}
+	if err := PushInflux(srv.URL+"/write?db=bad", r); err == nil || !strings.Contains(err.Error(), "database not found") {
+		t.Errorf("PushInflux() error = %v, want the server message", err)
+	}
+}
@@ -0,0 +1,59 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"iperf-tool/internal/model"
+)
+
+// WriteJSON writes test results to path as an indented JSON array. If the file
+// already exists its array is read back and the new results are appended, so
+// repeated calls accumulate a series the same way WriteCSV and WriteTXT do.
+func WriteJSON(path string, results []model.TestResult) error {
+	var all []model.TestResult
+	if fileExists(path) {
+		var err error
+		if all, err = ReadJSON(path); err != nil {
+			return err
+		}
+	}
+	all = append(all, results...)
+	if all == nil {
+		all = []model.TestResult{}
+	}
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+	data = append(data, '\n')
+
+	// Write to a temp file and rename so a crash never leaves a truncated array.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write json file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write json file: %w", err)
+	}
+	return nil
+}
+
+// ReadJSON reads a results file written by WriteJSON. An empty file yields
+// no results.
+func ReadJSON(path string) ([]model.TestResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read json file: %w", err)
+	}
+	var results []model.TestResult
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &results); err != nil {
+			return nil, fmt.Errorf("decode json file: %w", err)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,117 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+func TestWriteJSON_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.json")
+
+	first := model.TestResult{
+		Timestamp:  time.Date(2026, 2, 18, 14, 32, 7, 0, time.UTC),
+		ServerAddr: "192.168.1.1",
+		Port:       5201,
+		Protocol:   "TCP",
+		Parallel:   2,
+		SentBps:    940_000_000,
+		Streams: []model.StreamResult{
+			{ID: 1, SentBps: 470_000_000, Sender: true},
+			{ID: 2, SentBps: 470_000_000, Sender: true},
+		},
+		Intervals: []model.IntervalResult{
+			{TimeStart: 0, TimeEnd: 1, Bytes: 117_500_000, BandwidthBps: 940_000_000},
+		},
+		PingBaseline: &model.PingResult{PacketsSent: 4, PacketsRecv: 4, MinMs: 1.1, AvgMs: 1.5, MaxMs: 2.0},
+	}
+	second := model.TestResult{
+		Timestamp:  time.Date(2026, 2, 18, 14, 33, 0, 0, time.UTC),
+		ServerAddr: "10.0.0.1",
+		Port:       5201,
+		Protocol:   "UDP",
+		JitterMs:   0.25,
+		Error:      "connection refused",
+	}
+
+	if err := WriteJSON(path, []model.TestResult{first}); err != nil {
+		t.Fatalf("WriteJSON() first call error: %v", err)
+	}
+	if err := WriteJSON(path, []model.TestResult{second}); err != nil {
+		t.Fatalf("WriteJSON() second call error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file error: %v", err)
+	}
+	var got []model.TestResult
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(got))
+	}
+	if got[0].ServerAddr != "192.168.1.1" || got[1].ServerAddr != "10.0.0.1" {
+		t.Errorf("servers = %q, %q; want 192.168.1.1, 10.0.0.1", got[0].ServerAddr, got[1].ServerAddr)
+	}
+	if !got[0].Timestamp.Equal(first.Timestamp) {
+		t.Errorf("Timestamp = %v, want %v", got[0].Timestamp, first.Timestamp)
+	}
+	if len(got[0].Streams) != 2 || len(got[0].Intervals) != 1 {
+		t.Errorf("streams/intervals = %d/%d, want 2/1", len(got[0].Streams), len(got[0].Intervals))
+	}
+	if got[0].PingBaseline == nil || got[0].PingBaseline.AvgMs != 1.5 {
+		t.Errorf("PingBaseline = %+v, want AvgMs 1.5", got[0].PingBaseline)
+	}
+	if got[1].Error != "connection refused" {
+		t.Errorf("Error = %q, want connection refused", got[1].Error)
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	if err := WriteJSON(path, sampleResults()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadJSON(path)
+	if err != nil {
+		t.Fatalf("ReadJSON() error: %v", err)
+	}
+	if len(got) != len(sampleResults()) {
+		t.Errorf("ReadJSON() returned %d results, want %d", len(got), len(sampleResults()))
+	}
+
+	if _, err := ReadJSON(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
+
+func TestWriteJSON_EmptyResults(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "empty.json")
+
+	if err := WriteJSON(path, nil); err != nil {
+		t.Fatalf("WriteJSON() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "[]\n" {
+		t.Errorf("expected empty array, got %q", string(data))
+	}
+}
+
+func TestWriteJSON_CorruptExisting(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bad.json")
+	os.WriteFile(path, []byte("not json"), 0644)
+
+	if err := WriteJSON(path, sampleResults()); err == nil {
+		t.Error("expected error when existing file is not a JSON array")
+	}
+}
@@ -0,0 +1,119 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"iperf-tool/internal/format"
+	"iperf-tool/internal/model"
+)
+
+// WriteMarkdown appends one Markdown section per result to path, ready to
+// paste into an issue or wiki page. Like WriteTXT, an existing file is
+// appended to rather than overwritten.
+func WriteMarkdown(path string, results []model.TestResult) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("open markdown file: %w", err)
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	for i := range results {
+		writeMarkdownSection(&b, &results[i])
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		return fmt.Errorf("write markdown file: %w", err)
+	}
+	return nil
+}
+
+func writeMarkdownSection(w lineWriter, r *model.TestResult) {
+	title := fmt.Sprintf("## iperf2 %s:%d — %s", r.ServerAddr, r.Port, r.Timestamp.Local().Format("2006-01-02 15:04:05"))
+	if r.MeasurementID != "" {
+		title += fmt.Sprintf(" (%s)", r.MeasurementID)
+	}
+	writeln(w, title)
+	writeln(w, "")
+
+	dir := r.Direction
+	if dir == "" {
+		dir = "Normal"
+	}
+	writeln(w, "| Parameter | Value |")
+	writeln(w, "|---|---|")
+	writeln(w, fmt.Sprintf("| Protocol | %s |", r.Protocol))
+	writeln(w, fmt.Sprintf("| Direction | %s |", dir))
+	writeln(w, fmt.Sprintf("| Parallel | %d |", r.Parallel))
+	writeln(w, fmt.Sprintf("| Duration | %d s |", r.Duration))
+	if target := format.BandwidthTarget(r); target != "" {
+		writeln(w, fmt.Sprintf("| Bandwidth limit | %s |", target))
+	}
+	if r.WindowSize != "" {
+		writeln(w, fmt.Sprintf("| Window | %s |", r.WindowSize))
+	}
+	if r.DSCP != "" {
+		writeln(w, fmt.Sprintf("| DSCP/ToS | %s |", r.DSCP))
+	}
+	if r.IperfVersion != "" {
+		writeln(w, fmt.Sprintf("| iperf version | %s |", r.IperfVersion))
+	}
+	writeln(w, "")
+
+	if r.Error != "" {
+		writeln(w, fmt.Sprintf("**Error:** %s", mdEscape(r.Error)))
+		writeln(w, "")
+		return
+	}
+
+	isUDP := r.Protocol == "UDP"
+	writeln(w, fmt.Sprintf("| Direction | %s | %s | Retransmits | Jitter (ms) | Loss (%%) |", format.Units.MbpsUnit(), format.Units.MBUnit()))
+	writeln(w, "|---|---:|---:|---:|---:|---:|")
+	if r.Direction == "Bidirectional" {
+		writeln(w, mdSummaryRow("Forward", r.FwdActualMbps(), r.TotalFwdMB(), r.Retransmits, r.ActualJitterMs(), fwdLostPercent(*r), isUDP))
+		writeln(w, mdSummaryRow("Reverse", r.ReverseActualMbps(), r.TotalRevMB(), r.ReverseRetransmits, r.ReverseJitterMs, r.ReverseLostPercent, isUDP))
+	} else {
+		label := "Forward"
+		if r.Direction == "Reverse" {
+			label = "Reverse"
+		}
+		mbps := r.ReceivedMbps()
+		if mbps == 0 {
+			mbps = r.SentMbps()
+		}
+		writeln(w, mdSummaryRow(label, mbps, r.TotalFwdMB(), r.Retransmits, r.JitterMs, r.LostPercent, isUDP))
+	}
+	writeln(w, "")
+
+	if r.PingBaseline != nil || r.PingLoaded != nil {
+		method := r.LatencyMethod
+		if method == "" {
+			method = model.LatencyICMP
+		}
+		writeln(w, fmt.Sprintf("| Latency (%s) | Min (ms) | Avg (ms) | Max (ms) | Loss (%%) |", method))
+		writeln(w, "|---|---:|---:|---:|---:|")
+		if p := r.PingBaseline; p != nil {
+			writeln(w, fmt.Sprintf("| Baseline | %.2f | %.2f | %.2f | %.1f |", p.MinMs, p.AvgMs, p.MaxMs, p.PacketLoss))
+		}
+		if p := r.PingLoaded; p != nil {
+			writeln(w, fmt.Sprintf("| Under load | %.2f | %.2f | %.2f | %.1f |", p.MinMs, p.AvgMs, p.MaxMs, p.PacketLoss))
+		}
+		writeln(w, "")
+	}
+}
+
+// mdSummaryRow formats one summary table row; TCP-only and UDP-only columns
+// show a dash when they do not apply. mbps and mb are scaled to format.Units.
+func mdSummaryRow(label string, mbps, mb float64, retr int, jitter, loss float64, isUDP bool) string {
+	mbps, mb = format.Units.Scale(mbps), format.Units.Scale(mb)
+	if isUDP {
+		return fmt.Sprintf("| %s | %.2f | %.2f | — | %.3f | %.2f |", label, mbps, mb, jitter, loss)
+	}
+	return fmt.Sprintf("| %s | %.2f | %.2f | %d | — | — |", label, mbps, mb, retr)
+}
+
+// mdEscape keeps free text from breaking Markdown tables and emphasis.
+func mdEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ", "*", `\*`).Replace(s)
+}
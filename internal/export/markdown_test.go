@@ -0,0 +1,113 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"iperf-tool/internal/model"
+)
+
+func TestWriteMarkdown_TCP(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.md")
+	r := model.TestResult{
+		Timestamp:     baseTXTTime,
+		MeasurementID: "20260218-143207-01",
+		ServerAddr:    "192.168.1.1",
+		Port:          5201,
+		Protocol:      "TCP",
+		Parallel:      2,
+		Duration:      10,
+		SentBps:       100_000_000,
+		ReceivedBps:   95_000_000,
+		BytesSent:     125_000_000,
+		BytesReceived: 118_000_000,
+		Retransmits:   3,
+		PingBaseline:  &model.PingResult{PacketsSent: 4, PacketsRecv: 4, MinMs: 1, AvgMs: 1.5, MaxMs: 2},
+		PingLoaded:    &model.PingResult{PacketsSent: 10, PacketsRecv: 9, PacketLoss: 10, MinMs: 5, AvgMs: 8.25, MaxMs: 12},
+	}
+	if err := WriteMarkdown(path, []model.TestResult{r}); err != nil {
+		t.Fatalf("WriteMarkdown() error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{
+		"## iperf2 192.168.1.1:5201",
+		"(20260218-143207-01)",
+		"| Parameter | Value |",
+		"| Protocol | TCP |",
+		"| Direction | Normal |",
+		"| Parallel | 2 |",
+		"| Direction | Mbps | MB | Retransmits | Jitter (ms) | Loss (%) |",
+		"| Forward | 95.00 | 118.00 | 3 | — | — |",
+		"| Latency (ICMP ping) | Min (ms) | Avg (ms) | Max (ms) | Loss (%) |",
+		"| Baseline | 1.00 | 1.50 | 2.00 | 0.0 |",
+		"| Under load | 5.00 | 8.25 | 12.00 | 10.0 |",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("markdown missing %q\n%s", want, content)
+		}
+	}
+}
+
+func TestWriteMarkdown_BidirUDP(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.md")
+	r := model.TestResult{
+		Timestamp:          baseTXTTime,
+		ServerAddr:         "10.0.0.1",
+		Port:               5201,
+		Protocol:           "UDP",
+		Direction:          "Bidirectional",
+		SentBps:            50_000_000,
+		FwdReceivedBps:     48_000_000,
+		FwdJitterMs:        0.5,
+		FwdLostPercent:     4,
+		FwdPackets:         1000,
+		BytesSent:          60_000_000,
+		ReverseReceivedBps: 45_000_000,
+		ReverseJitterMs:    0.75,
+		ReverseLostPercent: 1.5,
+		ReverseBytesSent:   56_000_000,
+	}
+	if err := WriteMarkdown(path, []model.TestResult{r}); err != nil {
+		t.Fatalf("WriteMarkdown() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	for _, want := range []string{
+		"| Direction | Bidirectional |",
+		"| Forward | 48.00 | 60.00 | — | 0.500 | 4.00 |",
+		"| Reverse | 45.00 | 56.00 | — | 0.750 | 1.50 |",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("markdown missing %q\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "Latency") {
+		t.Error("latency table written without ping data")
+	}
+}
+
+func TestWriteMarkdown_AppendsAndError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.md")
+	first := model.TestResult{Timestamp: baseTXTTime, ServerAddr: "a", Port: 1, Protocol: "TCP"}
+	second := model.TestResult{Timestamp: baseTXTTime, ServerAddr: "b", Port: 2, Protocol: "TCP", Error: "connect failed | refused"}
+	if err := WriteMarkdown(path, []model.TestResult{first}); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteMarkdown(path, []model.TestResult{second}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if n := strings.Count(content, "## iperf2 "); n != 2 {
+		t.Errorf("sections = %d, want 2", n)
+	}
+	if !strings.Contains(content, `**Error:** connect failed \| refused`) {
+		t.Errorf("escaped error line missing:\n%s", content)
+	}
+}
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"iperf-tool/internal/model"
+)
+
+// promGauge is a single gauge in the textfile-collector output.
+type promGauge struct {
+	name  string
+	help  string
+	value float64
+}
+
+// WritePromMetrics writes the latest result as Prometheus gauges in the text
+// exposition format understood by node_exporter's textfile collector. Unlike
+// the CSV/TXT writers the file is overwritten on each call: it always holds
+// the most recent measurement. The file is written to a temp name and renamed
+// so the collector never reads a partial file.
+func WritePromMetrics(path string, r *model.TestResult) error {
+	gauges := []promGauge{
+		{"iperf_fwd_mbps", "Forward (client to server) throughput in Mbps.", r.FwdActualMbps()},
+		{"iperf_rev_mbps", "Reverse (server to client) throughput in Mbps.", r.ReverseActualMbps()},
+		{"iperf_retransmits", "TCP retransmits in the forward direction.", float64(r.Retransmits)},
+		{"iperf_jitter_ms", "Forward UDP jitter in milliseconds.", r.ActualJitterMs()},
+		{"iperf_lost_percent", "Forward UDP packet loss in percent.", fwdLostPercent(*r)},
+	}
+	if r.PingBaseline != nil {
+		gauges = append(gauges, promGauge{"iperf_ping_baseline_avg_ms", "Average ping RTT before the test in milliseconds.", r.PingBaseline.AvgMs})
+	}
+	if r.PingLoaded != nil {
+		gauges = append(gauges, promGauge{"iperf_ping_loaded_avg_ms", "Average ping RTT during the test in milliseconds.", r.PingLoaded.AvgMs})
+	}
+
+	labels := fmt.Sprintf(`{server="%s",protocol="%s"}`, promEscape(r.ServerAddr), promEscape(r.Protocol))
+
+	var b strings.Builder
+	for _, g := range gauges {
+		fmt.Fprintf(&b, "# HELP %s %s\n", g.name, g.help)
+		fmt.Fprintf(&b, "# TYPE %s gauge\n", g.name)
+		fmt.Fprintf(&b, "%s%s %g\n", g.name, labels, g.value)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("write prom file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write prom file: %w", err)
+	}
+	return nil
+}
+
+// promEscape escapes a label value per the Prometheus text format.
+func promEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
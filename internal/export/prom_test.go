@@ -0,0 +1,85 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"iperf-tool/internal/model"
+)
+
+func TestWritePromMetrics(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "iperf.prom")
+
+	r := &model.TestResult{
+		ServerAddr:     "192.168.1.1",
+		Protocol:       "TCP",
+		SentBps:        940_000_000,
+		FwdReceivedBps: 930_000_000,
+		Retransmits:    7,
+		PingBaseline:   &model.PingResult{AvgMs: 1.5},
+		PingLoaded:     &model.PingResult{AvgMs: 12.25},
+	}
+	if err := WritePromMetrics(path, r); err != nil {
+		t.Fatalf("WritePromMetrics() error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	for _, want := range []string{
+		"# HELP iperf_fwd_mbps ",
+		"# TYPE iperf_fwd_mbps gauge\n",
+		`iperf_fwd_mbps{server="192.168.1.1",protocol="TCP"} 930` + "\n",
+		`iperf_retransmits{server="192.168.1.1",protocol="TCP"} 7` + "\n",
+		`iperf_ping_baseline_avg_ms{server="192.168.1.1",protocol="TCP"} 1.5` + "\n",
+		`iperf_ping_loaded_avg_ms{server="192.168.1.1",protocol="TCP"} 12.25` + "\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in:\n%s", want, content)
+		}
+	}
+
+	// Every non-comment line must be "name{labels} value".
+	for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) != 2 || !strings.HasPrefix(fields[0], "iperf_") {
+			t.Errorf("malformed sample line %q", line)
+		}
+	}
+}
+
+func TestWritePromMetrics_Overwrites(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "iperf.prom")
+
+	first := &model.TestResult{ServerAddr: "a", Protocol: "TCP", PingBaseline: &model.PingResult{AvgMs: 1}}
+	second := &model.TestResult{ServerAddr: "b", Protocol: "UDP"}
+	if err := WritePromMetrics(path, first); err != nil {
+		t.Fatalf("first write error: %v", err)
+	}
+	if err := WritePromMetrics(path, second); err != nil {
+		t.Fatalf("second write error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if strings.Contains(content, `server="a"`) {
+		t.Error("previous run's metrics should be overwritten")
+	}
+	if strings.Contains(content, "iperf_ping_baseline_avg_ms") {
+		t.Error("ping gauges should be omitted when ping was not measured")
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temp file should not be left behind")
+	}
+}
+
+func TestPromEscape(t *testing.T) {
+	if got := promEscape(`a"b\c` + "\n"); got != `a\"b\\c\n` {
+		t.Errorf("promEscape() = %q", got)
+	}
+}
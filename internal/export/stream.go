@@ -0,0 +1,66 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+
+	"iperf-tool/internal/model"
+)
+
+// IntervalStream writes one JSON object per live interval (JSON Lines), so
+// other tools can follow a test while it runs:
+//
+//	{"t":1,"fwd_mbps":940.12,"rev_mbps":310.5}
+type IntervalStream struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// streamLine is the JSON shape of one streamed interval. A direction that
+// did not report in this interval is left out.
+type streamLine struct {
+	T       float64  `json:"t"`
+	FwdMbps *float64 `json:"fwd_mbps,omitempty"`
+	RevMbps *float64 `json:"rev_mbps,omitempty"`
+}
+
+// NewIntervalStream returns a stream that writes to w.
+func NewIntervalStream(w io.Writer) *IntervalStream {
+	return &IntervalStream{enc: json.NewEncoder(w)}
+}
+
+// Write emits one line for the paired forward/reverse interval. Either side
+// may be nil; nothing is written when both are.
+func (s *IntervalStream) Write(fwd, rev *model.IntervalResult) error {
+	if fwd == nil && rev == nil {
+		return nil
+	}
+	var line streamLine
+	if fwd != nil {
+		line.T = fwd.TimeEnd
+		v := round2(fwd.BandwidthMbps())
+		line.FwdMbps = &v
+	}
+	if rev != nil {
+		if fwd == nil {
+			line.T = rev.TimeEnd
+		}
+		v := round2(rev.BandwidthMbps())
+		line.RevMbps = &v
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.enc.Encode(line); err != nil {
+		return fmt.Errorf("write interval stream: %w", err)
+	}
+	return nil
+}
+
+// round2 rounds v to two decimal places.
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
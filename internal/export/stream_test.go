@@ -0,0 +1,40 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"iperf-tool/internal/model"
+)
+
+func TestIntervalStream_WritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewIntervalStream(&buf)
+
+	fwd := &model.IntervalResult{TimeStart: 0, TimeEnd: 1, BandwidthBps: 940_123_456}
+	rev := &model.IntervalResult{TimeStart: 0, TimeEnd: 1, BandwidthBps: 310_500_000}
+	if err := s.Write(fwd, rev); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	if err := s.Write(nil, &model.IntervalResult{TimeStart: 1, TimeEnd: 2, BandwidthBps: 5e6}); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	if err := s.Write(nil, nil); err != nil {
+		t.Fatalf("Write(nil, nil) error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := []string{
+		`{"t":1,"fwd_mbps":940.12,"rev_mbps":310.5}`,
+		`{"t":2,"rev_mbps":5}`,
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), buf.String())
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %s, want %s", i, lines[i], want[i])
+		}
+	}
+}
@@ -2,8 +2,10 @@ package export
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,10 +18,20 @@ const (
 	sectionDash = "------------------------------------------------------------------------------------------" // 90 chars
 )
 
+// TXTOptions controls optional WriteTXT behaviour.
+type TXTOptions struct {
+	Detailed bool // add Retr/s and cumulative retransmit columns to TCP interval tables, and a retransmit rate to the summary
+}
+
 // WriteTXT appends structured human-readable test result blocks to path.
 // If the file does not exist it is created; if it exists the new block is
 // appended (series logging).
 func WriteTXT(path string, results []model.TestResult) error {
+	return WriteTXTWithOptions(path, results, TXTOptions{})
+}
+
+// WriteTXTWithOptions is WriteTXT with the given options.
+func WriteTXTWithOptions(path string, results []model.TestResult, opts TXTOptions) error {
 	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		return fmt.Errorf("open txt file: %w", err)
@@ -30,7 +42,7 @@ func WriteTXT(path string, results []model.TestResult) error {
 		if i > 0 {
 			fmt.Fprintln(f)
 		}
-		writeBlock(f, &r)
+		writeBlock(f, &r, opts)
 	}
 	return nil
 }
@@ -39,17 +51,30 @@ type lineWriter interface {
 	WriteString(s string) (int, error)
 }
 
+// serverLabel returns "host:port", or "name (ip:port)" when the server was
+// given by a name that resolved to a different address.
+func serverLabel(r *model.TestResult) string {
+	port := strconv.Itoa(r.Port)
+	if ip := r.ServerResolvedIP; ip != "" && ip != r.ServerAddr {
+		return fmt.Sprintf("%s (%s)", r.ServerAddr, net.JoinHostPort(ip, port))
+	}
+	return fmt.Sprintf("%s:%d", r.ServerAddr, r.Port)
+}
+
 func writeln(w lineWriter, s string) {
 	w.WriteString(s + "\n") //nolint:errcheck
 }
 
-func writeBlock(w lineWriter, r *model.TestResult) {
+func writeBlock(w lineWriter, r *model.TestResult, opts TXTOptions) {
 	writeln(w, divider)
 
 	// Measurement ID (optional)
 	if r.MeasurementID != "" {
 		writeln(w, fmt.Sprintf("Measurement ID: %s", r.MeasurementID))
 	}
+	if r.Label != "" {
+		writeln(w, fmt.Sprintf("Label:          %s", r.Label))
+	}
 
 	// --- Header: date, env ---
 	local := r.Timestamp.Local()
@@ -67,6 +92,10 @@ func writeBlock(w lineWriter, r *model.TestResult) {
 	writeln(w, fmt.Sprintf("Time:            %s", local.Format("15:04:05")))
 	writeln(w, fmt.Sprintf("Timezone:        %s (%s)", tzName, utcStr))
 	writeln(w, fmt.Sprintf("RFC3339:         %s", r.Timestamp.Format("2006-01-02T15:04:05Z07:00")))
+	if el := r.Elapsed(); el > 0 {
+		writeln(w, fmt.Sprintf("Wall clock:      %s – %s (%.0fs elapsed)",
+			r.StartedAt.Local().Format("15:04:05"), r.FinishedAt.Local().Format("15:04:05"), el.Seconds()))
+	}
 	writeln(w, "")
 
 	if r.LocalHostname != "" {
@@ -80,9 +109,18 @@ func writeBlock(w lineWriter, r *model.TestResult) {
 	if r.LocalIP != "" {
 		writeln(w, fmt.Sprintf("Local IP:        %s", r.LocalIP))
 	}
+	if r.LocalInterface != "" {
+		writeln(w, fmt.Sprintf("Interface:       %s", format.InterfaceLabel(r)))
+	}
+	if r.BindAddr != "" {
+		writeln(w, fmt.Sprintf("Bind IP:         %s", r.BindAddr))
+	}
 	if r.IperfVersion != "" {
 		writeln(w, fmt.Sprintf("iperf version:   %s", r.IperfVersion))
 	}
+	if r.RemoteIperfVersion != "" {
+		writeln(w, fmt.Sprintf("Remote iperf:    %s", r.RemoteIperfVersion))
+	}
 	if r.Mode != "" {
 		writeln(w, fmt.Sprintf("Mode:            %s", r.Mode))
 	}
@@ -93,8 +131,20 @@ func writeBlock(w lineWriter, r *model.TestResult) {
 
 	// --- Test Parameters ---
 	writeln(w, "--- Test Parameters ---")
-	writeln(w, fmt.Sprintf("Server:          %s:%d", r.ServerAddr, r.Port))
+	writeln(w, fmt.Sprintf("Server:          %s", serverLabel(r)))
 	writeln(w, fmt.Sprintf("Protocol:        %s", r.Protocol))
+	if r.AddrFamily != "" {
+		writeln(w, fmt.Sprintf("Address family:  %s", r.AddrFamily))
+	}
+	if r.DSCP != "" {
+		writeln(w, fmt.Sprintf("DSCP/ToS:        %s", r.DSCP))
+	}
+	if r.NoDelay {
+		writeln(w, "TCP no-delay:    on (-N)")
+	}
+	if len(r.ExtraArgs) > 0 {
+		writeln(w, fmt.Sprintf("Extra args:      %s", strings.Join(r.ExtraArgs, " ")))
+	}
 
 	dir := r.Direction
 	switch dir {
@@ -107,13 +157,38 @@ func writeBlock(w lineWriter, r *model.TestResult) {
 	}
 	writeln(w, fmt.Sprintf("Direction:       %s", dir))
 	writeln(w, fmt.Sprintf("Parallel:        %d streams", r.Parallel))
-	writeln(w, fmt.Sprintf("Requested time:  %d seconds", r.Duration))
-	if r.Bandwidth != "" {
-		writeln(w, fmt.Sprintf("Bandwidth limit: %s Mbps/stream", r.Bandwidth))
+	if r.BytesLimit != "" || r.BlocksLimit > 0 {
+		writeln(w, fmt.Sprintf("Test bound:      %s", r.RunLabel()))
+	} else {
+		writeln(w, fmt.Sprintf("Requested time:  %d seconds", r.Duration))
+	}
+	if r.SampleCount > 1 {
+		writeln(w, fmt.Sprintf("Averaged:        mean of %d runs (stddev %s)", r.SampleCount, format.Units.Mbps(r.StdDevMbps)))
+	}
+	if r.OmitSeconds > 0 {
+		writeln(w, fmt.Sprintf("Omitted warm-up: %d seconds", r.OmitSeconds))
+	}
+	if target := format.BandwidthTarget(r); target != "" {
+		writeln(w, fmt.Sprintf("Bandwidth limit: %s", target))
 	}
 	if r.Congestion != "" {
 		writeln(w, fmt.Sprintf("Congestion:      %s", r.Congestion))
 	}
+	if r.WindowSize != "" {
+		writeln(w, fmt.Sprintf("Window (req):    %s", r.WindowSize))
+	}
+	if r.RequestedMSS > 0 {
+		writeln(w, fmt.Sprintf("MSS (req):       %d bytes", r.RequestedMSS))
+	}
+	if r.ConnectTimeoutMs > 0 {
+		writeln(w, fmt.Sprintf("Connect timeout: %d ms", r.ConnectTimeoutMs))
+	}
+	if r.MSS > 0 {
+		writeln(w, fmt.Sprintf("MSS:             %d bytes", r.MSS))
+	}
+	if r.SndBufBytes > 0 {
+		writeln(w, fmt.Sprintf("Socket buffer:   %d bytes", r.SndBufBytes))
+	}
 	writeln(w, "")
 
 	// Error case — short circuit
@@ -132,20 +207,47 @@ func writeBlock(w lineWriter, r *model.TestResult) {
 	}
 
 	// --- Results table ---
-	writeResultsTable(w, r)
+	writeResultsTable(w, r, opts)
 
 	// --- Summary section ---
-	writeSummarySection(w, r)
+	writeSummarySection(w, r, opts)
 
 	// --- Per-Stream Average Bandwidth ---
 	writeStreamSection(w, r)
 
+	// --- Raw server-side output (opt-in) ---
+	writeServerOutputSection(w, r)
+
 	// --- Latency + END OF MEASUREMENT ---
 	writeLatencySection(w, r)
 }
 
+// writeServerOutputSection writes r.ServerOutputText verbatim. It is only
+// set when the run was asked to keep the server output.
+func writeServerOutputSection(w lineWriter, r *model.TestResult) {
+	if strings.TrimSpace(r.ServerOutputText) == "" {
+		return
+	}
+	writeln(w, sectionDash)
+	writeln(w, "Server Output")
+	writeln(w, sectionDash)
+	writeln(w, "")
+	writeln(w, r.ServerOutputText)
+	writeln(w, "")
+}
+
+// txtMaxIntervalRows is the most interval rows the TXT report prints; longer
+// runs are downsampled to fit.
+const txtMaxIntervalRows = 500
+
+// txtTableHeader returns the interval table header with the rate and
+// transfer columns labelled in format.Units, followed by rest.
+func txtTableHeader(rest string) string {
+	return fmt.Sprintf("%-26s %-10s %-10s %s", "Timestamp", format.Units.MbpsUnit(), format.Units.MBUnit(), rest)
+}
+
 // writeResultsTable writes the Results table with sectionDash dividers.
-func writeResultsTable(w lineWriter, r *model.TestResult) {
+func writeResultsTable(w lineWriter, r *model.TestResult, opts TXTOptions) {
 	if len(r.Intervals) == 0 {
 		return
 	}
@@ -158,35 +260,58 @@ func writeResultsTable(w lineWriter, r *model.TestResult) {
 	isBidir := r.Direction == "Bidirectional"
 	isUDP := r.Protocol == "UDP"
 
+	tsLayout := "02.01.2006 15:04:05"
+	if model.HasSubSecondIntervals(r.Intervals) {
+		tsLayout = "02.01.2006 15:04:05.000"
+	}
+
+	// The interval CSV keeps full resolution; the report stays readable
+	intervals := r.DownsampleIntervals(txtMaxIntervalRows)
+	reverse := r.ReverseDownsampleIntervals(txtMaxIntervalRows)
+	if len(intervals) < len(r.Intervals) {
+		writeln(w, fmt.Sprintf("(downsampled from %d intervals)", len(r.Intervals)))
+	}
+
 	if isBidir {
 		writeln(w, "Timestamp                  "+format.FormatBidirIntervalHeader(isUDP))
-		for i, iv := range r.Intervals {
+		for i, iv := range intervals {
 			wallTime := r.Timestamp.Local().Add(time.Duration(iv.TimeStart * float64(time.Second)))
-			ts := wallTime.Format("02.01.2006 15:04:05")
+			ts := wallTime.Format(tsLayout)
 			var rev *model.IntervalResult
-			if i < len(r.ReverseIntervals) {
-				rv := r.ReverseIntervals[i]
+			if i < len(reverse) {
+				rv := reverse[i]
 				rev = &rv
 			}
 			writeln(w, fmt.Sprintf("%-26s %s", ts, format.FormatBidirInterval(&iv, rev, isUDP)))
 		}
 	} else if isUDP {
-		writeln(w, "Timestamp                  Mbps       MB         Packets   Lost   Loss%    Jitter")
-		for _, iv := range r.Intervals {
+		writeln(w, txtTableHeader("Packets   Lost   Loss%    Jitter"))
+		for _, iv := range intervals {
 			wallTime := r.Timestamp.Local().Add(time.Duration(iv.TimeStart * float64(time.Second)))
-			ts := wallTime.Format("02.01.2006 15:04:05")
+			ts := wallTime.Format(tsLayout)
 			writeln(w, fmt.Sprintf("%-26s %-10.2f %-10.2f %-9d %-6d %-8.2f %.3f ms",
-				ts, iv.BandwidthMbps(), iv.TransferMB(),
+				ts, format.Units.Scale(iv.BandwidthMbps()), format.Units.Scale(iv.TransferMB()),
 				iv.Packets, iv.LostPackets, iv.LostPercent, iv.JitterMs))
 		}
+	} else if opts.Detailed {
+		// Normal / Reverse TCP with retransmit rate and running total
+		writeln(w, txtTableHeader("Retr   Retr/s   Cum Retr"))
+		cum := 0
+		for _, iv := range intervals {
+			wallTime := r.Timestamp.Local().Add(time.Duration(iv.TimeStart * float64(time.Second)))
+			ts := wallTime.Format(tsLayout)
+			cum += iv.Retransmits
+			writeln(w, fmt.Sprintf("%-26s %-10.2f %-10.2f %-6d %-8.1f %d",
+				ts, format.Units.Scale(iv.BandwidthMbps()), format.Units.Scale(iv.TransferMB()), iv.Retransmits, iv.RetransmitsPerSecond(), cum))
+		}
 	} else {
 		// Normal / Reverse TCP
-		writeln(w, "Timestamp                  Mbps       MB         Retr")
-		for _, iv := range r.Intervals {
+		writeln(w, txtTableHeader("Retr"))
+		for _, iv := range intervals {
 			wallTime := r.Timestamp.Local().Add(time.Duration(iv.TimeStart * float64(time.Second)))
-			ts := wallTime.Format("02.01.2006 15:04:05")
+			ts := wallTime.Format(tsLayout)
 			writeln(w, fmt.Sprintf("%-26s %-10.2f %-10.2f %d",
-				ts, iv.BandwidthMbps(), iv.TransferMB(), iv.Retransmits))
+				ts, format.Units.Scale(iv.BandwidthMbps()), format.Units.Scale(iv.TransferMB()), iv.Retransmits))
 		}
 	}
 
@@ -195,7 +320,7 @@ func writeResultsTable(w lineWriter, r *model.TestResult) {
 
 // writeSummarySection writes the Summary block with sectionDash dividers.
 // The content mirrors FormatResult's --- Summary --- section for consistency.
-func writeSummarySection(w lineWriter, r *model.TestResult) {
+func writeSummarySection(w lineWriter, r *model.TestResult, opts TXTOptions) {
 	writeln(w, sectionDash)
 	writeln(w, "Summary")
 	writeln(w, sectionDash)
@@ -222,19 +347,19 @@ func writeSummarySection(w lineWriter, r *model.TestResult) {
 			revMbps = r.ReceivedMbps()
 		}
 		if isUDP {
-			writeln(w, fmt.Sprintf("Client Send:     %.2f Mbps", r.SentMbps()))
+			writeln(w, fmt.Sprintf("Client Send:     %s", format.Units.Mbps(r.SentMbps())))
 			if r.FwdReceivedBps > 0 {
-				writeln(w, fmt.Sprintf("Server Recv:     %.2f Mbps", r.FwdActualMbps()))
+				writeln(w, fmt.Sprintf("Server Recv:     %s", format.Units.Mbps(r.FwdActualMbps())))
 			} else {
 				writeln(w, "Server Recv:     N/A")
 			}
 			if r.Interrupted && r.ReverseSentBps == 0 {
 				writeln(w, "Server Send:     N/A")
 			} else {
-				writeln(w, fmt.Sprintf("Server Send:     %.2f Mbps", r.ReverseSentMbps()))
+				writeln(w, fmt.Sprintf("Server Send:     %s", format.Units.Mbps(r.ReverseSentMbps())))
 			}
 			if revRecv := r.ReverseActualMbps(); revRecv > 0 {
-				writeln(w, fmt.Sprintf("Client Recv:     %.2f Mbps", revRecv))
+				writeln(w, fmt.Sprintf("Client Recv:     %s", format.Units.Mbps(revRecv)))
 			}
 			if r.ActualJitterMs() > 0 {
 				writeln(w, fmt.Sprintf("C→S Jitter:      %.3f ms", r.ActualJitterMs()))
@@ -242,60 +367,89 @@ func writeSummarySection(w lineWriter, r *model.TestResult) {
 			if r.FwdPackets > 0 {
 				writeln(w, fmt.Sprintf("C→S Lost:        %d/%d (%.2f%%)", r.FwdLostPackets, r.FwdPackets, r.FwdLostPercent))
 			}
+			if line := format.EfficiencyLine(r, "C→S Efficiency:"); line != "" {
+				writeln(w, line)
+			}
+			if pps := r.AvgPPS(); pps > 0 {
+				writeln(w, fmt.Sprintf("C→S Packets/s:   %.0f", pps))
+			}
 			if r.ReverseJitterMs > 0 {
 				writeln(w, fmt.Sprintf("S→C Jitter:      %.3f ms", r.ReverseJitterMs))
 			}
 			if r.ReversePackets > 0 {
 				writeln(w, fmt.Sprintf("S→C Lost:        %d/%d (%.2f%%)", r.ReverseLostPackets, r.ReversePackets, r.ReverseLostPercent))
 			}
+			if pps := r.ReverseAvgPPS(); pps > 0 {
+				writeln(w, fmt.Sprintf("S→C Packets/s:   %.0f", pps))
+			}
 		} else {
-			writeln(w, fmt.Sprintf("Send:            %.2f Mbps (retransmits: %d)", r.FwdActualMbps(), r.Retransmits))
-			writeln(w, fmt.Sprintf("Receive:         %.2f Mbps (retransmits: %d)", revMbps, revRetrans))
+			writeln(w, fmt.Sprintf("Send:            %s (retransmits: %d)", format.Units.Mbps(r.FwdActualMbps()), r.Retransmits))
+			writeln(w, fmt.Sprintf("Receive:         %s (retransmits: %d)", format.Units.Mbps(revMbps), revRetrans))
 		}
 		// C→S: client sent, server received
-		csSent := float64(r.BytesSent) / 1e6
-		csRecv := float64(r.BytesReceived) / 1e6
+		csSent := format.Units.MB(r.SentMB())
+		csRecv := format.Units.MB(r.ReceivedMB())
 		if r.BytesReceived > 0 {
-			writeln(w, fmt.Sprintf("C→S transferred: %.2f MB sent / %.2f MB received", csSent, csRecv))
+			writeln(w, fmt.Sprintf("C→S transferred: %s sent / %s received", csSent, csRecv))
 		} else {
-			writeln(w, fmt.Sprintf("C→S transferred: %.2f MB sent", csSent))
+			writeln(w, fmt.Sprintf("C→S transferred: %s sent", csSent))
 		}
 		// S→C: server sent, client received
-		scSent := float64(r.ReverseBytesSent) / 1e6
-		scRecv := r.TotalRevMB()
+		scSent := format.Units.MB(float64(r.ReverseBytesSent) / 1e6)
+		scRecv := format.Units.MB(r.TotalRevMB())
 		if r.ReverseBytesSent > 0 {
-			writeln(w, fmt.Sprintf("S→C transferred: %.2f MB sent / %.2f MB received", scSent, scRecv))
+			writeln(w, fmt.Sprintf("S→C transferred: %s sent / %s received", scSent, scRecv))
 		} else {
-			writeln(w, fmt.Sprintf("S→C transferred: %.2f MB received", scRecv))
+			writeln(w, fmt.Sprintf("S→C transferred: %s received", scRecv))
+		}
+		if line := format.BidirDurationLine(r); line != "" {
+			writeln(w, line)
 		}
 	} else if isUDP {
-		writeln(w, fmt.Sprintf("Sent:            %.2f Mbps", r.SentMbps()))
+		writeln(w, fmt.Sprintf("Sent:            %s", format.Units.Mbps(r.SentMbps())))
 		if hasReceiver {
-			writeln(w, fmt.Sprintf("Received:        %.2f Mbps", r.ReceivedMbps()))
+			writeln(w, fmt.Sprintf("Received:        %s", format.Units.Mbps(r.ReceivedMbps())))
 		}
-		writeln(w, fmt.Sprintf("Jitter:          %.3f ms", r.JitterMs))
+		writeln(w, fmt.Sprintf("Jitter:          %.3f ms", r.ActualJitterMs()))
 		if r.FwdPackets > 0 {
 			writeln(w, fmt.Sprintf("Packet Loss:     %d/%d (%.2f%%)", r.FwdLostPackets, r.FwdPackets, r.FwdLostPercent))
 		} else {
 			writeln(w, fmt.Sprintf("Packet Loss:     %d/%d (%.2f%%)", r.LostPackets, r.Packets, r.LostPercent))
 		}
+		if line := format.EfficiencyLine(r, "Efficiency:"); line != "" {
+			writeln(w, line)
+		}
+		if pps := r.AvgPPS(); pps > 0 {
+			writeln(w, fmt.Sprintf("Packets/s:       %.0f", pps))
+		}
 	} else if hasReceiver {
-		writeln(w, fmt.Sprintf("Sent:            %.2f Mbps", r.SentMbps()))
-		writeln(w, fmt.Sprintf("Received:        %.2f Mbps", r.ReceivedMbps()))
+		writeln(w, fmt.Sprintf("Sent:            %s", format.Units.Mbps(r.SentMbps())))
+		writeln(w, fmt.Sprintf("Received:        %s", format.Units.Mbps(r.ReceivedMbps())))
 		writeln(w, fmt.Sprintf("Retransmits:     %d", r.Retransmits))
 	} else {
-		writeln(w, fmt.Sprintf("Bandwidth:       %.2f Mbps", r.SentMbps()))
+		writeln(w, fmt.Sprintf("Bandwidth:       %s", format.Units.Mbps(r.SentMbps())))
 		writeln(w, fmt.Sprintf("Retransmits:     %d", r.Retransmits))
 	}
+	if opts.Detailed && !isBidir && !isUDP && actualDur > 0 {
+		writeln(w, fmt.Sprintf("Retr rate:       %.1f/s (%d cumulative over %.1f s)",
+			float64(r.Retransmits)/actualDur, r.Retransmits, actualDur))
+	}
 
 	if !isBidir && (r.BytesSent > 0 || r.BytesReceived > 0) {
-		writeln(w, fmt.Sprintf("Transferred:     %.2f MB sent / %.2f MB received", r.SentMB(), r.ReceivedMB()))
+		writeln(w, fmt.Sprintf("Transferred:     %s sent / %s received", format.Units.MB(r.SentMB()), format.Units.MB(r.ReceivedMB())))
+	}
+
+	for _, line := range format.StabilityLines(r) {
+		writeln(w, line)
 	}
 
 	sentOK, recvOK := r.VerifyStreamTotals()
 	if !sentOK || !recvOK {
 		writeln(w, "WARNING: Per-stream totals do not match summary values")
 	}
+	if line := format.SkewWarning(r); line != "" {
+		writeln(w, line)
+	}
 
 	writeln(w, "")
 	errStr := "none"
@@ -304,6 +458,9 @@ func writeSummarySection(w lineWriter, r *model.TestResult) {
 	} else if r.Interrupted {
 		errStr = "Interrupted"
 	}
+	if len(r.Warnings) > 0 {
+		writeln(w, fmt.Sprintf("Warnings: %s", strings.Join(r.Warnings, "; ")))
+	}
 	writeln(w, fmt.Sprintf("Errors: %s", errStr))
 	if actualDur > 0 {
 		writeln(w, fmt.Sprintf("Actual duration: %.1f s", actualDur))
@@ -322,6 +479,13 @@ func writeStreamSection(w lineWriter, r *model.TestResult) {
 	writeln(w, "Per-Stream Results")
 	writeln(w, sectionDash)
 	writeln(w, "")
+	if r.Parallel > 1 {
+		writeln(w, fmt.Sprintf("Fairness:  %.3f (Jain's index, 1.000 = equal shares)", r.FairnessIndex()))
+		if line := format.StreamSpreadLine(r); line != "" {
+			writeln(w, line)
+		}
+		writeln(w, "")
+	}
 
 	isUDP := r.Protocol == "UDP"
 	isBidir := r.Direction == "Bidirectional"
@@ -335,14 +499,14 @@ func writeStreamSection(w lineWriter, r *model.TestResult) {
 					jitter = "N/A"
 				}
 				if s.Packets > 0 {
-					writeln(w, fmt.Sprintf("Stream %d [Fwd]:  %.2f Mbps  Jitter: %s  Lost: %d/%d (%.2f%%)",
-						s.ID, s.SentMbps(), jitter, s.LostPackets, s.Packets, s.LostPercent))
+					writeln(w, fmt.Sprintf("Stream %d [Fwd]:  %s  Jitter: %s  Lost: %d/%d (%.2f%%)",
+						s.ID, format.Units.Mbps(s.SentMbps()), jitter, s.LostPackets, s.Packets, s.LostPercent))
 				} else {
-					writeln(w, fmt.Sprintf("Stream %d [Fwd]:  %.2f Mbps  Jitter: %s",
-						s.ID, s.SentMbps(), jitter))
+					writeln(w, fmt.Sprintf("Stream %d [Fwd]:  %s  Jitter: %s",
+						s.ID, format.Units.Mbps(s.SentMbps()), jitter))
 				}
 			} else {
-				mbps := fmt.Sprintf("%.2f Mbps", s.SentMbps())
+				mbps := format.Units.Mbps(s.SentMbps())
 				if r.Interrupted && s.SentBps == 0 {
 					mbps = "N/A"
 				}
@@ -350,8 +514,8 @@ func writeStreamSection(w lineWriter, r *model.TestResult) {
 					s.ID, mbps, s.JitterMs, s.LostPackets, s.Packets, s.LostPercent))
 			}
 		} else if isUDP {
-			writeln(w, fmt.Sprintf("Stream %d:  %.2f Mbps  Jitter: %.3f ms  Lost: %d/%d (%.2f%%)",
-				s.ID, s.SentMbps(), s.JitterMs, s.LostPackets, s.Packets, s.LostPercent))
+			writeln(w, fmt.Sprintf("Stream %d:  %s  Jitter: %.3f ms  Lost: %d/%d (%.2f%%)",
+				s.ID, format.Units.Mbps(s.SentMbps()), s.JitterMs, s.LostPackets, s.Packets, s.LostPercent))
 		} else if isBidir {
 			dir := "Rev"
 			bps := s.ReceivedMbps()
@@ -359,12 +523,12 @@ func writeStreamSection(w lineWriter, r *model.TestResult) {
 				dir = "Fwd"
 				bps = s.SentMbps()
 			}
-			writeln(w, fmt.Sprintf("Stream %d [%s]:  %.2f Mbps", s.ID, dir, bps))
-		} else if hasReceiver {
-			writeln(w, fmt.Sprintf("Stream %d:  Sent: %.2f Mbps  Received: %.2f Mbps",
-				s.ID, s.SentMbps(), s.ReceivedMbps()))
+			writeln(w, fmt.Sprintf("Stream %d [%s]:  %s%s", s.ID, dir, format.Units.Mbps(bps), format.StreamTCPInfo(&s)))
+		} else if hasReceiver && s.ReceivedBps > 0 {
+			writeln(w, fmt.Sprintf("Stream %d:  Sent: %s  Received: %s%s",
+				s.ID, format.Units.Mbps(s.SentMbps()), format.Units.Mbps(s.ReceivedMbps()), format.StreamTCPInfo(&s)))
 		} else {
-			writeln(w, fmt.Sprintf("Stream %d:  %.2f Mbps", s.ID, s.SentMbps()))
+			writeln(w, fmt.Sprintf("Stream %d:  %s%s", s.ID, format.Units.Mbps(s.SentMbps()), format.StreamTCPInfo(&s)))
 		}
 	}
 
@@ -373,13 +537,22 @@ func writeStreamSection(w lineWriter, r *model.TestResult) {
 
 // writeLatencySection writes the freestanding LATENCY ANALYSIS block and END OF MEASUREMENT.
 func writeLatencySection(w lineWriter, r *model.TestResult) {
+	if r.PingSkipped && r.PingBaseline == nil && r.PingLoaded == nil {
+		writeln(w, "Latency:         skipped (ICMP appears blocked)")
+		writeln(w, "")
+		return
+	}
 	if r.PingBaseline != nil || r.PingLoaded != nil {
 		writeln(w, divider)
 		writeln(w, "LATENCY ANALYSIS")
 		writeln(w, divider)
 		writeln(w, "")
 
-		writeln(w, "Method:           ICMP ping")
+		method := r.LatencyMethod
+		if method == "" {
+			method = model.LatencyICMP
+		}
+		writeln(w, fmt.Sprintf("Method:           %s", method))
 
 		samples := 0
 		if r.PingLoaded != nil {
@@ -392,18 +565,27 @@ func writeLatencySection(w lineWriter, r *model.TestResult) {
 		writeln(w, "")
 
 		if r.PingBaseline != nil {
-			writeln(w, fmt.Sprintf("Baseline:         min/avg/max = %.2f / %.2f / %.2f ms",
-				r.PingBaseline.MinMs, r.PingBaseline.AvgMs, r.PingBaseline.MaxMs))
+			writeln(w, "Baseline:         "+format.PingStats(r.PingBaseline))
+			if r.PingBaseline.P50Ms > 0 {
+				writeln(w, fmt.Sprintf("                  p50/p95/p99 = %.2f / %.2f / %.2f ms",
+					r.PingBaseline.P50Ms, r.PingBaseline.P95Ms, r.PingBaseline.P99Ms))
+			}
 		}
 		if r.PingLoaded != nil {
-			writeln(w, fmt.Sprintf("Under load:       min/avg/max = %.2f / %.2f / %.2f ms",
-				r.PingLoaded.MinMs, r.PingLoaded.AvgMs, r.PingLoaded.MaxMs))
+			writeln(w, "Under load:       "+format.PingStats(r.PingLoaded))
+			if r.PingLoaded.P50Ms > 0 {
+				writeln(w, fmt.Sprintf("                  p50/p95/p99 = %.2f / %.2f / %.2f ms",
+					r.PingLoaded.P50Ms, r.PingLoaded.P95Ms, r.PingLoaded.P99Ms))
+			}
 		}
 		if r.PingBaseline != nil && r.PingLoaded != nil && r.PingBaseline.AvgMs > 0 {
 			increase := r.PingLoaded.AvgMs - r.PingBaseline.AvgMs
 			pct := increase / r.PingBaseline.AvgMs * 100
 			writeln(w, fmt.Sprintf("Increase:         +%.2f ms (+%.1f%%)", increase, pct))
 		}
+		if grade := r.BufferbloatGrade(); grade != "" {
+			writeln(w, fmt.Sprintf("Bufferbloat:      %s", grade))
+		}
 		writeln(w, "")
 	}
 
@@ -77,6 +77,50 @@ func TestWriteTXT_Basic(t *testing.T) {
 	}
 }
 
+func TestWriteTXT_DetailedRetransmits(t *testing.T) {
+	dir := t.TempDir()
+	result := model.TestResult{
+		Timestamp:      baseTXTTime,
+		ServerAddr:     "192.168.1.1",
+		Port:           5201,
+		Protocol:       "TCP",
+		Parallel:       1,
+		Duration:       2,
+		SentBps:        100_000_000,
+		Retransmits:    6,
+		ActualDuration: 2,
+		Intervals: []model.IntervalResult{
+			{TimeStart: 0, TimeEnd: 1, Bytes: 12_500_000, BandwidthBps: 100_000_000, Retransmits: 2},
+			{TimeStart: 1, TimeEnd: 2, Bytes: 12_500_000, BandwidthBps: 100_000_000, Retransmits: 4},
+		},
+	}
+
+	plain := filepath.Join(dir, "plain.txt")
+	if err := WriteTXT(plain, []model.TestResult{result}); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+	data, _ := os.ReadFile(plain)
+	if strings.Contains(string(data), "Retr/s") || strings.Contains(string(data), "Retr rate:") {
+		t.Errorf("Retr/s must be opt-in, got:\n%s", data)
+	}
+
+	detailed := filepath.Join(dir, "detailed.txt")
+	if err := WriteTXTWithOptions(detailed, []model.TestResult{result}, TXTOptions{Detailed: true}); err != nil {
+		t.Fatalf("WriteTXTWithOptions() error: %v", err)
+	}
+	data, _ = os.ReadFile(detailed)
+	content := string(data)
+	for _, want := range []string{
+		"Mbps       MB         Retr   Retr/s   Cum Retr",
+		"100.00     12.50      4      4.0      6",
+		"Retr rate:       3.0/s (6 cumulative over 2.0 s)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("detailed TXT missing %q\nFull content:\n%s", want, content)
+		}
+	}
+}
+
 func TestWriteTXT_ReverseMode(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "results.txt")
@@ -447,6 +491,9 @@ func TestWriteTXT_WithLatency(t *testing.T) {
 	if !strings.Contains(content, "Increase:") {
 		t.Error("missing Increase: line")
 	}
+	if !strings.Contains(content, "Bufferbloat:      B") {
+		t.Error("missing Bufferbloat grade (+8 ms = B)")
+	}
 	if !strings.Contains(content, "END OF MEASUREMENT") {
 		t.Error("missing END OF MEASUREMENT")
 	}
@@ -459,6 +506,26 @@ func TestWriteTXT_WithLatency(t *testing.T) {
 	}
 }
 
+func TestWriteTXT_LatencyPercentiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.txt")
+
+	results := []model.TestResult{{
+		Timestamp:  baseTXTTime,
+		ServerAddr: "192.168.1.1",
+		Protocol:   "TCP",
+		PingLoaded: &model.PingResult{MinMs: 5.0, AvgMs: 10.0, MaxMs: 50.0, P50Ms: 9.0, P95Ms: 33.0, P99Ms: 49.5},
+	}}
+	if err := WriteTXT(path, results); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "p50/p95/p99 = 9.00 / 33.00 / 49.50 ms") {
+		t.Errorf("missing percentile line in:\n%s", data)
+	}
+}
+
 func TestWriteTXT_WithTCPIntervals(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "results.txt")
@@ -513,6 +580,28 @@ func TestWriteTXT_WithTCPIntervals(t *testing.T) {
 	}
 }
 
+func TestWriteTXT_UDPEfficiency(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.txt")
+	results := []model.TestResult{{
+		Timestamp:           baseTXTTime,
+		ServerAddr:          "192.168.1.1",
+		Protocol:            "UDP",
+		Parallel:            2,
+		SentBps:             50_000_000,
+		FwdReceivedBps:      45_000_000,
+		ReceivedBps:         45_000_000,
+		TargetPerStreamMbps: 25,
+		TargetTotalMbps:     50,
+	}}
+	if err := WriteTXT(path, results); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "Efficiency:      90.0% of 50.00 Mbps target") {
+		t.Errorf("missing efficiency line:\n%s", data)
+	}
+}
+
 func TestWriteTXT_WithUDPIntervals(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "results.txt")
@@ -581,16 +670,17 @@ func TestWriteTXT_MeasurementID(t *testing.T) {
 
 	results := []model.TestResult{
 		{
-			Timestamp:     baseTXTTime,
-			ServerAddr:    "192.168.1.1",
-			Port:          5201,
-			Protocol:      "TCP",
-			Parallel:      1,
-			Duration:      10,
-			MeasurementID: "20260218-143207-01",
-			Mode:          "CLI",
-			LocalHostname: "myhost",
-			IperfVersion:  "3.17",
+			Timestamp:          baseTXTTime,
+			ServerAddr:         "192.168.1.1",
+			Port:               5201,
+			Protocol:           "TCP",
+			Parallel:           1,
+			Duration:           10,
+			MeasurementID:      "20260218-143207-01",
+			Mode:               "CLI",
+			LocalHostname:      "myhost",
+			IperfVersion:       "3.17",
+			RemoteIperfVersion: "2.1.9",
 		},
 	}
 
@@ -612,6 +702,9 @@ func TestWriteTXT_MeasurementID(t *testing.T) {
 	if !strings.Contains(content, "iperf version:   3.17") {
 		t.Error("missing iperf version")
 	}
+	if !strings.Contains(content, "Remote iperf:    2.1.9") {
+		t.Error("missing remote iperf version")
+	}
 }
 
 func TestWriteTXT_EmptyResults(t *testing.T) {
@@ -632,3 +725,378 @@ func TestWriteTXT_EmptyResults(t *testing.T) {
 		t.Errorf("expected empty file for nil results, got %d bytes: %q", len(data), string(data))
 	}
 }
+
+func TestWriteTXT_SocketInfo(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.txt")
+
+	results := []model.TestResult{
+		{
+			Timestamp:   baseTXTTime,
+			ServerAddr:  "192.168.1.1",
+			Port:        5201,
+			Protocol:    "TCP",
+			Parallel:    1,
+			Duration:    10,
+			MSS:         1448,
+			SndBufBytes: 87040,
+		},
+		{
+			Timestamp:  baseTXTTime,
+			ServerAddr: "192.168.1.1",
+			Port:       5201,
+			Protocol:   "UDP",
+			Parallel:   1,
+			Duration:   10,
+		},
+	}
+
+	if err := WriteTXT(path, results); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if strings.Count(content, "MSS:             1448 bytes") != 1 {
+		t.Error("expected MSS line exactly once (TCP block only)")
+	}
+	if strings.Count(content, "Socket buffer:   87040 bytes") != 1 {
+		t.Error("expected socket buffer line exactly once (TCP block only)")
+	}
+}
+
+func TestWriteTXT_OmitSeconds(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.txt")
+
+	results := []model.TestResult{
+		{
+			Timestamp:   baseTXTTime,
+			ServerAddr:  "192.168.1.1",
+			Port:        5201,
+			Protocol:    "TCP",
+			Parallel:    1,
+			Duration:    10,
+			OmitSeconds: 2,
+		},
+	}
+
+	if err := WriteTXT(path, results); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "Omitted warm-up: 2 seconds") {
+		t.Error("missing omit seconds line in Test Parameters")
+	}
+}
+
+func TestWriteTXT_AddrFamily(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.txt")
+
+	results := []model.TestResult{
+		{
+			Timestamp:  baseTXTTime,
+			ServerAddr: "server.example.com",
+			Port:       5201,
+			Protocol:   "TCP",
+			Parallel:   1,
+			Duration:   10,
+			AddrFamily: "IPv6",
+		},
+	}
+
+	if err := WriteTXT(path, results); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "Address family:  IPv6") {
+		t.Error("missing address family line")
+	}
+}
+
+func TestWriteTXT_DSCPAndWindow(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.txt")
+
+	results := []model.TestResult{
+		{
+			Timestamp:  baseTXTTime,
+			ServerAddr: "server.example.com",
+			Port:       5201,
+			Protocol:   "UDP",
+			Parallel:   1,
+			Duration:   10,
+			DSCP:       "ef",
+			WindowSize: "2M",
+			LocalIP:    "10.1.1.5",
+			BindAddr:   "10.1.1.5",
+			NoDelay:    true,
+
+			LocalInterface: "eth0",
+			LocalLinkMbps:  1000,
+
+			RequestedMSS:     1200,
+			ConnectTimeoutMs: 3000,
+			ExtraArgs:        []string{"--trip-times", "-Z", "bbr"},
+		},
+	}
+
+	if err := WriteTXT(path, results); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "DSCP/ToS:        ef") {
+		t.Error("missing DSCP line in Test Parameters")
+	}
+	if !strings.Contains(string(data), "Window (req):    2M") {
+		t.Error("missing requested window line in Test Parameters")
+	}
+	if !strings.Contains(string(data), "Interface:       eth0 (1000 Mbps)") {
+		t.Error("missing interface line in header")
+	}
+	if !strings.Contains(string(data), "Bind IP:         10.1.1.5") {
+		t.Error("missing Bind IP line in header")
+	}
+	if !strings.Contains(string(data), "MSS (req):       1200 bytes") {
+		t.Error("missing requested MSS line in Test Parameters")
+	}
+	if !strings.Contains(string(data), "TCP no-delay:    on (-N)") {
+		t.Error("missing no-delay line in Test Parameters")
+	}
+	if !strings.Contains(string(data), "Connect timeout: 3000 ms") {
+		t.Error("missing connect timeout line in Test Parameters")
+	}
+	if !strings.Contains(string(data), "Extra args:      --trip-times -Z bbr") {
+		t.Error("missing extra args line in Test Parameters")
+	}
+}
+
+func TestWriteTXT_Stability(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.txt")
+	r := model.TestResult{
+		Timestamp: baseTXTTime,
+		Protocol:  "TCP",
+		SentBps:   10_000_000,
+		Intervals: []model.IntervalResult{
+			{TimeStart: 0, TimeEnd: 1, BandwidthBps: 9_000_000},
+			{TimeStart: 1, TimeEnd: 2, BandwidthBps: 10_000_000},
+			{TimeStart: 2, TimeEnd: 3, BandwidthBps: 11_000_000},
+		},
+	}
+	if err := WriteTXT(path, []model.TestResult{r}); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Stability:       10.00±1.00 (9.00–11.00) Mbps") {
+		t.Errorf("missing stability line:\n%s", data)
+	}
+}
+
+func TestWriteTXT_WallClock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.txt")
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	r := model.TestResult{
+		Timestamp:  start,
+		Protocol:   "TCP",
+		Duration:   10,
+		StartedAt:  start,
+		FinishedAt: start.Add(14 * time.Second),
+	}
+	if err := WriteTXT(path, []model.TestResult{r}); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "Wall clock:      12:00:00 – 12:00:14 (14s elapsed)") {
+		t.Errorf("missing wall clock line:\n%s", data)
+	}
+}
+
+func TestWriteTXT_PingSkipped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.txt")
+	r := model.TestResult{Timestamp: baseTXTTime, Protocol: "TCP", PingSkipped: true}
+	if err := WriteTXT(path, []model.TestResult{r}); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "Latency:         skipped (ICMP appears blocked)") {
+		t.Error("missing skipped latency line")
+	}
+	if strings.Contains(string(data), "LATENCY ANALYSIS") {
+		t.Error("latency section should be omitted when ping was skipped")
+	}
+}
+
+func TestWriteTXT_TCPConnectLatency(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.txt")
+	r := model.TestResult{
+		Timestamp:     baseTXTTime,
+		Protocol:      "TCP",
+		ServerAddr:    "10.0.0.1",
+		PingBaseline:  &model.PingResult{PacketsSent: 4, PacketsRecv: 4, MinMs: 1.1, AvgMs: 1.5, MaxMs: 2.2},
+		LatencyMethod: model.LatencyTCPConnect,
+	}
+	if err := WriteTXT(path, []model.TestResult{r}); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "Method:           TCP connect") {
+		t.Errorf("missing TCP connect method line:\n%s", data)
+	}
+}
+
+// Terminal coloring must never reach the report files.
+func TestWriteTXT_NoColorCodes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.txt")
+	results := []model.TestResult{{
+		Timestamp: baseTXTTime, ServerAddr: "10.0.0.1", Port: 5201, Protocol: "UDP",
+		SentBps: 100e6, ReceivedBps: 90e6, LostPackets: 100, Packets: 1000, LostPercent: 10,
+	}}
+	if err := WriteTXT(path, results); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "\x1b[") {
+		t.Errorf("TXT output contains ANSI escape codes:\n%q", data)
+	}
+}
+
+func TestWriteTXT_Label(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.txt")
+	results := []model.TestResult{{
+		Timestamp: baseTXTTime, MeasurementID: "20260218-143207-01", Label: "vpn-on",
+		ServerAddr: "10.0.0.1", Port: 5201, Protocol: "TCP", SentBps: 1e8,
+	}}
+	if err := WriteTXT(path, results); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "Measurement ID: 20260218-143207-01\nLabel:          vpn-on\n") {
+		t.Errorf("missing Label line under the measurement ID:\n%s", data)
+	}
+}
+
+func TestWriteTXT_ServerResolvedIP(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.txt")
+	results := []model.TestResult{
+		{Timestamp: baseTXTTime, ServerAddr: "iperf.example.net", ServerResolvedIP: "192.0.2.7", Port: 5201, Protocol: "TCP"},
+		{Timestamp: baseTXTTime, ServerAddr: "10.0.0.1", ServerResolvedIP: "10.0.0.1", Port: 5201, Protocol: "TCP"},
+	}
+	if err := WriteTXT(path, results); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "Server:          iperf.example.net (192.0.2.7:5201)\n") {
+		t.Errorf("missing name (ip:port) server line:\n%s", data)
+	}
+	if !strings.Contains(string(data), "Server:          10.0.0.1:5201\n") {
+		t.Errorf("IP-only server line should be unchanged:\n%s", data)
+	}
+}
+
+func TestWriteTXT_Warnings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.txt")
+	results := []model.TestResult{{
+		Timestamp: baseTXTTime, ServerAddr: "10.0.0.1", Port: 5201, Protocol: "TCP",
+		Parallel: 1, Duration: 10, SentBps: 1e6,
+		Warnings: []string{"warning: unable to set TCP_CONGESTION"},
+	}}
+	if err := WriteTXT(path, results); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "Warnings: warning: unable to set TCP_CONGESTION\nErrors: none") {
+		t.Errorf("missing Warnings line:\n%s", data)
+	}
+}
+
+func TestWriteTXT_ServerOutputSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.txt")
+	r := model.TestResult{
+		Timestamp: baseTXTTime, ServerAddr: "10.0.0.1", Port: 5201, Protocol: "TCP",
+		Parallel: 1, Duration: 10, SentBps: 1e6,
+	}
+	withText := r
+	withText.ServerOutputText = "[  1] 0.00-10.00 sec  1.10 GBytes   941 Mbits/sec"
+	if err := WriteTXT(path, []model.TestResult{r, withText}); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if n := strings.Count(content, "\nServer Output\n"); n != 1 {
+		t.Fatalf("got %d Server Output sections, want 1 (only the result with text):\n%s", n, content)
+	}
+	if !strings.Contains(content, "Server Output\n"+sectionDash+"\n\n"+withText.ServerOutputText+"\n") {
+		t.Errorf("server output not written verbatim:\n%s", content)
+	}
+}
+
+func TestWriteTXT_Averaged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.txt")
+	results := []model.TestResult{{
+		Timestamp:   baseTXTTime,
+		ServerAddr:  "10.0.0.1",
+		Port:        5201,
+		Protocol:    "TCP",
+		Parallel:    1,
+		Duration:    5,
+		ReceivedBps: 200e6,
+		SampleCount: 5,
+		StdDevMbps:  12.5,
+	}}
+
+	if err := WriteTXT(path, results); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "Averaged:        mean of 5 runs (stddev 12.50 Mbps)") {
+		t.Errorf("missing averaged line:\n%s", data)
+	}
+}
+
+func TestWriteTXT_DownsamplesLongRuns(t *testing.T) {
+	r := model.TestResult{
+		Timestamp:  baseTXTTime,
+		ServerAddr: "10.0.0.1",
+		Port:       5201,
+		Protocol:   "TCP",
+		Parallel:   1,
+		Duration:   3000,
+		SentBps:    100e6,
+	}
+	for i := 0; i < 3000; i++ {
+		r.Intervals = append(r.Intervals, model.IntervalResult{TimeStart: float64(i), TimeEnd: float64(i + 1), Bytes: 12_500_000, BandwidthBps: 100e6})
+	}
+	path := filepath.Join(t.TempDir(), "results.txt")
+	if err := WriteTXT(path, []model.TestResult{r}); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	out := string(data)
+	if !strings.Contains(out, "(downsampled from 3000 intervals)") {
+		t.Errorf("missing downsampled note:\n%.600s", out)
+	}
+	// 3000 intervals in buckets of 6: 500 rows, each moving 75 MB at 100 Mbps
+	if rows := strings.Count(out, "100.00     75.00"); rows != txtMaxIntervalRows {
+		t.Errorf("got %d interval rows, want %d", rows, txtMaxIntervalRows)
+	}
+
+	r.Intervals = r.Intervals[:10]
+	path = filepath.Join(t.TempDir(), "short.txt")
+	if err := WriteTXT(path, []model.TestResult{r}); err != nil {
+		t.Fatalf("WriteTXT() error: %v", err)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "downsampled") {
+		t.Error("a short run should print every interval")
+	}
+}
@@ -0,0 +1,62 @@
+package format
+
+// ANSI SGR codes used by Colorizer.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// Loss and retransmit levels at which Colorizer switches from green to
+// yellow and from yellow to red.
+const (
+	LossWarnPercent = 1.0
+	LossBadPercent  = 5.0
+	RetransmitsWarn = 1
+	RetransmitsBad  = 100
+)
+
+// Colorizer highlights terminal output with ANSI colors. The zero value is
+// disabled and returns text unchanged, so file exports stay plain.
+type Colorizer struct {
+	Enabled bool
+}
+
+func (c Colorizer) paint(code, s string) string {
+	if !c.Enabled || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// Good colors s green.
+func (c Colorizer) Good(s string) string { return c.paint(ansiGreen, s) }
+
+// Warn colors s yellow.
+func (c Colorizer) Warn(s string) string { return c.paint(ansiYellow, s) }
+
+// Bad colors s red.
+func (c Colorizer) Bad(s string) string { return c.paint(ansiRed, s) }
+
+// Loss colors s by UDP loss percentage.
+func (c Colorizer) Loss(pct float64, s string) string {
+	switch {
+	case pct >= LossBadPercent:
+		return c.Bad(s)
+	case pct >= LossWarnPercent:
+		return c.Warn(s)
+	}
+	return c.Good(s)
+}
+
+// Retransmits colors s by TCP retransmit count.
+func (c Colorizer) Retransmits(n int, s string) string {
+	switch {
+	case n >= RetransmitsBad:
+		return c.Bad(s)
+	case n >= RetransmitsWarn:
+		return c.Warn(s)
+	}
+	return c.Good(s)
+}
@@ -2,15 +2,31 @@ package format
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 
 	"iperf-tool/internal/model"
 )
 
+// IntervalTimeLayout returns the wall-clock layout for live interval rows:
+// whole seconds normally, milliseconds when the interval is fractional.
+func IntervalTimeLayout(intervalSec float64) string {
+	if intervalSec != float64(int(intervalSec)) {
+		return "15:04:05.000"
+	}
+	return "15:04:05"
+}
+
+// IntervalTimeColumn returns the "Time" header cell padded to match layout.
+func IntervalTimeColumn(layout string) string {
+	return fmt.Sprintf("%-*s", len(layout)+2, "Time")
+}
+
 // FormatIntervalHeader returns a header line for interval output.
 func FormatIntervalHeader(isUDP bool) string {
 	if isUDP {
-		return fmt.Sprintf("%-14s %-12s %s", "Mbps", "MB", "Packets")
+		return fmt.Sprintf("%-14s %-12s %-12s %s", Units.MbpsUnit(), Units.MBUnit(), "Packets", "Packets/s")
 	}
 	return fmt.Sprintf("%-14s %-12s %s", "Bandwidth", "Transfer", "Retransmits")
 }
@@ -18,14 +34,15 @@ func FormatIntervalHeader(isUDP bool) string {
 // FormatInterval produces a single formatted line for an interval measurement.
 func FormatInterval(r *model.IntervalResult, isUDP bool) string {
 	if isUDP {
-		return fmt.Sprintf("%-14s %-12s %d pkts",
-			fmt.Sprintf("%.2f Mbps", r.BandwidthMbps()),
-			fmt.Sprintf("%.2f MB", r.TransferMB()),
-			r.Packets)
+		return fmt.Sprintf("%-14s %-12s %-12s %.0f pps",
+			Units.Mbps(r.BandwidthMbps()),
+			Units.MB(r.TransferMB()),
+			fmt.Sprintf("%d pkts", r.Packets),
+			r.PacketsPerSecond())
 	}
 	return fmt.Sprintf("%-14s %-12s %d retransmits",
-		fmt.Sprintf("%.2f Mbps", r.BandwidthMbps()),
-		fmt.Sprintf("%.2f MB", r.TransferMB()),
+		Units.Mbps(r.BandwidthMbps()),
+		Units.MB(r.TransferMB()),
 		r.Retransmits)
 }
 
@@ -33,11 +50,11 @@ func FormatInterval(r *model.IntervalResult, isUDP bool) string {
 func FormatBidirIntervalHeader(isUDP bool) string {
 	if isUDP {
 		return fmt.Sprintf("%-12s %-12s %-10s %-10s %-12s %-21s",
-			"Fwd Mbps", "Rev Mbps", "Fwd MB", "Rev MB",
+			"Fwd "+Units.MbpsUnit(), "Rev "+Units.MbpsUnit(), "Fwd "+Units.MBUnit(), "Rev "+Units.MBUnit(),
 			"Rev Jitter", "Rev Lost")
 	}
 	return fmt.Sprintf("%-12s %-12s %-10s %-10s %-10s %s",
-		"Fwd Mbps", "Rev Mbps", "Fwd MB", "Rev MB", "Fwd Retr", "Rev Retr")
+		"Fwd "+Units.MbpsUnit(), "Rev "+Units.MbpsUnit(), "Fwd "+Units.MBUnit(), "Rev "+Units.MBUnit(), "Fwd Retr", "Rev Retr")
 }
 
 // FormatBidirInterval produces a single formatted line for a bidirectional interval.
@@ -46,15 +63,15 @@ func FormatBidirInterval(fwd, rev *model.IntervalResult, isUDP bool) string {
 	var fwdMbps, fwdMB float64
 	fwdRetr := 0
 	if fwd != nil {
-		fwdMbps = fwd.BandwidthMbps()
-		fwdMB = fwd.TransferMB()
+		fwdMbps = Units.Scale(fwd.BandwidthMbps())
+		fwdMB = Units.Scale(fwd.TransferMB())
 		fwdRetr = fwd.Retransmits
 	}
 	var revMbps, revMB float64
 	revRetr := 0
 	if rev != nil {
-		revMbps = rev.BandwidthMbps()
-		revMB = rev.TransferMB()
+		revMbps = Units.Scale(rev.BandwidthMbps())
+		revMB = Units.Scale(rev.TransferMB())
 		revRetr = rev.Retransmits
 	}
 	if isUDP {
@@ -80,9 +97,18 @@ func FormatBidirInterval(fwd, rev *model.IntervalResult, isUDP bool) string {
 
 // FormatResult produces a human-readable formatted output of a test result.
 func FormatResult(r *model.TestResult) string {
+	return FormatResultColored(r, Colorizer{})
+}
+
+// FormatResultColored is FormatResult with throughput, loss, retransmit and
+// warning values highlighted by c.
+func FormatResultColored(r *model.TestResult, c Colorizer) string {
 	var b strings.Builder
 
 	b.WriteString("=== Test Results ===\n")
+	if r.Label != "" {
+		b.WriteString(fmt.Sprintf("Label:           %s\n", r.Label))
+	}
 	b.WriteString(fmt.Sprintf("Timestamp:       %s\n", r.Timestamp.Format("2006-01-02 15:04:05")))
 	b.WriteString(fmt.Sprintf("Server:          %s:%d\n", r.ServerAddr, r.Port))
 	b.WriteString(fmt.Sprintf("Protocol:        %s\n", r.Protocol))
@@ -93,18 +119,34 @@ func FormatResult(r *model.TestResult) string {
 	if r.Congestion != "" {
 		b.WriteString(fmt.Sprintf("Congestion:      %s\n", r.Congestion))
 	}
-	if r.Bandwidth != "" {
-		b.WriteString(fmt.Sprintf("Bandwidth Target: %s Mbps/stream\n", r.Bandwidth))
+	if target := BandwidthTarget(r); target != "" {
+		b.WriteString(fmt.Sprintf("Bandwidth Target: %s\n", target))
 	}
 
 	if r.Parallel > 1 {
 		b.WriteString(fmt.Sprintf("Parallel:        %d streams\n", r.Parallel))
 	}
 
-	b.WriteString(fmt.Sprintf("Duration:        %d seconds\n", r.Duration))
+	if r.BytesLimit != "" || r.BlocksLimit > 0 {
+		b.WriteString(fmt.Sprintf("Test bound:      %s\n", r.RunLabel()))
+	} else {
+		b.WriteString(fmt.Sprintf("Duration:        %d seconds\n", r.Duration))
+	}
+	if r.MSS > 0 {
+		b.WriteString(fmt.Sprintf("MSS:             %d bytes\n", r.MSS))
+	}
+	if r.SndBufBytes > 0 {
+		b.WriteString(fmt.Sprintf("Socket buffer:   %d bytes\n", r.SndBufBytes))
+	}
+	if r.Attempts > 1 {
+		b.WriteString(fmt.Sprintf("Attempts:        %d\n", r.Attempts))
+	}
+	if r.SampleCount > 1 {
+		b.WriteString(fmt.Sprintf("Averaged:        mean of %d runs (stddev %s)\n", r.SampleCount, Units.Mbps(r.StdDevMbps)))
+	}
 
 	if r.Error != "" {
-		b.WriteString(fmt.Sprintf("\nError: %s\n", r.Error))
+		b.WriteString(fmt.Sprintf("\nError: %s\n", c.Bad(r.Error)))
 		b.WriteString("=========================================================================================")
 		return b.String()
 	}
@@ -116,6 +158,12 @@ func FormatResult(r *model.TestResult) string {
 
 	if len(r.Streams) > 1 {
 		b.WriteString("\n--- Per-Stream Results ---\n")
+		if r.Parallel > 1 {
+			b.WriteString(fmt.Sprintf("Fairness:  %.3f (Jain's index, 1.000 = equal shares)\n", r.FairnessIndex()))
+			if line := StreamSpreadLine(r); line != "" {
+				b.WriteString(line + "\n")
+			}
+		}
 		for _, s := range r.Streams {
 			if isUDP && isBidir {
 				if s.Sender {
@@ -124,14 +172,14 @@ func FormatResult(r *model.TestResult) string {
 						jitter = "N/A"
 					}
 					if s.Packets > 0 {
-						b.WriteString(fmt.Sprintf("Stream %d [Fwd]:  %.2f Mbps  Jitter: %s  Lost: %d/%d (%.2f%%)\n",
-							s.ID, s.SentMbps(), jitter, s.LostPackets, s.Packets, s.LostPercent))
+						b.WriteString(fmt.Sprintf("Stream %d [Fwd]:  %s  Jitter: %s  Lost: %d/%d (%.2f%%)\n",
+							s.ID, Units.Mbps(s.SentMbps()), jitter, s.LostPackets, s.Packets, s.LostPercent))
 					} else {
-						b.WriteString(fmt.Sprintf("Stream %d [Fwd]:  %.2f Mbps  Jitter: %s\n",
-							s.ID, s.SentMbps(), jitter))
+						b.WriteString(fmt.Sprintf("Stream %d [Fwd]:  %s  Jitter: %s\n",
+							s.ID, Units.Mbps(s.SentMbps()), jitter))
 					}
 				} else {
-					mbps := fmt.Sprintf("%.2f Mbps", s.SentMbps())
+					mbps := Units.Mbps(s.SentMbps())
 					if r.Interrupted && s.SentBps == 0 {
 						mbps = "N/A"
 					}
@@ -139,8 +187,8 @@ func FormatResult(r *model.TestResult) string {
 						s.ID, mbps, s.JitterMs, s.LostPackets, s.Packets, s.LostPercent))
 				}
 			} else if isUDP {
-				b.WriteString(fmt.Sprintf("Stream %d:  %.2f Mbps  Jitter: %.3f ms  Lost: %d/%d (%.2f%%)\n",
-					s.ID, s.SentMbps(), s.JitterMs, s.LostPackets, s.Packets, s.LostPercent))
+				b.WriteString(fmt.Sprintf("Stream %d:  %s  Jitter: %.3f ms  Lost: %d/%d (%.2f%%)\n",
+					s.ID, Units.Mbps(s.SentMbps()), s.JitterMs, s.LostPackets, s.Packets, s.LostPercent))
 			} else if isBidir {
 				dir := "Rev"
 				bps := s.ReceivedMbps()
@@ -148,14 +196,14 @@ func FormatResult(r *model.TestResult) string {
 					dir = "Fwd"
 					bps = s.SentMbps()
 				}
-				b.WriteString(fmt.Sprintf("Stream %d [%s]:  %.2f Mbps\n",
-					s.ID, dir, bps))
-			} else if hasReceiver {
-				b.WriteString(fmt.Sprintf("Stream %d:  Sent: %.2f Mbps  Received: %.2f Mbps\n",
-					s.ID, s.SentMbps(), s.ReceivedMbps()))
+				b.WriteString(fmt.Sprintf("Stream %d [%s]:  %s%s\n",
+					s.ID, dir, Units.Mbps(bps), StreamTCPInfo(&s)))
+			} else if hasReceiver && s.ReceivedBps > 0 {
+				b.WriteString(fmt.Sprintf("Stream %d:  Sent: %s  Received: %s%s\n",
+					s.ID, Units.Mbps(s.SentMbps()), Units.Mbps(s.ReceivedMbps()), StreamTCPInfo(&s)))
 			} else {
-				b.WriteString(fmt.Sprintf("Stream %d:  %.2f Mbps\n",
-					s.ID, s.SentMbps()))
+				b.WriteString(fmt.Sprintf("Stream %d:  %s%s\n",
+					s.ID, Units.Mbps(s.SentMbps()), StreamTCPInfo(&s)))
 			}
 		}
 	}
@@ -168,41 +216,57 @@ func FormatResult(r *model.TestResult) string {
 			revMbps = r.ReceivedMbps()
 		}
 		if isUDP {
-			b.WriteString(fmt.Sprintf("Client Send:     %.2f Mbps\n", r.SentMbps()))
+			b.WriteString(fmt.Sprintf("Client Send:     %s\n", c.Good(Units.Mbps(r.SentMbps()))))
 			if r.FwdReceivedBps > 0 {
-				b.WriteString(fmt.Sprintf("Server Recv:     %.2f Mbps\n", r.FwdActualMbps()))
+				b.WriteString(fmt.Sprintf("Server Recv:     %s\n", c.Good(Units.Mbps(r.FwdActualMbps()))))
 			} else {
 				b.WriteString("Server Recv:     N/A\n")
 			}
 			if r.Interrupted && r.ReverseSentBps == 0 {
 				b.WriteString("Server Send:     N/A\n")
 			} else {
-				b.WriteString(fmt.Sprintf("Server Send:     %.2f Mbps\n", r.ReverseSentMbps()))
+				b.WriteString(fmt.Sprintf("Server Send:     %s\n", c.Good(Units.Mbps(r.ReverseSentMbps()))))
 			}
 			if revRecv := r.ReverseActualMbps(); revRecv > 0 {
-				b.WriteString(fmt.Sprintf("Client Recv:     %.2f Mbps\n", revRecv))
+				b.WriteString(fmt.Sprintf("Client Recv:     %s\n", c.Good(Units.Mbps(revRecv))))
 			}
 			if r.ActualJitterMs() > 0 {
 				b.WriteString(fmt.Sprintf("C→S Jitter:      %.3f ms\n", r.ActualJitterMs()))
 			}
 			if r.FwdPackets > 0 {
-				b.WriteString(fmt.Sprintf("C→S Lost:        %d/%d (%.2f%%)\n", r.FwdLostPackets, r.FwdPackets, r.FwdLostPercent))
+				b.WriteString(fmt.Sprintf("C→S Lost:        %s\n", c.Loss(r.FwdLostPercent,
+					fmt.Sprintf("%d/%d (%.2f%%)", r.FwdLostPackets, r.FwdPackets, r.FwdLostPercent))))
+			}
+			if line := EfficiencyLine(r, "C→S Efficiency:"); line != "" {
+				b.WriteString(line + "\n")
+			}
+			if pps := r.AvgPPS(); pps > 0 {
+				b.WriteString(fmt.Sprintf("C→S Packets/s:   %.0f\n", pps))
 			}
 			if r.ReverseJitterMs > 0 {
 				b.WriteString(fmt.Sprintf("S→C Jitter:      %.3f ms\n", r.ReverseJitterMs))
 			}
 			if r.ReversePackets > 0 {
-				b.WriteString(fmt.Sprintf("S→C Lost:        %d/%d (%.2f%%)\n", r.ReverseLostPackets, r.ReversePackets, r.ReverseLostPercent))
+				b.WriteString(fmt.Sprintf("S→C Lost:        %s\n", c.Loss(r.ReverseLostPercent,
+					fmt.Sprintf("%d/%d (%.2f%%)", r.ReverseLostPackets, r.ReversePackets, r.ReverseLostPercent))))
+			}
+			if pps := r.ReverseAvgPPS(); pps > 0 {
+				b.WriteString(fmt.Sprintf("S→C Packets/s:   %.0f\n", pps))
 			}
 		} else {
-			b.WriteString(fmt.Sprintf("Send:            %.2f Mbps (retransmits: %d)\n", r.FwdActualMbps(), r.Retransmits))
-			b.WriteString(fmt.Sprintf("Receive:         %.2f Mbps (retransmits: %d)\n", revMbps, revRetrans))
+			b.WriteString(fmt.Sprintf("Send:            %s (retransmits: %s)\n",
+				c.Good(Units.Mbps(r.FwdActualMbps())), c.Retransmits(r.Retransmits, strconv.Itoa(r.Retransmits))))
+			b.WriteString(fmt.Sprintf("Receive:         %s (retransmits: %s)\n",
+				c.Good(Units.Mbps(revMbps)), c.Retransmits(revRetrans, strconv.Itoa(revRetrans))))
 		}
 		b.WriteString(formatBidirTransferred(r))
+		if line := BidirDurationLine(r); line != "" {
+			b.WriteString(line + "\n")
+		}
 	} else if isUDP {
-		b.WriteString(fmt.Sprintf("Sent:            %.2f Mbps\n", r.SentMbps()))
+		b.WriteString(fmt.Sprintf("Sent:            %s\n", c.Good(Units.Mbps(r.SentMbps()))))
 		if hasReceiver {
-			b.WriteString(fmt.Sprintf("Received:        %.2f Mbps\n", r.ReceivedMbps()))
+			b.WriteString(fmt.Sprintf("Received:        %s\n", c.Good(Units.Mbps(r.ReceivedMbps()))))
 		}
 		// Detect fabricated Server Report: 0.000 ms jitter with 0% loss is likely
 		// fabricated by the client when the server ACK was not received (NAT).
@@ -211,40 +275,70 @@ func FormatResult(r *model.TestResult) string {
 			b.WriteString("Jitter:          N/A (Server Report unavailable — likely NAT)\n")
 			b.WriteString("Packet Loss:     N/A (Server Report unavailable — likely NAT)\n")
 		} else {
-			b.WriteString(fmt.Sprintf("Jitter:          %.3f ms\n", r.JitterMs))
+			b.WriteString(fmt.Sprintf("Jitter:          %.3f ms\n", r.ActualJitterMs()))
 			if r.FwdPackets > 0 {
-				b.WriteString(fmt.Sprintf("Packet Loss:     %d/%d (%.2f%%)\n", r.FwdLostPackets, r.FwdPackets, r.FwdLostPercent))
+				b.WriteString(fmt.Sprintf("Packet Loss:     %s\n", c.Loss(r.FwdLostPercent,
+					fmt.Sprintf("%d/%d (%.2f%%)", r.FwdLostPackets, r.FwdPackets, r.FwdLostPercent))))
 			} else {
-				b.WriteString(fmt.Sprintf("Packet Loss:     %d/%d (%.2f%%)\n", r.LostPackets, r.Packets, r.LostPercent))
+				b.WriteString(fmt.Sprintf("Packet Loss:     %s\n", c.Loss(r.LostPercent,
+					fmt.Sprintf("%d/%d (%.2f%%)", r.LostPackets, r.Packets, r.LostPercent))))
 			}
 		}
+		if line := EfficiencyLine(r, "Efficiency:"); line != "" {
+			b.WriteString(line + "\n")
+		}
+		if pps := r.AvgPPS(); pps > 0 {
+			b.WriteString(fmt.Sprintf("Packets/s:       %.0f\n", pps))
+		}
 	} else if hasReceiver {
-		b.WriteString(fmt.Sprintf("Sent:            %.2f Mbps\n", r.SentMbps()))
-		b.WriteString(fmt.Sprintf("Received:        %.2f Mbps\n", r.ReceivedMbps()))
-		b.WriteString(fmt.Sprintf("Retransmits:     %d\n", r.Retransmits))
+		b.WriteString(fmt.Sprintf("Sent:            %s\n", c.Good(Units.Mbps(r.SentMbps()))))
+		b.WriteString(fmt.Sprintf("Received:        %s\n", c.Good(Units.Mbps(r.ReceivedMbps()))))
+		b.WriteString(fmt.Sprintf("Retransmits:     %s\n", c.Retransmits(r.Retransmits, strconv.Itoa(r.Retransmits))))
 	} else {
-		b.WriteString(fmt.Sprintf("Bandwidth:       %.2f Mbps\n", r.SentMbps()))
-		b.WriteString(fmt.Sprintf("Retransmits:     %d\n", r.Retransmits))
+		b.WriteString(fmt.Sprintf("Bandwidth:       %s\n", c.Good(Units.Mbps(r.SentMbps()))))
+		b.WriteString(fmt.Sprintf("Retransmits:     %s\n", c.Retransmits(r.Retransmits, strconv.Itoa(r.Retransmits))))
 	}
 
 	if !isBidir && (r.BytesSent > 0 || r.BytesReceived > 0) {
-		b.WriteString(fmt.Sprintf("Transferred:     %.2f MB sent / %.2f MB received\n", r.SentMB(), r.ReceivedMB()))
+		b.WriteString(fmt.Sprintf("Transferred:     %s sent / %s received\n", Units.MB(r.SentMB()), Units.MB(r.ReceivedMB())))
+	}
+
+	for _, line := range StabilityLines(r) {
+		b.WriteString(line + "\n")
 	}
 
 	sentOK, recvOK := r.VerifyStreamTotals()
 	if !sentOK || !recvOK {
-		b.WriteString("WARNING: Per-stream totals do not match summary values\n")
+		b.WriteString(c.Warn("WARNING: Per-stream totals do not match summary values") + "\n")
+	}
+	if line := SkewWarning(r); line != "" {
+		b.WriteString(c.Warn(line) + "\n")
 	}
 
-	if r.PingBaseline != nil || r.PingLoaded != nil {
-		b.WriteString("\n--- Latency ---\n")
+	if r.PingSkipped && r.PingBaseline == nil && r.PingLoaded == nil {
+		b.WriteString("\n--- Latency ---\nSkipped: ICMP appears blocked\n")
+	} else if r.PingBaseline != nil || r.PingLoaded != nil {
+		if r.LatencyMethod == model.LatencyTCPConnect {
+			b.WriteString("\n--- Latency (TCP connect) ---\n")
+		} else {
+			b.WriteString("\n--- Latency ---\n")
+		}
 		if r.PingBaseline != nil {
-			b.WriteString(fmt.Sprintf("Baseline:    min/avg/max = %.2f / %.2f / %.2f ms\n",
-				r.PingBaseline.MinMs, r.PingBaseline.AvgMs, r.PingBaseline.MaxMs))
+			b.WriteString("Baseline:    " + PingStats(r.PingBaseline) + "\n")
+			if r.PingBaseline.P50Ms > 0 {
+				b.WriteString(fmt.Sprintf("             p50/p95/p99 = %.2f / %.2f / %.2f ms\n",
+					r.PingBaseline.P50Ms, r.PingBaseline.P95Ms, r.PingBaseline.P99Ms))
+			}
 		}
 		if r.PingLoaded != nil {
-			b.WriteString(fmt.Sprintf("Under load:  min/avg/max = %.2f / %.2f / %.2f ms\n",
-				r.PingLoaded.MinMs, r.PingLoaded.AvgMs, r.PingLoaded.MaxMs))
+			b.WriteString("Under load:  " + PingStats(r.PingLoaded) + "\n")
+			if r.PingLoaded.P50Ms > 0 {
+				b.WriteString(fmt.Sprintf("             p50/p95/p99 = %.2f / %.2f / %.2f ms\n",
+					r.PingLoaded.P50Ms, r.PingLoaded.P95Ms, r.PingLoaded.P99Ms))
+			}
+		}
+		if grade := r.BufferbloatGrade(); grade != "" {
+			b.WriteString(fmt.Sprintf("Bufferbloat: %s\n", grade))
 		}
 	}
 
@@ -254,32 +348,235 @@ func FormatResult(r *model.TestResult) string {
 	} else if r.Interrupted {
 		errStr = "Interrupted"
 	}
+	if len(r.Warnings) > 0 {
+		b.WriteString(fmt.Sprintf("Warnings:    %s\n", strings.Join(r.Warnings, "; ")))
+	}
 	b.WriteString(fmt.Sprintf("Errors:      %s\n", errStr))
 
 	b.WriteString(strings.Repeat("=", 90))
 	return b.String()
 }
 
+// FormatResultCompact returns a one-line summary for scripted use, e.g.
+// "10.0.0.1 TCP fwd=940.12Mbps retr=3" (Mibps with binary Units). Reverse runs report rev= only,
+// bidir runs both directions, and UDP runs loss= instead of retr=.
+func FormatResultCompact(r *model.TestResult) string {
+	parts := []string{r.ServerAddr, r.Protocol}
+	if r.Error != "" {
+		return strings.Join(append(parts, "error="+r.Error), " ")
+	}
+
+	isUDP := r.Protocol == "UDP"
+	rate := func(key string, mbps float64) string {
+		return fmt.Sprintf("%s=%.2f%s", key, Units.Scale(mbps), Units.MbpsUnit())
+	}
+	switch r.Direction {
+	case "Reverse":
+		parts = append(parts, rate("rev", r.FwdActualMbps()))
+	case "Bidirectional":
+		revMbps := r.ReverseActualMbps()
+		if revMbps == 0 && r.ReceivedBps > 0 {
+			revMbps = r.ReceivedMbps()
+		}
+		parts = append(parts, rate("fwd", r.FwdActualMbps()), rate("rev", revMbps))
+	default:
+		parts = append(parts, rate("fwd", r.FwdActualMbps()))
+	}
+
+	if isUDP {
+		loss := r.LostPercent
+		if r.FwdPackets > 0 {
+			loss = r.FwdLostPercent
+		}
+		parts = append(parts, fmt.Sprintf("loss=%.2f%%", loss))
+		if r.ReversePackets > 0 {
+			parts = append(parts, fmt.Sprintf("rev_loss=%.2f%%", r.ReverseLostPercent))
+		}
+	} else {
+		parts = append(parts, fmt.Sprintf("retr=%d", r.Retransmits))
+		if r.Direction == "Bidirectional" {
+			parts = append(parts, fmt.Sprintf("rev_retr=%d", r.ReverseRetransmits))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// BandwidthTarget describes the -b target in Units, e.g. "25.00 Mbps/stream
+// (100.00 Mbps total)". Results without the numeric targets (older logs)
+// fall back to the raw Bandwidth setting, e.g. "25M/stream". Empty when
+// unlimited.
+func BandwidthTarget(r *model.TestResult) string {
+	if r.TargetPerStreamMbps > 0 {
+		if r.Parallel > 1 {
+			return fmt.Sprintf("%s/stream (%s total)", Units.Mbps(r.TargetPerStreamMbps), Units.Mbps(r.TargetTotalMbps))
+		}
+		return Units.Mbps(r.TargetPerStreamMbps) + "/stream"
+	}
+	if r.Bandwidth != "" {
+		return r.Bandwidth + "/stream"
+	}
+	return ""
+}
+
+// EfficiencyLine returns "Efficiency: N% of T Mbps target" for the given
+// label width, or "" when no bitrate target was set.
+func EfficiencyLine(r *model.TestResult, label string) string {
+	if r.TargetTotalMbps <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%-17s%.1f%% of %s target", label, r.EfficiencyPercent(), Units.Mbps(r.TargetTotalMbps))
+}
+
+// PingStats formats a latency summary as "min/avg/max = 1.00 / 2.00 / 3.00 ms",
+// adding stddev when the ping summary reported it.
+func PingStats(p *model.PingResult) string {
+	if p.StdDevMs > 0 {
+		return fmt.Sprintf("min/avg/max/stddev = %.2f / %.2f / %.2f / %.2f ms", p.MinMs, p.AvgMs, p.MaxMs, p.StdDevMs)
+	}
+	return fmt.Sprintf("min/avg/max = %.2f / %.2f / %.2f ms", p.MinMs, p.AvgMs, p.MaxMs)
+}
+
+// StreamTCPInfo returns the per-stream TCP sender columns, e.g.
+// "  Retr: 3  RTT min/avg/max: 1.20/3.12/5.00 ms  Cwnd: 372 KB", or "" when the stream
+// has no RTT/cwnd data.
+func StreamTCPInfo(s *model.StreamResult) string {
+	if !s.HasTCPInfo() {
+		return ""
+	}
+	out := fmt.Sprintf("  Retr: %d", s.Retransmits)
+	if s.MaxRTTUs > 0 {
+		out += fmt.Sprintf("  RTT min/avg/max: %.2f/%.2f/%.2f ms", float64(s.MinRTTUs)/1000, float64(s.MeanRTTUs)/1000, float64(s.MaxRTTUs)/1000)
+	}
+	if s.MaxCwndBytes > 0 {
+		out += fmt.Sprintf("  Cwnd: %d KB", s.MaxCwndBytes/1024)
+	}
+	return out
+}
+
+// StabilityLines returns the interval throughput spread as
+// "mean±stddev (min–max) Mbps" lines: one for unidirectional tests, one per
+// direction for bidir. Empty when there are too few intervals.
+func StabilityLines(r *model.TestResult) []string {
+	line := func(label string, mean, stddev, minV, maxV float64) string {
+		u := Units
+		return fmt.Sprintf("%-17s%.2f±%.2f (%.2f–%.2f) %s", label,
+			u.Scale(mean), u.Scale(stddev), u.Scale(minV), u.Scale(maxV), u.MbpsUnit())
+	}
+	var lines []string
+	if r.Direction == "Bidirectional" {
+		if mean, sd, lo, hi := r.IntervalStats(); mean > 0 {
+			lines = append(lines, line("Fwd stability:", mean, sd, lo, hi))
+		}
+		if mean, sd, lo, hi := r.ReverseIntervalStats(); mean > 0 {
+			lines = append(lines, line("Rev stability:", mean, sd, lo, hi))
+		}
+	} else if mean, sd, lo, hi := r.IntervalStats(); mean > 0 {
+		lines = append(lines, line("Stability:", mean, sd, lo, hi))
+	}
+	return append(lines, steadyLines(r)...)
+}
+
+// steadyLines returns the trimmed steady-state throughput, one line per
+// direction like StabilityLines. Empty unless r.SteadyTrim is set.
+func steadyLines(r *model.TestResult) []string {
+	n := r.SteadyTrim
+	if n <= 0 {
+		return nil
+	}
+	line := func(label string, mbps float64) string {
+		return fmt.Sprintf("%-17s%s (first/last %d intervals trimmed)", label, Units.Mbps(mbps), n)
+	}
+	var lines []string
+	if r.Direction == "Bidirectional" {
+		if v := r.SteadyStateMbps(n, n); v > 0 {
+			lines = append(lines, line("Fwd steady:", v))
+		}
+		if v := r.ReverseSteadyStateMbps(n, n); v > 0 {
+			lines = append(lines, line("Rev steady:", v))
+		}
+	} else if v := r.SteadyStateMbps(n, n); v > 0 {
+		lines = append(lines, line("Steady state:", v))
+	}
+	return lines
+}
+
 // formatBidirTransferred returns two lines showing per-direction byte counts for
 // bidirectional tests. Each line shows sent/received for that direction; a side
 // is omitted when its byte count is zero (e.g. server-output unavailable).
 func formatBidirTransferred(r *model.TestResult) string {
 	var b strings.Builder
 	// C→S: client sent, server received
-	csSent := float64(r.BytesSent) / 1e6
-	csRecv := float64(r.BytesReceived) / 1e6
+	csSent := Units.MB(r.SentMB())
+	csRecv := Units.MB(r.ReceivedMB())
 	if r.BytesReceived > 0 {
-		b.WriteString(fmt.Sprintf("C→S transferred: %.2f MB sent / %.2f MB received\n", csSent, csRecv))
+		b.WriteString(fmt.Sprintf("C→S transferred: %s sent / %s received\n", csSent, csRecv))
 	} else {
-		b.WriteString(fmt.Sprintf("C→S transferred: %.2f MB sent\n", csSent))
+		b.WriteString(fmt.Sprintf("C→S transferred: %s sent\n", csSent))
 	}
 	// S→C: server sent, client received
-	scSent := float64(r.ReverseBytesSent) / 1e6
-	scRecv := r.TotalRevMB() // prefers ReverseBytesReceived, falls back to ReverseBytesSent
+	scSent := Units.MB(float64(r.ReverseBytesSent) / 1e6)
+	scRecv := Units.MB(r.TotalRevMB()) // prefers ReverseBytesReceived, falls back to ReverseBytesSent
 	if r.ReverseBytesSent > 0 {
-		b.WriteString(fmt.Sprintf("S→C transferred: %.2f MB sent / %.2f MB received\n", scSent, scRecv))
+		b.WriteString(fmt.Sprintf("S→C transferred: %s sent / %s received\n", scSent, scRecv))
 	} else {
-		b.WriteString(fmt.Sprintf("S→C transferred: %.2f MB received\n", scRecv))
+		b.WriteString(fmt.Sprintf("S→C transferred: %s received\n", scRecv))
 	}
 	return b.String()
 }
+
+// BidirDurationLine returns the per-direction measured durations of a
+// bidirectional test, noting when they differ by a second or more (one
+// direction stalled or ended early). Empty when the reverse duration is unknown.
+func BidirDurationLine(r *model.TestResult) string {
+	if r.ReverseActualDuration <= 0 {
+		return ""
+	}
+	fwd := r.ActualDuration
+	if last := model.LastActiveEnd(r.Intervals); last > 0 {
+		fwd = last
+	}
+	line := fmt.Sprintf("Durations:       C→S %.1f s / S→C %.1f s", fwd, r.ReverseActualDuration)
+	if gap := math.Abs(fwd - r.ReverseActualDuration); gap >= 1 {
+		line += fmt.Sprintf(" (%.1f s apart)", gap)
+	}
+	return line
+}
+
+// StreamSpreadLine names the fastest and slowest forward streams, e.g.
+// "Fastest: stream 3 (250.00 Mbps), Slowest: stream 1 (90.00 Mbps), spread 2.8x".
+// Empty for single-stream runs or when every stream got the same rate; the
+// spread is left out when the slowest stream carried nothing.
+func StreamSpreadLine(r *model.TestResult) string {
+	if r.Parallel <= 1 {
+		return ""
+	}
+	best, worst := r.BestStream(), r.WorstStream()
+	if best == nil || best == worst || best.SentBps == worst.SentBps {
+		return ""
+	}
+	line := fmt.Sprintf("Fastest: stream %d (%s), Slowest: stream %d (%s)",
+		best.ID, Units.Mbps(best.SentMbps()), worst.ID, Units.Mbps(worst.SentMbps()))
+	if worst.SentBps > 0 {
+		line += fmt.Sprintf(", spread %.1fx", best.SentBps/worst.SentBps)
+	}
+	return line
+}
+
+// InterfaceLabel returns the local interface with its link speed, e.g.
+// "eth0 (1000 Mbps)", or just the name when the speed is unknown.
+func InterfaceLabel(r *model.TestResult) string {
+	if r.LocalLinkMbps > 0 {
+		return fmt.Sprintf("%s (%d Mbps)", r.LocalInterface, r.LocalLinkMbps)
+	}
+	return r.LocalInterface
+}
+
+// SkewWarning returns a warning when intervals ran faster than the local
+// link rate allows; empty when none did or the link rate is unknown.
+func SkewWarning(r *model.TestResult) string {
+	n := r.SuspectIntervals(float64(r.LocalLinkMbps))
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("WARNING: %d intervals exceed link rate (%d Mbps) — possible clock skew", n, r.LocalLinkMbps)
+}
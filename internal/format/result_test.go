@@ -164,6 +164,9 @@ func TestFormatIntervalUDP(t *testing.T) {
 	if !strings.Contains(out, "50 pkts") {
 		t.Error("should contain packet count")
 	}
+	if !strings.Contains(out, "50 pps") {
+		t.Errorf("should contain packet rate, got: %s", out)
+	}
 	if strings.Contains(out, "lost") {
 		t.Error("UDP interval should not show lost count")
 	}
@@ -224,6 +227,26 @@ func TestFormatResultUDP(t *testing.T) {
 	}
 }
 
+func TestFormatResultUDPEfficiency(t *testing.T) {
+	r := &model.TestResult{
+		Protocol:            "UDP",
+		Parallel:            1,
+		SentBps:             100_000_000,
+		FwdReceivedBps:      94_000_000,
+		ReceivedBps:         94_000_000,
+		TargetPerStreamMbps: 100,
+		TargetTotalMbps:     100,
+	}
+	if out := FormatResult(r); !strings.Contains(out, "Efficiency:      94.0% of 100.00 Mbps target") {
+		t.Errorf("missing efficiency line:\n%s", out)
+	}
+
+	r.TargetPerStreamMbps, r.TargetTotalMbps = 0, 0
+	if out := FormatResult(r); strings.Contains(out, "Efficiency:") {
+		t.Errorf("efficiency shown without a target:\n%s", out)
+	}
+}
+
 func TestFormatResultUDPMultiStream(t *testing.T) {
 	r := &model.TestResult{
 		Timestamp:   time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC),
@@ -334,6 +357,56 @@ func TestFormatResultWithPing(t *testing.T) {
 	if !strings.Contains(out, "12.34") {
 		t.Error("missing loaded avg")
 	}
+	if !strings.Contains(out, "Bufferbloat: B") {
+		t.Error("missing Bufferbloat grade (+10 ms = B)")
+	}
+}
+
+func TestFormatResultPingPercentiles(t *testing.T) {
+	r := &model.TestResult{
+		ServerAddr:   "192.168.1.1",
+		Protocol:     "TCP",
+		PingBaseline: &model.PingResult{MinMs: 1, AvgMs: 2, MaxMs: 3},
+		PingLoaded:   &model.PingResult{MinMs: 5, AvgMs: 12, MaxMs: 60, P50Ms: 11.5, P95Ms: 40.25, P99Ms: 58},
+	}
+
+	out := FormatResult(r)
+
+	if !strings.Contains(out, "p50/p95/p99 = 11.50 / 40.25 / 58.00 ms") {
+		t.Errorf("missing loaded percentiles in:\n%s", out)
+	}
+	if strings.Count(out, "p50/p95/p99") != 1 {
+		t.Error("baseline without samples should not print percentiles")
+	}
+}
+
+func TestFormatResultPingStdDev(t *testing.T) {
+	r := &model.TestResult{
+		ServerAddr:   "192.168.1.1",
+		Protocol:     "TCP",
+		PingBaseline: &model.PingResult{MinMs: 0.543, AvgMs: 0.594, MaxMs: 0.621, StdDevMs: 0.029},
+		PingLoaded:   &model.PingResult{MinMs: 5, AvgMs: 12, MaxMs: 60},
+	}
+
+	out := FormatResult(r)
+
+	if !strings.Contains(out, "Baseline:    min/avg/max/stddev = 0.54 / 0.59 / 0.62 / 0.03 ms\n") {
+		t.Errorf("missing baseline stddev in:\n%s", out)
+	}
+	if !strings.Contains(out, "Under load:  min/avg/max = 5.00 / 12.00 / 60.00 ms\n") {
+		t.Errorf("loaded ping without stddev should keep min/avg/max in:\n%s", out)
+	}
+}
+
+func TestFormatResultAttempts(t *testing.T) {
+	r := &model.TestResult{ServerAddr: "10.0.0.1", Protocol: "TCP", Attempts: 3}
+	if out := FormatResult(r); !strings.Contains(out, "Attempts:        3") {
+		t.Errorf("missing attempts line in:\n%s", out)
+	}
+	r.Attempts = 1
+	if out := FormatResult(r); strings.Contains(out, "Attempts:") {
+		t.Error("single attempt should not be reported")
+	}
 }
 
 func TestFormatResultDirection(t *testing.T) {
@@ -694,3 +767,385 @@ func TestFormatResultError(t *testing.T) {
 		t.Error("should not show summary on error")
 	}
 }
+
+func TestFormatResultSocketInfo(t *testing.T) {
+	r := &model.TestResult{
+		Timestamp:   time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC),
+		ServerAddr:  "192.168.1.1",
+		Port:        5201,
+		Protocol:    "TCP",
+		Duration:    10,
+		MSS:         1448,
+		SndBufBytes: 87040,
+		SentBps:     940_000_000,
+	}
+
+	out := FormatResult(r)
+	if !strings.Contains(out, "MSS:             1448 bytes") {
+		t.Error("missing MSS line")
+	}
+	if !strings.Contains(out, "Socket buffer:   87040 bytes") {
+		t.Error("missing socket buffer line")
+	}
+
+	r.MSS, r.SndBufBytes = 0, 0
+	out = FormatResult(r)
+	if strings.Contains(out, "MSS:") || strings.Contains(out, "Socket buffer:") {
+		t.Error("MSS/socket buffer lines should be omitted when zero")
+	}
+}
+
+func TestIntervalTimeLayout(t *testing.T) {
+	if got := IntervalTimeLayout(1); got != "15:04:05" {
+		t.Errorf("IntervalTimeLayout(1) = %q", got)
+	}
+	if got := IntervalTimeLayout(0.5); got != "15:04:05.000" {
+		t.Errorf("IntervalTimeLayout(0.5) = %q", got)
+	}
+	if got := IntervalTimeColumn("15:04:05"); got != "Time      " {
+		t.Errorf("IntervalTimeColumn() = %q, want 10-wide Time column", got)
+	}
+}
+
+func TestFormatResultStability(t *testing.T) {
+	r := &model.TestResult{
+		Timestamp: time.Now(),
+		Protocol:  "TCP",
+		SentBps:   10e6,
+		Intervals: []model.IntervalResult{
+			{TimeStart: 0, TimeEnd: 1, BandwidthBps: 8e6},
+			{TimeStart: 1, TimeEnd: 2, BandwidthBps: 10e6},
+			{TimeStart: 2, TimeEnd: 3, BandwidthBps: 12e6},
+		},
+	}
+	output := FormatResult(r)
+	if !strings.Contains(output, "Stability:       10.00±2.00 (8.00–12.00) Mbps") {
+		t.Errorf("missing stability line:\n%s", output)
+	}
+
+	r.Intervals = r.Intervals[:2]
+	if strings.Contains(FormatResult(r), "Stability:") {
+		t.Error("stability line should be omitted with fewer than 3 intervals")
+	}
+}
+
+func TestStabilityLinesBidir(t *testing.T) {
+	ivs := func(a, b, c float64) []model.IntervalResult {
+		return []model.IntervalResult{{BandwidthBps: a}, {BandwidthBps: b}, {BandwidthBps: c}}
+	}
+	r := &model.TestResult{
+		Direction:        "Bidirectional",
+		Intervals:        ivs(5e6, 5e6, 5e6),
+		ReverseIntervals: ivs(20e6, 30e6, 40e6),
+	}
+	lines := StabilityLines(r)
+	if len(lines) != 2 {
+		t.Fatalf("lines = %q, want 2", lines)
+	}
+	if lines[0] != "Fwd stability:   5.00±0.00 (5.00–5.00) Mbps" {
+		t.Errorf("fwd line = %q", lines[0])
+	}
+	if lines[1] != "Rev stability:   30.00±10.00 (20.00–40.00) Mbps" {
+		t.Errorf("rev line = %q", lines[1])
+	}
+}
+
+func TestFormatResultPingSkipped(t *testing.T) {
+	r := &model.TestResult{Timestamp: time.Now(), Protocol: "TCP", SentBps: 1e6, PingSkipped: true}
+	output := FormatResult(r)
+	if !strings.Contains(output, "--- Latency ---\nSkipped: ICMP appears blocked") {
+		t.Errorf("missing skipped latency note:\n%s", output)
+	}
+	if strings.Contains(output, "Baseline:") {
+		t.Error("no baseline line expected when ping was skipped")
+	}
+}
+
+func TestFormatResultTCPConnectLatency(t *testing.T) {
+	r := &model.TestResult{
+		Timestamp:     time.Now(),
+		Protocol:      "TCP",
+		SentBps:       1e6,
+		PingBaseline:  &model.PingResult{MinMs: 1, AvgMs: 2, MaxMs: 3},
+		LatencyMethod: model.LatencyTCPConnect,
+	}
+	output := FormatResult(r)
+	if !strings.Contains(output, "--- Latency (TCP connect) ---\nBaseline:    min/avg/max = 1.00 / 2.00 / 3.00 ms") {
+		t.Errorf("missing TCP connect latency block:\n%s", output)
+	}
+}
+
+func TestFormatResultCompact(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *model.TestResult
+		want string
+	}{
+		{
+			name: "tcp forward",
+			r:    &model.TestResult{ServerAddr: "10.0.0.1", Protocol: "TCP", SentBps: 940e6, Retransmits: 3},
+			want: "10.0.0.1 TCP fwd=940.00Mbps retr=3",
+		},
+		{
+			name: "tcp reverse",
+			r:    &model.TestResult{ServerAddr: "10.0.0.1", Protocol: "TCP", Direction: "Reverse", SentBps: 500e6},
+			want: "10.0.0.1 TCP rev=500.00Mbps retr=0",
+		},
+		{
+			name: "udp bidir",
+			r: &model.TestResult{
+				ServerAddr: "10.0.0.1", Protocol: "UDP", Direction: "Bidirectional",
+				SentBps: 100e6, FwdReceivedBps: 99e6, FwdPackets: 1000, FwdLostPercent: 1,
+				ReverseReceivedBps: 98e6, ReversePackets: 1000, ReverseLostPercent: 2,
+			},
+			want: "10.0.0.1 UDP fwd=99.00Mbps rev=98.00Mbps loss=1.00% rev_loss=2.00%",
+		},
+		{
+			name: "error",
+			r:    &model.TestResult{ServerAddr: "10.0.0.1", Protocol: "TCP", Error: "connection refused"},
+			want: "10.0.0.1 TCP error=connection refused",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatResultCompact(tt.r); got != tt.want {
+				t.Errorf("FormatResultCompact() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatResultColored(t *testing.T) {
+	r := &model.TestResult{
+		Timestamp: time.Now(), ServerAddr: "10.0.0.1", Port: 5201, Protocol: "UDP",
+		SentBps: 100e6, ReceivedBps: 90e6, LostPackets: 100, Packets: 1000, LostPercent: 10,
+	}
+	plain := FormatResult(r)
+	if got := FormatResultColored(r, Colorizer{}); got != plain {
+		t.Errorf("disabled Colorizer changed output:\n%s\nwant:\n%s", got, plain)
+	}
+	if strings.Contains(plain, "\x1b[") {
+		t.Error("FormatResult must not emit escape codes")
+	}
+
+	colored := FormatResultColored(r, Colorizer{Enabled: true})
+	if !strings.Contains(colored, ansiRed+"100/1000 (10.00%)"+ansiReset) {
+		t.Errorf("10%% loss should be red:\n%q", colored)
+	}
+	if !strings.Contains(colored, ansiGreen+"100.00 Mbps"+ansiReset) {
+		t.Errorf("throughput should be green:\n%q", colored)
+	}
+	stripped := strings.NewReplacer(ansiRed, "", ansiGreen, "", ansiYellow, "", ansiReset, "").Replace(colored)
+	if stripped != plain {
+		t.Errorf("colored output differs from plain beyond escape codes:\n%s", stripped)
+	}
+}
+
+func TestColorizerLevels(t *testing.T) {
+	c := Colorizer{Enabled: true}
+	if got := c.Loss(0.5, "x"); got != ansiGreen+"x"+ansiReset {
+		t.Errorf("Loss(0.5) = %q", got)
+	}
+	if got := c.Loss(2, "x"); got != ansiYellow+"x"+ansiReset {
+		t.Errorf("Loss(2) = %q", got)
+	}
+	if got := c.Retransmits(0, "0"); got != ansiGreen+"0"+ansiReset {
+		t.Errorf("Retransmits(0) = %q", got)
+	}
+	if got := c.Retransmits(250, "250"); got != ansiRed+"250"+ansiReset {
+		t.Errorf("Retransmits(250) = %q", got)
+	}
+}
+
+func TestFormatResultPPS(t *testing.T) {
+	r := &model.TestResult{
+		Timestamp: time.Now(), Protocol: "UDP", SentBps: 10e6,
+		Packets: 8930, ActualDuration: 10,
+	}
+	if out := FormatResult(r); !strings.Contains(out, "Packets/s:       893\n") {
+		t.Errorf("missing Packets/s line:\n%s", out)
+	}
+	r.ActualDuration = 0
+	if out := FormatResult(r); strings.Contains(out, "Packets/s:") {
+		t.Errorf("Packets/s should be omitted without a duration:\n%s", out)
+	}
+}
+
+func TestFormatResultLabel(t *testing.T) {
+	r := &model.TestResult{Timestamp: time.Now(), Protocol: "TCP", SentBps: 1e6, Label: "office-wifi"}
+	if out := FormatResult(r); !strings.Contains(out, "=== Test Results ===\nLabel:           office-wifi\n") {
+		t.Errorf("missing Label line:\n%s", out)
+	}
+	r.Label = ""
+	if out := FormatResult(r); strings.Contains(out, "Label:") {
+		t.Errorf("Label line should be omitted when empty:\n%s", out)
+	}
+}
+
+func TestFormatResultWarnings(t *testing.T) {
+	r := &model.TestResult{Timestamp: time.Now(), Protocol: "TCP", SentBps: 1e6,
+		Warnings: []string{"warning: unable to set TCP_CONGESTION", "WARNING: window size mismatch"}}
+	want := "Warnings:    warning: unable to set TCP_CONGESTION; WARNING: window size mismatch\nErrors:      none\n"
+	if out := FormatResult(r); !strings.Contains(out, want) {
+		t.Errorf("missing Warnings line:\n%s", out)
+	}
+	r.Warnings = nil
+	if out := FormatResult(r); strings.Contains(out, "Warnings:") {
+		t.Errorf("Warnings line should be omitted when empty:\n%s", out)
+	}
+}
+
+func TestFormatResultSteadyState(t *testing.T) {
+	var ivs []model.IntervalResult
+	for i, mbps := range []float64{100, 900, 900, 900, 200} {
+		ivs = append(ivs, model.IntervalResult{TimeStart: float64(i), TimeEnd: float64(i + 1), BandwidthBps: mbps * 1e6})
+	}
+	r := &model.TestResult{Timestamp: time.Now(), Protocol: "TCP", SentBps: 600e6, Intervals: ivs}
+	if out := FormatResult(r); strings.Contains(out, "Steady state:") {
+		t.Errorf("Steady state line should be off by default:\n%s", out)
+	}
+	r.SteadyTrim = 1
+	if out := FormatResult(r); !strings.Contains(out, "Steady state:    900.00 Mbps (first/last 1 intervals trimmed)\n") {
+		t.Errorf("missing Steady state line:\n%s", out)
+	}
+}
+
+func TestFormatResultStreamTCPInfo(t *testing.T) {
+	r := &model.TestResult{
+		Timestamp: time.Now(), Protocol: "TCP", Parallel: 2,
+		SentBps: 141.6e6, ReceivedBps: 140e6,
+		Streams: []model.StreamResult{
+			{ID: 1, SentBps: 94.4e6, Retransmits: 3, MinRTTUs: 1200, MeanRTTUs: 2160, MaxRTTUs: 3120, MaxCwndBytes: 410 * 1024, Sender: true},
+			{ID: 2, SentBps: 47.2e6, Sender: true},
+		},
+	}
+	out := FormatResult(r)
+	if !strings.Contains(out, "Stream 1:  94.40 Mbps  Retr: 3  RTT min/avg/max: 1.20/2.16/3.12 ms  Cwnd: 410 KB\n") {
+		t.Errorf("missing stream 1 TCP info:\n%s", out)
+	}
+	if !strings.Contains(out, "Stream 2:  47.20 Mbps\n") {
+		t.Errorf("stream without TCP info should have no extra columns:\n%s", out)
+	}
+	if strings.Contains(out, "WARNING: Per-stream totals") {
+		t.Errorf("sender-only streams should not trigger the receive total warning:\n%s", out)
+	}
+}
+
+func TestFormatResultFairness(t *testing.T) {
+	r := &model.TestResult{
+		Protocol: "TCP", Parallel: 2, SentBps: 100e6,
+		Streams: []model.StreamResult{{ID: 1, SentBps: 75e6, Sender: true}, {ID: 2, SentBps: 25e6, Sender: true}},
+	}
+	out := FormatResult(r)
+	if !strings.Contains(out, "--- Per-Stream Results ---\nFairness:  0.800 (Jain's index, 1.000 = equal shares)\n") {
+		t.Errorf("missing fairness line:\n%s", out)
+	}
+
+	r.Parallel = 1
+	r.Direction = "Bidirectional"
+	r.Streams[1].Sender = false
+	if out := FormatResult(r); strings.Contains(out, "Fairness:") {
+		t.Errorf("single-stream bidir should not show fairness:\n%s", out)
+	}
+}
+
+func TestFormatResultUDPForwardServerJitter(t *testing.T) {
+	r := &model.TestResult{
+		ServerAddr:     "10.0.0.1",
+		Protocol:       "UDP",
+		Parallel:       1,
+		Duration:       10,
+		SentBps:        7_340_000,
+		FwdReceivedBps: 4_300_000,
+		FwdJitterMs:    6.405,
+		FwdPackets:     6129,
+		FwdLostPackets: 2461,
+		FwdLostPercent: 40.2,
+	}
+
+	out := FormatResult(r)
+	if !strings.Contains(out, "Jitter:          6.405 ms") {
+		t.Errorf("expected server-measured jitter, got: %s", out)
+	}
+}
+
+func TestFormatResultBidirDurations(t *testing.T) {
+	r := &model.TestResult{
+		ServerAddr:            "10.0.0.1",
+		Protocol:              "TCP",
+		Direction:             "Bidirectional",
+		Parallel:              1,
+		Duration:              10,
+		SentBps:               900e6,
+		ReverseReceivedBps:    400e6,
+		ActualDuration:        10,
+		ReverseActualDuration: 6.5,
+	}
+
+	out := FormatResult(r)
+	if !strings.Contains(out, "Durations:       C→S 10.0 s / S→C 6.5 s (3.5 s apart)") {
+		t.Errorf("expected per-direction durations, got: %s", out)
+	}
+
+	r.ReverseActualDuration = 9.8
+	if line := BidirDurationLine(r); line != "Durations:       C→S 10.0 s / S→C 9.8 s" {
+		t.Errorf("BidirDurationLine() = %q", line)
+	}
+}
+
+func TestStreamSpreadLine(t *testing.T) {
+	r := &model.TestResult{
+		Protocol: "TCP",
+		Parallel: 3,
+		Streams: []model.StreamResult{
+			{ID: 1, SentBps: 90e6},
+			{ID: 2, SentBps: 160e6},
+			{ID: 3, SentBps: 250e6},
+		},
+	}
+	want := "Fastest: stream 3 (250.00 Mbps), Slowest: stream 1 (90.00 Mbps), spread 2.8x"
+	if got := StreamSpreadLine(r); got != want {
+		t.Errorf("StreamSpreadLine() = %q, want %q", got, want)
+	}
+	if out := FormatResult(r); !strings.Contains(out, want) {
+		t.Errorf("per-stream header missing spread line:\n%s", out)
+	}
+
+	r.Streams[0].SentBps = 0
+	if got := StreamSpreadLine(r); strings.Contains(got, "spread") {
+		t.Errorf("idle slowest stream should drop the spread, got %q", got)
+	}
+
+	equal := &model.TestResult{Parallel: 2, Streams: []model.StreamResult{{ID: 1, SentBps: 5e6}, {ID: 2, SentBps: 5e6}}}
+	single := &model.TestResult{Parallel: 1, Streams: []model.StreamResult{{ID: 1, SentBps: 5e6}}}
+	for _, r := range []*model.TestResult{equal, single} {
+		if got := StreamSpreadLine(r); got != "" {
+			t.Errorf("StreamSpreadLine() = %q, want empty", got)
+		}
+	}
+}
+
+func TestFormatResultSkewWarning(t *testing.T) {
+	r := &model.TestResult{
+		ServerAddr:    "10.0.0.1",
+		Protocol:      "TCP",
+		Parallel:      1,
+		Duration:      3,
+		SentBps:       1500e6,
+		LocalLinkMbps: 1000,
+		Intervals: []model.IntervalResult{
+			{TimeStart: 0, TimeEnd: 1, BandwidthBps: 940e6},
+			{TimeStart: 1, TimeEnd: 2, BandwidthBps: 2600e6},
+			{TimeStart: 2, TimeEnd: 3, BandwidthBps: 950e6},
+		},
+	}
+	out := FormatResult(r)
+	if !strings.Contains(out, "WARNING: 1 intervals exceed link rate (1000 Mbps) — possible clock skew") {
+		t.Errorf("missing clock-skew warning:\n%s", out)
+	}
+
+	r.LocalLinkMbps = 0
+	if line := SkewWarning(r); line != "" {
+		t.Errorf("SkewWarning() with unknown link = %q, want empty", line)
+	}
+}
@@ -0,0 +1,108 @@
+package format
+
+import (
+	"fmt"
+	"time"
+)
+
+// HumanBytes formats n in the largest fitting unit of Units: "512 B",
+// "1.5 MB", "18.3 GB", or "1.4 MiB" etc. with binary units.
+func HumanBytes(n int64) string {
+	base, units := 1000.0, []string{"KB", "MB", "GB", "TB"}
+	if Units == Binary {
+		base, units = 1024, []string{"KiB", "MiB", "GiB", "TiB"}
+	}
+	if float64(n) < base {
+		return fmt.Sprintf("%d B", n)
+	}
+	v := float64(n)
+	for _, unit := range units {
+		v /= base
+		if v < 999.95 || unit == units[len(units)-1] {
+			return fmt.Sprintf("%.1f %s", v, unit)
+		}
+	}
+	return "" // unreachable
+}
+
+// HumanDuration formats d rounded to whole seconds in its two largest
+// units: "42s", "5m30s", "2h11m".
+func HumanDuration(d time.Duration) string {
+	s := int64(d.Round(time.Second) / time.Second)
+	switch {
+	case s >= 3600:
+		return fmt.Sprintf("%dh%02dm", s/3600, s%3600/60)
+	case s >= 60:
+		return fmt.Sprintf("%dm%02ds", s/60, s%60)
+	}
+	return fmt.Sprintf("%ds", s)
+}
+
+// UnitSystem selects how byte counts and bit rates are displayed.
+type UnitSystem int
+
+const (
+	// Decimal uses SI units: MB = 10^6 bytes, Mbps = 10^6 bit/s.
+	Decimal UnitSystem = iota
+	// Binary uses IEC units: MiB = 2^20 bytes, Mibps = 2^20 bit/s.
+	Binary
+)
+
+// Units is the unit system used by every display path in this package.
+// Results keep bytes and bit/s internally; only rendering changes. Set once
+// at startup from -units.
+var Units = Decimal
+
+// ParseUnitSystem parses a -units value: "decimal" or "binary".
+func ParseUnitSystem(s string) (UnitSystem, error) {
+	switch s {
+	case "decimal":
+		return Decimal, nil
+	case "binary":
+		return Binary, nil
+	}
+	return Decimal, fmt.Errorf("unknown unit system %q (want decimal or binary)", s)
+}
+
+// String returns the -units name of u.
+func (u UnitSystem) String() string {
+	if u == Binary {
+		return "binary"
+	}
+	return "decimal"
+}
+
+// Scale converts a 10^6-based value, as returned by the model's *MB() and
+// *Mbps() methods, into u's mega unit.
+func (u UnitSystem) Scale(mega float64) float64 {
+	if u == Binary {
+		return mega * 1e6 / (1 << 20)
+	}
+	return mega
+}
+
+// MBUnit returns the byte unit label: "MB" or "MiB".
+func (u UnitSystem) MBUnit() string {
+	if u == Binary {
+		return "MiB"
+	}
+	return "MB"
+}
+
+// MbpsUnit returns the rate unit label: "Mbps" or "Mibps".
+func (u UnitSystem) MbpsUnit() string {
+	if u == Binary {
+		return "Mibps"
+	}
+	return "Mbps"
+}
+
+// MB formats a 10^6-based byte value, e.g. "1.05 MB" or "1.00 MiB".
+func (u UnitSystem) MB(mega float64) string {
+	return fmt.Sprintf("%.2f %s", u.Scale(mega), u.MBUnit())
+}
+
+// Mbps formats a 10^6-based rate, e.g. "94.37 Mbps" or "90.00 Mibps".
+func (u UnitSystem) Mbps(mega float64) string {
+	return fmt.Sprintf("%.2f %s", u.Scale(mega), u.MbpsUnit())
+}
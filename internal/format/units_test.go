@@ -0,0 +1,132 @@
+package format
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+func TestHumanBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{999, "999 B"},
+		{1500, "1.5 KB"},
+		{8_750_000, "8.8 MB"},
+		{999_960_000, "1.0 GB"},
+		{18_300_000_000, "18.3 GB"},
+		{2_500_000_000_000_000, "2500.0 TB"},
+	}
+	for _, tt := range tests {
+		if got := HumanBytes(tt.n); got != tt.want {
+			t.Errorf("HumanBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestHumanDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{400 * time.Millisecond, "0s"},
+		{42 * time.Second, "42s"},
+		{5*time.Minute + 30*time.Second, "5m30s"},
+		{2*time.Hour + 11*time.Minute + 40*time.Second, "2h11m"},
+	}
+	for _, tt := range tests {
+		if got := HumanDuration(tt.d); got != tt.want {
+			t.Errorf("HumanDuration(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func useUnits(t *testing.T, u UnitSystem) {
+	t.Helper()
+	orig := Units
+	t.Cleanup(func() { Units = orig })
+	Units = u
+}
+
+func TestUnitSystem_MB(t *testing.T) {
+	iv := model.IntervalResult{Bytes: 1_048_576, BandwidthBps: 8_388_608}
+	tests := []struct {
+		units    UnitSystem
+		mb, mbps string
+	}{
+		{Decimal, "1.05 MB", "8.39 Mbps"},
+		{Binary, "1.00 MiB", "8.00 Mibps"},
+	}
+	for _, tt := range tests {
+		if got := tt.units.MB(iv.TransferMB()); got != tt.mb {
+			t.Errorf("%v MB = %q, want %q", tt.units, got, tt.mb)
+		}
+		if got := tt.units.Mbps(iv.BandwidthMbps()); got != tt.mbps {
+			t.Errorf("%v Mbps = %q, want %q", tt.units, got, tt.mbps)
+		}
+	}
+}
+
+func TestFormatInterval_BinaryUnits(t *testing.T) {
+	useUnits(t, Binary)
+	iv := &model.IntervalResult{Bytes: 1_048_576, BandwidthBps: 8_388_608}
+
+	if got := FormatInterval(iv, false); got != "8.00 Mibps     1.00 MiB     0 retransmits" {
+		t.Errorf("FormatInterval = %q, want binary units", got)
+	}
+	if got := FormatBidirIntervalHeader(false); !strings.HasPrefix(got, "Fwd Mibps    Rev Mibps    Fwd MiB") {
+		t.Errorf("FormatBidirIntervalHeader = %q, want binary labels", got)
+	}
+	r := &model.TestResult{ServerAddr: "10.0.0.1", Protocol: "TCP", SentBps: 8_388_608}
+	if got := FormatResultCompact(r); got != "10.0.0.1 TCP fwd=8.00Mibps retr=0" {
+		t.Errorf("FormatResultCompact = %q", got)
+	}
+}
+
+func TestBandwidthTarget_Units(t *testing.T) {
+	useUnits(t, Decimal)
+	r := &model.TestResult{Parallel: 4, TargetPerStreamMbps: 25, TargetTotalMbps: 100}
+	if got, want := BandwidthTarget(r), "25.00 Mbps/stream (100.00 Mbps total)"; got != want {
+		t.Errorf("decimal BandwidthTarget() = %q, want %q", got, want)
+	}
+	useUnits(t, Binary)
+	if got, want := BandwidthTarget(r), "23.84 Mibps/stream (95.37 Mibps total)"; got != want {
+		t.Errorf("binary BandwidthTarget() = %q, want %q", got, want)
+	}
+	if got, want := BandwidthTarget(&model.TestResult{Bandwidth: "25M"}), "25M/stream"; got != want {
+		t.Errorf("raw BandwidthTarget() = %q, want %q", got, want)
+	}
+}
+
+func TestHumanBytes_Binary(t *testing.T) {
+	useUnits(t, Binary)
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{1023, "1023 B"},
+		{1536, "1.5 KiB"},
+		{1_048_576, "1.0 MiB"},
+		{5 << 30, "5.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := HumanBytes(tt.n); got != tt.want {
+			t.Errorf("HumanBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestParseUnitSystem(t *testing.T) {
+	for s, want := range map[string]UnitSystem{"decimal": Decimal, "binary": Binary} {
+		if got, err := ParseUnitSystem(s); err != nil || got != want {
+			t.Errorf("ParseUnitSystem(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseUnitSystem("metric"); err == nil {
+		t.Error("expected error for metric")
+	}
+}
@@ -0,0 +1,101 @@
+package iperf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Command is one process a test run starts, in execution order.
+type Command struct {
+	Remote bool     // true = run on the SSH host, false = run locally
+	Args   []string // argv, binary first
+}
+
+// String renders the command as a shell line prefixed with where it runs.
+func (c Command) String() string {
+	where := "local"
+	if c.Remote {
+		where = "remote"
+	}
+	return where + ": " + strings.Join(c.Args, " ")
+}
+
+// Bidirectional test modes. Dualtest runs both directions in one local
+// iperf2 -d process; split runs the forward client locally and the reverse
+// client on the SSH host, merging the two outputs.
+const (
+	BidirAuto     = "auto"
+	BidirDualtest = "dualtest"
+	BidirSplit    = "split"
+)
+
+// UseDualtest reports whether a bidirectional run uses dualtest rather than
+// split mode. Auto picks dualtest without SSH and split with it; forcing
+// split without an SSH connection is an error.
+func (c *Config) UseDualtest(withSSH bool) (bool, error) {
+	switch c.BidirMode {
+	case BidirDualtest:
+		return true, nil
+	case BidirSplit:
+		if !withSSH {
+			return false, fmt.Errorf("split bidir mode needs an SSH connection to run the reverse client; use dualtest or connect with --ssh")
+		}
+		return false, nil
+	}
+	return !withSSH, nil
+}
+
+// BuildCommands returns the iperf2 command lines a run with cfg would start,
+// without executing anything. It validates cfg and picks the same mode and
+// argument builders as RunForward, RunReverse, RunBidir and
+// RunBidirDualtest. withSSH reports whether an SSH client would be passed.
+//
+// UDP reachability probes can still switch the live run to SSH fallback mode;
+// the dry run shows the commands for cfg.SSHFallback as given.
+func (r *Runner) BuildCommands(cfg Config, withSSH bool) ([]Command, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	local := func(args []string) Command {
+		return Command{Args: append([]string{cfg.BinaryPath}, args...)}
+	}
+	remote := func(cmd string) Command {
+		return Command{Remote: true, Args: strings.Fields(cmd)}
+	}
+
+	dualtest, err := cfg.UseDualtest(withSSH)
+	if cfg.Bidir && err != nil {
+		return nil, err
+	}
+
+	switch {
+	case cfg.Bidir && dualtest:
+		return []Command{local(cfg.dualtestClientArgs())}, nil
+	case cfg.Bidir:
+		if cfg.Protocol == "udp" {
+			cfg.useServerOutput()
+		}
+		return []Command{
+			remote(cfg.remoteServerStartCmd()),
+			local(cfg.revServerArgs()),
+			local(cfg.fwdClientArgs()),
+			remote(cfg.revClientCmd()),
+		}, nil
+	case cfg.Reverse:
+		if !withSSH {
+			return nil, fmt.Errorf("SSH connection required for reverse tests")
+		}
+		return []Command{
+			local(cfg.revServerArgs()),
+			remote(cfg.revClientCmd()),
+		}, nil
+	}
+
+	var cmds []Command
+	if withSSH {
+		cfg.useServerOutput()
+		cmds = append(cmds, remote(cfg.remoteServerStartCmd()))
+	}
+	return append(cmds, local(cfg.fwdClientArgs())), nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -18,12 +19,17 @@ type Config struct {
 	Port             int           // server port (default 5201)
 	Parallel         int           // number of parallel streams (maps to port range)
 	Duration         int           // test duration in seconds
+	BytesLimit       string        // -n: stop after this much data (e.g. "1G") instead of -t; empty = time-bounded
+	BlocksLimit      int           // -k: stop after this many blocks/datagrams instead of -t; 0 = off
 	Interval         int           // reporting interval in seconds
+	IntervalSec      float64       // fractional reporting interval (e.g. 0.5); overrides Interval when > 0
 	Protocol         string        // "tcp" or "udp"
 	BlockSize        int           // buffer/datagram size in bytes (0 = iperf2 default)
 	MeasurePing      bool          // run ping before and during test
+	PingCount        int           // baseline ping packets (0 = ping.DefaultCount)
 	Reverse          bool          // reverse direction (remote→local)
 	Bidir            bool          // bidirectional (both directions simultaneously)
+	BidirMode        string        // "" or "auto" (dualtest without SSH, split with it), "dualtest" or "split"
 	Bandwidth        string        // -b: target bandwidth (e.g. "100M", "1G"), empty = unlimited
 	Enhanced         bool          // -e flag (enhanced output with latency, PPS, etc.)
 	LocalAddr        string        // local IP address for reverse/bidir connections
@@ -34,6 +40,20 @@ type Config struct {
 	SkipProbe        bool          // skip pre-flight UDP reachability probe
 	KillWaitMs       int           // post-kill wait before reading file, default 500
 	IPv6             bool          // Use IPv6 (-V flag)
+	AddrFamily       string        // "" (auto), "4" or "6"; "6" implies -V, "4" forbids it
+	OmitSeconds      int           // leading warm-up seconds flagged as omitted (iperf2 has no -O; applied to the parsed intervals)
+	WindowSize       string        // -w: socket buffer / TCP window (e.g. "256K", "2M"), empty = OS default
+	DSCP             string        // -S: ToS byte (0-255) or DSCP keyword ("ef", "af41", "cs5"); empty = unmarked
+	BindAddr         string        // -B: local IP the client binds to on multi-homed hosts; empty = OS choice
+	NoDelay          bool          // -N: disable Nagle on the sending socket (TCP only; iperf2 has no zero-copy mode)
+	MSS              int           // -M: requested TCP MSS clamp in bytes (TCP only); 0 = OS default
+	ConnectTimeoutMs int           // --connect-timeout: TCP connect timeout in ms (TCP only); 0 = iperf2 default
+	Label            string        // free-text tag copied onto the result; not passed to iperf2
+	ExtraArgs        []string      // extra iperf2 client flags appended verbatim after the structured args
+	NoServerOutput   bool          // don't log the SSH-started server to RemoteOutputFile; receiver data then comes only from the Server Report
+	KeepServerOutput bool          // store the raw server-side output on the result (ServerOutputText)
+	RequireVersion   bool          // refuse to run with a local iperf2 older than MinVersion; not passed to iperf2
+	StreamIntervals  bool          // live runs: local client interval lines go to onInterval only, not into the parsed output (-interval-cap)
 }
 
 // IperfConfig is an alias for Config to ease the migration.
@@ -46,17 +66,26 @@ func DefaultConfig() Config {
 		BinaryPath:   "iperf",
 		Port:         5201,
 		Parallel:     1,
-		Duration:     10,
+		Duration:     DefaultDuration,
 		Interval:     1,
 		Protocol:     "tcp",
 		ProbeTimeout: 2 * time.Second,
 		KillWaitMs:   500,
+		PingCount:    4,
 	}
 }
 
+// MinMSS and MaxMSS bound the -M MSS clamp: 88 bytes is the smallest MSS
+// Linux accepts, 9000 covers jumbo frames.
+const (
+	MinMSS = 88
+	MaxMSS = 9000
+)
+
 var (
 	validHostname  = regexp.MustCompile(`^[a-zA-Z0-9._:-]+$`)
 	validBandwidth = regexp.MustCompile(`^\d+[KMGkmg]?$`)
+	validExtraArg  = regexp.MustCompile(`^[A-Za-z0-9._:=+,/@%-]+$`)
 )
 
 // Validate checks the config for invalid or dangerous values.
@@ -76,11 +105,36 @@ func (c *Config) Validate() error {
 	if c.Duration < 1 {
 		return fmt.Errorf("duration must be at least 1 second, got %d", c.Duration)
 	}
-	if c.Interval < 1 {
-		return fmt.Errorf("interval must be at least 1 second, got %d", c.Interval)
+	if c.BytesLimit != "" && c.BlocksLimit != 0 {
+		return fmt.Errorf("byte limit (-n) and block limit (-k) are mutually exclusive")
+	}
+	if c.BlocksLimit < 0 {
+		return fmt.Errorf("block limit must be >= 1, got %d", c.BlocksLimit)
+	}
+	if c.BytesLimit != "" && !validBandwidth.MatchString(c.BytesLimit) {
+		return fmt.Errorf("byte limit must match pattern digits[KMG], got %q", c.BytesLimit)
+	}
+	if c.DataLimited() && c.Duration != DefaultDuration {
+		return fmt.Errorf("a data limit (-n/-k) replaces the duration (-t); leave the duration at its default of %d s", DefaultDuration)
+	}
+	// A -n/-k run has no known length, so its interval and omit are only
+	// bounded below; Duration is then just the unused default.
+	if iv := c.IntervalSeconds(); iv <= 0 {
+		return fmt.Errorf("interval must be greater than 0 seconds, got %s", c.intervalArg())
+	} else if !c.DataLimited() && iv > float64(c.Duration) {
+		return fmt.Errorf("interval must not exceed the duration (%d s), got %s", c.Duration, c.intervalArg())
+	}
+	if c.OmitSeconds < 0 {
+		return fmt.Errorf("omit seconds must be >= 0, got %d", c.OmitSeconds)
+	}
+	if !c.DataLimited() && c.OmitSeconds >= c.Duration {
+		return fmt.Errorf("omit seconds must be between 0 and %d (less than duration), got %d", c.Duration-1, c.OmitSeconds)
 	}
 	if c.Protocol != "tcp" && c.Protocol != "udp" {
-		return fmt.Errorf("protocol must be tcp or udp, got %q", c.Protocol)
+		return fmt.Errorf("protocol must be tcp or udp (iperf2 has no sctp mode), got %q", c.Protocol)
+	}
+	if c.Protocol == "udp" && c.BlockSize > MaxUDPDatagram {
+		return fmt.Errorf("UDP datagram size must be between 1 and %d bytes, got %d", MaxUDPDatagram, c.BlockSize)
 	}
 	if c.BlockSize < 0 || c.BlockSize > 134217728 {
 		return fmt.Errorf("block size must be between 1 and 134217728, got %d", c.BlockSize)
@@ -88,12 +142,43 @@ func (c *Config) Validate() error {
 	if c.BinaryPath == "" {
 		return fmt.Errorf("iperf binary path is required")
 	}
+	switch c.BidirMode {
+	case "", BidirAuto, BidirDualtest, BidirSplit:
+	default:
+		return fmt.Errorf("bidir mode must be auto, dualtest or split, got %q", c.BidirMode)
+	}
 	if c.Reverse && c.Bidir {
 		return fmt.Errorf("reverse (-R) and bidirectional (--bidir) are mutually exclusive")
 	}
+	if c.AddrFamily != "" && c.AddrFamily != "4" && c.AddrFamily != "6" {
+		return fmt.Errorf("address family must be 4 or 6, got %q", c.AddrFamily)
+	}
+	if c.AddrFamily == "4" && c.IPv6 {
+		return fmt.Errorf("address family 4 conflicts with IPv6 (-V)")
+	}
 	if c.Bandwidth != "" && !validBandwidth.MatchString(c.Bandwidth) {
 		return fmt.Errorf("bandwidth must match pattern digits[KMG], got %q", c.Bandwidth)
 	}
+	if c.WindowSize != "" && !validBandwidth.MatchString(c.WindowSize) {
+		return fmt.Errorf("window size must match pattern digits[KMG], got %q", c.WindowSize)
+	}
+	if c.ConnectTimeoutMs < 0 {
+		return fmt.Errorf("connect timeout must be >= 0 ms, got %d", c.ConnectTimeoutMs)
+	}
+	if c.MSS != 0 && (c.MSS < MinMSS || c.MSS > MaxMSS) {
+		return fmt.Errorf("MSS must be between %d and %d bytes, got %d", MinMSS, MaxMSS, c.MSS)
+	}
+	if c.DSCP != "" {
+		if _, err := ParseTOS(c.DSCP); err != nil {
+			return err
+		}
+	}
+	if c.PingCount < 0 {
+		return fmt.Errorf("ping count must be >= 1, got %d", c.PingCount)
+	}
+	if c.BindAddr != "" && net.ParseIP(c.BindAddr) == nil {
+		return fmt.Errorf("bind address must be an IP address, got %q", c.BindAddr)
+	}
 	// Check port range fits for bidir (forward + reverse need separate ranges)
 	if c.Bidir {
 		if c.Port+c.Parallel*2-1 > 65535 {
@@ -102,12 +187,118 @@ func (c *Config) Validate() error {
 	} else if c.Port+c.Parallel-1 > 65535 {
 		return fmt.Errorf("port range exceeds 65535: need %d ports starting at %d", c.Parallel, c.Port)
 	}
+	for _, a := range c.ExtraArgs {
+		if !validExtraArg.MatchString(a) {
+			return fmt.Errorf("extra argument %q may only contain letters, digits and ._:=+,/@%%-", a)
+		}
+		if managedFlags[extraArgFlag(a)] {
+			return fmt.Errorf("extra argument %q conflicts with a flag the tool sets itself", a)
+		}
+	}
 	if c.SSHFallback && c.RemoteOutputFile == "" {
 		return fmt.Errorf("remote output file path is required when SSH fallback is enabled")
 	}
 	return nil
 }
 
+// managedFlags are iperf2 options the tool sets or parses itself; passing
+// them through ExtraArgs would break the run or its output parsing.
+var managedFlags = map[string]bool{
+	"-c": true, "--client": true, "-s": true, "--server": true,
+	"-p": true, "--port": true, "-f": true, "--format": true,
+	"-i": true, "--interval": true, "-y": true, "--reportstyle": true,
+	"-o": true, "--output": true, "-D": true, "--daemon": true,
+	"-d": true, "--dualtest": true, "-r": true, "--tradeoff": true,
+	"-t": true, "--time": true, "-n": true, "--num": true,
+	"-k": true, "--blockcount": true, "-P": true, "--parallel": true,
+	"-u": true, "--udp": true, "-R": true, "--reverse": true,
+	"-e": true, "--enhanced": true, "-l": true, "--len": true,
+}
+
+// extraArgFlag returns the option name of an extra argument: a long option
+// up to any "=", a short one by its first two characters so that "-t5"
+// counts as -t.
+func extraArgFlag(a string) string {
+	if strings.HasPrefix(a, "--") {
+		name, _, _ := strings.Cut(a, "=")
+		return name
+	}
+	if strings.HasPrefix(a, "-") && len(a) > 2 {
+		return a[:2]
+	}
+	return a
+}
+
+// StreamsPerCPU is the number of parallel streams per CPU core above which
+// AdviseParallel warns that a test is likely CPU-bound.
+const StreamsPerCPU = 4
+
+// AdviseParallel returns a warning when n parallel streams exceed
+// StreamsPerCPU per core on this host, where the sender typically runs out
+// of CPU before the link saturates. It is advice only; Validate still
+// accepts up to 128 streams.
+func AdviseParallel(n int) (string, bool) {
+	return adviseParallel(n, runtime.NumCPU())
+}
+
+func adviseParallel(n, cores int) (string, bool) {
+	if limit := max(cores, 1) * StreamsPerCPU; n > limit {
+		return fmt.Sprintf("warning: %d parallel streams on %d CPU cores (more than %d per core); results may be CPU-limited",
+			n, cores, StreamsPerCPU), true
+	}
+	return "", false
+}
+
+const (
+	// MaxUDPDatagram is the largest UDP payload an IPv4 packet can carry.
+	MaxUDPDatagram = 65507
+	// EthernetMTU is the standard, non-jumbo path MTU.
+	EthernetMTU = 1500
+)
+
+// MaxUnfragmentedDatagram returns the largest UDP payload that fits in one
+// IP packet on a path with the given MTU (1472 for IPv4 at 1500).
+func MaxUnfragmentedDatagram(mtu int, ipv6 bool) int {
+	if ipv6 {
+		return mtu - 40 - 8
+	}
+	return mtu - 20 - 8
+}
+
+// UDPFragments returns how many IP packets one UDP datagram of size bytes
+// is split into on a path with the given MTU; 1 means no fragmentation.
+func UDPFragments(size, mtu int, ipv6 bool) int {
+	if size <= MaxUnfragmentedDatagram(mtu, ipv6) {
+		return 1
+	}
+	// Fragment payloads are multiples of 8 bytes; IPv6 adds an 8-byte
+	// fragment header to every fragment.
+	perFrag := (mtu - 20) &^ 7
+	if ipv6 {
+		perFrag = (mtu - 40 - 8) &^ 7
+	}
+	if perFrag <= 0 {
+		return 0
+	}
+	return (size + 8 + perFrag - 1) / perFrag
+}
+
+// AdviseDatagram returns a warning when a UDP test's datagram size (-l)
+// will be fragmented on a standard 1500-byte MTU path. Losing any fragment
+// drops the whole datagram, so loss figures get inflated. It is advice
+// only: jumbo-frame paths carry larger datagrams fine.
+func (c *Config) AdviseDatagram() (string, bool) {
+	if c.Protocol != "udp" || c.BlockSize == 0 {
+		return "", false
+	}
+	ipv6 := c.useIPv6()
+	if limit := MaxUnfragmentedDatagram(EthernetMTU, ipv6); c.BlockSize > limit {
+		return fmt.Sprintf("warning: %d-byte UDP datagrams exceed %d bytes and are fragmented into %d IP packets on a %d-byte MTU path (fine with jumbo frames)",
+			c.BlockSize, limit, UDPFragments(c.BlockSize, EthernetMTU, ipv6), EthernetMTU), true
+	}
+	return "", false
+}
+
 // parseBandwidthBits parses a bandwidth string (e.g. "50M", "500K", "1G", "100")
 // and returns the value in bits per second. Returns 0 on parse error.
 func parseBandwidthBits(bw string) float64 {
@@ -134,6 +325,51 @@ func parseBandwidthBits(bw string) float64 {
 	return v * mult
 }
 
+// IntervalSeconds returns the reporting interval in seconds, preferring the
+// fractional IntervalSec over the whole-second Interval.
+func (c *Config) IntervalSeconds() float64 {
+	if c.IntervalSec > 0 {
+		return c.IntervalSec
+	}
+	return float64(c.Interval)
+}
+
+// intervalArg formats the interval for -i without trailing zeros ("0.5", "2").
+func (c *Config) intervalArg() string {
+	return strconv.FormatFloat(c.IntervalSeconds(), 'f', -1, 64)
+}
+
+// noDelayArgs returns -N for TCP senders when NoDelay is set, or nil.
+func (c *Config) noDelayArgs() []string {
+	if c.NoDelay && c.Protocol != "udp" {
+		return []string{"-N"}
+	}
+	return nil
+}
+
+// connectTimeoutArgs returns --connect-timeout <ms> for TCP clients when
+// ConnectTimeoutMs is set, or nil.
+func (c *Config) connectTimeoutArgs() []string {
+	if c.ConnectTimeoutMs > 0 && c.Protocol != "udp" {
+		return []string{"--connect-timeout", strconv.Itoa(c.ConnectTimeoutMs)}
+	}
+	return nil
+}
+
+// mssArgs returns -M <n> for TCP senders when MSS is set, or nil.
+func (c *Config) mssArgs() []string {
+	if c.MSS > 0 && c.Protocol != "udp" {
+		return []string{"-M", strconv.Itoa(c.MSS)}
+	}
+	return nil
+}
+
+// useIPv6 reports whether iperf2 should be run with -V. iperf2 has no -4
+// flag: IPv4 is its default, so forcing family 4 simply means omitting -V.
+func (c *Config) useIPv6() bool {
+	return c.IPv6 || c.AddrFamily == "6"
+}
+
 // BandwidthPerStreamMbps returns the per-stream target in Mbps.
 // iperf2 applies the -b value per stream, so this returns the -b value directly.
 // Returns 0 if Bandwidth is empty (unlimited).
@@ -145,6 +381,32 @@ func (c *Config) BandwidthPerStreamMbps() float64 {
 	return bits / 1_000_000
 }
 
+// BandwidthTotalMbps returns the aggregate target in Mbps: the per-stream -b
+// value times the number of parallel streams. Returns 0 if unlimited.
+func (c *Config) BandwidthTotalMbps() float64 {
+	return c.BandwidthPerStreamMbps() * float64(max(c.Parallel, 1))
+}
+
+// DefaultDuration is the -t test length in seconds when none is given.
+const DefaultDuration = 10
+
+// DataLimited reports whether the test is bounded by -n or -k rather than -t.
+func (c *Config) DataLimited() bool {
+	return c.BytesLimit != "" || c.BlocksLimit > 0
+}
+
+// limitArgs returns the flag that bounds the test: -n or -k for a
+// data-limited run, -t otherwise.
+func (c *Config) limitArgs() []string {
+	switch {
+	case c.BytesLimit != "":
+		return []string{"-n", c.BytesLimit}
+	case c.BlocksLimit > 0:
+		return []string{"-k", strconv.Itoa(c.BlocksLimit)}
+	}
+	return []string{"-t", strconv.Itoa(c.Duration)}
+}
+
 const (
 	// DefaultUDPBlockSize is the iperf2 default datagram size for UDP tests (bytes).
 	DefaultUDPBlockSize = 1470
@@ -168,6 +430,8 @@ func (c *Config) ApplyToResult(result *model.TestResult, mode string) {
 	if c.Duration != 0 {
 		result.Duration = c.Duration
 	}
+	result.BytesLimit = c.BytesLimit
+	result.BlocksLimit = c.BlocksLimit
 	if c.Parallel != 0 {
 		result.Parallel = c.Parallel
 	}
@@ -187,15 +451,52 @@ func (c *Config) ApplyToResult(result *model.TestResult, mode string) {
 	} else {
 		result.Direction = "Forward"
 	}
-	if bw := c.BandwidthPerStreamMbps(); bw > 0 {
-		result.Bandwidth = fmt.Sprintf("%.2f", bw)
-	} else if isUDP {
+	perStream := c.BandwidthPerStreamMbps()
+	if perStream == 0 && isUDP {
 		udpDefault := Config{Bandwidth: "1M", Parallel: c.Parallel}
-		result.Bandwidth = fmt.Sprintf("%.2f", udpDefault.BandwidthPerStreamMbps())
+		perStream = udpDefault.BandwidthPerStreamMbps()
 	}
+	if perStream > 0 {
+		result.Bandwidth = fmt.Sprintf("%.2f", perStream)
+		result.TargetPerStreamMbps = perStream
+		result.TargetTotalMbps = perStream * float64(max(c.Parallel, 1))
+	}
+	switch {
+	case c.useIPv6():
+		result.AddrFamily = "IPv6"
+	case c.AddrFamily == "4":
+		result.AddrFamily = "IPv4"
+	}
+	result.Label = c.Label
+	result.ExtraArgs = c.ExtraArgs
+	result.OmitSeconds = c.OmitSeconds
+	result.WindowSize = c.WindowSize
+	result.DSCP = c.DSCP
+	result.BindAddr = c.BindAddr
+	result.NoDelay = c.NoDelay && c.Protocol != "udp"
+	if c.Protocol != "udp" {
+		result.RequestedMSS = c.MSS
+		result.ConnectTimeoutMs = c.ConnectTimeoutMs
+	}
+	markOmitted(result.Intervals, c.OmitSeconds)
+	markOmitted(result.ReverseIntervals, c.OmitSeconds)
 	result.Mode = mode
 }
 
+// markOmitted flags intervals that end within the first omit seconds of the
+// test. iperf2 has no equivalent of iperf3's -O, so the warm-up window is
+// applied after parsing instead of being passed to the binary.
+func markOmitted(intervals []model.IntervalResult, omit int) {
+	if omit <= 0 {
+		return
+	}
+	for i := range intervals {
+		if intervals[i].TimeEnd <= float64(omit)+0.005 {
+			intervals[i].Omitted = true
+		}
+	}
+}
+
 // PortRangeStr returns a port range string for iperf2 -p flag.
 // offset shifts the starting port (0 for forward, Parallel for reverse).
 // Single port: "5201". Multiple: "5201-5202".
@@ -214,14 +515,17 @@ func (c *Config) fwdServerArgs() []string {
 	if c.Protocol == "udp" {
 		args = append(args, "-u")
 	}
-	args = append(args, "-p", c.PortRangeStr(0), "-f", "m", "-i", strconv.Itoa(c.Interval))
+	args = append(args, "-p", c.PortRangeStr(0), "-f", "m", "-i", c.intervalArg())
+	if c.WindowSize != "" {
+		args = append(args, "-w", c.WindowSize)
+	}
 	if c.Enhanced {
 		args = append(args, "-e")
 	}
 	if c.RemoteOutputFile != "" {
 		args = append(args, "-o", c.RemoteOutputFile)
 	}
-	if c.IPv6 {
+	if c.useIPv6() {
 		args = append(args, "-V")
 	}
 	return args
@@ -233,22 +537,32 @@ func (c *Config) fwdClientArgs() []string {
 	if c.Protocol == "udp" {
 		args = append(args, "-u")
 	}
-	args = append(args, "-p", c.PortRangeStr(0),
-		"-t", strconv.Itoa(c.Duration),
-		"-f", "m",
-		"-i", strconv.Itoa(c.Interval))
+	args = append(args, "-p", c.PortRangeStr(0))
+	args = append(args, c.limitArgs()...)
+	args = append(args, "-f", "m", "-i", c.intervalArg())
 	if c.BlockSize > 0 {
 		args = append(args, "-l", strconv.Itoa(c.BlockSize))
 	}
+	if c.WindowSize != "" {
+		args = append(args, "-w", c.WindowSize)
+	}
 	if c.Bandwidth != "" && c.Protocol == "udp" {
 		args = append(args, "-b", c.Bandwidth)
 	}
 	if c.Enhanced {
 		args = append(args, "-e")
 	}
-	if c.IPv6 {
+	args = append(args, c.tosArgs()...)
+	args = append(args, c.noDelayArgs()...)
+	args = append(args, c.mssArgs()...)
+	args = append(args, c.connectTimeoutArgs()...)
+	if c.BindAddr != "" {
+		args = append(args, "-B", c.BindAddr)
+	}
+	if c.useIPv6() {
 		args = append(args, "-V")
 	}
+	args = append(args, c.ExtraArgs...)
 	return args
 }
 
@@ -260,11 +574,14 @@ func (c *Config) revServerArgs() []string {
 	}
 	args = append(args, "-p", c.PortRangeStr(c.Parallel),
 		"-f", "m",
-		"-i", strconv.Itoa(c.Interval))
+		"-i", c.intervalArg())
+	if c.WindowSize != "" {
+		args = append(args, "-w", c.WindowSize)
+	}
 	if c.Enhanced {
 		args = append(args, "-e")
 	}
-	if c.IPv6 {
+	if c.useIPv6() {
 		args = append(args, "-V")
 	}
 	return args
@@ -281,22 +598,29 @@ func (c *Config) revClientCmd() string {
 	if c.Protocol == "udp" {
 		parts = append(parts, "-u")
 	}
-	parts = append(parts, "-p", c.PortRangeStr(c.Parallel),
-		"-t", strconv.Itoa(c.Duration),
-		"-f", "m",
-		"-i", strconv.Itoa(c.Interval))
+	parts = append(parts, "-p", c.PortRangeStr(c.Parallel))
+	parts = append(parts, c.limitArgs()...)
+	parts = append(parts, "-f", "m", "-i", c.intervalArg())
 	if c.BlockSize > 0 {
 		parts = append(parts, "-l", strconv.Itoa(c.BlockSize))
 	}
+	if c.WindowSize != "" {
+		parts = append(parts, "-w", c.WindowSize)
+	}
 	if c.Bandwidth != "" && c.Protocol == "udp" {
 		parts = append(parts, "-b", c.Bandwidth)
 	}
 	if c.Enhanced {
 		parts = append(parts, "-e")
 	}
-	if c.IPv6 {
+	parts = append(parts, c.tosArgs()...)
+	parts = append(parts, c.noDelayArgs()...)
+	parts = append(parts, c.mssArgs()...)
+	parts = append(parts, c.connectTimeoutArgs()...)
+	if c.useIPv6() {
 		parts = append(parts, "-V")
 	}
+	parts = append(parts, c.ExtraArgs...)
 	return strings.Join(parts, " ")
 }
 
@@ -308,22 +632,32 @@ func (c *Config) dualtestClientArgs() []string {
 		args = append(args, "-u")
 	}
 	args = append(args, "-d") // dualtest flag
-	args = append(args, "-p", c.PortRangeStr(0),
-		"-t", strconv.Itoa(c.Duration),
-		"-f", "m",
-		"-i", strconv.Itoa(c.Interval))
+	args = append(args, "-p", c.PortRangeStr(0))
+	args = append(args, c.limitArgs()...)
+	args = append(args, "-f", "m", "-i", c.intervalArg())
 	if c.BlockSize > 0 {
 		args = append(args, "-l", strconv.Itoa(c.BlockSize))
 	}
+	if c.WindowSize != "" {
+		args = append(args, "-w", c.WindowSize)
+	}
 	if c.Bandwidth != "" && c.Protocol == "udp" {
 		args = append(args, "-b", c.Bandwidth)
 	}
 	if c.Enhanced {
 		args = append(args, "-e")
 	}
-	if c.IPv6 {
+	args = append(args, c.tosArgs()...)
+	args = append(args, c.noDelayArgs()...)
+	args = append(args, c.mssArgs()...)
+	args = append(args, c.connectTimeoutArgs()...)
+	if c.BindAddr != "" {
+		args = append(args, "-B", c.BindAddr)
+	}
+	if c.useIPv6() {
 		args = append(args, "-V")
 	}
+	args = append(args, c.ExtraArgs...)
 	return args
 }
 
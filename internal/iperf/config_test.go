@@ -3,6 +3,9 @@ package iperf
 import (
 	"strings"
 	"testing"
+
+	"iperf-tool/internal/format"
+	"iperf-tool/internal/model"
 )
 
 func validConfig() Config {
@@ -52,6 +55,78 @@ func TestValidate_BlockSize(t *testing.T) {
 	}
 }
 
+func TestValidate_UDPDatagramSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		blockSize int
+		wantErr   bool
+	}{
+		{"zero (default)", 0, false},
+		{"fits 1500 MTU", 1472, false},
+		{"jumbo", 8972, false},
+		{"max IPv4 payload", MaxUDPDatagram, false},
+		{"above max payload", MaxUDPDatagram + 1, true},
+		{"TCP-sized buffer", 131072, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Protocol = "udp"
+			cfg.BlockSize = tt.blockSize
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUDPFragments(t *testing.T) {
+	tests := []struct {
+		size, mtu int
+		ipv6      bool
+		want      int
+	}{
+		{1470, 1500, false, 1},
+		{1472, 1500, false, 1},
+		{1473, 1500, false, 2},
+		{8000, 1500, false, 6},
+		{8972, 9000, false, 1},
+		{1452, 1500, true, 1},
+		{1453, 1500, true, 2},
+		{MaxUDPDatagram, 1500, false, 45},
+	}
+	for _, tt := range tests {
+		if got := UDPFragments(tt.size, tt.mtu, tt.ipv6); got != tt.want {
+			t.Errorf("UDPFragments(%d, %d, %v) = %d, want %d", tt.size, tt.mtu, tt.ipv6, got, tt.want)
+		}
+	}
+}
+
+func TestAdviseDatagram(t *testing.T) {
+	cfg := validConfig()
+	cfg.Protocol = "udp"
+	cfg.BlockSize = 1472
+	if msg, ok := cfg.AdviseDatagram(); ok {
+		t.Errorf("1472-byte datagrams fit a 1500 MTU, got %q", msg)
+	}
+	cfg.BlockSize = 8000
+	if msg, ok := cfg.AdviseDatagram(); !ok || !strings.Contains(msg, "fragmented into 6 IP packets") {
+		t.Errorf("AdviseDatagram() = %q, %v", msg, ok)
+	}
+	cfg.AddrFamily = "6"
+	cfg.BlockSize = 1460
+	if _, ok := cfg.AdviseDatagram(); !ok {
+		t.Error("1460-byte datagrams exceed the IPv6 limit of 1452")
+	}
+	cfg.Protocol = "tcp"
+	cfg.BlockSize = 131072
+	if _, ok := cfg.AdviseDatagram(); ok {
+		t.Error("TCP block sizes are never fragmented")
+	}
+}
+
 func TestValidate_ReverseBidirMutuallyExclusive(t *testing.T) {
 	cfg := validConfig()
 	cfg.Reverse = true
@@ -279,6 +354,54 @@ func TestBandwidthPerStreamMbps(t *testing.T) {
 	}
 }
 
+func TestBandwidthTotalMbps(t *testing.T) {
+	tests := []struct {
+		bw       string
+		parallel int
+		want     float64
+	}{
+		{"", 4, 0},
+		{"25M", 4, 100}, // iperf2 applies -b per stream
+		{"100M", 4, 400},
+		{"1G", 0, 1000},
+	}
+	for _, tt := range tests {
+		cfg := Config{Bandwidth: tt.bw, Parallel: tt.parallel}
+		if got := cfg.BandwidthTotalMbps(); got != tt.want {
+			t.Errorf("BandwidthTotalMbps(%q, %d) = %f, want %f", tt.bw, tt.parallel, got, tt.want)
+		}
+	}
+}
+
+func TestApplyToResult_Targets(t *testing.T) {
+	tests := []struct {
+		name          string
+		protocol, bw  string
+		parallel      int
+		wantPerStream float64
+		wantTotal     float64
+		wantLabel     string
+	}{
+		{"tcp unlimited", "tcp", "", 4, 0, 0, ""},
+		{"tcp 25M x4", "tcp", "25M", 4, 25, 100, "25.00 Mbps/stream (100.00 Mbps total)"},
+		{"udp default", "udp", "", 2, 1, 2, "1.00 Mbps/stream (2.00 Mbps total)"},
+		{"udp single", "udp", "50M", 1, 50, 50, "50.00 Mbps/stream"},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Protocol, cfg.Bandwidth, cfg.Parallel = tt.protocol, tt.bw, tt.parallel
+		result := &model.TestResult{}
+		cfg.ApplyToResult(result, "CLI")
+		if result.TargetPerStreamMbps != tt.wantPerStream || result.TargetTotalMbps != tt.wantTotal {
+			t.Errorf("%s: targets = %v/%v, want %v/%v", tt.name,
+				result.TargetPerStreamMbps, result.TargetTotalMbps, tt.wantPerStream, tt.wantTotal)
+		}
+		if got := format.BandwidthTarget(result); got != tt.wantLabel {
+			t.Errorf("%s: format.BandwidthTarget() = %q, want %q", tt.name, got, tt.wantLabel)
+		}
+	}
+}
+
 func TestConfigValidation(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -424,3 +547,617 @@ func containsArg(args []string, target string) bool {
 	}
 	return false
 }
+
+// containsArgPair reports whether flag is immediately followed by value.
+func containsArgPair(args []string, flag, value string) bool {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == flag && args[i+1] == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidate_OmitSeconds(t *testing.T) {
+	tests := []struct {
+		name    string
+		omit    int
+		wantErr bool
+	}{
+		{"zero (default)", 0, false},
+		{"within duration", 2, false},
+		{"duration minus one", 9, false},
+		{"equal to duration", 10, true},
+		{"negative", -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig() // Duration 10
+			cfg.OmitSeconds = tt.omit
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyToResult_OmitSeconds(t *testing.T) {
+	cfg := validConfig()
+	cfg.OmitSeconds = 2
+	result := &model.TestResult{
+		Intervals: []model.IntervalResult{
+			{TimeStart: 0, TimeEnd: 1},
+			{TimeStart: 1, TimeEnd: 2},
+			{TimeStart: 2, TimeEnd: 3},
+		},
+		ReverseIntervals: []model.IntervalResult{
+			{TimeStart: 0, TimeEnd: 1.001},
+			{TimeStart: 2, TimeEnd: 3},
+		},
+	}
+	cfg.ApplyToResult(result, "CLI")
+
+	if result.OmitSeconds != 2 {
+		t.Errorf("OmitSeconds = %d, want 2", result.OmitSeconds)
+	}
+	for i, want := range []bool{true, true, false} {
+		if result.Intervals[i].Omitted != want {
+			t.Errorf("Intervals[%d].Omitted = %v, want %v", i, result.Intervals[i].Omitted, want)
+		}
+	}
+	if !result.ReverseIntervals[0].Omitted || result.ReverseIntervals[1].Omitted {
+		t.Errorf("ReverseIntervals omitted = [%v %v], want [true false]",
+			result.ReverseIntervals[0].Omitted, result.ReverseIntervals[1].Omitted)
+	}
+}
+
+func TestValidate_AddrFamily(t *testing.T) {
+	tests := []struct {
+		name    string
+		family  string
+		ipv6    bool
+		wantErr bool
+	}{
+		{"auto", "", false, false},
+		{"ipv4", "4", false, false},
+		{"ipv6", "6", false, false},
+		{"ipv6 with -V", "6", true, false},
+		{"invalid", "5", false, true},
+		{"ipv4 conflicts with -V", "4", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.AddrFamily = tt.family
+			cfg.IPv6 = tt.ipv6
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddrFamilyArgs(t *testing.T) {
+	cfg := validConfig()
+	cfg.AddrFamily = "6"
+	if !containsArg(cfg.fwdClientArgs(), "-V") {
+		t.Error("expected -V in fwdClientArgs for address family 6")
+	}
+	if !containsArg(cfg.fwdServerArgs(), "-V") {
+		t.Error("expected -V in fwdServerArgs for address family 6")
+	}
+	cfg.LocalAddr = "192.168.1.2"
+	if !strings.Contains(cfg.revClientCmd(), "-V") {
+		t.Error("expected -V in revClientCmd for address family 6")
+	}
+
+	cfg.AddrFamily = "4"
+	if containsArg(cfg.fwdClientArgs(), "-V") {
+		t.Error("unexpected -V in fwdClientArgs for address family 4")
+	}
+}
+
+func TestApplyToResult_AddrFamily(t *testing.T) {
+	tests := []struct {
+		family string
+		ipv6   bool
+		want   string
+	}{
+		{"", false, ""},
+		{"4", false, "IPv4"},
+		{"6", false, "IPv6"},
+		{"", true, "IPv6"},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.AddrFamily = tt.family
+		cfg.IPv6 = tt.ipv6
+		result := &model.TestResult{}
+		cfg.ApplyToResult(result, "CLI")
+		if result.AddrFamily != tt.want {
+			t.Errorf("family=%q ipv6=%v: AddrFamily = %q, want %q", tt.family, tt.ipv6, result.AddrFamily, tt.want)
+		}
+	}
+}
+
+func TestParseTOS(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"0", 0, false},
+		{"184", 184, false},
+		{"255", 255, false},
+		{"0xb8", 184, false},
+		{"ef", 184, false},
+		{"EF", 184, false},
+		{"af41", 136, false},
+		{"cs5", 160, false},
+		{"cs0", 0, false},
+		{"256", 0, true},
+		{"-1", 0, true},
+		{"af99", 0, true},
+		{"ef; rm -rf /", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseTOS(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTOS(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseTOS(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_DSCP(t *testing.T) {
+	for _, v := range []string{"", "46", "ef", "af41", "cs5"} {
+		cfg := validConfig()
+		cfg.DSCP = v
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("DSCP %q: unexpected error %v", v, err)
+		}
+	}
+	for _, v := range []string{"300", "garbage", "af5"} {
+		cfg := validConfig()
+		cfg.DSCP = v
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("DSCP %q: expected validation error", v)
+		}
+	}
+}
+
+func TestDSCPArgs(t *testing.T) {
+	cfg := validConfig()
+	if containsArg(cfg.fwdClientArgs(), "-S") {
+		t.Error("unexpected -S without DSCP")
+	}
+
+	cfg.DSCP = "ef"
+	args := cfg.fwdClientArgs()
+	if !containsArgPair(args, "-S", "184") {
+		t.Errorf("expected -S 184 in fwdClientArgs, got %v", args)
+	}
+	if !containsArgPair(cfg.dualtestClientArgs(), "-S", "184") {
+		t.Error("expected -S 184 in dualtestClientArgs")
+	}
+	cfg.LocalAddr = "192.168.1.2"
+	if !strings.Contains(cfg.revClientCmd(), "-S 184") {
+		t.Error("expected -S 184 in revClientCmd")
+	}
+	if containsArg(cfg.fwdServerArgs(), "-S") {
+		t.Error("server args should not carry -S")
+	}
+
+	result := &model.TestResult{}
+	cfg.ApplyToResult(result, "CLI")
+	if result.DSCP != "ef" {
+		t.Errorf("result.DSCP = %q, want ef", result.DSCP)
+	}
+}
+
+func TestValidate_WindowSize(t *testing.T) {
+	for _, v := range []string{"", "65536", "256K", "2M", "1g"} {
+		cfg := validConfig()
+		cfg.WindowSize = v
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("WindowSize %q: unexpected error %v", v, err)
+		}
+	}
+	for _, v := range []string{"2MB", "-1", "big", "1.5M"} {
+		cfg := validConfig()
+		cfg.WindowSize = v
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("WindowSize %q: expected validation error", v)
+		}
+	}
+}
+
+func TestWindowSizeArgs(t *testing.T) {
+	cfg := validConfig()
+	if containsArg(cfg.fwdClientArgs(), "-w") {
+		t.Error("unexpected -w without WindowSize")
+	}
+
+	cfg.WindowSize = "256K"
+	if !containsArgPair(cfg.fwdClientArgs(), "-w", "256K") {
+		t.Error("expected -w 256K in fwdClientArgs")
+	}
+	if !containsArgPair(cfg.fwdServerArgs(), "-w", "256K") {
+		t.Error("expected -w 256K in fwdServerArgs")
+	}
+	if !containsArgPair(cfg.revServerArgs(), "-w", "256K") {
+		t.Error("expected -w 256K in revServerArgs")
+	}
+	if !containsArgPair(cfg.dualtestClientArgs(), "-w", "256K") {
+		t.Error("expected -w 256K in dualtestClientArgs")
+	}
+	cfg.LocalAddr = "192.168.1.2"
+	if !strings.Contains(cfg.revClientCmd(), "-w 256K") {
+		t.Error("expected -w 256K in revClientCmd")
+	}
+
+	result := &model.TestResult{}
+	cfg.ApplyToResult(result, "CLI")
+	if result.WindowSize != "256K" {
+		t.Errorf("result.WindowSize = %q, want 256K", result.WindowSize)
+	}
+}
+
+func TestValidate_FractionalInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval int
+		sec      float64
+		wantErr  bool
+	}{
+		{"whole seconds", 1, 0, false},
+		{"half second", 0, 0.5, false},
+		{"fractional overrides int", 1, 0.25, false},
+		{"equal to duration", 0, 10, false},
+		{"zero", 0, 0, true},
+		{"negative", 0, -0.5, true},
+		{"longer than duration", 0, 10.5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Duration = 10
+			cfg.Interval = tt.interval
+			cfg.IntervalSec = tt.sec
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFractionalIntervalArgs(t *testing.T) {
+	cfg := validConfig()
+	cfg.IntervalSec = 0.5
+	if !containsArgPair(cfg.fwdClientArgs(), "-i", "0.5") {
+		t.Errorf("expected -i 0.5 in fwdClientArgs, got %v", cfg.fwdClientArgs())
+	}
+	if !containsArgPair(cfg.fwdServerArgs(), "-i", "0.5") {
+		t.Error("expected -i 0.5 in fwdServerArgs")
+	}
+
+	cfg.IntervalSec = 2.0
+	if !containsArgPair(cfg.fwdClientArgs(), "-i", "2") {
+		t.Errorf("expected -i 2 without trailing zeros, got %v", cfg.fwdClientArgs())
+	}
+}
+
+func TestValidate_BindAddr(t *testing.T) {
+	for _, v := range []string{"", "192.168.1.10", "fe80::1"} {
+		cfg := validConfig()
+		cfg.BindAddr = v
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("BindAddr %q: unexpected error %v", v, err)
+		}
+	}
+	for _, v := range []string{"eth0", "10.0.0", "host.example.com"} {
+		cfg := validConfig()
+		cfg.BindAddr = v
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("BindAddr %q: expected validation error", v)
+		}
+	}
+}
+
+func TestBindAddrArgs(t *testing.T) {
+	cfg := validConfig()
+	if containsArg(cfg.fwdClientArgs(), "-B") {
+		t.Error("unexpected -B without BindAddr")
+	}
+
+	cfg.BindAddr = "10.1.1.5"
+	if args := cfg.fwdClientArgs(); !containsArgPair(args, "-B", "10.1.1.5") {
+		t.Errorf("expected -B 10.1.1.5 in fwdClientArgs, got %v", args)
+	}
+	if !containsArgPair(cfg.dualtestClientArgs(), "-B", "10.1.1.5") {
+		t.Error("expected -B in dualtestClientArgs")
+	}
+	cfg.LocalAddr = "192.168.1.2"
+	if strings.Contains(cfg.revClientCmd(), "-B") {
+		t.Error("remote client command should not bind to a local address")
+	}
+
+	result := &model.TestResult{}
+	cfg.ApplyToResult(result, "CLI")
+	if result.BindAddr != "10.1.1.5" {
+		t.Errorf("result.BindAddr = %q, want 10.1.1.5", result.BindAddr)
+	}
+}
+
+func TestNoDelayArgs(t *testing.T) {
+	cfg := validConfig()
+	cfg.LocalAddr = "192.168.1.2"
+	if containsArg(cfg.fwdClientArgs(), "-N") || strings.Contains(cfg.revClientCmd(), " -N") {
+		t.Error("unexpected -N without NoDelay")
+	}
+
+	cfg.NoDelay = true
+	if !containsArg(cfg.fwdClientArgs(), "-N") {
+		t.Error("expected -N in fwdClientArgs")
+	}
+	if !containsArg(cfg.dualtestClientArgs(), "-N") {
+		t.Error("expected -N in dualtestClientArgs")
+	}
+	if !strings.Contains(cfg.revClientCmd(), " -N") {
+		t.Error("expected -N in revClientCmd")
+	}
+	if containsArg(cfg.fwdServerArgs(), "-N") {
+		t.Error("server args should not carry -N")
+	}
+
+	cfg.Protocol = "udp"
+	if containsArg(cfg.fwdClientArgs(), "-N") {
+		t.Error("-N is TCP-only")
+	}
+	result := &model.TestResult{}
+	cfg.ApplyToResult(result, "CLI")
+	if result.NoDelay {
+		t.Error("NoDelay should not be recorded for UDP")
+	}
+}
+
+func TestMSSArgs(t *testing.T) {
+	cfg := validConfig()
+	cfg.LocalAddr = "192.168.1.2"
+	if containsArg(cfg.fwdClientArgs(), "-M") {
+		t.Error("unexpected -M without MSS")
+	}
+
+	cfg.MSS = 1200
+	for name, args := range map[string][]string{
+		"fwdClientArgs":      cfg.fwdClientArgs(),
+		"dualtestClientArgs": cfg.dualtestClientArgs(),
+	} {
+		if !strings.Contains(strings.Join(args, " "), "-M 1200") {
+			t.Errorf("expected -M 1200 in %s: %v", name, args)
+		}
+	}
+	if !strings.Contains(cfg.revClientCmd(), " -M 1200") {
+		t.Error("expected -M 1200 in revClientCmd")
+	}
+	if containsArg(cfg.fwdServerArgs(), "-M") {
+		t.Error("server args should not carry -M")
+	}
+	result := &model.TestResult{}
+	cfg.ApplyToResult(result, "CLI")
+	if result.RequestedMSS != 1200 {
+		t.Errorf("RequestedMSS = %d, want 1200", result.RequestedMSS)
+	}
+
+	cfg.Protocol = "udp"
+	if containsArg(cfg.fwdClientArgs(), "-M") {
+		t.Error("-M is TCP-only")
+	}
+}
+
+func TestApplyToResult_Label(t *testing.T) {
+	cfg := validConfig()
+	cfg.Label = "vpn-on"
+	result := &model.TestResult{}
+	cfg.ApplyToResult(result, "CLI")
+	if result.Label != "vpn-on" {
+		t.Errorf("result.Label = %q, want vpn-on", result.Label)
+	}
+	if args := strings.Join(cfg.fwdClientArgs(), " "); strings.Contains(args, "vpn-on") {
+		t.Errorf("label must not reach iperf2 args: %s", args)
+	}
+}
+
+func TestValidate_MSS(t *testing.T) {
+	for _, tt := range []struct {
+		mss     int
+		wantErr bool
+	}{{0, false}, {88, false}, {1460, false}, {9000, false}, {87, true}, {9001, true}, {-1, true}} {
+		cfg := validConfig()
+		cfg.MSS = tt.mss
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("MSS %d: Validate() error = %v, wantErr %v", tt.mss, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidate_PingCount(t *testing.T) {
+	for _, tt := range []struct {
+		count   int
+		wantErr bool
+	}{{0, false}, {1, false}, {20, false}, {-1, true}} {
+		cfg := validConfig()
+		cfg.PingCount = tt.count
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("PingCount %d: Validate() error = %v, wantErr %v", tt.count, err, tt.wantErr)
+		}
+	}
+	if got := DefaultConfig().PingCount; got != 4 {
+		t.Errorf("DefaultConfig().PingCount = %d, want 4", got)
+	}
+}
+
+func TestAdviseParallel(t *testing.T) {
+	tests := []struct {
+		n, cores int
+		want     bool
+	}{
+		{1, 1, false},
+		{4, 1, false},
+		{5, 1, true},
+		{32, 8, false},
+		{33, 8, true},
+		{128, 32, false},
+		{8, 0, true}, // unknown core count counts as one core
+	}
+	for _, tt := range tests {
+		msg, got := adviseParallel(tt.n, tt.cores)
+		if got != tt.want {
+			t.Errorf("adviseParallel(%d, %d) = %v, want %v", tt.n, tt.cores, got, tt.want)
+		}
+		if got != (msg != "") {
+			t.Errorf("adviseParallel(%d, %d) message %q does not match flag %v", tt.n, tt.cores, msg, got)
+		}
+	}
+	if msg, _ := adviseParallel(33, 8); !strings.Contains(msg, "33 parallel streams on 8 CPU cores") {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
+
+func TestLimitArgs_DataLimited(t *testing.T) {
+	cfg := validConfig()
+	cfg.BytesLimit = "1G"
+	args := cfg.fwdClientArgs()
+	if !containsArg(args, "-n") || containsArg(args, "-t") {
+		t.Errorf("expected -n without -t, got %v", args)
+	}
+
+	cfg = validConfig()
+	cfg.BlocksLimit = 5000
+	args = cfg.dualtestClientArgs()
+	if !containsArg(args, "-k") || containsArg(args, "-t") {
+		t.Errorf("expected -k without -t, got %v", args)
+	}
+	if !cfg.DataLimited() {
+		t.Error("DataLimited() = false with -k set")
+	}
+}
+
+func TestValidate_DataLimit(t *testing.T) {
+	cfg := validConfig()
+	cfg.BytesLimit = "500M"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"n and k", func(c *Config) { c.BytesLimit = "1G"; c.BlocksLimit = 10 }},
+		{"n with duration", func(c *Config) { c.BytesLimit = "1G"; c.Duration = 20 }},
+		{"bad size", func(c *Config) { c.BytesLimit = "lots" }},
+		{"negative blocks", func(c *Config) { c.BlocksLimit = -1 }},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		tt.modify(&cfg)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestValidate_DataLimitIgnoresDuration(t *testing.T) {
+	// Duration is only the unused default here; -O and -i may exceed it.
+	cfg := validConfig()
+	cfg.BytesLimit = "10G"
+	cfg.OmitSeconds = 30
+	cfg.IntervalSec = 60
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want -n with -O 30 -i 60 accepted", err)
+	}
+	cfg.OmitSeconds = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a negative omit with -n")
+	}
+}
+
+func TestExtraArgs(t *testing.T) {
+	cfg := validConfig()
+	cfg.ExtraArgs = []string{"--trip-times", "-Z", "cubic"}
+
+	for name, args := range map[string][]string{
+		"fwd":      cfg.fwdClientArgs(),
+		"dualtest": cfg.dualtestClientArgs(),
+		"rev":      strings.Fields(cfg.revClientCmd()),
+	} {
+		tail := args[len(args)-3:]
+		if strings.Join(tail, " ") != "--trip-times -Z cubic" {
+			t.Errorf("%s: extra args not appended last: %v", name, args)
+		}
+	}
+	if containsArg(cfg.fwdServerArgs(), "--trip-times") {
+		t.Error("extra args must not reach the server command")
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestValidate_ExtraArgs(t *testing.T) {
+	for _, bad := range []string{"--fq-rate;reboot", "$(id)", "a b", "-y", "--output=/tmp/x", "-p",
+		"-t5", "-P4", "--parallel=4", "-u", "--enhanced", "-l1400", "-n1G", "-k", "-R"} {
+		cfg := validConfig()
+		cfg.ExtraArgs = []string{bad}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for extra arg %q", bad)
+		}
+	}
+}
+
+func TestExtraArgFlag(t *testing.T) {
+	for in, want := range map[string]string{
+		"-t5": "-t", "-P": "-P", "--parallel=4": "--parallel", "--trip-times": "--trip-times", "bbr": "bbr",
+	} {
+		if got := extraArgFlag(in); got != want {
+			t.Errorf("extraArgFlag(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestConnectTimeoutArgs(t *testing.T) {
+	cfg := validConfig()
+	cfg.ConnectTimeoutMs = 3000
+	for name, args := range map[string][]string{
+		"fwd":      cfg.fwdClientArgs(),
+		"dualtest": cfg.dualtestClientArgs(),
+		"rev":      strings.Fields(cfg.revClientCmd()),
+	} {
+		if !strings.Contains(strings.Join(args, " "), "--connect-timeout 3000") {
+			t.Errorf("%s: missing --connect-timeout 3000 in %v", name, args)
+		}
+	}
+
+	cfg.Protocol = "udp"
+	if containsArg(cfg.fwdClientArgs(), "--connect-timeout") {
+		t.Error("UDP clients have no connect to time out")
+	}
+
+	cfg = validConfig()
+	if containsArg(cfg.fwdClientArgs(), "--connect-timeout") {
+		t.Error("--connect-timeout emitted without being set")
+	}
+	cfg.ConnectTimeoutMs = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative connect timeout")
+	}
+}
@@ -0,0 +1,58 @@
+//go:build !windows
+
+package iperf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDebugRunnerWithPath_WritesLog runs a fake iperf client and checks the
+// raw output lands in the configured debug log.
+func TestDebugRunnerWithPath_WritesLog(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "client.txt")
+	if err := os.WriteFile(out, []byte(sampleTCPOutput+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bin := filepath.Join(dir, "iperf")
+	script := "#!/bin/sh\ncat " + out + "\n"
+	if err := os.WriteFile(bin, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.BinaryPath = bin
+	cfg.ServerAddr = "127.0.0.1"
+
+	logPath := filepath.Join(dir, "debug.log")
+	if _, err := NewDebugRunnerWithPath(logPath).RunForward(context.Background(), cfg, nil, nil); err != nil {
+		t.Fatalf("RunForward() error: %v", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("debug log not written: %v", err)
+	}
+	log := string(data)
+	if !strings.Contains(log, " client ===\nargs: [-c 127.0.0.1") {
+		t.Errorf("debug log missing header line:\n%s", log)
+	}
+	for _, want := range []string{
+		"[  1]  0.00-1.00 sec  1.12 MBytes  9.44 Mbits/sec",
+		"[  2]  1.00-2.00 sec  1.10 MBytes  9.23 Mbits/sec",
+	} {
+		if !strings.Contains(log, want) {
+			t.Errorf("debug log missing interval line %q", want)
+		}
+	}
+}
+
+func TestNewDebugRunnerWithPath_EmptyUsesDefault(t *testing.T) {
+	if r := NewDebugRunnerWithPath(""); r.debugPath != DebugLogPath {
+		t.Errorf("debugPath = %q, want %q", r.debugPath, DebugLogPath)
+	}
+}
@@ -0,0 +1,52 @@
+package iperf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dscpKeywords maps DSCP class names to their 6-bit code points.
+var dscpKeywords = map[string]int{
+	"cs0": 0, "cs1": 8, "cs2": 16, "cs3": 24, "cs4": 32, "cs5": 40, "cs6": 48, "cs7": 56,
+	"af11": 10, "af12": 12, "af13": 14,
+	"af21": 18, "af22": 20, "af23": 22,
+	"af31": 26, "af32": 28, "af33": 30,
+	"af41": 34, "af42": 36, "af43": 38,
+	"ef": 46, "va": 44, "le": 1,
+}
+
+// ParseTOS converts a DSCP setting to the IP ToS byte passed to iperf2 -S.
+// It accepts a numeric ToS byte (0-255; decimal, 0x hex or 0 octal) or a
+// DSCP keyword such as "ef", "af41" or "cs5". iperf2 has no --dscp option,
+// so keywords are translated to the ToS byte (code point << 2).
+func ParseTOS(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty DSCP value")
+	}
+	if dscp, ok := dscpKeywords[strings.ToLower(s)]; ok {
+		return dscp << 2, nil
+	}
+	v, err := strconv.ParseInt(s, 0, 0)
+	if err != nil {
+		return 0, fmt.Errorf("DSCP must be a ToS byte (0-255) or a keyword like ef, af41, cs5, got %q", s)
+	}
+	if v < 0 || v > 255 {
+		return 0, fmt.Errorf("ToS byte must be between 0 and 255, got %d", v)
+	}
+	return int(v), nil
+}
+
+// tosArgs returns the -S argument pair for the configured DSCP, or nil.
+// The value has already been checked by Validate.
+func (c *Config) tosArgs() []string {
+	if c.DSCP == "" {
+		return nil
+	}
+	tos, err := ParseTOS(c.DSCP)
+	if err != nil {
+		return nil
+	}
+	return []string{"-S", strconv.Itoa(tos)}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -59,8 +60,9 @@ var (
 
 	// Enhanced TCP client with -V flag: Write/Err  Rtry  Cwnd/RTT  NetPwr
 	// [  1] 0.00-3.35 sec  28.5 MBytes  71.4 Mbits/sec  229/0          0       NA/98000(49)us    91.10
+	// [  1] 0.00-1.00 sec  11.2 MBytes  94.4 Mbits/sec  90/0         0      372K/3120(84) us  3783
 	reClientTCPVerbose = regexp.MustCompile(
-		`^\[\s*(\d+)\]\s+([\d.]+)-([\d.]+)\s+sec\s+([\d.]+)\s+MBytes\s+([\d.]+)\s+Mbits/sec\s+(\d+)/(\d+)\s+(\d+)`)
+		`^\[\s*(\d+)\]\s+([\d.]+)-([\d.]+)\s+sec\s+([\d.]+)\s+MBytes\s+([\d.]+)\s+Mbits/sec\s+(\d+)/(\d+)\s+(\d+)(?:\s+(NA|\d+)K?/(\d+)\(\d+\)\s*us)?`)
 
 	// SUM line (server-side, with loss):
 	// [SUM-2]  0.00-10.00 sec  9.77 MBytes  5.59 Mbits/sec  4942/11909 (41%)
@@ -82,6 +84,27 @@ var (
 
 	// WARNING: ack of last datagram failed — server report is fabricated
 	reACKWarning = regexp.MustCompile(`WARNING.*ack.*last.*datagram`)
+
+	// Client header socket buffer (SO_SNDBUF as reported by iperf2):
+	// TCP window size: 0.06 MByte (default)
+	// TCP window size:  85.0 KByte (default)
+	reTCPWindow = regexp.MustCompile(`TCP window size:\s*([\d.]+)\s*(Byte|KByte|MByte)`)
+
+	// Negotiated MSS from the -e connection line or the -m header:
+	// [  1] local 10.0.0.2 port 5678 connected with 10.0.0.1 port 5201 (sock=3) (icwnd/mss/irtt=14/1448/120)
+	// MSS size 1448 bytes (MTU 1500 bytes, ethernet)
+	reConnMSS   = regexp.MustCompile(`icwnd/mss/irtt=\d+/(\d+)/`)
+	reHeaderMSS = regexp.MustCompile(`MSS size (\d+) bytes`)
+
+	// Test header lines naming the peer, protocol and port:
+	// Client connecting to 10.0.0.1, TCP port 5201
+	// Server listening on UDP port 5201
+	reClientHeader = regexp.MustCompile(`^Client connecting to (\S+?), (TCP|UDP) port (\d+)`)
+	reServerHeader = regexp.MustCompile(`^Server listening on (TCP|UDP) port (\d+)`)
+
+	// Connection line naming the peer address and port:
+	// [  1] local 10.0.0.2 port 52800 connected with 10.0.0.1 port 5201
+	reConnPeer = regexp.MustCompile(`local \S+ port \d+ connected with (\S+) port (\d+)`)
 )
 
 // parsedLine holds the parsed fields from a single iperf2 output line.
@@ -106,6 +129,11 @@ type parsedLine struct {
 	writeCount   int // client-side -e
 	errCount     int
 	timeoCount   int
+	// TCP sender info from the -e client Rtry and Cwnd/RTT columns
+	tcpInfo   bool
+	retries   int
+	cwndBytes int64 // 0 when iperf2 prints NA
+	rttUs     int
 }
 
 // parseSingleLine attempts to parse a single iperf2 output line.
@@ -258,6 +286,12 @@ func parseClientTCPVerboseMatch(m []string) *parsedLine {
 	p.bandwidthBps = bw * 1_000_000
 	p.writeCount, _ = strconv.Atoi(m[6])
 	p.errCount, _ = strconv.Atoi(m[7])
+	p.tcpInfo = true
+	p.retries, _ = strconv.Atoi(m[8])
+	if kb, err := strconv.ParseInt(m[9], 10, 64); err == nil {
+		p.cwndBytes = kb * 1024
+	}
+	p.rttUs, _ = strconv.Atoi(m[10])
 	return p
 }
 
@@ -376,9 +410,119 @@ func ParseOutput(text string, isServerSide bool) (*model.TestResult, error) {
 		result.ActualDuration = result.Intervals[len(result.Intervals)-1].TimeEnd
 	}
 
+	// Socket info describes the sending side; server output reports its
+	// receive buffer instead, so only the client header is used.
+	if !isServerSide {
+		parseSocketInfo(lines, result)
+		result.Streams = tcpSenderStreams(allParsed)
+		if result.Retransmits == 0 {
+			for _, st := range result.Streams {
+				result.Retransmits += st.Retransmits
+			}
+		}
+	}
+	parseTestHeader(lines, result)
+
 	return result, nil
 }
 
+// parseTestHeader fills the server address, port, protocol and direction
+// from the iperf2 banner so output produced outside this tool can be
+// labelled without a Config. Only empty fields are set; ApplyToResult
+// overrides them for runs started here. iperf2 prints no omit or interval
+// settings, so those still come from the Config.
+func parseTestHeader(lines []string, result *model.TestResult) {
+	client, server := false, false
+	direction := ""
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if m := reClientHeader.FindStringSubmatch(trimmed); m != nil {
+			client = true
+			if result.ServerAddr == "" {
+				result.ServerAddr = m[1]
+			}
+			setHeaderProtoPort(result, m[2], m[3])
+		} else if m := reServerHeader.FindStringSubmatch(trimmed); m != nil {
+			server = true
+			setHeaderProtoPort(result, m[1], m[2])
+		}
+		switch {
+		case strings.Contains(trimmed, "(full-duplex)"):
+			direction = "Bidirectional"
+		case strings.Contains(trimmed, "(reverse)") && direction == "":
+			direction = "Reverse"
+		}
+	}
+	// A dualtest (-d) client prints its own listener banner as well.
+	if client && server && direction == "" {
+		direction = "Bidirectional"
+	}
+	if direction == "" && client {
+		direction = "Forward"
+	}
+	if result.Direction == "" {
+		result.Direction = direction
+	}
+
+	// The client's outgoing connection names the server IP it actually
+	// dialled; incoming (dualtest/reverse) connections have a client peer.
+	if client && result.ServerResolvedIP == "" {
+		port := strconv.Itoa(result.Port)
+		for _, line := range lines {
+			if m := reConnPeer.FindStringSubmatch(line); m != nil && m[2] == port {
+				result.ServerResolvedIP = m[1]
+				break
+			}
+		}
+	}
+}
+
+// setHeaderProtoPort sets Protocol and Port from banner values when unset.
+func setHeaderProtoPort(result *model.TestResult, proto, port string) {
+	if result.Protocol == "" {
+		result.Protocol = proto
+	}
+	if result.Port == 0 {
+		result.Port, _ = strconv.Atoi(port)
+	}
+}
+
+// parseSocketInfo extracts the negotiated TCP MSS and the socket send buffer
+// size from iperf2 client header lines. Fields are left zero when the lines
+// are absent (e.g. UDP runs or iperf2 built without -e support).
+func parseSocketInfo(lines []string, result *model.TestResult) {
+	for _, line := range lines {
+		if result.SndBufBytes == 0 {
+			if m := reTCPWindow.FindStringSubmatch(line); m != nil {
+				result.SndBufBytes = parseWindowBytes(m[1], m[2])
+			}
+		}
+		if result.MSS == 0 {
+			if m := reConnMSS.FindStringSubmatch(line); m != nil {
+				result.MSS, _ = strconv.Atoi(m[1])
+			} else if m := reHeaderMSS.FindStringSubmatch(line); m != nil {
+				result.MSS, _ = strconv.Atoi(m[1])
+			}
+		}
+	}
+}
+
+// parseWindowBytes converts an iperf2 "TCP window size" value to bytes.
+// iperf2 uses binary units for buffer sizes (1 KByte = 1024 bytes).
+func parseWindowBytes(value, unit string) int {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	switch unit {
+	case "KByte":
+		v *= 1024
+	case "MByte":
+		v *= 1024 * 1024
+	}
+	return int(math.Round(v))
+}
+
 // parseSumLine parses a [SUM] or [SUM-N] line.
 func parseSumLine(line string) *parsedLine {
 	// Try server SUM with jitter
@@ -468,9 +612,34 @@ func aggregateInterval(lines []*parsedLine, isServer bool) *model.IntervalResult
 		iv.JitterMs = jitterSum / float64(jitterCount)
 	}
 
+	if len(lines) > 1 {
+		iv.PerStream = make([]model.IntervalResult, 0, len(lines))
+		for _, p := range lines {
+			iv.PerStream = append(iv.PerStream, streamInterval(p))
+		}
+	}
+
 	return iv
 }
 
+// streamInterval converts one per-stream parsed line to an IntervalResult.
+func streamInterval(p *parsedLine) model.IntervalResult {
+	s := model.IntervalResult{
+		TimeStart:    p.timeStart,
+		TimeEnd:      p.timeEnd,
+		Bytes:        p.bytes,
+		BandwidthBps: p.bandwidthBps,
+		StreamID:     p.streamID,
+	}
+	if p.hasUDP {
+		s.LostPackets = p.lostPackets
+		s.Packets = p.totalPackets
+		s.LostPercent = p.lostPct
+		s.JitterMs = p.jitterMs
+	}
+	return s
+}
+
 // applySumToResult populates the TestResult summary fields from a SUM line.
 func applySumToResult(result *model.TestResult, sum *parsedLine, isServerSide bool) {
 	result.ActualDuration = sum.timeEnd
@@ -569,6 +738,68 @@ func buildSummaryFromParsed(result *model.TestResult, parsed []*parsedLine, isSe
 	}
 }
 
+// tcpSenderStreams builds one StreamResult per stream from -e TCP client
+// lines, which carry retries and the sender's cwnd/RTT. Throughput and
+// retries come from each stream's final (longest) line; RTT min/mean/max and
+// the peak cwnd are taken over the interval lines. Nil for any other output.
+func tcpSenderStreams(parsed []*parsedLine) []model.StreamResult {
+	type acc struct {
+		lines   []*parsedLine
+		final   *parsedLine
+		maxCwnd int64
+	}
+	byStream := map[int]*acc{}
+	var ids []int
+	for _, p := range parsed {
+		if !p.tcpInfo || p.hasUDP {
+			continue
+		}
+		a, ok := byStream[p.streamID]
+		if !ok {
+			a = &acc{}
+			byStream[p.streamID] = a
+			ids = append(ids, p.streamID)
+		}
+		a.lines = append(a.lines, p)
+		if a.final == nil || p.timeEnd-p.timeStart > a.final.timeEnd-a.final.timeStart {
+			a.final = p
+		}
+		a.maxCwnd = max(a.maxCwnd, p.cwndBytes)
+	}
+
+	sort.Ints(ids)
+	var streams []model.StreamResult
+	for _, id := range ids {
+		a := byStream[id]
+		st := model.StreamResult{
+			ID:           id,
+			SentBps:      a.final.bandwidthBps,
+			Retransmits:  a.final.retries,
+			MaxCwndBytes: a.maxCwnd,
+			Sender:       true,
+		}
+		// The final summary line repeats the last sample; leave it out of
+		// the RTT spread unless it is the only line.
+		var sum, n int
+		for _, p := range a.lines {
+			if p.rttUs == 0 || (p == a.final && len(a.lines) > 1) {
+				continue
+			}
+			if n == 0 || p.rttUs < st.MinRTTUs {
+				st.MinRTTUs = p.rttUs
+			}
+			st.MaxRTTUs = max(st.MaxRTTUs, p.rttUs)
+			sum += p.rttUs
+			n++
+		}
+		if n > 0 {
+			st.MeanRTTUs = sum / n
+		}
+		streams = append(streams, st)
+	}
+	return streams
+}
+
 // PairBidirIntervals returns an onInterval callback that buffers half-pairs
 // (forward-only or reverse-only) until the matching opposite half arrives, then
 // emits a combined (fwd, rev) row to `emit`. Pairing is keyed by rounded
@@ -667,6 +898,7 @@ type IntervalAggregator struct {
 	curLost    int
 	curPkts    int
 	curStreams int
+	curPer     []model.IntervalResult
 	hasCur     bool
 }
 
@@ -717,6 +949,7 @@ func (a *IntervalAggregator) Add(iv *model.IntervalResult) {
 		a.curEnd = iv.TimeEnd
 	}
 	a.curStreams++
+	a.curPer = append(a.curPer, *iv)
 }
 
 // Flush emits the current bucket if any data is pending.
@@ -741,6 +974,9 @@ func (a *IntervalAggregator) flushCurrent() {
 	if a.curPkts > 0 {
 		out.LostPercent = float64(a.curLost) / float64(a.curPkts) * 100
 	}
+	if a.curStreams > 1 {
+		out.PerStream = a.curPer
+	}
 	a.emit(out)
 	a.hasCur = false
 	a.curBytes = 0
@@ -750,6 +986,7 @@ func (a *IntervalAggregator) flushCurrent() {
 	a.curLost = 0
 	a.curPkts = 0
 	a.curStreams = 0
+	a.curPer = nil
 }
 
 // IntervalFilter rejects per-stream and SUM "totals" lines that iperf2 prints
@@ -921,11 +1158,79 @@ func MergeBidirResults(fwdClient, fwdServer, revClient, revServer *model.TestRes
 			result.ReverseLostPercent = revServer.LostPercent
 		}
 		result.ReverseIntervals = revServer.Intervals
+		result.ReverseActualDuration = model.LastActiveEnd(revServer.Intervals)
 	}
 
 	return &result
 }
 
+// receiverStreams builds one StreamResult per stream from server output,
+// taking the receive rate and, for UDP, jitter and loss from each stream's
+// final (longest) line. Streams are returned in ID order.
+func receiverStreams(text string) []model.StreamResult {
+	byStream := map[int]*parsedLine{}
+	var ids []int
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[SUM") {
+			continue
+		}
+		p, ok := parseSingleLine(trimmed)
+		if !ok {
+			continue
+		}
+		final, seen := byStream[p.streamID]
+		if !seen {
+			ids = append(ids, p.streamID)
+		}
+		if !seen || p.timeEnd-p.timeStart > final.timeEnd-final.timeStart {
+			byStream[p.streamID] = p
+		}
+	}
+
+	sort.Ints(ids)
+	streams := make([]model.StreamResult, 0, len(ids))
+	for _, id := range ids {
+		p := byStream[id]
+		streams = append(streams, model.StreamResult{
+			ID:          id,
+			ReceivedBps: p.bandwidthBps,
+			JitterMs:    p.jitterMs,
+			LostPackets: p.lostPackets,
+			LostPercent: p.lostPct,
+			Packets:     p.totalPackets,
+		})
+	}
+	return streams
+}
+
+// addReverseStreams appends the reverse (Sender=false) per-stream entries of
+// a bidirectional result: receive rate, jitter and loss as measured by the
+// local server, with send rate, retries and RTT from the remote client's
+// stream at the same position. The two ends number their streams
+// independently, so they are matched in ID order rather than by ID. Nothing
+// is added for a single reverse stream, whose figures are the summary.
+func addReverseStreams(result, revClient *model.TestResult, revServer []model.StreamResult) {
+	if len(revServer) < 2 {
+		return
+	}
+	streams := append([]model.StreamResult(nil), result.Streams...)
+	for i, s := range revServer {
+		if revClient != nil && i < len(revClient.Streams) {
+			c := revClient.Streams[i]
+			s.SentBps, s.Retransmits = c.SentBps, c.Retransmits
+			s.MinRTTUs, s.MeanRTTUs, s.MaxRTTUs, s.MaxCwndBytes = c.MinRTTUs, c.MeanRTTUs, c.MaxRTTUs, c.MaxCwndBytes
+		}
+		// UDP clients report no per-stream send data; show what arrived
+		if s.SentBps == 0 {
+			s.SentBps = s.ReceivedBps
+		}
+		s.Sender = false
+		streams = append(streams, s)
+	}
+	result.Streams = streams
+}
+
 // MergeUnidirResults merges client and server results for a unidirectional test.
 // client: send-side data, server: receive-side data (jitter, loss).
 func MergeUnidirResults(client, server *model.TestResult) *model.TestResult {
@@ -963,8 +1268,10 @@ func MergeUnidirResults(client, server *model.TestResult) *model.TestResult {
 				result.BytesReceived = server.BytesSent
 			}
 		}
-		// Set jitter/loss on legacy fields for non-bidir display
-		if result.JitterMs == 0 {
+		// Set jitter/loss on legacy fields for non-bidir display. Jitter is
+		// only meaningful at the receiver, so the server's figure replaces
+		// whatever the client derived from its Server Report.
+		if result.FwdJitterMs > 0 {
 			result.JitterMs = result.FwdJitterMs
 		}
 		if result.LostPackets == 0 && result.FwdLostPackets > 0 {
@@ -993,10 +1300,6 @@ func ParseDualtestOutput(text string) (*model.TestResult, error) {
 	lines := strings.Split(text, "\n")
 
 	// First pass: collect all parsed lines and discover stream IDs
-	type lineInfo struct {
-		parsed *parsedLine
-		raw    string
-	}
 	var allLines []lineInfo
 	streamIDs := map[int]bool{}
 
@@ -1018,44 +1321,43 @@ func ParseDualtestOutput(text string) (*model.TestResult, error) {
 	}
 
 	// In dualtest mode, there are typically 2 stream IDs.
-	// The forward direction (client sending) has client-side lines (no UDP fields).
-	// The reverse direction (receiving) has server-side lines (with UDP fields for UDP tests).
+	// For UDP the directions are told apart by line shape: the client's
+	// sending streams print plain lines (no jitter/loss), while the local
+	// server's receiving streams print jitter/loss. A sending stream's
+	// Server Report line also carries jitter/loss, but it describes the
+	// forward direction and must not be counted as reverse data.
 	// For TCP, both sides look similar — we separate by stream ID order:
 	// the first stream ID seen is the forward (client-initiated) direction.
-
-	// Determine which stream IDs are forward vs reverse.
-	// Forward (outgoing): the client stream — first ID encountered, typically lower.
-	// Reverse (incoming): the server-initiated stream — second ID, typically higher.
-	var fwdID, revID int
-	fwdSet := false
-	for _, li := range allLines {
-		if !fwdSet {
-			fwdID = li.parsed.streamID
-			fwdSet = true
-		} else if li.parsed.streamID != fwdID {
-			revID = li.parsed.streamID
-			break
+	var fwdLines, revLines, fwdIntervalLines []*parsedLine
+	if senders := udpSenderIDs(allLines); senders != nil {
+		for _, li := range allLines {
+			switch {
+			case !senders[li.parsed.streamID]:
+				revLines = append(revLines, li.parsed)
+			case !li.parsed.hasUDP:
+				fwdIntervalLines = append(fwdIntervalLines, li.parsed)
+				fallthrough
+			default:
+				fwdLines = append(fwdLines, li.parsed)
+			}
 		}
-	}
-
-	// If only one stream ID found, treat all as forward
-	if revID == 0 && fwdSet {
-		revID = -1 // sentinel — no reverse data
-	}
-
-	// Separate lines by direction
-	var fwdLines, revLines []*parsedLine
-	for _, li := range allLines {
-		if li.parsed.streamID == fwdID {
-			fwdLines = append(fwdLines, li.parsed)
-		} else {
-			revLines = append(revLines, li.parsed)
+	} else {
+		// Forward (outgoing): the client stream — first ID encountered, typically lower.
+		// Reverse (incoming): the server-initiated stream — every other ID.
+		fwdID := allLines[0].parsed.streamID
+		for _, li := range allLines {
+			if li.parsed.streamID == fwdID {
+				fwdLines = append(fwdLines, li.parsed)
+			} else {
+				revLines = append(revLines, li.parsed)
+			}
 		}
+		fwdIntervalLines = fwdLines
 	}
 
 	// Build interval results for each direction
-	fwdIntervals := buildIntervals(fwdLines, false)
-	revIntervals := buildIntervals(revLines, true)
+	fwdIntervals := buildIntervals(intervalLines(fwdIntervalLines), false)
+	revIntervals := buildIntervals(intervalLines(revLines), true)
 
 	result := &model.TestResult{
 		Timestamp:        time.Now(),
@@ -1102,10 +1404,59 @@ func ParseDualtestOutput(text string) (*model.TestResult, error) {
 	} else if len(result.ReverseIntervals) > 0 {
 		result.ActualDuration = result.ReverseIntervals[len(result.ReverseIntervals)-1].TimeEnd
 	}
+	result.ReverseActualDuration = model.LastActiveEnd(result.ReverseIntervals)
+
+	parseSocketInfo(lines, result)
+	parseTestHeader(lines, result)
 
 	return result, nil
 }
 
+// lineInfo is one parsed dualtest line with its source text.
+type lineInfo struct {
+	parsed *parsedLine
+	raw    string
+}
+
+// udpSenderIDs returns the stream IDs that printed client (sender) lines in
+// UDP dualtest output, or nil when no line has jitter/loss (TCP).
+func udpSenderIDs(lines []lineInfo) map[int]bool {
+	udp := false
+	senders := map[int]bool{}
+	for _, li := range lines {
+		if li.parsed.hasUDP {
+			udp = true
+		} else {
+			senders[li.parsed.streamID] = true
+		}
+	}
+	if !udp {
+		return nil
+	}
+	return senders
+}
+
+// intervalLines drops each stream's end-of-test summary line (starting at 0
+// and longer than the stream's interval length), so it is not merged into
+// the first interval bucket.
+func intervalLines(lines []*parsedLine) []*parsedLine {
+	shortest := map[int]float64{}
+	for _, p := range lines {
+		d := p.timeEnd - p.timeStart
+		if s, ok := shortest[p.streamID]; !ok || d < s {
+			shortest[p.streamID] = d
+		}
+	}
+	var out []*parsedLine
+	for _, p := range lines {
+		if p.timeStart == 0 && roundTime(p.timeEnd-p.timeStart) > roundTime(shortest[p.streamID]) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
 // buildIntervals groups parsed lines by time bucket and aggregates them into intervals.
 func buildIntervals(lines []*parsedLine, isServer bool) []model.IntervalResult {
 	type bucket struct {
@@ -331,6 +331,69 @@ func TestMergeUnidirResults(t *testing.T) {
 	}
 }
 
+func TestMergeUnidirResults_UDPServerJitter(t *testing.T) {
+	client, err := ParseOutput(sampleClientWithValidServerReport, false)
+	if err != nil {
+		t.Fatalf("parse client: %v", err)
+	}
+	server, err := ParseOutput(sampleServerOutput, true)
+	if err != nil {
+		t.Fatalf("parse server: %v", err)
+	}
+
+	merged := MergeUnidirResults(client, server)
+
+	if merged.FwdJitterMs != 6.405 {
+		t.Errorf("FwdJitterMs = %f, want 6.405 (server SUM)", merged.FwdJitterMs)
+	}
+	if merged.JitterMs != 6.405 {
+		t.Errorf("JitterMs = %f, want server-measured 6.405 over client %f", merged.JitterMs, client.JitterMs)
+	}
+	if got := merged.ActualJitterMs(); got != 6.405 {
+		t.Errorf("ActualJitterMs() = %f, want 6.405", got)
+	}
+}
+
+const sampleTCPServerOutput = `------------------------------------------------------------
+Server listening on TCP port 5201 with pid 3120
+Read buffer size:  128 KByte (Dist bin width=16.0 KByte)
+TCP window size:  128 KByte (default)
+------------------------------------------------------------
+[  1] local 10.0.0.1 port 5201 connected with 10.0.0.2 port 51514 (sock=4) (peer 2.1.9)
+[ ID] Interval        Transfer    Bandwidth       Reads=Dist
+[  1] 0.00-1.00 sec  10.9 MBytes  91.2 Mbits/sec  1790=1790:0:0:0:0:0:0:0
+[  1] 1.00-2.00 sec  10.8 MBytes  90.6 Mbits/sec  1782=1782:0:0:0:0:0:0:0
+[  1] 0.00-2.01 sec  21.7 MBytes  90.5 Mbits/sec  3572=3572:0:0:0:0:0:0:0`
+
+func TestMergeUnidirResults_TCPServerGoodput(t *testing.T) {
+	client, err := ParseOutput(sampleTCPEnhancedOutput, false)
+	if err != nil {
+		t.Fatalf("parse client: %v", err)
+	}
+	server, err := ParseOutput(sampleTCPServerOutput, true)
+	if err != nil {
+		t.Fatalf("parse server: %v", err)
+	}
+
+	merged := MergeUnidirResults(client, server)
+
+	if merged.FwdReceivedBps != 90.5e6 {
+		t.Errorf("FwdReceivedBps = %f, want 90.5e6 (server-side goodput)", merged.FwdReceivedBps)
+	}
+	if got := merged.FwdActualMbps(); got != 90.5 {
+		t.Errorf("FwdActualMbps() = %f, want 90.5 (prefer receiver over sender)", got)
+	}
+	if merged.SentBps != client.SentBps {
+		t.Errorf("SentBps = %f, want client value %f", merged.SentBps, client.SentBps)
+	}
+	if merged.ReceivedBps != merged.FwdReceivedBps {
+		t.Errorf("ReceivedBps = %f, want %f", merged.ReceivedBps, merged.FwdReceivedBps)
+	}
+	if merged.FwdLostPackets != 0 || merged.FwdJitterMs != 0 {
+		t.Error("TCP merge should not invent UDP loss/jitter")
+	}
+}
+
 func TestParseSumLineServer(t *testing.T) {
 	line := "[SUM-2]  0.00-10.03 sec  9.77 MBytes  8.17 Mbits/sec   6.405 ms 4942/11909 (41%)"
 	p := parseSumLine(line)
@@ -525,6 +588,71 @@ func TestParseDualtestOutput_ForwardReverseInterleaved(t *testing.T) {
 	}
 }
 
+// UDP dualtest with two sending streams (1, 3) and one receiving stream (2).
+// Forward jitter only appears in the sending streams' Server Reports; the
+// reverse jitter comes from stream 2's receiver lines.
+const sampleDualtestUDPOutput = `------------------------------------------------------------
+Client connecting to 192.168.1.1, UDP port 5201
+------------------------------------------------------------
+[  1] local 192.168.1.2 port 40001 connected with 192.168.1.1 port 5201
+[  3] local 192.168.1.2 port 40002 connected with 192.168.1.1 port 5201
+------------------------------------------------------------
+Server listening on UDP port 5201
+------------------------------------------------------------
+[  2] local 192.168.1.2 port 5201 connected with 192.168.1.1 port 40003
+[  1]  0.00-1.00 sec  0.131 MBytes  1.05 Mbits/sec
+[  3]  0.00-1.00 sec  0.131 MBytes  1.05 Mbits/sec
+[  2]  0.00-1.00 sec  0.125 MBytes  1.00 Mbits/sec   3.500 ms    2/   90 (2.2%)
+[  1]  1.00-2.00 sec  0.131 MBytes  1.05 Mbits/sec
+[  3]  1.00-2.00 sec  0.131 MBytes  1.05 Mbits/sec
+[  2]  1.00-2.00 sec  0.125 MBytes  1.00 Mbits/sec   4.500 ms    4/   90 (4.4%)
+[  1]  0.00-2.00 sec  0.262 MBytes  1.05 Mbits/sec
+[  1] Sent 187 datagrams
+[  3]  0.00-2.00 sec  0.262 MBytes  1.05 Mbits/sec
+[  3] Sent 187 datagrams
+[  2]  0.00-2.00 sec  0.250 MBytes  1.00 Mbits/sec   4.000 ms    6/  180 (3.3%)
+[  1] Server Report:
+[  1]  0.00-2.00 sec  0.262 MBytes  1.05 Mbits/sec   0.250 ms    0/  187 (0%)
+[  3] Server Report:
+[  3]  0.00-2.00 sec  0.262 MBytes  1.05 Mbits/sec   0.250 ms    0/  187 (0%)`
+
+func TestParseDualtestOutput_UDPJitterSeparated(t *testing.T) {
+	result, err := ParseDualtestOutput(sampleDualtestUDPOutput)
+	if err != nil {
+		t.Fatalf("ParseDualtestOutput error: %v", err)
+	}
+
+	if len(result.Intervals) != 2 || len(result.ReverseIntervals) != 2 {
+		t.Fatalf("intervals = %d fwd / %d rev, want 2 / 2", len(result.Intervals), len(result.ReverseIntervals))
+	}
+	for i, iv := range result.Intervals {
+		if iv.JitterMs != 0 || iv.Packets != 0 {
+			t.Errorf("forward interval %d has jitter %.3f ms / %d packets, want none", i, iv.JitterMs, iv.Packets)
+		}
+		if len(iv.PerStream) != 2 {
+			t.Errorf("forward interval %d has %d streams, want 2", i, len(iv.PerStream))
+		}
+		if want := 2.1e6; math.Abs(iv.BandwidthBps-want) > 1 {
+			t.Errorf("forward interval %d bandwidth = %.0f, want %.0f", i, iv.BandwidthBps, want)
+		}
+	}
+	for i, want := range []float64{3.5, 4.5} {
+		iv := result.ReverseIntervals[i]
+		if iv.JitterMs != want {
+			t.Errorf("reverse interval %d jitter = %.3f ms, want %.3f", i, iv.JitterMs, want)
+		}
+		if iv.Packets != 90 {
+			t.Errorf("reverse interval %d packets = %d, want 90", i, iv.Packets)
+		}
+	}
+	if result.ReverseJitterMs != 4.0 {
+		t.Errorf("ReverseJitterMs = %.3f, want 4.000", result.ReverseJitterMs)
+	}
+	if result.ReversePackets != 180 {
+		t.Errorf("ReversePackets = %d, want 180", result.ReversePackets)
+	}
+}
+
 func TestParseOutput_ReverseServerOutput(t *testing.T) {
 	// Local server output for reverse direction
 	input := strings.Join([]string{
@@ -546,3 +674,349 @@ func TestParseOutput_ReverseServerOutput(t *testing.T) {
 		t.Error("expected lost packets in aggregated interval")
 	}
 }
+
+const sampleTCPEnhancedOutput = `------------------------------------------------------------
+Client connecting to 10.0.0.1, TCP port 5201 with pid 4242 (1 flows)
+Write buffer size: 131072 Byte
+TCP window size: 85.0 KByte (default)
+------------------------------------------------------------
+[  1] local 10.0.0.2 port 51514 connected with 10.0.0.1 port 5201 (sock=3) (icwnd/mss/irtt=14/1448/120) (ct=0.20 ms)
+[ ID] Interval        Transfer    Bandwidth       Write/Err  Rtry     Cwnd/RTT(var)        NetPwr
+[  1] 0.00-1.00 sec  11.2 MBytes  94.4 Mbits/sec  90/0         0      372K/3120(84) us  3783
+[  1] 0.00-2.00 sec  22.5 MBytes  94.4 Mbits/sec  180/0        0      372K/3120(84) us  3783`
+
+func TestParseOutput_SocketInfo(t *testing.T) {
+	result, err := ParseOutput(sampleTCPEnhancedOutput, false)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	if result.MSS != 1448 {
+		t.Errorf("MSS = %d, want 1448", result.MSS)
+	}
+	if result.SndBufBytes != 87040 {
+		t.Errorf("SndBufBytes = %d, want 87040", result.SndBufBytes)
+	}
+}
+
+func TestParseOutput_SocketInfoMByte(t *testing.T) {
+	result, err := ParseOutput(sampleTCPOutput, false)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	// 0.06 MByte = 62914.56 bytes
+	if result.SndBufBytes != 62915 {
+		t.Errorf("SndBufBytes = %d, want 62915", result.SndBufBytes)
+	}
+	if result.MSS != 0 {
+		t.Errorf("MSS = %d, want 0 when not reported", result.MSS)
+	}
+}
+
+func TestParseOutput_SocketInfoAbsentForUDP(t *testing.T) {
+	result, err := ParseOutput(sampleClientOutput, false)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	if result.MSS != 0 || result.SndBufBytes != 0 {
+		t.Errorf("UDP run: MSS = %d, SndBufBytes = %d, want both 0", result.MSS, result.SndBufBytes)
+	}
+}
+
+func TestParseOutput_SocketInfoIgnoredServerSide(t *testing.T) {
+	result, err := ParseOutput(sampleTCPEnhancedOutput, true)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	if result.SndBufBytes != 0 {
+		t.Errorf("server-side SndBufBytes = %d, want 0", result.SndBufBytes)
+	}
+}
+
+func TestParseOutput_PerStreamIntervals(t *testing.T) {
+	result, err := ParseOutput(sampleTCPOutput, false)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	if len(result.Intervals) != 2 {
+		t.Fatalf("expected 2 aggregate intervals, got %d", len(result.Intervals))
+	}
+
+	iv := result.Intervals[0]
+	if len(iv.PerStream) != 2 {
+		t.Fatalf("expected 2 per-stream rows, got %d", len(iv.PerStream))
+	}
+	// Aggregate path is unchanged: the sum of both streams
+	if math.Abs(iv.BandwidthBps-18_840_000) > 1 {
+		t.Errorf("aggregate BandwidthBps = %f, want 18840000", iv.BandwidthBps)
+	}
+	if iv.PerStream[0].StreamID != 1 || iv.PerStream[1].StreamID != 2 {
+		t.Errorf("stream IDs = %d, %d; want 1, 2", iv.PerStream[0].StreamID, iv.PerStream[1].StreamID)
+	}
+	if math.Abs(iv.PerStream[1].BandwidthBps-9_400_000) > 1 {
+		t.Errorf("stream 2 BandwidthBps = %f, want 9400000", iv.PerStream[1].BandwidthBps)
+	}
+}
+
+func TestParseOutput_SingleStreamNoPerStream(t *testing.T) {
+	single := `[  1]  0.00-1.00 sec  1.12 MBytes  9.44 Mbits/sec
+[  1]  1.00-2.00 sec  1.10 MBytes  9.23 Mbits/sec`
+	result, err := ParseOutput(single, false)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	if len(result.Intervals) != 2 {
+		t.Fatalf("expected 2 intervals, got %d", len(result.Intervals))
+	}
+	for i, iv := range result.Intervals {
+		if iv.PerStream != nil {
+			t.Errorf("interval %d: single-stream output should not populate PerStream", i)
+		}
+	}
+}
+
+func TestIntervalAggregator_PerStream(t *testing.T) {
+	var got []*model.IntervalResult
+	agg := NewIntervalAggregator(func(iv *model.IntervalResult) { got = append(got, iv) })
+	agg.Add(&model.IntervalResult{TimeStart: 0, TimeEnd: 1, BandwidthBps: 6e6, StreamID: 1})
+	agg.Add(&model.IntervalResult{TimeStart: 0, TimeEnd: 1, BandwidthBps: 2e6, StreamID: 2})
+	agg.Add(&model.IntervalResult{TimeStart: 1, TimeEnd: 2, BandwidthBps: 5e6, StreamID: 1})
+	agg.Flush()
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 emitted intervals, got %d", len(got))
+	}
+	if len(got[0].PerStream) != 2 || got[0].PerStream[1].BandwidthBps != 2e6 {
+		t.Errorf("first bucket PerStream = %+v", got[0].PerStream)
+	}
+	if got[1].PerStream != nil {
+		t.Error("bucket with one stream should leave PerStream nil")
+	}
+}
+
+func TestParseOutput_TestHeader(t *testing.T) {
+	result, err := ParseOutput(sampleTCPOutput, false)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	if result.ServerAddr != "100.89.230.34" || result.Port != 5201 || result.Protocol != "TCP" {
+		t.Errorf("header = %s:%d %s", result.ServerAddr, result.Port, result.Protocol)
+	}
+	if result.Direction != "Forward" {
+		t.Errorf("Direction = %q, want Forward", result.Direction)
+	}
+
+	srv, err := ParseOutput(sampleServerOutput, true)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	if srv.Protocol != "UDP" || srv.Direction != "" {
+		t.Errorf("server header: Protocol=%q Direction=%q", srv.Protocol, srv.Direction)
+	}
+}
+
+func TestParseTestHeader_Direction(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"reverse", "Client connecting to 10.0.0.1, TCP port 5001 with pid 42 (reverse)", "Reverse"},
+		{"full duplex", "Client connecting to 10.0.0.1, TCP port 5001 with pid 42 (full-duplex)", "Bidirectional"},
+		{"dualtest", "Server listening on TCP port 5001\nClient connecting to 10.0.0.1, TCP port 5001", "Bidirectional"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &model.TestResult{}
+			parseTestHeader(strings.Split(tt.header, "\n"), result)
+			if result.Direction != tt.want {
+				t.Errorf("Direction = %q, want %q", result.Direction, tt.want)
+			}
+			if result.Port != 5001 || result.Protocol != "TCP" {
+				t.Errorf("Port=%d Protocol=%q", result.Port, result.Protocol)
+			}
+		})
+	}
+}
+
+func TestParseTestHeader_ServerResolvedIP(t *testing.T) {
+	lines := strings.Split(`Client connecting to iperf.example.net, TCP port 5001
+Server listening on TCP port 5001
+[  2] local 10.0.0.2 port 5001 connected with 10.0.0.1 port 52801
+[  1] local 10.0.0.2 port 52800 connected with 10.0.0.1 port 5001`, "\n")
+	result := &model.TestResult{}
+	parseTestHeader(lines, result)
+	if result.ServerAddr != "iperf.example.net" {
+		t.Errorf("ServerAddr = %q", result.ServerAddr)
+	}
+	// The incoming dualtest connection is ignored; only the dialled peer counts.
+	if result.ServerResolvedIP != "10.0.0.1" {
+		t.Errorf("ServerResolvedIP = %q, want 10.0.0.1", result.ServerResolvedIP)
+	}
+
+	srv := &model.TestResult{}
+	parseTestHeader(lines[1:3], srv)
+	if srv.ServerResolvedIP != "" {
+		t.Errorf("server-only output should not set ServerResolvedIP, got %q", srv.ServerResolvedIP)
+	}
+}
+
+const sampleTCPEnhancedTwoStreams = `------------------------------------------------------------
+Client connecting to 10.0.0.1, TCP port 5201 with pid 4242 (2 flows)
+TCP window size: 85.0 KByte (default)
+------------------------------------------------------------
+[  1] local 10.0.0.2 port 51514 connected with 10.0.0.1 port 5201 (sock=3) (icwnd/mss/irtt=14/1448/120)
+[  2] local 10.0.0.2 port 51516 connected with 10.0.0.1 port 5202 (sock=4) (icwnd/mss/irtt=14/1448/130)
+[ ID] Interval        Transfer    Bandwidth       Write/Err  Rtry     Cwnd/RTT(var)        NetPwr
+[  1] 0.00-1.00 sec  11.2 MBytes  94.4 Mbits/sec  90/0         2      372K/3120(84) us  3783
+[  2] 0.00-1.00 sec  5.62 MBytes  47.2 Mbits/sec  45/0         9      128K/9800(410) us  602
+[  1] 1.00-2.00 sec  11.2 MBytes  94.4 Mbits/sec  90/0         1      410K/1200(40) us  9833
+[  2] 1.00-2.00 sec  5.62 MBytes  47.2 Mbits/sec  45/0         4      96K/12400(900) us  476
+[  1] 0.00-2.00 sec  22.5 MBytes  94.4 Mbits/sec  180/0        3      410K/1200(40) us  9833
+[  2] 0.00-2.00 sec  11.2 MBytes  47.2 Mbits/sec  90/0        13      96K/12400(900) us  476
+[SUM] 0.00-2.00 sec  33.8 MBytes   142 Mbits/sec  270/0       16`
+
+func TestParseOutput_TCPStreamRTT(t *testing.T) {
+	result, err := ParseOutput(sampleTCPEnhancedTwoStreams, false)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	if len(result.Streams) != 2 {
+		t.Fatalf("got %d streams, want 2", len(result.Streams))
+	}
+	s1, s2 := result.Streams[0], result.Streams[1]
+	if s1.ID != 1 || s1.Retransmits != 3 || s1.SentBps != 94.4e6 || !s1.Sender {
+		t.Errorf("stream 1 = %+v", s1)
+	}
+	if s1.MinRTTUs != 1200 || s1.MeanRTTUs != 2160 || s1.MaxRTTUs != 3120 || s1.MaxCwndBytes != 410*1024 {
+		t.Errorf("stream 1 RTT/cwnd = %d/%d/%d us, %d bytes", s1.MinRTTUs, s1.MeanRTTUs, s1.MaxRTTUs, s1.MaxCwndBytes)
+	}
+	if s2.MinRTTUs != 9800 || s2.MaxRTTUs != 12400 || s2.MaxCwndBytes != 128*1024 || s2.Retransmits != 13 {
+		t.Errorf("stream 2 = %+v", s2)
+	}
+	if result.Retransmits != 16 {
+		t.Errorf("Retransmits = %d, want 16 (sum of streams)", result.Retransmits)
+	}
+	if sentOK, recvOK := result.VerifyStreamTotals(); !sentOK || !recvOK {
+		t.Errorf("VerifyStreamTotals() = %v, %v; rounding in the SUM line should pass", sentOK, recvOK)
+	}
+}
+
+func TestParseOutput_TCPStreamRTT_NA(t *testing.T) {
+	out := `Client connecting to 10.0.0.1, TCP port 5201
+[  1] 0.00-3.35 sec  28.5 MBytes  71.4 Mbits/sec  229/0          0       NA/98000(49)us    91.10`
+	result, err := ParseOutput(out, false)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	if len(result.Streams) != 1 {
+		t.Fatalf("got %d streams, want 1", len(result.Streams))
+	}
+	if s := result.Streams[0]; s.MaxCwndBytes != 0 || s.MinRTTUs != 98000 || s.MeanRTTUs != 98000 {
+		t.Errorf("stream = %+v, want NA cwnd and a single 98000 us sample", s)
+	}
+}
+
+func TestParseOutput_NoStreamsWithoutTCPInfo(t *testing.T) {
+	result, err := ParseOutput(sampleTCPOutput, false)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	if len(result.Streams) != 0 {
+		t.Errorf("plain client output should not produce streams, got %d", len(result.Streams))
+	}
+}
+
+func TestMergeBidirResults_DirectionDurations(t *testing.T) {
+	ivs := func(n int, omit int) []model.IntervalResult {
+		out := make([]model.IntervalResult, n)
+		for i := range out {
+			out[i] = model.IntervalResult{TimeStart: float64(i), TimeEnd: float64(i + 1), Omitted: i < omit}
+		}
+		return out
+	}
+	fwdClient := &model.TestResult{Intervals: ivs(10, 0), ActualDuration: 10}
+	revServer := &model.TestResult{Intervals: ivs(6, 2)} // reverse stalled after 6 s
+
+	merged := MergeBidirResults(fwdClient, nil, nil, revServer)
+	if merged.ActualDuration != 10 {
+		t.Errorf("ActualDuration = %v, want 10", merged.ActualDuration)
+	}
+	if merged.ReverseActualDuration != 6 {
+		t.Errorf("ReverseActualDuration = %v, want 6", merged.ReverseActualDuration)
+	}
+
+	allOmitted := MergeBidirResults(fwdClient, nil, nil, &model.TestResult{Intervals: ivs(2, 2)})
+	if allOmitted.ReverseActualDuration != 0 {
+		t.Errorf("ReverseActualDuration with only omitted intervals = %v, want 0", allOmitted.ReverseActualDuration)
+	}
+}
+
+func TestAddReverseStreams_BidirUDP(t *testing.T) {
+	// Local server output for the reverse direction of a -P 2 bidir UDP test
+	const revServerOutput = `------------------------------------------------------------
+Server listening on UDP port 5201
+UDP buffer size:  208 KByte (default)
+------------------------------------------------------------
+[  3] local 10.0.0.2 port 5201 connected with 10.0.0.1 port 40001
+[  4] local 10.0.0.2 port 5201 connected with 10.0.0.1 port 40002
+[ ID] Interval            Transfer     Bandwidth        Jitter   Lost/Total  Latency avg/min/max/stdev PPS
+[  3]  0.00-1.00 sec  0.600 MBytes  5.03 Mbits/sec   0.512 ms   10/  438 (2.3%)  0.500/ 0.100/ 1.000/ 0.200 ms  428 pps
+[  4]  0.00-1.00 sec  0.400 MBytes  3.36 Mbits/sec   1.204 ms  150/  435 (34%)  0.700/ 0.100/ 2.000/ 0.400 ms  285 pps
+[  3]  0.00-10.00 sec  6.00 MBytes  5.03 Mbits/sec   0.498 ms  100/ 4380 (2.3%)
+[  4]  0.00-10.00 sec  4.00 MBytes  3.36 Mbits/sec   1.180 ms 1500/ 4350 (34%)
+[SUM-2]  0.00-10.00 sec  10.0 MBytes  8.39 Mbits/sec   0.839 ms 1600/ 8730 (18%)`
+
+	streams := receiverStreams(revServerOutput)
+	if len(streams) != 2 {
+		t.Fatalf("receiverStreams() = %d streams, want 2", len(streams))
+	}
+
+	fwd := model.StreamResult{ID: 1, SentBps: 5e6, Sender: true}
+	result := &model.TestResult{Direction: "Bidirectional", Streams: []model.StreamResult{fwd, {ID: 2, SentBps: 5e6, Sender: true}}}
+	addReverseStreams(result, &model.TestResult{}, streams)
+
+	if len(result.Streams) != 4 {
+		t.Fatalf("got %d streams, want 2 forward + 2 reverse", len(result.Streams))
+	}
+	if result.Streams[0] != fwd {
+		t.Errorf("forward stream changed: %+v", result.Streams[0])
+	}
+	rev := result.Streams[2:]
+	if rev[0].Sender || rev[1].Sender {
+		t.Error("reverse streams should have Sender=false")
+	}
+	if rev[0].ID != 3 || rev[0].LostPackets != 100 || rev[0].Packets != 4380 || rev[0].JitterMs != 0.498 {
+		t.Errorf("reverse stream 3 = %+v, want 100/4380 lost, 0.498 ms jitter", rev[0])
+	}
+	if rev[1].ID != 4 || rev[1].LostPackets != 1500 || rev[1].LostPercent != 34 || rev[1].JitterMs != 1.18 {
+		t.Errorf("reverse stream 4 = %+v, want 1500 lost (34%%), 1.18 ms jitter", rev[1])
+	}
+	if rev[1].ReceivedBps != 3.36e6 || rev[1].SentBps != 3.36e6 {
+		t.Errorf("reverse stream 4 rate = %v sent / %v received, want 3.36e6 both", rev[1].SentBps, rev[1].ReceivedBps)
+	}
+}
+
+func TestAddReverseStreams_ClientSendData(t *testing.T) {
+	revServer := []model.StreamResult{{ID: 3, ReceivedBps: 400e6}, {ID: 4, ReceivedBps: 300e6}}
+	revClient := &model.TestResult{Streams: []model.StreamResult{
+		{ID: 1, SentBps: 410e6, Retransmits: 2, Sender: true},
+		{ID: 2, SentBps: 320e6, Retransmits: 7, MaxRTTUs: 900, Sender: true},
+	}}
+	result := &model.TestResult{}
+	addReverseStreams(result, revClient, revServer)
+
+	if len(result.Streams) != 2 {
+		t.Fatalf("got %d streams, want 2", len(result.Streams))
+	}
+	// Matched by position: server stream 4 pairs with client stream 2
+	s := result.Streams[1]
+	if s.ID != 4 || s.SentBps != 320e6 || s.ReceivedBps != 300e6 || s.Retransmits != 7 || s.MaxRTTUs != 900 || s.Sender {
+		t.Errorf("reverse stream = %+v", s)
+	}
+
+	single := &model.TestResult{}
+	addReverseStreams(single, revClient, revServer[:1])
+	if len(single.Streams) != 0 {
+		t.Errorf("single reverse stream added %d entries, want none", len(single.Streams))
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"iperf-tool/internal/model"
 )
 
+// DebugLogPath is the default raw-output log used by NewDebugRunner.
 var DebugLogPath = filepath.Join(os.TempDir(), "iperf-debug.log")
 
 // SSHClient is the interface for executing commands on a remote host.
@@ -47,7 +48,10 @@ type Runner struct {
 	fwdCmd      *exec.Cmd
 	stopped     bool
 	debug       bool
-	onStatus    StatusCallback // optional callback for status/log messages
+	debugPath   string
+	onStatus    StatusCallback   // optional callback for status/log messages
+	now         func() time.Time // wall clock for StartedAt/FinishedAt; nil = time.Now
+	warnings    []string         // local client stderr lines from the current run
 }
 
 // StatusCallback is a function that receives status/log messages from the runner.
@@ -59,6 +63,20 @@ func NewRunner() *Runner {
 	return &Runner{}
 }
 
+// useServerOutput points RemoteOutputFile at the default server log unless
+// one is set. With NoServerOutput it instead clears the log and the SSH
+// fallback that depends on it.
+func (c *Config) useServerOutput() {
+	if c.NoServerOutput {
+		c.RemoteOutputFile = ""
+		c.SSHFallback = false
+		return
+	}
+	if c.RemoteOutputFile == "" {
+		c.RemoteOutputFile = defaultRemoteOutputFile(c.IsWindows)
+	}
+}
+
 // defaultRemoteOutputFile returns a sensible default path for the remote server
 // output file based on the remote OS.
 func defaultRemoteOutputFile(isWindows bool) string {
@@ -82,7 +100,43 @@ func (r *Runner) logStatus(onInterval func(fwd, rev *model.IntervalResult), form
 
 // NewDebugRunner creates a Runner that logs raw output to DebugLogPath.
 func NewDebugRunner() *Runner {
-	return &Runner{debug: true}
+	return NewDebugRunnerWithPath(DebugLogPath)
+}
+
+// NewDebugRunnerWithPath creates a Runner that logs raw output to path.
+// An empty path falls back to DebugLogPath.
+func NewDebugRunnerWithPath(path string) *Runner {
+	if path == "" {
+		path = DebugLogPath
+	}
+	return &Runner{debug: true, debugPath: path}
+}
+
+// clock returns the current wall-clock time.
+func (r *Runner) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// timed runs a test and stamps the result with the wall-clock time either
+// side of it, so setup and teardown show up in Elapsed.
+func (r *Runner) timed(run func() (*model.TestResult, error)) (*model.TestResult, error) {
+	r.mu.Lock()
+	r.warnings = nil
+	r.mu.Unlock()
+
+	started := r.clock()
+	result, err := run()
+	if result != nil {
+		result.StartedAt = started
+		result.FinishedAt = r.clock()
+		r.mu.Lock()
+		result.Warnings = r.warnings
+		r.mu.Unlock()
+	}
+	return result, err
 }
 
 // SetStatusCallback sets a callback for status/log messages (probe results,
@@ -97,7 +151,7 @@ type nopWriteCloser struct{ io.Writer }
 
 func (nopWriteCloser) Close() error { return nil }
 
-// debugWriter opens or creates DebugLogPath in append mode and writes a
+// debugWriter opens or creates the runner's debug log in append mode and writes a
 // timestamped header line. Caller must close the returned WriteCloser.
 func (r *Runner) debugWriter(label string, args []string) (io.WriteCloser, func(string, ...any)) {
 	nop := nopWriteCloser{io.Discard}
@@ -105,9 +159,9 @@ func (r *Runner) debugWriter(label string, args []string) (io.WriteCloser, func(
 	if !r.debug {
 		return nop, nopf
 	}
-	f, err := os.OpenFile(DebugLogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	f, err := os.OpenFile(r.debugPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "iperf debug: cannot open log %s: %v\n", DebugLogPath, err)
+		fmt.Fprintf(os.Stderr, "iperf debug: cannot open log %s: %v\n", r.debugPath, err)
 		return nop, nopf
 	}
 	fmt.Fprintf(f, "\n=== %s %s ===\nargs: %v\n", time.Now().Format("2006-01-02 15:04:05"), label, args)
@@ -128,6 +182,20 @@ func (r *Runner) Stop() {
 	}
 }
 
+// cancelGrace is how long a cancelled iperf process gets to print its final
+// report after SIGTERM before it is killed outright.
+const cancelGrace = 3 * time.Second
+
+// terminateOnCancel makes cmd receive the same signal as Stop() when its
+// context is cancelled, instead of exec's default SIGKILL, so iperf still
+// prints the summary for the data transferred so far.
+func terminateOnCancel(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		return stopProcess(cmd.Process)
+	}
+	cmd.WaitDelay = cancelGrace
+}
+
 var versionRegex = regexp.MustCompile(`iperf\s+version\s+([\d.]+)`)
 
 // CheckVersion runs iperf --version and returns the version string.
@@ -136,22 +204,31 @@ func CheckVersion(binaryPath string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("run iperf --version: %w", err)
 	}
-	matches := versionRegex.FindSubmatch(out)
-	if len(matches) < 2 {
-		return "", fmt.Errorf("could not parse iperf version from: %s", strings.TrimSpace(string(out)))
-	}
-	return string(matches[1]), nil
+	return ParseVersion(string(out))
+}
+
+// SupportsCongestionControl reports whether the local iperf2 lists
+// -Z/--tcp-congestion in its --help output. Some releases exit non-zero
+// after printing help, so the output is checked regardless of the error.
+func SupportsCongestionControl(binaryPath string) bool {
+	out, _ := exec.Command(binaryPath, "--help").CombinedOutput()
+	return strings.Contains(string(out), "--tcp-congestion")
 }
 
 // RunForward runs a forward-only test (local client → remote server).
 // sshCli may be nil for local-only tests (server must already be running).
 func (r *Runner) RunForward(ctx context.Context, cfg Config, sshCli SSHClient, onInterval func(fwd, rev *model.IntervalResult)) (*model.TestResult, error) {
+	return r.timed(func() (*model.TestResult, error) { return r.runForward(ctx, cfg, sshCli, onInterval) })
+}
+
+func (r *Runner) runForward(ctx context.Context, cfg Config, sshCli SSHClient, onInterval func(fwd, rev *model.IntervalResult)) (*model.TestResult, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
-	// For UDP forward tests with SSH, probe reachability to decide direct vs fallback.
-	if sshCli != nil && cfg.Protocol == "udp" && !cfg.SkipProbe {
+	// For UDP forward tests with SSH, probe reachability to decide direct vs
+	// fallback. Without a server log there is nothing to fall back to.
+	if sshCli != nil && cfg.Protocol == "udp" && !cfg.SkipProbe && !cfg.NoServerOutput {
 		localAddr := cfg.LocalAddr
 		if localAddr == "" {
 			if lap, ok := sshCli.(LocalAddrProvider); ok {
@@ -159,7 +236,7 @@ func (r *Runner) RunForward(ctx context.Context, cfg Config, sshCli SSHClient, o
 			}
 		}
 		if localAddr != "" {
-			isOpen, err := ProbeUDPReachability(ctx, sshCli, localAddr, cfg.ProbeTimeout, cfg.IsWindows, cfg.IPv6)
+			isOpen, err := ProbeUDPReachability(ctx, sshCli, localAddr, cfg.ProbeTimeout, cfg.IsWindows, cfg.useIPv6())
 			if err != nil {
 				r.logStatus(onInterval, "iperf probe warning: %v — using SSH fallback", err)
 				cfg.SSHFallback = true
@@ -176,8 +253,12 @@ func (r *Runner) RunForward(ctx context.Context, cfg Config, sshCli SSHClient, o
 	} else if sshCli != nil && cfg.Protocol == "udp" && cfg.SkipProbe {
 		// Probe skipped: honour whatever SSHFallback the caller set.
 	}
-	if sshCli != nil && cfg.Protocol == "udp" && cfg.RemoteOutputFile == "" {
-		cfg.RemoteOutputFile = defaultRemoteOutputFile(cfg.IsWindows)
+	// The remote server always logs to a file when SSH is available: for UDP
+	// it is the fallback for a missing Server Report, and for TCP (where
+	// iperf2 sends no Server Report at all) it is the only source of the
+	// receiver-side goodput.
+	if sshCli != nil {
+		cfg.useServerOutput()
 	}
 
 	// Start remote server if SSH is available
@@ -190,7 +271,8 @@ func (r *Runner) RunForward(ctx context.Context, cfg Config, sshCli SSHClient, o
 
 	// Run local client
 	clientArgs := cfg.fwdClientArgs()
-	clientOutput, err := r.runLocalClient(ctx, cfg.BinaryPath, clientArgs, onInterval)
+	clientBuf := cfg.clientOutput()
+	clientOutput, err := r.runLocalClient(ctx, cfg.BinaryPath, clientArgs, onInterval, clientBuf)
 	if err != nil {
 		return nil, fmt.Errorf("forward client: %w", err)
 	}
@@ -198,20 +280,29 @@ func (r *Runner) RunForward(ctx context.Context, cfg Config, sshCli SSHClient, o
 	// Parse client output
 	clientResult, err := ParseOutput(clientOutput, false)
 	if err != nil {
+		if line := connectFailure(clientOutput); line != "" {
+			return nil, fmt.Errorf("forward client: %s", line)
+		}
 		return nil, fmt.Errorf("parse client output: %w", err)
 	}
+	clientBuf.applyTCPInfo(clientResult.Streams)
 
 	// Get server-side data: try Server Report first, fall back to SSH file read
 	var serverResult *model.TestResult
+	var serverText string
 	if sshCli != nil && cfg.SSHFallback {
 		serverOutput, readErr := r.readRemoteServerOutput(cfg, sshCli)
 		if readErr == nil && strings.TrimSpace(serverOutput) != "" {
 			serverResult, _ = ParseOutput(serverOutput, true)
+			serverText = serverOutput
 		}
 	} else {
 		status := ValidateServerReport(clientOutput)
 		if status == ServerReportValid {
 			serverResult, _ = parseServerReportFromClient(clientOutput)
+			if serverResult != nil {
+				serverText = serverReportText(clientOutput)
+			}
 		} else if status == ServerReportFabricated {
 			clientResult.FabricatedServerReport = true
 		}
@@ -221,19 +312,26 @@ func (r *Runner) RunForward(ctx context.Context, cfg Config, sshCli SSHClient, o
 			if readErr == nil && strings.TrimSpace(serverOutput) != "" {
 				serverResult, _ = ParseOutput(serverOutput, true)
 				clientResult.FabricatedServerReport = false
+				serverText = serverOutput
 			}
 		}
 	}
 
+	result := clientResult
 	if serverResult != nil {
-		return MergeUnidirResults(clientResult, serverResult), nil
+		result = MergeUnidirResults(clientResult, serverResult)
 	}
-	return clientResult, nil
+	cfg.keepServerOutput(result, serverText)
+	return result, nil
 }
 
 // RunReverse runs a reverse-only test (remote client → local server).
 // sshCli is required for reverse tests.
 func (r *Runner) RunReverse(ctx context.Context, cfg Config, sshCli SSHClient, onInterval func(fwd, rev *model.IntervalResult)) (*model.TestResult, error) {
+	return r.timed(func() (*model.TestResult, error) { return r.runReverse(ctx, cfg, sshCli, onInterval) })
+}
+
+func (r *Runner) runReverse(ctx context.Context, cfg Config, sshCli SSHClient, onInterval func(fwd, rev *model.IntervalResult)) (*model.TestResult, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
@@ -249,7 +347,7 @@ func (r *Runner) RunReverse(ctx context.Context, cfg Config, sshCli SSHClient, o
 			}
 		}
 		if localAddr != "" {
-			isOpen, err := ProbeUDPReachability(ctx, sshCli, localAddr, cfg.ProbeTimeout, cfg.IsWindows, cfg.IPv6)
+			isOpen, err := ProbeUDPReachability(ctx, sshCli, localAddr, cfg.ProbeTimeout, cfg.IsWindows, cfg.useIPv6())
 			if err != nil {
 				r.logStatus(onInterval, "iperf probe warning: %v", err)
 			} else if !isOpen {
@@ -266,6 +364,7 @@ func (r *Runner) RunReverse(ctx context.Context, cfg Config, sshCli SSHClient, o
 	localSrvArgs := cfg.revServerArgs()
 	var localSrvBuf bytes.Buffer
 	localSrvCmd := exec.CommandContext(ctx, cfg.BinaryPath, localSrvArgs...)
+	terminateOnCancel(localSrvCmd)
 	localSrvCmd.Stdout = &localSrvBuf
 	localSrvCmd.Stderr = &localSrvBuf
 
@@ -332,6 +431,7 @@ func (r *Runner) RunReverse(ctx context.Context, cfg Config, sshCli SSHClient, o
 	if revClientResult != nil {
 		result := MergeUnidirResults(revClientResult, localSrvResult)
 		result.Direction = "Reverse"
+		cfg.keepServerOutput(result, localSrvOutput)
 		// Replay intervals only if we didn't stream them live
 		if onInterval != nil && !revStreamed {
 			for i := range localSrvResult.Intervals {
@@ -342,6 +442,7 @@ func (r *Runner) RunReverse(ctx context.Context, cfg Config, sshCli SSHClient, o
 	}
 
 	localSrvResult.Direction = "Reverse"
+	cfg.keepServerOutput(localSrvResult, localSrvOutput)
 	if onInterval != nil && !revStreamed {
 		for i := range localSrvResult.Intervals {
 			onInterval(&localSrvResult.Intervals[i], nil)
@@ -353,6 +454,10 @@ func (r *Runner) RunReverse(ctx context.Context, cfg Config, sshCli SSHClient, o
 // RunBidir runs a bidirectional test — both directions simultaneously.
 // sshCli is required for bidirectional tests.
 func (r *Runner) RunBidir(ctx context.Context, cfg Config, sshCli SSHClient, onInterval func(fwd, rev *model.IntervalResult)) (*model.TestResult, error) {
+	return r.timed(func() (*model.TestResult, error) { return r.runBidir(ctx, cfg, sshCli, onInterval) })
+}
+
+func (r *Runner) runBidir(ctx context.Context, cfg Config, sshCli SSHClient, onInterval func(fwd, rev *model.IntervalResult)) (*model.TestResult, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
@@ -372,7 +477,7 @@ func (r *Runner) RunBidir(ctx context.Context, cfg Config, sshCli SSHClient, onI
 			}
 		}
 		if localAddr != "" {
-			isOpen, err := ProbeUDPReachability(ctx, sshCli, localAddr, cfg.ProbeTimeout, cfg.IsWindows, cfg.IPv6)
+			isOpen, err := ProbeUDPReachability(ctx, sshCli, localAddr, cfg.ProbeTimeout, cfg.IsWindows, cfg.useIPv6())
 			if err != nil {
 				r.logStatus(onInterval, "iperf probe warning: %v — using SSH fallback", err)
 				cfg.SSHFallback = true
@@ -390,8 +495,8 @@ func (r *Runner) RunBidir(ctx context.Context, cfg Config, sshCli SSHClient, onI
 	} else if cfg.Protocol == "udp" && cfg.SkipProbe {
 		// Probe skipped: honour whatever SSHFallback the caller set (default false).
 	}
-	if cfg.Protocol == "udp" && cfg.RemoteOutputFile == "" {
-		cfg.RemoteOutputFile = defaultRemoteOutputFile(cfg.IsWindows)
+	if cfg.Protocol == "udp" || cfg.NoServerOutput {
+		cfg.useServerOutput()
 	}
 
 	// Kill any leftover remote iperf
@@ -408,6 +513,7 @@ func (r *Runner) RunBidir(ctx context.Context, cfg Config, sshCli SSHClient, onI
 	localSrvArgs := cfg.revServerArgs()
 	var localSrvBuf bytes.Buffer
 	localSrvCmd := exec.CommandContext(ctx, cfg.BinaryPath, localSrvArgs...)
+	terminateOnCancel(localSrvCmd)
 	localSrvCmd.Stdout = &localSrvBuf
 	localSrvCmd.Stderr = &localSrvBuf
 
@@ -450,9 +556,10 @@ func (r *Runner) RunBidir(ctx context.Context, cfg Config, sshCli SSHClient, onI
 	}
 
 	// Forward: local client → remote server (streamed)
+	fwdBuf := cfg.clientOutput()
 	go func() {
 		clientArgs := cfg.fwdClientArgs()
-		out, err := r.runLocalClient(ctx, cfg.BinaryPath, clientArgs, fwdStream)
+		out, err := r.runLocalClient(ctx, cfg.BinaryPath, clientArgs, fwdStream, fwdBuf)
 		fwdCh <- cmdResult{out, err}
 	}()
 
@@ -502,6 +609,7 @@ func (r *Runner) RunBidir(ctx context.Context, cfg Config, sshCli SSHClient, onI
 	if fwdClientResult == nil {
 		fwdClientResult = &model.TestResult{Timestamp: time.Now()}
 	}
+	fwdBuf.applyTCPInfo(fwdClientResult.Streams)
 
 	if fwdOut.err == nil && !cfg.SSHFallback {
 		// Try direct mode first: get server data from client's Server Report
@@ -525,6 +633,8 @@ func (r *Runner) RunBidir(ctx context.Context, cfg Config, sshCli SSHClient, onI
 
 	// Merge all results
 	merged := MergeBidirResults(fwdClientResult, fwdServerResult, revClientResult, revServerResult)
+	addReverseStreams(merged, revClientResult, receiverStreams(localSrvOutput))
+	cfg.keepServerOutput(merged, labelledServerOutput(remoteSrvOutput, localSrvOutput))
 
 	// Fire onInterval callbacks (post-test replay) only for any direction that
 	// wasn't streamed live. Forward is always streamed via runLocalClient; the
@@ -543,6 +653,10 @@ func (r *Runner) RunBidir(ctx context.Context, cfg Config, sshCli SSHClient, onI
 // flag. No SSH connection is needed — the remote server connects back to the
 // local client. Both directions run simultaneously in a single process.
 func (r *Runner) RunBidirDualtest(ctx context.Context, cfg Config, onInterval func(fwd, rev *model.IntervalResult)) (*model.TestResult, error) {
+	return r.timed(func() (*model.TestResult, error) { return r.runBidirDualtest(ctx, cfg, onInterval) })
+}
+
+func (r *Runner) runBidirDualtest(ctx context.Context, cfg Config, onInterval func(fwd, rev *model.IntervalResult)) (*model.TestResult, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
@@ -550,7 +664,7 @@ func (r *Runner) RunBidirDualtest(ctx context.Context, cfg Config, onInterval fu
 	r.logStatus(onInterval, "Starting iperf2 bidirectional dualtest (-d) — server must be able to reach this client (no NAT)")
 
 	clientArgs := cfg.dualtestClientArgs()
-	clientOutput, err := r.runLocalClient(ctx, cfg.BinaryPath, clientArgs, nil)
+	clientOutput, err := r.runLocalClient(ctx, cfg.BinaryPath, clientArgs, nil, nil)
 	if err != nil {
 		// Check for common NAT-blocked error patterns
 		if strings.Contains(err.Error(), "connect failed") ||
@@ -574,6 +688,9 @@ func (r *Runner) RunBidirDualtest(ctx context.Context, cfg Config, onInterval fu
 	// Parse dualtest output — iperf2 -d interleaves forward and reverse streams
 	result, err := ParseDualtestOutput(clientOutput)
 	if err != nil {
+		if line := connectFailure(clientOutput); line != "" {
+			return nil, fmt.Errorf("dualtest client: %s", line)
+		}
 		return nil, fmt.Errorf("parse dualtest output: %w", err)
 	}
 
@@ -619,6 +736,27 @@ func (r *Runner) killRemoteServer(cfg Config, sshCli SSHClient) {
 	sshCli.RunCommand(cfg.remoteServerKillCmd())
 }
 
+// keepServerOutput stores the raw server-side text on result when
+// KeepServerOutput is set.
+func (c *Config) keepServerOutput(result *model.TestResult, text string) {
+	if c.KeepServerOutput && result != nil {
+		result.ServerOutputText = strings.TrimRight(text, "\n")
+	}
+}
+
+// labelledServerOutput joins the two server outputs of an SSH bidir test,
+// each under a header naming the side it came from.
+func labelledServerOutput(remote, local string) string {
+	var parts []string
+	if strings.TrimSpace(remote) != "" {
+		parts = append(parts, "[remote server: client to server]\n"+strings.TrimRight(remote, "\n"))
+	}
+	if strings.TrimSpace(local) != "" {
+		parts = append(parts, "[local server: server to client]\n"+strings.TrimRight(local, "\n"))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
 // readRemoteServerOutput reads the server output file via SSH.
 func (r *Runner) readRemoteServerOutput(cfg Config, sshCli SSHClient) (string, error) {
 	sshCli.RunCommand(cfg.remoteServerKillCmd()) // graceful kill first
@@ -627,12 +765,14 @@ func (r *Runner) readRemoteServerOutput(cfg Config, sshCli SSHClient) (string, e
 }
 
 // runLocalClient executes a local iperf client command, piping output line by line.
-// Returns the full output text.
-func (r *Runner) runLocalClient(ctx context.Context, binaryPath string, args []string, onInterval func(fwd, rev *model.IntervalResult)) (string, error) {
+// Returns the full output text, or with a non-nil out (and an onInterval to
+// carry them) the text without its interval lines, see summaryOutput.
+func (r *Runner) runLocalClient(ctx context.Context, binaryPath string, args []string, onInterval func(fwd, rev *model.IntervalResult), out *summaryOutput) (string, error) {
 	dbg, logf := r.debugWriter("client", args)
 	defer dbg.Close()
 
 	cmd := exec.CommandContext(ctx, binaryPath, args...)
+	terminateOnCancel(cmd)
 	r.mu.Lock()
 	r.fwdCmd = cmd
 	r.stopped = false
@@ -655,7 +795,10 @@ func (r *Runner) runLocalClient(ctx context.Context, binaryPath string, args []s
 		return "", fmt.Errorf("start iperf: %w", err)
 	}
 
-	var buf bytes.Buffer
+	buf := out
+	if buf == nil || onInterval == nil {
+		buf = &summaryOutput{all: true}
+	}
 	scanner := bufio.NewScanner(stdout)
 	var agg *IntervalAggregator
 	if onInterval != nil {
@@ -665,7 +808,7 @@ func (r *Runner) runLocalClient(ctx context.Context, binaryPath string, args []s
 	}
 	for scanner.Scan() {
 		line := scanner.Text()
-		buf.WriteString(line + "\n")
+		buf.add(line)
 		logf("%s\n", line)
 
 		if agg != nil {
@@ -677,6 +820,7 @@ func (r *Runner) runLocalClient(ctx context.Context, binaryPath string, args []s
 	if agg != nil {
 		agg.Flush()
 	}
+	buf.flush()
 
 	waitErr := cmd.Wait()
 
@@ -684,16 +828,219 @@ func (r *Runner) runLocalClient(ctx context.Context, binaryPath string, args []s
 	userStopped := r.stopped
 	r.mu.Unlock()
 
-	if waitErr != nil && !userStopped && buf.Len() == 0 {
-		return "", fmt.Errorf("iperf failed: %w: %s", waitErr, strings.TrimSpace(stderr.String()))
+	// A cancelled context is handled like Stop(): whatever iperf printed
+	// before exiting is kept as a partial result.
+	if waitErr != nil && buf.empty() {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("iperf cancelled: %w", ctx.Err())
+		}
+		if !userStopped {
+			return "", fmt.Errorf("iperf failed: %w: %s", waitErr, strings.TrimSpace(stderr.String()))
+		}
 	}
 
 	// Append stderr to output so callers can see ERROR/WARNING lines
 	if stderrStr := stderr.String(); strings.TrimSpace(stderrStr) != "" {
-		buf.WriteString(stderrStr)
+		buf.buf.WriteString(stderrStr)
+		r.recordWarnings(stderrStr)
+	}
+
+	return buf.buf.String(), nil
+}
+
+// summaryOutput collects a local client's output. With all set it keeps
+// every line. Otherwise interval lines, already delivered to onInterval,
+// are dropped so a long run does not hold its whole log: per stream only
+// the latest one is held back, for a run cut short before its summary, and
+// is discarded once the closing 0.0-N line arrives. Other lines and the
+// Server Report section are kept as is. The -e sender RTT and cwnd of the
+// dropped lines are folded into tcp, for applyTCPInfo.
+type summaryOutput struct {
+	all      bool
+	buf      bytes.Buffer
+	held     map[int]heldInterval // latest interval per stream; -1 = [SUM]
+	order    []int                // streams in arrival order, for flush
+	inReport bool
+	tcp      map[int]*streamTCPInfo
+}
+
+// streamTCPInfo accumulates one stream's -e sender RTT and cwnd samples.
+type streamTCPInfo struct {
+	minRTT, maxRTT, sumRTT, n int
+	maxCwnd                   int64
+}
+
+// clientOutput returns the summary-only collector for StreamIntervals runs,
+// or nil to keep the full client output.
+func (c *Config) clientOutput() *summaryOutput {
+	if !c.StreamIntervals {
+		return nil
+	}
+	return &summaryOutput{}
+}
+
+type heldInterval struct {
+	start float64
+	line  string
+}
+
+// add appends one output line.
+func (o *summaryOutput) add(line string) {
+	if o.all || o.inReport {
+		o.write(line)
+		return
+	}
+	if reServerReport.MatchString(line) {
+		o.flush()
+		o.inReport = true
+		o.write(line)
+		return
+	}
+	p, key := parseOutputInterval(line)
+	if p == nil {
+		o.write(line)
+		return
+	}
+	if o.held == nil {
+		o.held = map[int]heldInterval{}
 	}
+	prev, ok := o.held[key]
+	if ok && p.timeStart < prev.start {
+		// Back at the start: the stream's summary line.
+		delete(o.held, key)
+		o.write(line)
+		return
+	}
+	if !ok {
+		o.order = append(o.order, key)
+	}
+	o.held[key] = heldInterval{start: p.timeStart, line: line}
+	o.foldTCPInfo(p)
+}
 
-	return buf.String(), nil
+// foldTCPInfo records the RTT and cwnd of a sender interval line.
+func (o *summaryOutput) foldTCPInfo(p *parsedLine) {
+	if !p.tcpInfo || p.hasUDP {
+		return
+	}
+	if o.tcp == nil {
+		o.tcp = map[int]*streamTCPInfo{}
+	}
+	a := o.tcp[p.streamID]
+	if a == nil {
+		a = &streamTCPInfo{}
+		o.tcp[p.streamID] = a
+	}
+	a.maxCwnd = max(a.maxCwnd, p.cwndBytes)
+	if p.rttUs == 0 {
+		return
+	}
+	if a.n == 0 || p.rttUs < a.minRTT {
+		a.minRTT = p.rttUs
+	}
+	a.maxRTT = max(a.maxRTT, p.rttUs)
+	a.sumRTT += p.rttUs
+	a.n++
+}
+
+// applyTCPInfo replaces the per-stream RTT spread and max cwnd, which the
+// parser could only take from the summary lines, with those of every
+// interval. A nil o (full output) leaves streams as parsed.
+func (o *summaryOutput) applyTCPInfo(streams []model.StreamResult) {
+	if o == nil {
+		return
+	}
+	for i := range streams {
+		a := o.tcp[streams[i].ID]
+		if a == nil {
+			continue
+		}
+		streams[i].MaxCwndBytes = max(streams[i].MaxCwndBytes, a.maxCwnd)
+		if a.n > 0 {
+			streams[i].MinRTTUs, streams[i].MaxRTTUs = a.minRTT, a.maxRTT
+			streams[i].MeanRTTUs = a.sumRTT / a.n
+		}
+	}
+}
+
+// parseOutputInterval parses an interval or [SUM] line of client output and
+// returns it with its stream key, or nil for any other line.
+func parseOutputInterval(line string) (*parsedLine, int) {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "[SUM") {
+		if p := parseSumLine(trimmed); p != nil {
+			return p, -1
+		}
+	}
+	if p, ok := parseSingleLine(trimmed); ok {
+		return p, p.streamID
+	}
+	return nil, 0
+}
+
+// flush writes the held-back intervals, which then stand in for the
+// summary of a stream that never printed one.
+func (o *summaryOutput) flush() {
+	for _, key := range o.order {
+		if h, ok := o.held[key]; ok {
+			o.write(h.line)
+			delete(o.held, key)
+		}
+	}
+	o.order = nil
+}
+
+func (o *summaryOutput) write(line string) {
+	o.buf.WriteString(line)
+	o.buf.WriteString("\n")
+}
+
+// empty reports whether nothing was printed.
+func (o *summaryOutput) empty() bool {
+	return o.buf.Len() == 0 && len(o.held) == 0
+}
+
+// connectFailurePhrases mark iperf2 client lines saying the connection never
+// came up. iperf2 prints its banner first and may still exit 0, so these
+// arrive as output rather than as a process error.
+var connectFailurePhrases = []string{"connect failed", "connection refused", "server is busy"}
+
+// connectFailure returns the first line of output reporting a failed
+// connection, or "" when there is none.
+func connectFailure(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		lower := strings.ToLower(line)
+		for _, phrase := range connectFailurePhrases {
+			if strings.Contains(lower, phrase) {
+				return strings.TrimSpace(line)
+			}
+		}
+	}
+	return ""
+}
+
+// recordWarnings keeps the non-empty stderr lines of a run that still
+// produced output, so they end up on the result instead of being lost.
+func (r *Runner) recordWarnings(stderr string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range strings.Split(stderr, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			r.warnings = append(r.warnings, line)
+		}
+	}
+}
+
+// serverReportText returns the Server Report section of client output,
+// from the first "Server Report:" header on: the server-side text of a run
+// without SSH.
+func serverReportText(clientOutput string) string {
+	loc := reServerReport.FindStringIndex(clientOutput)
+	if loc == nil {
+		return ""
+	}
+	start := strings.LastIndex(clientOutput[:loc[0]], "\n") + 1
+	return strings.TrimSpace(clientOutput[start:])
 }
 
 // parseServerReportFromClient extracts the server report data
@@ -0,0 +1,241 @@
+//go:build !windows
+
+package iperf
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+func TestRunLocalClient_ContextCancelSignalsProcess(t *testing.T) {
+	// Fake iperf: prints a line, then loops until SIGTERM, reporting it.
+	script := `trap 'echo got-sigterm; exit 0' TERM; echo partial-interval; while :; do sleep 0.05; done`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	r := NewRunner()
+	start := time.Now()
+	out, err := r.runLocalClient(ctx, "sh", []string{"-c", script}, nil, nil)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("runLocalClient() error: %v", err)
+	}
+	if elapsed > cancelGrace {
+		t.Errorf("runLocalClient() took %v; should return promptly after cancel", elapsed)
+	}
+	if !strings.Contains(out, "partial-interval") {
+		t.Errorf("partial output lost: %q", out)
+	}
+	if !strings.Contains(out, "got-sigterm") {
+		t.Errorf("process was not sent SIGTERM: %q", out)
+	}
+}
+
+func TestRunLocalClient_ContextCancelNoOutput(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	r := NewRunner()
+	_, err := r.runLocalClient(ctx, "sleep", []string{"30"}, nil, nil)
+	if err == nil {
+		t.Fatal("expected error when cancelled before any output")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error should wrap the context error, got %v", err)
+	}
+}
+
+func TestTimed_KeepsStderrWarnings(t *testing.T) {
+	// Fake iperf: succeeds but complains on stderr.
+	script := `echo out; echo "warning: unable to set TCP_CONGESTION" >&2; echo >&2; exit 0`
+
+	r := NewRunner()
+	result, err := r.timed(func() (*model.TestResult, error) {
+		out, err := r.runLocalClient(context.Background(), "sh", []string{"-c", script}, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.Contains(out, "out") {
+			t.Errorf("stdout lost: %q", out)
+		}
+		return &model.TestResult{}, nil
+	})
+	if err != nil {
+		t.Fatalf("timed() error: %v", err)
+	}
+	want := []string{"warning: unable to set TCP_CONGESTION"}
+	if len(result.Warnings) != 1 || result.Warnings[0] != want[0] {
+		t.Errorf("Warnings = %q, want %q", result.Warnings, want)
+	}
+
+	// A later run without stderr must not inherit the old warnings.
+	result, err = r.timed(func() (*model.TestResult, error) {
+		if _, err := r.runLocalClient(context.Background(), "sh", []string{"-c", "echo out"}, nil, nil); err != nil {
+			return nil, err
+		}
+		return &model.TestResult{}, nil
+	})
+	if err != nil {
+		t.Fatalf("timed() error: %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("Warnings = %q, want none", result.Warnings)
+	}
+}
+
+// TestRunForward_ConnectRefusedOutput runs a fake iperf that, like iperf2,
+// prints its banner on stdout before reporting the refused connect on
+// stderr, and checks the error still names the refusal so it is retryable.
+func TestRunForward_ConnectRefusedOutput(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "iperf")
+	script := "#!/bin/sh\n" +
+		"echo '------------------------------------------------------------'\n" +
+		"echo 'Client connecting to 127.0.0.1, TCP port 5999'\n" +
+		"echo 'TCP window size: 85.0 KByte (default)'\n" +
+		"echo '------------------------------------------------------------'\n" +
+		"echo 'tcp connect failed: Connection refused' >&2\n" +
+		"exit 1\n"
+	if err := os.WriteFile(bin, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.BinaryPath = bin
+	cfg.ServerAddr = "127.0.0.1"
+	cfg.Port = 5999
+
+	_, err := NewRunner().RunForward(context.Background(), cfg, nil, nil)
+	if err == nil {
+		t.Fatal("expected an error for a refused connect")
+	}
+	if !strings.Contains(err.Error(), "tcp connect failed: Connection refused") {
+		t.Errorf("error = %q, want the iperf2 connect failure", err)
+	}
+	if kind := model.ClassifyErrorString(err.Error()); kind != model.ErrorConnectionRefused {
+		t.Errorf("kind = %v, want %v", kind, model.ErrorConnectionRefused)
+	}
+}
+
+func TestConnectFailure(t *testing.T) {
+	tests := []struct {
+		output, want string
+	}{
+		{"Client connecting to 10.0.0.1, TCP port 5201\ntcp connect failed: Connection refused\n", "tcp connect failed: Connection refused"},
+		{"connect failed: Connection refused\n", "connect failed: Connection refused"},
+		{"ERROR: server is busy\n", "ERROR: server is busy"},
+		{"Client connecting to 10.0.0.1, TCP port 5201\n", ""},
+	}
+	for _, tt := range tests {
+		if got := connectFailure(tt.output); got != tt.want {
+			t.Errorf("connectFailure(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+// TestRunLocalClient_SummaryOnly checks a long streamed run keeps only its
+// summary in the returned output while every interval still reaches the
+// callback.
+func TestRunLocalClient_SummaryOnly(t *testing.T) {
+	script := `i=0
+while [ $i -lt 2000 ]; do
+  echo "[  1] $i.00-$((i+1)).00 sec  1.00 MBytes  8.39 Mbits/sec"
+  i=$((i+1))
+done
+echo "[  1] 0.00-2000.00 sec  2000.00 MBytes  8.39 Mbits/sec"`
+
+	streamed := 0
+	onInterval := func(fwd, _ *model.IntervalResult) {
+		if fwd != nil {
+			streamed++
+		}
+	}
+	out, err := NewRunner().runLocalClient(context.Background(), "sh", []string{"-c", script}, onInterval, &summaryOutput{})
+	if err != nil {
+		t.Fatalf("runLocalClient() error: %v", err)
+	}
+	if want := "[  1] 0.00-2000.00 sec  2000.00 MBytes  8.39 Mbits/sec\n"; out != want {
+		t.Errorf("output = %d bytes %q..., want only the summary line", len(out), out[:min(len(out), 120)])
+	}
+	if streamed != 2000 {
+		t.Errorf("streamed %d intervals, want 2000", streamed)
+	}
+}
+
+// TestRunForward_KeepsServerReportText runs a UDP test without SSH, where
+// the Server Report inside the client output is the only server-side text.
+func TestRunForward_KeepsServerReportText(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "client.txt")
+	if err := os.WriteFile(out, []byte(sampleClientWithValidServerReport+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bin := filepath.Join(dir, "iperf")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\ncat "+out+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.BinaryPath = bin
+	cfg.ServerAddr = "127.0.0.1"
+	cfg.Protocol = "udp"
+	cfg.KeepServerOutput = true
+
+	result, err := NewRunner().RunForward(context.Background(), cfg, nil, nil)
+	if err != nil {
+		t.Fatalf("RunForward() error: %v", err)
+	}
+	want := "[  1] Server Report:\n[  1]  0.00-10.03 sec  5.14 MBytes  4.30 Mbits/sec  10.088 ms 2461/6129 (40%)"
+	if result.ServerOutputText != want {
+		t.Errorf("ServerOutputText = %q, want the Server Report block %q", result.ServerOutputText, want)
+	}
+}
+
+// TestRunForward_StreamIntervalsKeepsStreamRTT checks the per-stream RTT
+// and cwnd come out the same whether or not the interval lines are kept.
+func TestRunForward_StreamIntervalsKeepsStreamRTT(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "client.txt")
+	if err := os.WriteFile(out, []byte(sampleTCPEnhancedTwoStreams+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bin := filepath.Join(dir, "iperf")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\ncat "+out+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(stream bool) []model.StreamResult {
+		cfg := DefaultConfig()
+		cfg.BinaryPath = bin
+		cfg.ServerAddr = "10.0.0.1"
+		cfg.Parallel = 2
+		cfg.StreamIntervals = stream
+		result, err := NewRunner().RunForward(context.Background(), cfg, nil, func(_, _ *model.IntervalResult) {})
+		if err != nil {
+			t.Fatalf("RunForward(StreamIntervals=%v) error: %v", stream, err)
+		}
+		return result.Streams
+	}
+	full, capped := run(false), run(true)
+	if len(full) != 2 || len(capped) != 2 {
+		t.Fatalf("streams: %d full, %d capped; want 2 each", len(full), len(capped))
+	}
+	for i := range full {
+		f, c := full[i], capped[i]
+		if f.MinRTTUs != c.MinRTTUs || f.MeanRTTUs != c.MeanRTTUs || f.MaxRTTUs != c.MaxRTTUs || f.MaxCwndBytes != c.MaxCwndBytes {
+			t.Errorf("stream %d RTT/cwnd with StreamIntervals = %d/%d/%d us %d B, want %d/%d/%d us %d B", f.ID,
+				c.MinRTTUs, c.MeanRTTUs, c.MaxRTTUs, c.MaxCwndBytes, f.MinRTTUs, f.MeanRTTUs, f.MaxRTTUs, f.MaxCwndBytes)
+		}
+	}
+	if full[0].MinRTTUs != 1200 || full[0].MaxRTTUs != 3120 {
+		t.Errorf("stream 1 full RTT = %d-%d us, want 1200-3120", full[0].MinRTTUs, full[0].MaxRTTUs)
+	}
+}
@@ -1,7 +1,12 @@
 package iperf
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"time"
+
+	"iperf-tool/internal/model"
 )
 
 func TestDefaultRunnerConfig(t *testing.T) {
@@ -45,3 +50,256 @@ func TestStop(t *testing.T) {
 		t.Error("Stop() should set stopped=true")
 	}
 }
+
+func TestBuildCommands(t *testing.T) {
+	r := NewRunner()
+	cfg := DefaultConfig()
+	cfg.ServerAddr = "10.0.0.1"
+
+	cmds, err := r.BuildCommands(cfg, false)
+	if err != nil {
+		t.Fatalf("BuildCommands() error: %v", err)
+	}
+	if len(cmds) != 1 {
+		t.Fatalf("forward without SSH: got %d commands, want 1", len(cmds))
+	}
+	want := "local: iperf -c 10.0.0.1 -p 5201 -t 10 -f m -i 1"
+	if got := cmds[0].String(); got != want {
+		t.Errorf("forward command = %q, want %q", got, want)
+	}
+
+	cmds, _ = r.BuildCommands(cfg, true)
+	if len(cmds) != 2 || !cmds[0].Remote || !strings.Contains(cmds[0].String(), "iperf -s") {
+		t.Errorf("forward with SSH should start the remote server first: %v", cmds)
+	}
+
+	cfg.Bidir = true
+	cmds, _ = r.BuildCommands(cfg, false)
+	if len(cmds) != 1 || !containsArg(cmds[0].Args, "-d") {
+		t.Errorf("bidir without SSH should use dualtest: %v", cmds)
+	}
+
+	cfg.Bidir = false
+	cfg.Reverse = true
+	if _, err := r.BuildCommands(cfg, false); err == nil {
+		t.Error("reverse without SSH should fail")
+	}
+
+	cfg.Reverse = false
+	cfg.Port = 0
+	if _, err := r.BuildCommands(cfg, false); err == nil {
+		t.Error("expected validation error for port 0")
+	}
+}
+
+func TestListenArgs(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Parallel = 2
+	cfg.Protocol = "udp"
+	cfg.RemoteOutputFile = "/tmp/should-not-appear.txt"
+
+	args := cfg.ListenArgs(false)
+	want := []string{"-s", "-u", "-p", "5201-5202", "-f", "m", "-i", "1"}
+	if strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Errorf("ListenArgs() = %v, want %v", args, want)
+	}
+	if !containsArg(cfg.ListenArgs(true), "-D") {
+		t.Error("ListenArgs(true) should add -D")
+	}
+	if got := cfg.ListenCommand(false); got != "iperf -s -u -p 5201-5202 -f m -i 1" {
+		t.Errorf("ListenCommand() = %q", got)
+	}
+}
+
+func TestValidateListen(t *testing.T) {
+	cfg := DefaultConfig() // no server address
+	if err := cfg.ValidateListen(); err != nil {
+		t.Errorf("ValidateListen() without server address: %v", err)
+	}
+	cfg.Port = 0
+	if err := cfg.ValidateListen(); err == nil {
+		t.Error("expected error for port 0")
+	}
+	cfg.Port = 5201
+	cfg.BinaryPath = ""
+	if err := cfg.ValidateListen(); err == nil {
+		t.Error("expected error for empty binary path")
+	}
+}
+
+func TestTimed_StampsWallClock(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	r := NewRunner()
+	r.now = func() time.Time { return now }
+
+	result, err := r.timed(func() (*model.TestResult, error) {
+		now = now.Add(13 * time.Second) // handshake + 10s test + teardown
+		return &model.TestResult{Duration: 10}, nil
+	})
+	if err != nil {
+		t.Fatalf("timed() error: %v", err)
+	}
+	if !result.StartedAt.Equal(start) || !result.FinishedAt.Equal(start.Add(13*time.Second)) {
+		t.Errorf("StartedAt=%v FinishedAt=%v", result.StartedAt, result.FinishedAt)
+	}
+	if result.Elapsed() != 13*time.Second {
+		t.Errorf("Elapsed() = %v, want 13s", result.Elapsed())
+	}
+
+	if _, err := r.timed(func() (*model.TestResult, error) { return nil, errors.New("boom") }); err == nil {
+		t.Error("timed() should pass the error through")
+	}
+}
+
+func TestBuildCommands_NoServerOutput(t *testing.T) {
+	r := NewRunner()
+	cfg := DefaultConfig()
+	cfg.ServerAddr = "10.0.0.1"
+
+	cmds, err := r.BuildCommands(cfg, true)
+	if err != nil {
+		t.Fatalf("BuildCommands() error: %v", err)
+	}
+	if !strings.Contains(cmds[0].String(), "-o /tmp/iperf2_server_output.txt") {
+		t.Errorf("remote server should log to a file by default: %s", cmds[0])
+	}
+
+	cfg.NoServerOutput = true
+	cfg.RemoteOutputFile = "/tmp/custom.txt"
+	cmds, _ = r.BuildCommands(cfg, true)
+	if containsArg(cmds[0].Args, "-o") {
+		t.Errorf("-o should be absent with NoServerOutput: %s", cmds[0])
+	}
+
+	cfg.Bidir = true
+	cfg.Protocol = "udp"
+	cmds, _ = r.BuildCommands(cfg, true)
+	if containsArg(cmds[0].Args, "-o") {
+		t.Errorf("-o should be absent for UDP bidir with NoServerOutput: %s", cmds[0])
+	}
+}
+
+func TestKeepServerOutput(t *testing.T) {
+	result := &model.TestResult{}
+	cfg := Config{}
+	cfg.keepServerOutput(result, "server line\n")
+	if result.ServerOutputText != "" {
+		t.Errorf("ServerOutputText = %q, want empty without KeepServerOutput", result.ServerOutputText)
+	}
+	cfg.KeepServerOutput = true
+	cfg.keepServerOutput(result, "server line\n")
+	if result.ServerOutputText != "server line" {
+		t.Errorf("ServerOutputText = %q, want %q", result.ServerOutputText, "server line")
+	}
+
+	got := labelledServerOutput("fwd\n", "")
+	if got != "[remote server: client to server]\nfwd" {
+		t.Errorf("labelledServerOutput(remote only) = %q", got)
+	}
+	got = labelledServerOutput("fwd", "rev")
+	if !strings.Contains(got, "fwd\n\n[local server: server to client]\nrev") {
+		t.Errorf("labelledServerOutput(both) = %q", got)
+	}
+}
+
+func TestUseDualtest(t *testing.T) {
+	tests := []struct {
+		mode    string
+		withSSH bool
+		want    bool
+		wantErr bool
+	}{
+		{"", false, true, false},
+		{BidirAuto, true, false, false},
+		{BidirDualtest, true, true, false},
+		{BidirSplit, true, false, false},
+		{BidirSplit, false, false, true},
+	}
+	for _, tt := range tests {
+		cfg := Config{BidirMode: tt.mode}
+		got, err := cfg.UseDualtest(tt.withSSH)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("UseDualtest(mode=%q, ssh=%v) = %v, %v; want %v, err=%v", tt.mode, tt.withSSH, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestBuildCommands_BidirMode(t *testing.T) {
+	r := NewRunner()
+	cfg := DefaultConfig()
+	cfg.ServerAddr = "10.0.0.1"
+	cfg.LocalAddr = "10.0.0.2"
+	cfg.Bidir = true
+
+	cfg.BidirMode = BidirDualtest
+	cmds, err := r.BuildCommands(cfg, true)
+	if err != nil || len(cmds) != 1 || !containsArg(cmds[0].Args, "-d") {
+		t.Errorf("forced dualtest with SSH should run one -d client: %v, %v", cmds, err)
+	}
+
+	cfg.BidirMode = BidirSplit
+	cmds, err = r.BuildCommands(cfg, true)
+	if err != nil || len(cmds) != 4 {
+		t.Errorf("split with SSH should start four processes: %v, %v", cmds, err)
+	}
+	if _, err := r.BuildCommands(cfg, false); err == nil {
+		t.Error("forced split without SSH should fail")
+	}
+
+	cfg.BidirMode = "batch"
+	if _, err := r.BuildCommands(cfg, false); err == nil {
+		t.Error("unknown bidir mode should fail validation")
+	}
+}
+
+func TestSummaryOutput_KeepsSummary(t *testing.T) {
+	for name, text := range map[string]string{
+		"tcp":         sampleTCPOutput,
+		"two streams": sampleTCPEnhancedTwoStreams,
+		"udp":         sampleClientOutput,
+	} {
+		o := &summaryOutput{}
+		for _, line := range strings.Split(text, "\n") {
+			o.add(line)
+		}
+		o.flush()
+		thin := o.buf.String()
+		if len(thin) >= len(text) {
+			t.Errorf("%s: summary-only output is %d bytes, want fewer than the full %d", name, len(thin), len(text))
+		}
+
+		full, err := ParseOutput(text, false)
+		if err != nil {
+			t.Fatalf("%s: ParseOutput(full) error: %v", name, err)
+		}
+		got, err := ParseOutput(thin, false)
+		if err != nil {
+			t.Fatalf("%s: ParseOutput(summary only) error: %v\n%s", name, err, thin)
+		}
+		if got.SentBps != full.SentBps || got.ReceivedBps != full.ReceivedBps ||
+			got.Retransmits != full.Retransmits || got.ActualDuration != full.ActualDuration {
+			t.Errorf("%s: summary-only result sent=%v recv=%v retr=%d dur=%v, want sent=%v recv=%v retr=%d dur=%v",
+				name, got.SentBps, got.ReceivedBps, got.Retransmits, got.ActualDuration,
+				full.SentBps, full.ReceivedBps, full.Retransmits, full.ActualDuration)
+		}
+	}
+}
+
+func TestSummaryOutput_CutShort(t *testing.T) {
+	o := &summaryOutput{}
+	for _, line := range []string{
+		"[  1] 0.00-1.00 sec  1.00 MBytes  8.39 Mbits/sec",
+		"[  1] 1.00-2.00 sec  1.00 MBytes  8.39 Mbits/sec",
+		"[  1] 2.00-3.00 sec  2.00 MBytes  16.8 Mbits/sec",
+	} {
+		o.add(line)
+	}
+	if o.empty() {
+		t.Fatal("held interval not counted as output")
+	}
+	o.flush()
+	if got, want := o.buf.String(), "[  1] 2.00-3.00 sec  2.00 MBytes  16.8 Mbits/sec\n"; got != want {
+		t.Errorf("cut-short output = %q, want only the last interval %q", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package iperf
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os/exec"
+	"strings"
+)
+
+// ValidateListen checks the settings used by a local listen-mode server.
+// Unlike Validate it does not require a server address.
+func (c *Config) ValidateListen() error {
+	// Reuse Validate with the client-only settings neutralised.
+	probe := *c
+	probe.ServerAddr = "127.0.0.1"
+	probe.Reverse, probe.Bidir = false, false
+	probe.SSHFallback = false
+	probe.Duration = math.MaxInt32
+	probe.OmitSeconds = 0
+	return probe.Validate()
+}
+
+// ListenArgs returns the arguments for a local iperf2 server on c.Port,
+// covering Parallel ports like the remote server. daemon adds -D so iperf2
+// detaches and keeps running after this process exits.
+func (c *Config) ListenArgs(daemon bool) []string {
+	local := *c
+	local.RemoteOutputFile = ""
+	args := local.fwdServerArgs()
+	if daemon {
+		args = append(args, "-D")
+	}
+	return args
+}
+
+// RunServer runs a local iperf2 server until ctx is cancelled, Stop is
+// called or the process exits, passing each output line to onLine.
+// A cancelled or stopped server returns nil.
+func (r *Runner) RunServer(ctx context.Context, cfg Config, daemon bool, onLine func(string)) error {
+	if err := cfg.ValidateListen(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	args := cfg.ListenArgs(daemon)
+	dbg, logf := r.debugWriter("server", args)
+	defer dbg.Close()
+
+	cmd := exec.CommandContext(ctx, cfg.BinaryPath, args...)
+	terminateOnCancel(cmd)
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start local server: %w", err)
+	}
+	r.mu.Lock()
+	r.localSrvCmd = cmd
+	r.stopped = false
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.localSrvCmd = nil
+		r.mu.Unlock()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(pr)
+		for scanner.Scan() {
+			line := scanner.Text()
+			logf("%s\n", line)
+			if onLine != nil {
+				onLine(line)
+			}
+		}
+		io.Copy(io.Discard, pr) // drain anything past an over-long line
+	}()
+
+	waitErr := cmd.Wait()
+	pw.Close()
+	<-done
+
+	r.mu.Lock()
+	userStopped := r.stopped
+	r.mu.Unlock()
+	if waitErr != nil && ctx.Err() == nil && !userStopped {
+		return fmt.Errorf("local server exited: %w", waitErr)
+	}
+	return nil
+}
+
+// ListenCommand renders the local server command line for display.
+func (c *Config) ListenCommand(daemon bool) string {
+	return strings.Join(append([]string{c.BinaryPath}, c.ListenArgs(daemon)...), " ")
+}
@@ -0,0 +1,56 @@
+//go:build !windows
+
+package iperf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRunServer_StreamsUntilCancel runs a fake iperf that prints its
+// arguments and then blocks, checking output is streamed and cancellation
+// stops it without an error.
+func TestRunServer_StreamsUntilCancel(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "iperf")
+	script := "#!/bin/sh\necho \"listening $*\"\nexec sleep 30\n"
+	if err := os.WriteFile(bin, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.BinaryPath = bin
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	var lines []string
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- NewRunner().RunServer(ctx, cfg, false, func(line string) {
+			mu.Lock()
+			lines = append(lines, line)
+			mu.Unlock()
+			cancel()
+		})
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("RunServer() error: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		cancel()
+		t.Fatal("RunServer() did not return after cancel")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "listening -s -p 5201") {
+		t.Errorf("streamed lines = %q", lines)
+	}
+}
@@ -0,0 +1,95 @@
+package iperf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MinVersion is the oldest iperf2 release expected to work: earlier ones
+// lack the enhanced (-e) report fields the parser relies on.
+const MinVersion = "2.0.10"
+
+// ParseVersion extracts the version number from iperf --version output.
+func ParseVersion(out string) (string, error) {
+	matches := versionRegex.FindStringSubmatch(out)
+	if len(matches) < 2 {
+		return "", fmt.Errorf("could not parse iperf version from: %s", strings.TrimSpace(out))
+	}
+	return matches[1], nil
+}
+
+// RemoteVersion runs "<binary> --version" over SSH and parses the result.
+// iperf2 prints the version to stderr and some releases exit non-zero, so
+// the output is parsed even when the command reports an error.
+func RemoteVersion(client SSHClient, binary string) (string, error) {
+	if binary == "" {
+		binary = "iperf"
+	}
+	out, err := client.RunCommand(binary + " --version")
+	v, perr := ParseVersion(out)
+	if perr != nil {
+		if err != nil {
+			return "", fmt.Errorf("run remote iperf --version: %w", err)
+		}
+		return "", perr
+	}
+	return v, nil
+}
+
+// RequireMinVersion returns an error naming the local version when it is
+// older than MinVersion, for runs that must fail rather than degrade.
+func RequireMinVersion(version string) error {
+	if CompareVersions(version, MinVersion) < 0 {
+		return fmt.Errorf("local iperf2 %s is older than the required %s (no enhanced -e report fields); upgrade iperf2", version, MinVersion)
+	}
+	return nil
+}
+
+// CompareVersions compares dotted version strings numerically and returns
+// -1, 0 or 1. Missing components count as 0.
+func CompareVersions(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			y, _ = strconv.Atoi(pb[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// VersionWarnings returns human-readable warnings for a remote iperf2 that
+// is older than MinVersion or from a different major.minor series than the
+// local client. local may be empty when the local version is unknown.
+func VersionWarnings(local, remote string) []string {
+	var warnings []string
+	if remote == "" {
+		return nil
+	}
+	if CompareVersions(remote, MinVersion) < 0 {
+		warnings = append(warnings, fmt.Sprintf("remote iperf2 %s is older than %s; results may be incomplete", remote, MinVersion))
+	}
+	if local != "" && series(local) != series(remote) {
+		warnings = append(warnings, fmt.Sprintf("local iperf2 %s and remote %s differ; mismatched releases can disagree on the test protocol", local, remote))
+	}
+	return warnings
+}
+
+// series returns the major.minor part of a version ("2.1.9" -> "2.1").
+func series(v string) string {
+	parts := strings.SplitN(v, ".", 3)
+	if len(parts) < 2 {
+		return v
+	}
+	return parts[0] + "." + parts[1]
+}
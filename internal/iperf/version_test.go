@@ -0,0 +1,90 @@
+package iperf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	v, err := ParseVersion("iperf version 2.1.9 (14 March 2023) pthreads\n")
+	if err != nil || v != "2.1.9" {
+		t.Errorf("ParseVersion() = %q, %v; want 2.1.9", v, err)
+	}
+	if _, err := ParseVersion("command not found"); err == nil {
+		t.Error("expected error for unparseable output")
+	}
+}
+
+type versionClient struct {
+	out string
+	err error
+	cmd string
+}
+
+func (c *versionClient) RunCommand(cmd string) (string, error) {
+	c.cmd = cmd
+	return c.out, c.err
+}
+
+func TestRemoteVersion(t *testing.T) {
+	// iperf2 writes the version to stderr and may exit 1; the output still counts.
+	c := &versionClient{out: "iperf version 2.0.13 (21 Jan 2019) pthreads", err: errors.New("exit status 1")}
+	v, err := RemoteVersion(c, "/opt/iperf2/bin/iperf")
+	if err != nil || v != "2.0.13" {
+		t.Fatalf("RemoteVersion() = %q, %v; want 2.0.13", v, err)
+	}
+	if c.cmd != "/opt/iperf2/bin/iperf --version" {
+		t.Errorf("command = %q", c.cmd)
+	}
+
+	c = &versionClient{out: "sh: iperf: not found", err: errors.New("exit status 127")}
+	if _, err := RemoteVersion(c, ""); err == nil {
+		t.Error("expected error when iperf is missing")
+	}
+	if c.cmd != "iperf --version" {
+		t.Errorf("default command = %q", c.cmd)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2.1.9", "2.1.9", 0},
+		{"2.0.9", "2.0.10", -1},
+		{"2.1", "2.0.13", 1},
+		{"2.1", "2.1.0", 0},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestVersionWarnings(t *testing.T) {
+	if w := VersionWarnings("2.1.9", "2.1.8"); len(w) != 0 {
+		t.Errorf("same series: warnings = %q, want none", w)
+	}
+	w := VersionWarnings("2.1.9", "2.0.5")
+	if len(w) != 2 || !strings.Contains(w[0], "older than") || !strings.Contains(w[1], "differ") {
+		t.Errorf("old mismatched remote: warnings = %q", w)
+	}
+	if w := VersionWarnings("", "2.0.13"); len(w) != 0 {
+		t.Errorf("unknown local: warnings = %q, want none", w)
+	}
+}
+
+func TestRequireMinVersion(t *testing.T) {
+	for _, v := range []string{"2.0.10", "2.1.9"} {
+		if err := RequireMinVersion(v); err != nil {
+			t.Errorf("RequireMinVersion(%q) = %v, want nil", v, err)
+		}
+	}
+	err := RequireMinVersion("2.0.9")
+	if err == nil || !strings.Contains(err.Error(), "2.0.9 is older than the required 2.0.10") {
+		t.Errorf("RequireMinVersion(2.0.9) = %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package model
+
+import "strings"
+
+// ErrorKind classifies a failed test so callers can react differently to,
+// say, a server that is down versus one that is momentarily busy.
+type ErrorKind int
+
+const (
+	ErrorNone ErrorKind = iota
+	ErrorConnectionRefused
+	ErrorTimeout
+	ErrorServerBusy
+	ErrorVersionMismatch
+	ErrorUnknown
+)
+
+// String returns a short lowercase name for the kind.
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorNone:
+		return "none"
+	case ErrorConnectionRefused:
+		return "connection refused"
+	case ErrorTimeout:
+		return "timeout"
+	case ErrorServerBusy:
+		return "server busy"
+	case ErrorVersionMismatch:
+		return "version mismatch"
+	}
+	return "unknown"
+}
+
+// errorPatterns maps lowercase substrings of iperf2/iperf3 error messages to
+// their kind. Checked in order: busy, mismatch and timeout messages can also
+// mention a failed connection, so they go before the generic refusal.
+var errorPatterns = []struct {
+	kind    ErrorKind
+	phrases []string
+}{
+	{ErrorServerBusy, []string{"server is busy", "busy running a test"}},
+	{ErrorVersionMismatch, []string{"unknown control message", "version mismatch", "unable to receive parameters"}},
+	{ErrorTimeout, []string{"timed out", "timeout", "deadline exceeded", "operation now in progress"}},
+	{ErrorConnectionRefused, []string{"connection refused", "unable to connect"}},
+}
+
+// ClassifyErrorString returns the kind of an error message, ErrorNone for an
+// empty message and ErrorUnknown when no known phrasing matches.
+func ClassifyErrorString(msg string) ErrorKind {
+	if strings.TrimSpace(msg) == "" {
+		return ErrorNone
+	}
+	lower := strings.ToLower(msg)
+	for _, p := range errorPatterns {
+		for _, phrase := range p.phrases {
+			if strings.Contains(lower, phrase) {
+				return p.kind
+			}
+		}
+	}
+	return ErrorUnknown
+}
+
+// ClassifyError returns the kind of r.Error.
+func (r *TestResult) ClassifyError() ErrorKind {
+	return ClassifyErrorString(r.Error)
+}
@@ -0,0 +1,41 @@
+package model
+
+import "testing"
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want ErrorKind
+	}{
+		{"", ErrorNone},
+		// iperf3
+		{"error - unable to connect to server: Connection refused", ErrorConnectionRefused},
+		{"error - the server is busy running a test. try again later", ErrorServerBusy},
+		{"error - received an unknown control message", ErrorVersionMismatch},
+		{"error - unable to receive parameters from server", ErrorVersionMismatch},
+		{"error - unable to connect to server: Connection timed out", ErrorTimeout},
+		// iperf2
+		{"connect failed: Connection refused", ErrorConnectionRefused},
+		{"tcp connect to 10.0.0.1 port 5201 failed (Connection refused)", ErrorConnectionRefused},
+		{"connect failed: Operation now in progress", ErrorTimeout},
+		{"connect failed: Connection timed out", ErrorTimeout},
+		// wrapped Go errors from this tool
+		{"iperf cancelled: context deadline exceeded", ErrorTimeout},
+		{"iperf failed: exit status 1: write failed: Broken pipe", ErrorUnknown},
+	}
+	for _, tt := range tests {
+		r := &TestResult{Error: tt.msg}
+		if got := r.ClassifyError(); got != tt.want {
+			t.Errorf("ClassifyError(%q) = %v, want %v", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestErrorKindString(t *testing.T) {
+	if ErrorConnectionRefused.String() != "connection refused" {
+		t.Errorf("String() = %q", ErrorConnectionRefused.String())
+	}
+	if ErrorKind(99).String() != "unknown" {
+		t.Errorf("out-of-range kind should be unknown")
+	}
+}
@@ -0,0 +1,143 @@
+package model
+
+// IntervalKeeper downsamples the intervals of a run of any length into at
+// most a fixed number of rows. Each row merges a bucket of consecutive
+// intervals (see mergeIntervals); when the rows run out, neighbouring rows
+// are merged pairwise and the bucket doubles. Every row covers its span,
+// so byte, packet and retransmit totals still add up to the whole run
+// while memory stays bounded.
+type IntervalKeeper struct {
+	limit  int
+	bucket int // intervals merged into each row
+	fill   int // intervals in the last row
+	seen   int
+	kept   []IntervalResult
+}
+
+// NewIntervalKeeper returns a keeper holding at most limit intervals (min 1).
+func NewIntervalKeeper(limit int) *IntervalKeeper {
+	if limit < 1 {
+		limit = 1
+	}
+	return &IntervalKeeper{limit: limit, bucket: 1}
+}
+
+// Add offers the next interval of the run.
+func (k *IntervalKeeper) Add(iv IntervalResult) {
+	k.seen++
+	if len(k.kept) > 0 && k.fill == k.bucket && len(k.kept) == k.limit {
+		n := 0
+		for i := 0; i < len(k.kept); i += 2 {
+			if i+1 < len(k.kept) {
+				k.kept[n] = mergeIntervals(k.kept[i : i+2])
+			} else {
+				k.kept[n] = k.kept[i]
+			}
+			n++
+		}
+		// An odd row out keeps its old, half-size bucket.
+		if len(k.kept)%2 == 0 {
+			k.fill = k.bucket * 2
+		}
+		k.kept = k.kept[:n]
+		k.bucket *= 2
+	}
+	if len(k.kept) > 0 && k.fill < k.bucket {
+		last := &k.kept[len(k.kept)-1]
+		*last = mergeIntervals([]IntervalResult{*last, iv})
+		k.fill++
+		return
+	}
+	k.kept = append(k.kept, iv)
+	k.fill = 1
+}
+
+// Intervals returns the retained intervals in arrival order.
+func (k *IntervalKeeper) Intervals() []IntervalResult {
+	return k.kept
+}
+
+// Seen returns how many intervals were offered.
+func (k *IntervalKeeper) Seen() int {
+	return k.seen
+}
+
+// CapIntervals returns intervals merged down to at most limit rows by an
+// IntervalKeeper. limit < 1, or a slice already within it, is returned as is.
+func CapIntervals(intervals []IntervalResult, limit int) []IntervalResult {
+	if limit < 1 || len(intervals) <= limit {
+		return intervals
+	}
+	k := NewIntervalKeeper(limit)
+	for _, iv := range intervals {
+		k.Add(iv)
+	}
+	return k.Intervals()
+}
+
+// DownsampleIntervals merges the forward intervals into at most maxRows
+// rows of consecutive buckets, for reports too long to read row by row.
+// Bytes, retransmits and packet counts are summed, so totals are preserved;
+// throughput and jitter are time-weighted. Intervals are returned as
+// is when they already fit or maxRows < 1.
+func (r *TestResult) DownsampleIntervals(maxRows int) []IntervalResult {
+	return downsample(r.Intervals, r.downsampleBucket(maxRows))
+}
+
+// ReverseDownsampleIntervals is DownsampleIntervals over the bidir
+// ReverseIntervals. Both directions use the same bucket size, so row i of
+// each covers the same stretch of the test.
+func (r *TestResult) ReverseDownsampleIntervals(maxRows int) []IntervalResult {
+	return downsample(r.ReverseIntervals, r.downsampleBucket(maxRows))
+}
+
+// downsampleBucket returns how many intervals go into one row so that the
+// longer direction fits in maxRows; 1 means no merging.
+func (r *TestResult) downsampleBucket(maxRows int) int {
+	n := max(len(r.Intervals), len(r.ReverseIntervals))
+	if maxRows < 1 || n <= maxRows {
+		return 1
+	}
+	return (n + maxRows - 1) / maxRows
+}
+
+func downsample(intervals []IntervalResult, bucket int) []IntervalResult {
+	if bucket <= 1 {
+		return intervals
+	}
+	out := make([]IntervalResult, 0, (len(intervals)+bucket-1)/bucket)
+	for i := 0; i < len(intervals); i += bucket {
+		out = append(out, mergeIntervals(intervals[i:min(i+bucket, len(intervals))]))
+	}
+	return out
+}
+
+// mergeIntervals combines consecutive intervals into one spanning them all.
+func mergeIntervals(ivs []IntervalResult) IntervalResult {
+	m := IntervalResult{TimeStart: ivs[0].TimeStart, TimeEnd: ivs[len(ivs)-1].TimeEnd, Omitted: true}
+	var bitSecs, secs, jitter, jitterSecs float64
+	for _, iv := range ivs {
+		m.Bytes += iv.Bytes
+		m.Retransmits += iv.Retransmits
+		m.Packets += iv.Packets
+		m.LostPackets += iv.LostPackets
+		m.Omitted = m.Omitted && iv.Omitted
+		d := iv.TimeEnd - iv.TimeStart
+		bitSecs += iv.BandwidthBps * d
+		secs += d
+		jitter += iv.JitterMs
+		jitterSecs += iv.JitterMs * d
+	}
+	if secs > 0 {
+		m.BandwidthBps = bitSecs / secs
+	}
+	if m.Packets > 0 {
+		m.LostPercent = float64(m.LostPackets) / float64(m.Packets) * 100
+	}
+	if secs > 0 {
+		m.JitterMs = jitterSecs / secs
+	} else {
+		m.JitterMs = jitter / float64(len(ivs))
+	}
+	return m
+}
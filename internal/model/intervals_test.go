@@ -0,0 +1,168 @@
+package model
+
+import "testing"
+
+func TestIntervalKeeper_Bounded(t *testing.T) {
+	// A 6-hour run at 1 s intervals
+	const total, limit = 21600, 500
+	k := NewIntervalKeeper(limit)
+	peak := 0
+	for i := 0; i < total; i++ {
+		k.Add(IntervalResult{TimeStart: float64(i), TimeEnd: float64(i + 1), Bytes: 1})
+		peak = max(peak, len(k.Intervals()))
+	}
+	if peak > limit {
+		t.Errorf("retained up to %d intervals, want at most %d", peak, limit)
+	}
+	if k.Seen() != total {
+		t.Errorf("Seen() = %d, want %d", k.Seen(), total)
+	}
+	var bytes int64
+	for _, iv := range k.Intervals() {
+		bytes += iv.Bytes
+	}
+	if bytes != total {
+		t.Errorf("kept rows hold %d bytes, want all %d", bytes, total)
+	}
+
+	kept := k.Intervals()
+	if len(kept) < limit/2 {
+		t.Errorf("kept %d intervals, want at least %d", len(kept), limit/2)
+	}
+	if kept[0].TimeStart != 0 {
+		t.Errorf("first kept interval starts at %v, want 0", kept[0].TimeStart)
+	}
+	// Evenly spread: one bucket between neighbours, reaching the end of the run
+	stride := kept[1].TimeStart - kept[0].TimeStart
+	for i := 1; i < len(kept); i++ {
+		if d := kept[i].TimeStart - kept[i-1].TimeStart; d != stride {
+			t.Fatalf("gap %v between kept intervals %d and %d, want %v", d, i-1, i, stride)
+		}
+	}
+	if last := kept[len(kept)-1].TimeStart; last < total-stride {
+		t.Errorf("last kept interval starts at %v, want within %v of %d", last, stride, total)
+	}
+}
+
+func TestCapIntervals_TotalsPreserved(t *testing.T) {
+	const total = 1237
+	ivs := make([]IntervalResult, total)
+	var want IntervalResult
+	for i := range ivs {
+		iv := IntervalResult{TimeStart: float64(i), TimeEnd: float64(i + 1), Bytes: int64(1000 + i%17),
+			Retransmits: i % 3, Packets: 100, LostPackets: i % 5, BandwidthBps: 8000}
+		ivs[i] = iv
+		want.Bytes += iv.Bytes
+		want.Retransmits += iv.Retransmits
+		want.Packets += iv.Packets
+		want.LostPackets += iv.LostPackets
+	}
+	for _, limit := range []int{1, 7, 64, 500} {
+		got := CapIntervals(ivs, limit)
+		if len(got) > limit {
+			t.Fatalf("cap %d kept %d rows", limit, len(got))
+		}
+		var sum IntervalResult
+		for i, iv := range got {
+			sum.Bytes += iv.Bytes
+			sum.Retransmits += iv.Retransmits
+			sum.Packets += iv.Packets
+			sum.LostPackets += iv.LostPackets
+			if i > 0 && iv.TimeStart != got[i-1].TimeEnd {
+				t.Errorf("cap %d: row %d starts at %v, want %v (rows must cover the run)", limit, i, iv.TimeStart, got[i-1].TimeEnd)
+			}
+		}
+		if sum.Bytes != want.Bytes || sum.Retransmits != want.Retransmits || sum.Packets != want.Packets || sum.LostPackets != want.LostPackets {
+			t.Errorf("cap %d: totals bytes=%d retr=%d pkts=%d lost=%d, want %d/%d/%d/%d", limit,
+				sum.Bytes, sum.Retransmits, sum.Packets, sum.LostPackets, want.Bytes, want.Retransmits, want.Packets, want.LostPackets)
+		}
+		if got[0].TimeStart != 0 || got[len(got)-1].TimeEnd != total {
+			t.Errorf("cap %d: rows span %v-%v, want 0-%d", limit, got[0].TimeStart, got[len(got)-1].TimeEnd, total)
+		}
+	}
+}
+
+func TestCapIntervals(t *testing.T) {
+	ivs := make([]IntervalResult, 10)
+	for i := range ivs {
+		ivs[i] = IntervalResult{TimeStart: float64(i)}
+	}
+	if got := CapIntervals(ivs, 0); len(got) != 10 {
+		t.Errorf("cap 0 kept %d, want all 10", len(got))
+	}
+	if got := CapIntervals(ivs, 10); len(got) != 10 {
+		t.Errorf("cap 10 kept %d, want all 10", len(got))
+	}
+	got := CapIntervals(ivs, 4)
+	if len(got) > 4 {
+		t.Fatalf("cap 4 kept %d", len(got))
+	}
+	for i, iv := range got {
+		if want := float64(i * 4); iv.TimeStart != want {
+			t.Errorf("kept[%d].TimeStart = %v, want %v", i, iv.TimeStart, want)
+		}
+	}
+}
+
+func TestDownsampleIntervals(t *testing.T) {
+	r := &TestResult{}
+	var totalBytes int64
+	totalRetr := 0
+	for i := 0; i < 1000; i++ {
+		iv := IntervalResult{TimeStart: float64(i), TimeEnd: float64(i + 1), Bytes: int64(100_000 + i), BandwidthBps: 8e5, Retransmits: i % 3}
+		totalBytes += iv.Bytes
+		totalRetr += iv.Retransmits
+		r.Intervals = append(r.Intervals, iv)
+	}
+
+	rows := r.DownsampleIntervals(100)
+	if len(rows) > 100 {
+		t.Fatalf("got %d rows, want at most 100", len(rows))
+	}
+	var gotBytes int64
+	gotRetr := 0
+	for _, iv := range rows {
+		gotBytes += iv.Bytes
+		gotRetr += iv.Retransmits
+	}
+	if gotBytes != totalBytes || gotRetr != totalRetr {
+		t.Errorf("downsampled totals = %d bytes / %d retr, want %d / %d", gotBytes, gotRetr, totalBytes, totalRetr)
+	}
+	if rows[0].TimeStart != 0 || rows[len(rows)-1].TimeEnd != 1000 {
+		t.Errorf("rows span %v-%v, want 0-1000", rows[0].TimeStart, rows[len(rows)-1].TimeEnd)
+	}
+	if rows[0].BandwidthBps != 8e5 {
+		t.Errorf("row bandwidth = %v, want 8e5", rows[0].BandwidthBps)
+	}
+
+	if got := r.DownsampleIntervals(2000); len(got) != 1000 {
+		t.Errorf("a result that fits returned %d rows, want all 1000", len(got))
+	}
+}
+
+func TestDownsampleIntervals_UDPAndReverseAlignment(t *testing.T) {
+	r := &TestResult{
+		Intervals: []IntervalResult{
+			{TimeStart: 0, TimeEnd: 1, Packets: 100, LostPackets: 10, JitterMs: 1, Omitted: true},
+			{TimeStart: 1, TimeEnd: 2, Packets: 100, LostPackets: 30, JitterMs: 3},
+			{TimeStart: 2, TimeEnd: 3, Packets: 100},
+			{TimeStart: 3, TimeEnd: 4, Packets: 100},
+		},
+		ReverseIntervals: []IntervalResult{{TimeStart: 0, TimeEnd: 1}, {TimeStart: 1, TimeEnd: 2}, {TimeStart: 2, TimeEnd: 3}},
+	}
+	fwd := r.DownsampleIntervals(2)
+	if len(fwd) != 2 {
+		t.Fatalf("got %d rows, want 2", len(fwd))
+	}
+	if fwd[0].LostPackets != 40 || fwd[0].Packets != 200 || fwd[0].LostPercent != 20 || fwd[0].JitterMs != 2 {
+		t.Errorf("row 1 = %+v, want 40/200 lost (20%%), 2 ms jitter", fwd[0])
+	}
+	if fwd[0].Omitted {
+		t.Error("a row with a non-omitted interval should not be omitted")
+	}
+	// The shorter reverse side is bucketed like the forward one
+	rev := r.ReverseDownsampleIntervals(2)
+	if len(rev) != 2 || rev[0].TimeEnd != 2 || rev[1].TimeStart != 2 || rev[1].TimeEnd != 3 {
+		t.Errorf("reverse rows = %+v, want 0-2 and 2-3", rev)
+	}
+}
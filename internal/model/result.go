@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"math"
 	"time"
 )
@@ -13,6 +14,10 @@ type PingResult struct {
 	MinMs       float64
 	AvgMs       float64
 	MaxMs       float64
+	StdDevMs    float64 // stddev/mdev from the ping summary; 0 when not reported
+	P50Ms       float64 // percentiles from per-reply samples; 0 when only the summary was parsed
+	P95Ms       float64
+	P99Ms       float64
 }
 
 // IntervalResult holds a single interval measurement from an iperf test.
@@ -28,6 +33,9 @@ type IntervalResult struct {
 	JitterMs     float64 // UDP only
 	Omitted      bool
 	StreamID     int // iperf stream/socket ID; 0 = aggregate/unknown
+	// PerStream holds the individual stream rows behind an aggregate
+	// interval when more than one stream reported; nil otherwise.
+	PerStream []IntervalResult `json:",omitempty"`
 }
 
 // BandwidthMbps returns the interval bandwidth in Mbps.
@@ -40,6 +48,67 @@ func (r *IntervalResult) TransferMB() float64 {
 	return float64(r.Bytes) / 1_000_000
 }
 
+// PacketsPerSecond returns the interval's UDP packet rate, or 0 for an
+// interval of zero length.
+func (r *IntervalResult) PacketsPerSecond() float64 {
+	secs := r.TimeEnd - r.TimeStart
+	if secs <= 0 {
+		return 0
+	}
+	return float64(r.Packets) / secs
+}
+
+// RetransmitsPerSecond returns the interval's TCP retransmit rate, or 0
+// for an interval of zero length.
+func (r *IntervalResult) RetransmitsPerSecond() float64 {
+	secs := r.TimeEnd - r.TimeStart
+	if secs <= 0 {
+		return 0
+	}
+	return float64(r.Retransmits) / secs
+}
+
+// HasSubSecondIntervals reports whether any interval starts off a whole
+// second, i.e. the test ran with a fractional reporting interval.
+func HasSubSecondIntervals(intervals []IntervalResult) bool {
+	for _, iv := range intervals {
+		if frac := iv.TimeStart - math.Trunc(iv.TimeStart); frac > 0.005 && frac < 0.995 {
+			return true
+		}
+	}
+	return false
+}
+
+// LastActiveEnd returns the end time of the last non-omitted interval, or 0
+// when there is none.
+func LastActiveEnd(intervals []IntervalResult) float64 {
+	for i := len(intervals) - 1; i >= 0; i-- {
+		if !intervals[i].Omitted {
+			return intervals[i].TimeEnd
+		}
+	}
+	return 0
+}
+
+// SuspectIntervals counts forward and reverse intervals faster than 1.1×
+// linkMbps, which the link cannot carry and so point at timing glitches
+// such as clock skew on a sleeping laptop. Returns 0 when linkMbps is unknown.
+func (r *TestResult) SuspectIntervals(linkMbps float64) int {
+	if linkMbps <= 0 {
+		return 0
+	}
+	limit := 1.1 * linkMbps
+	n := 0
+	for _, ivs := range [][]IntervalResult{r.Intervals, r.ReverseIntervals} {
+		for i := range ivs {
+			if ivs[i].BandwidthMbps() > limit {
+				n++
+			}
+		}
+	}
+	return n
+}
+
 // StreamResult holds per-stream throughput data.
 type StreamResult struct {
 	ID          int
@@ -52,6 +121,18 @@ type StreamResult struct {
 	LostPercent float64
 	Packets     int
 	Sender      bool // true = forward/TX stream, false = reverse/RX stream (bidir mode)
+
+	// TCP sender RTT and congestion window from the iperf2 -e client;
+	// zero when not reported (UDP, non-Linux senders).
+	MinRTTUs     int
+	MeanRTTUs    int
+	MaxRTTUs     int
+	MaxCwndBytes int64
+}
+
+// HasTCPInfo reports whether the stream carries RTT or cwnd data.
+func (s *StreamResult) HasTCPInfo() bool {
+	return s.MaxRTTUs > 0 || s.MaxCwndBytes > 0
 }
 
 // SentMbps returns the sent throughput in Mbps.
@@ -64,22 +145,39 @@ func (s *StreamResult) ReceivedMbps() float64 {
 	return s.ReceivedBps / 1_000_000
 }
 
+// Latency measurement methods recorded in TestResult.LatencyMethod.
+const (
+	LatencyICMP       = "ICMP ping"
+	LatencyTCPConnect = "TCP connect"
+)
+
 // TestResult holds the parsed output of a single iperf test run.
 type TestResult struct {
 	Timestamp     time.Time
+	StartedAt     time.Time // wall clock when the runner started the test; zero = unknown
+	FinishedAt    time.Time // wall clock when the runner returned
 	ServerAddr    string
+	ServerHostname   string // server as the user gave it (hostname or IP)
+	ServerResolvedIP string // IP actually tested: the parsed connection peer, else a DNS lookup; empty = unknown
 	Port          int
 	Parallel      int
 	Duration      int
+	BytesLimit    string // -n data volume that bounded the test (e.g. "1G"); empty = time-bounded
+	BlocksLimit   int    // -k block/datagram count that bounded the test; 0 = not set
 	BlockSize     int // -l buffer/datagram size in bytes; 0 = iperf default
 	Interval      int
 	Protocol      string
 	MeasurementID string // e.g. "20260218-163958-01"; empty = not set
+	Label         string // free-text tag such as "office-wifi"; empty = none
+	ExtraArgs     []string // extra iperf2 client flags passed through verbatim (-x); nil = none
 	SSHRemoteHost string // remote SSH host if used; empty = local
 	IperfVersion  string // e.g. "3.17"
+	RemoteIperfVersion string // iperf2 version on the SSH-managed host; empty = unknown/local
 	Mode          string // "CLI" or "GUI"
 	LocalHostname string // os.Hostname() at test time
 	LocalIP       string // primary outbound IP at test time; empty = unknown
+	LocalInterface string // network interface holding LocalIP (e.g. "en0"); empty = unknown
+	LocalLinkMbps  int    // LocalInterface link speed; 0 = not reported (WiFi, unknown)
 	SentBps       float64
 	ReceivedBps   float64
 	Retransmits   int
@@ -92,7 +190,19 @@ type TestResult struct {
 	BytesReceived int64  // total bytes received
 	Direction     string // "Reverse", "Bidirectional", or "" (normal)
 	Bandwidth            string // target bandwidth setting used
+	TargetPerStreamMbps  float64 // -b target per stream in Mbps; 0 = unlimited
+	TargetTotalMbps      float64 // TargetPerStreamMbps × streams
 	Congestion           string // congestion algorithm used
+	MSS                  int     // negotiated TCP MSS in bytes; 0 = not reported
+	SndBufBytes          int     // socket send buffer ("TCP window size") in bytes; 0 = not reported
+	OmitSeconds          int     // leading warm-up seconds whose intervals are flagged Omitted
+	AddrFamily           string  // "IPv4" or "IPv6" when forced; empty = resolver default
+	WindowSize           string  // requested -w socket buffer (e.g. "256K"); empty = OS default
+	DSCP                 string  // requested DSCP/ToS marking as given (e.g. "ef", "184"); empty = unmarked
+	BindAddr             string  // -B local bind IP; empty = OS choice
+	NoDelay              bool    // -N: TCP_NODELAY was set on the sending sockets
+	RequestedMSS         int     // -M MSS clamp requested in bytes; 0 = OS default (see MSS for the negotiated value)
+	ConnectTimeoutMs     int     // --connect-timeout requested in ms (TCP); 0 = iperf2 default
 	ReverseSentBps       float64 // bidir reverse: sent bps
 	ReverseReceivedBps   float64 // bidir reverse: received bps
 	ReverseRetransmits   int     // bidir reverse: retransmits
@@ -107,13 +217,23 @@ type TestResult struct {
 	FwdLostPercent       float64 // bidir fwd: UDP lost percent (server-measured)
 	FwdPackets           int     // bidir fwd: UDP total packets (server-measured)
 	ActualDuration       float64         // measured duration from last interval (seconds)
+	ReverseActualDuration float64        // bidir reverse: measured duration from last non-omitted reverse interval; 0 if not bidir
+	SteadyTrim           int             // intervals trimmed from each end for the steady-state figure; 0 = not shown
 	Streams              []StreamResult
 	Intervals            []IntervalResult // forward / single-direction intervals
 	ReverseIntervals     []IntervalResult // bidir reverse-direction intervals (empty if not bidir)
 	PingBaseline         *PingResult
 	PingLoaded           *PingResult
+	PingSkipped          bool   // MeasurePing was on but the baseline ping got no replies
+	LatencyMethod        string // LatencyICMP or LatencyTCPConnect; empty = not measured
 	Error                string
+	Warnings             []string `json:",omitempty"` // non-fatal stderr lines from the local iperf client
+	ServerOutputText     string   `json:",omitempty"` // raw server-side iperf2 output; kept only on request, never in CSV
 	Interrupted          bool // true if test was stopped by user before natural completion
+	Attempts             int  // iperf runs made, including retries; 0 when not tracked
+	SampleCount          int     // runs averaged into this result (-average); 0 = a single run
+	StdDevMbps           float64 // sample stddev of the averaged runs' forward Mbps
+	CPULimitedWarning    bool // more parallel streams than the local CPU count comfortably drives
 	FabricatedServerReport bool // true if UDP Server Report was fabricated (NAT blocked ACK)
 }
 
@@ -180,8 +300,8 @@ func (r *TestResult) FwdActualMbps() float64 {
 }
 
 // ActualJitterMs returns the best available forward jitter in ms.
-// In UDP bidir mode, prefers FwdJitterMs (server-measured via --get-server-output)
-// over JitterMs (which is 0 for the sender side).
+// Prefers FwdJitterMs (measured by the receiving server, from its output file
+// or Server Report) over JitterMs, which is 0 on the sender side.
 func (r *TestResult) ActualJitterMs() float64 {
 	if r.FwdJitterMs > 0 {
 		return r.FwdJitterMs
@@ -199,6 +319,15 @@ func (r *TestResult) ReverseActualMbps() float64 {
 	return r.ReverseSentBps / 1_000_000
 }
 
+// Elapsed returns the wall-clock time the run took, including connection
+// setup and retries, or 0 when StartedAt/FinishedAt were not recorded.
+func (r *TestResult) Elapsed() time.Duration {
+	if r.StartedAt.IsZero() || r.FinishedAt.Before(r.StartedAt) {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
 // Status returns "OK" or the error string.
 func (r *TestResult) Status() string {
 	if r.Error != "" {
@@ -207,13 +336,211 @@ func (r *TestResult) Status() string {
 	return "OK"
 }
 
+// EfficiencyPercent returns the forward throughput as a percentage of
+// TargetTotalMbps, or 0 when no target was set.
+func (r *TestResult) EfficiencyPercent() float64 {
+	if r.TargetTotalMbps <= 0 {
+		return 0
+	}
+	return r.FwdActualMbps() / r.TargetTotalMbps * 100
+}
+
+// AvgPPS returns the forward UDP packet rate over ActualDuration, preferring
+// the server-measured FwdPackets in bidir mode. 0 when the duration is unknown.
+func (r *TestResult) AvgPPS() float64 {
+	if r.ActualDuration <= 0 {
+		return 0
+	}
+	packets := r.Packets
+	if r.Direction == "Bidirectional" && r.FwdPackets > 0 {
+		packets = r.FwdPackets
+	}
+	return float64(packets) / r.ActualDuration
+}
+
+// ReverseAvgPPS returns the reverse UDP packet rate over
+// ReverseActualDuration, or over ActualDuration for results that do not
+// record the reverse one. 0 when the duration is unknown.
+func (r *TestResult) ReverseAvgPPS() float64 {
+	d := r.ReverseActualDuration
+	if d <= 0 {
+		d = r.ActualDuration
+	}
+	if d <= 0 {
+		return 0
+	}
+	return float64(r.ReversePackets) / d
+}
+
+// BufferbloatGrade grades the average latency increase under load from
+// PingBaseline to PingLoaded: A under 5 ms, B under 30 ms, C under 60 ms,
+// D under 200 ms, otherwise F. It is empty unless both pings were measured.
+func (r *TestResult) BufferbloatGrade() string {
+	if r.PingBaseline == nil || r.PingLoaded == nil {
+		return ""
+	}
+	increase := r.PingLoaded.AvgMs - r.PingBaseline.AvgMs
+	switch {
+	case increase < 5:
+		return "A"
+	case increase < 30:
+		return "B"
+	case increase < 60:
+		return "C"
+	case increase < 200:
+		return "D"
+	}
+	return "F"
+}
+
+// MinStatsIntervals is the fewest non-omitted intervals IntervalStats will
+// summarise; below it a spread figure says little about link stability.
+const MinStatsIntervals = 3
+
+// IntervalStats returns the mean, sample standard deviation, minimum and
+// maximum forward interval throughput in Mbps, skipping omitted intervals.
+// All values are zero with fewer than MinStatsIntervals intervals.
+func (r *TestResult) IntervalStats() (mean, stddev, minV, maxV float64) {
+	return intervalStats(r.Intervals)
+}
+
+// ReverseIntervalStats is IntervalStats over the bidir ReverseIntervals.
+func (r *TestResult) ReverseIntervalStats() (mean, stddev, minV, maxV float64) {
+	return intervalStats(r.ReverseIntervals)
+}
+
+func intervalStats(intervals []IntervalResult) (mean, stddev, minV, maxV float64) {
+	var vals []float64
+	for i := range intervals {
+		if !intervals[i].Omitted {
+			vals = append(vals, intervals[i].BandwidthMbps())
+		}
+	}
+	if len(vals) < MinStatsIntervals {
+		return 0, 0, 0, 0
+	}
+	minV, maxV = vals[0], vals[0]
+	var sum float64
+	for _, v := range vals {
+		sum += v
+		minV = math.Min(minV, v)
+		maxV = math.Max(maxV, v)
+	}
+	n := float64(len(vals))
+	mean = sum / n
+	var sq float64
+	for _, v := range vals {
+		sq += (v - mean) * (v - mean)
+	}
+	stddev = math.Sqrt(sq / (n - 1))
+	return mean, stddev, minV, maxV
+}
+
+// SteadyStateMbps returns the mean forward interval throughput in Mbps with
+// the first skipFirst and last skipLast non-omitted intervals dropped, which
+// trims TCP ramp-up and teardown edge effects. Zero when nothing is left.
+func (r *TestResult) SteadyStateMbps(skipFirst, skipLast int) float64 {
+	return steadyState(r.Intervals, skipFirst, skipLast)
+}
+
+// ReverseSteadyStateMbps is SteadyStateMbps over the bidir ReverseIntervals.
+func (r *TestResult) ReverseSteadyStateMbps(skipFirst, skipLast int) float64 {
+	return steadyState(r.ReverseIntervals, skipFirst, skipLast)
+}
+
+func steadyState(intervals []IntervalResult, skipFirst, skipLast int) float64 {
+	var vals []float64
+	for i := range intervals {
+		if !intervals[i].Omitted {
+			vals = append(vals, intervals[i].BandwidthMbps())
+		}
+	}
+	skipFirst, skipLast = max(skipFirst, 0), max(skipLast, 0)
+	if skipFirst+skipLast >= len(vals) {
+		return 0
+	}
+	vals = vals[skipFirst : len(vals)-skipLast]
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	return sum / float64(len(vals))
+}
+
+// RunLabel describes what bounded the test: "Transferred 1G" for a -n run,
+// "Sent 1000 blocks" for -k, otherwise "Ran 10s".
+func (r *TestResult) RunLabel() string {
+	switch {
+	case r.BytesLimit != "":
+		return "Transferred " + r.BytesLimit
+	case r.BlocksLimit > 0:
+		return fmt.Sprintf("Sent %d blocks", r.BlocksLimit)
+	}
+	return fmt.Sprintf("Ran %ds", r.Duration)
+}
+
+// FairnessIndex returns Jain's fairness index over the forward streams'
+// SentBps: 1.0 means every stream got the same share, 1/n means one stream
+// took everything. A single stream is trivially fair (1.0); no streams or
+// no traffic returns 0. Reverse streams of a bidir test are left out.
+func (r *TestResult) FairnessIndex() float64 {
+	isBidir := r.Direction == "Bidirectional"
+	var n int
+	var sum, sumSq float64
+	for _, s := range r.Streams {
+		if isBidir && !s.Sender {
+			continue
+		}
+		n++
+		sum += s.SentBps
+		sumSq += s.SentBps * s.SentBps
+	}
+	if n == 1 {
+		return 1
+	}
+	if n == 0 || sumSq == 0 {
+		return 0
+	}
+	return sum * sum / (float64(n) * sumSq)
+}
+
+// BestStream returns the forward stream with the highest SentBps, the
+// first one on a tie, or nil when there are no forward streams. Reverse
+// streams of a bidir test are left out, as in FairnessIndex.
+func (r *TestResult) BestStream() *StreamResult {
+	return r.pickStream(func(a, b float64) bool { return a > b })
+}
+
+// WorstStream is BestStream for the lowest SentBps.
+func (r *TestResult) WorstStream() *StreamResult {
+	return r.pickStream(func(a, b float64) bool { return a < b })
+}
+
+func (r *TestResult) pickStream(better func(a, b float64) bool) *StreamResult {
+	isBidir := r.Direction == "Bidirectional"
+	var pick *StreamResult
+	for i := range r.Streams {
+		s := &r.Streams[i]
+		if isBidir && !s.Sender {
+			continue
+		}
+		if pick == nil || better(s.SentBps, pick.SentBps) {
+			pick = s
+		}
+	}
+	return pick
+}
+
 // VerifyStreamTotals checks that the sum of per-stream bps matches summary
-// values within 0.1% tolerance. Returns (sentOK, recvOK).
+// values within 1% tolerance; iperf2 prints rates to three significant
+// digits, so stream rows and the SUM line differ by rounding alone. Streams
+// parsed from the sending client carry no received rate, so recvOK is true
+// when none of them has one. Returns (sentOK, recvOK).
 func (r *TestResult) VerifyStreamTotals() (sentOK, recvOK bool) {
 	if len(r.Streams) == 0 {
 		return true, true
 	}
-	tolerance := 0.001
+	tolerance := 0.01
 
 	// UDP streams have a single "udp" entry with no separate sent/received.
 	// Compare the stream's SentBps against the summary SentBps.
@@ -244,6 +571,6 @@ func (r *TestResult) VerifyStreamTotals() (sentOK, recvOK bool) {
 		recvSum += s.ReceivedBps
 	}
 	sentOK = r.SentBps == 0 || math.Abs(sentSum-r.SentBps)/r.SentBps <= tolerance
-	recvOK = r.ReceivedBps == 0 || math.Abs(recvSum-r.ReceivedBps)/r.ReceivedBps <= tolerance
+	recvOK = r.ReceivedBps == 0 || recvSum == 0 || math.Abs(recvSum-r.ReceivedBps)/r.ReceivedBps <= tolerance
 	return sentOK, recvOK
 }
@@ -0,0 +1,294 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func mbpsIntervals(vals ...float64) []IntervalResult {
+	out := make([]IntervalResult, len(vals))
+	for i, v := range vals {
+		out[i] = IntervalResult{TimeStart: float64(i), TimeEnd: float64(i + 1), BandwidthBps: v * 1e6}
+	}
+	return out
+}
+
+func TestIntervalStats(t *testing.T) {
+	// 2, 4, 4, 4, 5, 5, 7, 9: mean 5, sample variance 32/7.
+	r := &TestResult{Intervals: mbpsIntervals(2, 4, 4, 4, 5, 5, 7, 9)}
+	mean, stddev, minV, maxV := r.IntervalStats()
+	if math.Abs(mean-5) > 1e-9 {
+		t.Errorf("mean = %v, want 5", mean)
+	}
+	if want := math.Sqrt(32.0 / 7); math.Abs(stddev-want) > 1e-9 {
+		t.Errorf("stddev = %v, want %v", stddev, want)
+	}
+	if minV != 2 || maxV != 9 {
+		t.Errorf("min/max = %v/%v, want 2/9", minV, maxV)
+	}
+}
+
+func TestIntervalStats_SkipsOmittedAndShortRuns(t *testing.T) {
+	ivs := mbpsIntervals(100, 10, 10, 10)
+	ivs[0].Omitted = true
+	r := &TestResult{Intervals: ivs}
+	mean, stddev, minV, maxV := r.IntervalStats()
+	if mean != 10 || stddev != 0 || minV != 10 || maxV != 10 {
+		t.Errorf("stats = %v±%v (%v-%v), want 10±0 (10-10)", mean, stddev, minV, maxV)
+	}
+
+	r.Intervals = r.Intervals[:3] // two usable intervals
+	if mean, _, _, _ := r.IntervalStats(); mean != 0 {
+		t.Errorf("mean = %v, want 0 below MinStatsIntervals", mean)
+	}
+}
+
+func TestReverseIntervalStats(t *testing.T) {
+	r := &TestResult{
+		Intervals:        mbpsIntervals(1, 2, 3),
+		ReverseIntervals: mbpsIntervals(10, 20, 30),
+	}
+	if mean, stddev, _, _ := r.ReverseIntervalStats(); mean != 20 || math.Abs(stddev-10) > 1e-9 {
+		t.Errorf("reverse = %v±%v, want 20±10", mean, stddev)
+	}
+}
+
+func TestElapsed(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := &TestResult{StartedAt: start, FinishedAt: start.Add(12500 * time.Millisecond)}
+	if got := r.Elapsed(); got != 12500*time.Millisecond {
+		t.Errorf("Elapsed() = %v, want 12.5s", got)
+	}
+	if got := (&TestResult{FinishedAt: start}).Elapsed(); got != 0 {
+		t.Errorf("Elapsed() without StartedAt = %v, want 0", got)
+	}
+}
+
+func TestBufferbloatGrade(t *testing.T) {
+	tests := []struct {
+		loadedMs float64
+		want     string
+	}{
+		{8, "A"}, // decrease under load
+		{14.99, "A"},
+		{15, "B"},
+		{39.99, "B"},
+		{40, "C"},
+		{69.99, "C"},
+		{70, "D"},
+		{209.99, "D"},
+		{210, "F"},
+	}
+	for _, tt := range tests {
+		r := &TestResult{
+			PingBaseline: &PingResult{AvgMs: 10},
+			PingLoaded:   &PingResult{AvgMs: tt.loadedMs},
+		}
+		if got := r.BufferbloatGrade(); got != tt.want {
+			t.Errorf("BufferbloatGrade() loaded=%.2f = %q, want %q", tt.loadedMs, got, tt.want)
+		}
+	}
+
+	for _, r := range []*TestResult{
+		{},
+		{PingBaseline: &PingResult{AvgMs: 10}},
+		{PingLoaded: &PingResult{AvgMs: 300}},
+	} {
+		if got := r.BufferbloatGrade(); got != "" {
+			t.Errorf("BufferbloatGrade() without both pings = %q, want empty", got)
+		}
+	}
+}
+
+func TestEfficiencyPercent(t *testing.T) {
+	tests := []struct {
+		name string
+		r    TestResult
+		want float64
+	}{
+		{"full delivery", TestResult{FwdReceivedBps: 100e6, TargetTotalMbps: 100}, 100},
+		{"partial delivery", TestResult{FwdReceivedBps: 94e6, SentBps: 100e6, TargetTotalMbps: 100}, 94},
+		{"sender rate fallback", TestResult{SentBps: 50e6, TargetTotalMbps: 200}, 25},
+		{"unset target", TestResult{FwdReceivedBps: 940e6}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.r.EfficiencyPercent(); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: EfficiencyPercent() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPacketsPerSecond(t *testing.T) {
+	iv := IntervalResult{TimeStart: 2, TimeEnd: 2.5, Packets: 450}
+	if got := iv.PacketsPerSecond(); got != 900 {
+		t.Errorf("PacketsPerSecond() = %v, want 900", got)
+	}
+	zero := IntervalResult{TimeStart: 3, TimeEnd: 3, Packets: 10}
+	if got := zero.PacketsPerSecond(); got != 0 {
+		t.Errorf("zero-length PacketsPerSecond() = %v, want 0", got)
+	}
+}
+
+func TestAvgPPS(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        TestResult
+		fwd, rev float64
+	}{
+		{"udp forward", TestResult{Packets: 8930, ActualDuration: 10}, 893, 0},
+		{"bidir prefers server count", TestResult{Direction: "Bidirectional", Packets: 1, FwdPackets: 2000, ReversePackets: 1500, ActualDuration: 5}, 400, 300},
+		{"bidir reverse ends early", TestResult{Direction: "Bidirectional", FwdPackets: 2000, ReversePackets: 1500, ActualDuration: 5, ReverseActualDuration: 3}, 400, 500},
+		{"unknown duration", TestResult{Packets: 8930}, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.r.AvgPPS(); math.Abs(got-tt.fwd) > 1e-9 {
+			t.Errorf("%s: AvgPPS() = %v, want %v", tt.name, got, tt.fwd)
+		}
+		if got := tt.r.ReverseAvgPPS(); math.Abs(got-tt.rev) > 1e-9 {
+			t.Errorf("%s: ReverseAvgPPS() = %v, want %v", tt.name, got, tt.rev)
+		}
+	}
+}
+
+func TestSteadyStateMbps(t *testing.T) {
+	// Ramp up over two intervals, plateau at 900, ramp down over one.
+	r := &TestResult{Intervals: mbpsIntervals(100, 500, 900, 900, 900, 900, 300)}
+	if got := r.SteadyStateMbps(2, 1); math.Abs(got-900) > 1e-9 {
+		t.Errorf("SteadyStateMbps(2, 1) = %v, want 900", got)
+	}
+	if got, want := r.SteadyStateMbps(0, 0), 4500.0/7; math.Abs(got-want) > 1e-9 {
+		t.Errorf("SteadyStateMbps(0, 0) = %v, want %v", got, want)
+	}
+	if got := r.SteadyStateMbps(4, 3); got != 0 {
+		t.Errorf("SteadyStateMbps(4, 3) = %v, want 0 when everything is trimmed", got)
+	}
+}
+
+func TestSteadyStateMbps_SkipsOmitted(t *testing.T) {
+	// The omitted warm-up interval is not counted towards skipFirst.
+	ivs := mbpsIntervals(10, 400, 800, 800, 200)
+	ivs[0].Omitted = true
+	r := &TestResult{Intervals: ivs, ReverseIntervals: mbpsIntervals(50, 100, 100, 50)}
+	if got := r.SteadyStateMbps(1, 1); math.Abs(got-800) > 1e-9 {
+		t.Errorf("SteadyStateMbps(1, 1) = %v, want 800", got)
+	}
+	if got := r.ReverseSteadyStateMbps(1, 1); math.Abs(got-100) > 1e-9 {
+		t.Errorf("ReverseSteadyStateMbps(1, 1) = %v, want 100", got)
+	}
+}
+
+func TestFairnessIndex(t *testing.T) {
+	streams := func(bps ...float64) []StreamResult {
+		var out []StreamResult
+		for i, b := range bps {
+			out = append(out, StreamResult{ID: i + 1, SentBps: b, Sender: true})
+		}
+		return out
+	}
+	tests := []struct {
+		name string
+		r    TestResult
+		want float64
+	}{
+		{"equal", TestResult{Streams: streams(25e6, 25e6, 25e6, 25e6)}, 1},
+		{"one dominant", TestResult{Streams: streams(97e6, 1e6, 1e6, 1e6)}, 0.2656},
+		{"one of two idle", TestResult{Streams: streams(50e6, 0)}, 0.5},
+		{"single stream", TestResult{Streams: streams(90e6)}, 1},
+		{"no streams", TestResult{}, 0},
+		{"no traffic", TestResult{Streams: streams(0, 0)}, 0},
+		{"bidir ignores reverse", TestResult{Direction: "Bidirectional", Streams: []StreamResult{
+			{ID: 1, SentBps: 40e6, Sender: true},
+			{ID: 2, SentBps: 40e6, Sender: true},
+			{ID: 3, SentBps: 5e6, Sender: false},
+		}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.FairnessIndex(); math.Abs(got-tt.want) > 0.0001 {
+				t.Errorf("FairnessIndex() = %.4f, want %.4f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBestWorstStream(t *testing.T) {
+	streams := func(bps ...float64) []StreamResult {
+		var out []StreamResult
+		for i, b := range bps {
+			out = append(out, StreamResult{ID: i + 1, SentBps: b, Sender: true})
+		}
+		return out
+	}
+	tests := []struct {
+		name        string
+		r           TestResult
+		best, worst int // stream IDs; 0 = nil
+	}{
+		{"spread", TestResult{Streams: streams(90e6, 180e6, 250e6, 120e6)}, 3, 1},
+		{"tie picks first", TestResult{Streams: streams(100e6, 200e6, 200e6, 100e6)}, 2, 1},
+		{"single stream", TestResult{Streams: streams(90e6)}, 1, 1},
+		{"no streams", TestResult{}, 0, 0},
+		{"bidir forward only", TestResult{Direction: "Bidirectional", Streams: []StreamResult{
+			{ID: 1, SentBps: 40e6, Sender: true},
+			{ID: 2, SentBps: 500e6, Sender: false},
+			{ID: 3, SentBps: 60e6, Sender: true},
+			{ID: 4, SentBps: 1e6, Sender: false},
+		}}, 3, 1},
+	}
+	id := func(s *StreamResult) int {
+		if s == nil {
+			return 0
+		}
+		return s.ID
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := id(tt.r.BestStream()); got != tt.best {
+				t.Errorf("BestStream() = stream %d, want %d", got, tt.best)
+			}
+			if got := id(tt.r.WorstStream()); got != tt.worst {
+				t.Errorf("WorstStream() = stream %d, want %d", got, tt.worst)
+			}
+		})
+	}
+}
+
+func TestRunLabel(t *testing.T) {
+	tests := []struct {
+		r    TestResult
+		want string
+	}{
+		{TestResult{Duration: 10}, "Ran 10s"},
+		{TestResult{Duration: 10, BytesLimit: "1G"}, "Transferred 1G"},
+		{TestResult{Duration: 10, BlocksLimit: 5000}, "Sent 5000 blocks"},
+	}
+	for _, tt := range tests {
+		if got := tt.r.RunLabel(); got != tt.want {
+			t.Errorf("RunLabel() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestSuspectIntervals(t *testing.T) {
+	ivs := func(mbps ...float64) []IntervalResult {
+		var out []IntervalResult
+		for i, m := range mbps {
+			out = append(out, IntervalResult{TimeStart: float64(i), TimeEnd: float64(i + 1), BandwidthBps: m * 1e6})
+		}
+		return out
+	}
+	r := TestResult{
+		Intervals:        ivs(940, 945, 3800, 938, 1150, 1100, 941), // 3800 and 1150 exceed 1.1 × 1000
+		ReverseIntervals: ivs(900, 1099, 2500),
+	}
+	if got := r.SuspectIntervals(1000); got != 3 {
+		t.Errorf("SuspectIntervals(1000) = %d, want 3", got)
+	}
+	if got := r.SuspectIntervals(10000); got != 0 {
+		t.Errorf("SuspectIntervals(10000) = %d, want 0", got)
+	}
+	if got := r.SuspectIntervals(0); got != 0 {
+		t.Errorf("SuspectIntervals(0) = %d, want 0 for an unknown link rate", got)
+	}
+}
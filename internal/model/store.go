@@ -0,0 +1,117 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// DefaultStoreCap is the number of results a ResultStore keeps when no
+// capacity is given.
+const DefaultStoreCap = 100
+
+// ResultStore keeps the most recent test results in memory, up to a fixed
+// capacity, and can persist them as a JSON array. It is safe for concurrent
+// use.
+type ResultStore struct {
+	mu       sync.Mutex
+	results  []TestResult // oldest first
+	capacity int
+}
+
+// NewResultStore returns an empty store holding at most capacity results;
+// capacity < 1 means DefaultStoreCap.
+func NewResultStore(capacity int) *ResultStore {
+	if capacity < 1 {
+		capacity = DefaultStoreCap
+	}
+	return &ResultStore{capacity: capacity}
+}
+
+// Add stores a copy of r, dropping the oldest result once the store is full.
+func (s *ResultStore) Add(r *TestResult) {
+	if r == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, *r)
+	s.trim()
+}
+
+// Recent returns up to n results, newest first; n < 1 returns all of them.
+func (s *ResultStore) Recent(n int) []TestResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 1 || n > len(s.results) {
+		n = len(s.results)
+	}
+	out := make([]TestResult, 0, n)
+	for i := len(s.results) - 1; i >= len(s.results)-n; i-- {
+		out = append(out, s.results[i])
+	}
+	return out
+}
+
+// Len returns the number of stored results.
+func (s *ResultStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.results)
+}
+
+// Save writes the stored results to path as a JSON array, oldest first.
+func (s *ResultStore) Save(path string) error {
+	s.mu.Lock()
+	results := s.results
+	if results == nil {
+		results = []TestResult{}
+	}
+	data, err := json.Marshal(results)
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encode result store: %w", err)
+	}
+
+	// Write to a temp file and rename so a crash never leaves a truncated file.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write result store: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write result store: %w", err)
+	}
+	return nil
+}
+
+// Load replaces the stored results with those saved at path, keeping only
+// the newest ones that fit. A missing file leaves the store empty.
+func (s *ResultStore) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read result store: %w", err)
+	}
+	var results []TestResult
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &results); err != nil {
+			return fmt.Errorf("decode result store: %w", err)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = results
+	s.trim()
+	return nil
+}
+
+// trim drops the oldest results beyond capacity. Callers hold s.mu.
+func (s *ResultStore) trim() {
+	if extra := len(s.results) - s.capacity; extra > 0 {
+		s.results = append([]TestResult(nil), s.results[extra:]...)
+	}
+}
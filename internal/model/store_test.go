@@ -0,0 +1,94 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResultStore_AddRecent(t *testing.T) {
+	s := NewResultStore(3)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		s.Add(&TestResult{MeasurementID: id})
+	}
+	s.Add(nil)
+
+	if s.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3 (capped)", s.Len())
+	}
+	got := s.Recent(0)
+	want := []string{"d", "c", "b"}
+	for i, r := range got {
+		if r.MeasurementID != want[i] {
+			t.Errorf("Recent(0)[%d] = %q, want %q", i, r.MeasurementID, want[i])
+		}
+	}
+	if got := s.Recent(2); len(got) != 2 || got[0].MeasurementID != "d" || got[1].MeasurementID != "c" {
+		t.Errorf("Recent(2) = %v, want d, c", got)
+	}
+	if got := s.Recent(10); len(got) != 3 {
+		t.Errorf("Recent(10) returned %d results, want 3", len(got))
+	}
+}
+
+func TestNewResultStore_DefaultCap(t *testing.T) {
+	s := NewResultStore(0)
+	for i := 0; i < DefaultStoreCap+5; i++ {
+		s.Add(&TestResult{Port: i})
+	}
+	if s.Len() != DefaultStoreCap {
+		t.Errorf("Len() = %d, want %d", s.Len(), DefaultStoreCap)
+	}
+	if got := s.Recent(1)[0].Port; got != DefaultStoreCap+4 {
+		t.Errorf("newest Port = %d, want %d", got, DefaultStoreCap+4)
+	}
+}
+
+func TestResultStore_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	s := NewResultStore(5)
+	s.Add(&TestResult{MeasurementID: "a", ServerAddr: "10.0.0.1", SentBps: 940_000_000})
+	s.Add(&TestResult{MeasurementID: "b", PingBaseline: &PingResult{AvgMs: 1.5}})
+	if err := s.Save(path); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded := NewResultStore(5)
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	got := loaded.Recent(0)
+	if len(got) != 2 || got[0].MeasurementID != "b" || got[1].MeasurementID != "a" {
+		t.Fatalf("Recent(0) after Load = %v, want b, a", got)
+	}
+	if got[1].SentBps != 940_000_000 || got[0].PingBaseline == nil || got[0].PingBaseline.AvgMs != 1.5 {
+		t.Errorf("fields not restored: %+v", got)
+	}
+
+	// A smaller store keeps only the newest results.
+	small := NewResultStore(1)
+	if err := small.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := small.Recent(0); len(got) != 1 || got[0].MeasurementID != "b" {
+		t.Errorf("capped Load = %v, want only b", got)
+	}
+}
+
+func TestResultStore_LoadMissingAndCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	s := NewResultStore(5)
+	if err := s.Load(filepath.Join(dir, "missing.json")); err != nil {
+		t.Errorf("Load() of a missing file error: %v", err)
+	}
+	if s.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", s.Len())
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(bad, []byte("not json"), 0644)
+	if err := s.Load(bad); err == nil {
+		t.Error("expected error for a corrupt file")
+	}
+}
@@ -0,0 +1,109 @@
+package netutil
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"iperf-tool/internal/model"
+)
+
+// ActiveInterface returns the interface carrying the preferred outbound
+// route (see OutboundIP) and its link speed in Mbps; 0 when the OS does not
+// report one, as for most WiFi adapters.
+func ActiveInterface() (name string, speedMbps int, err error) {
+	ip := OutboundIP()
+	if ip == "" {
+		return "", 0, fmt.Errorf("no outbound IP")
+	}
+	return InterfaceFor(ip)
+}
+
+// InterfaceFor returns the interface holding ip and its link speed in Mbps.
+func InterfaceFor(ip string) (name string, speedMbps int, err error) {
+	want := net.ParseIP(ip)
+	if want == nil {
+		return "", 0, fmt.Errorf("invalid IP %q", ip)
+	}
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", 0, fmt.Errorf("list interfaces: %w", err)
+	}
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			if n, ok := a.(*net.IPNet); ok && n.IP.Equal(want) {
+				return iface.Name, linkSpeedMbps(iface.Name), nil
+			}
+		}
+	}
+	return "", 0, fmt.Errorf("no interface has address %s", ip)
+}
+
+// RecordInterface stores the interface behind result.LocalIP and its link
+// speed on result. It leaves both empty when the interface cannot be found.
+func RecordInterface(result *model.TestResult) {
+	if result.LocalIP == "" {
+		return
+	}
+	if name, speed, err := InterfaceFor(result.LocalIP); err == nil {
+		result.LocalInterface = name
+		result.LocalLinkMbps = speed
+	}
+}
+
+func linkSpeedMbps(name string) int {
+	switch runtime.GOOS {
+	case "linux":
+		return sysLinkSpeed("/sys", name)
+	case "darwin", "freebsd", "openbsd", "netbsd":
+		out, err := exec.Command("ifconfig", name).Output()
+		if err != nil {
+			return 0
+		}
+		return ifconfigLinkSpeed(string(out))
+	}
+	return 0
+}
+
+// sysLinkSpeed reads <root>/class/net/<name>/speed. Wireless and down links
+// report -1 or fail to read, which both give 0.
+func sysLinkSpeed(root, name string) int {
+	data, err := os.ReadFile(filepath.Join(root, "class", "net", name, "speed"))
+	if err != nil {
+		return 0
+	}
+	speed, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || speed <= 0 {
+		return 0
+	}
+	return speed
+}
+
+// mediaRe matches the negotiated rate in an ifconfig media line.
+// Example: "media: autoselect (1000baseT <full-duplex>)"
+// Example: "media: autoselect (10Gbase-T <full-duplex>)"
+var mediaRe = regexp.MustCompile(`media:.*\((\d+)(G?)base`)
+
+// ifconfigLinkSpeed parses the link speed in Mbps from BSD/macOS ifconfig
+// output; 0 when the media line names no rate (WiFi, inactive links).
+func ifconfigLinkSpeed(output string) int {
+	m := mediaRe.FindStringSubmatch(output)
+	if m == nil {
+		return 0
+	}
+	speed, _ := strconv.Atoi(m[1])
+	if m[2] == "G" {
+		speed *= 1000
+	}
+	return speed
+}
@@ -0,0 +1,71 @@
+package netutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSysLinkSpeed(t *testing.T) {
+	root := t.TempDir()
+	for name, speed := range map[string]string{
+		"eth0":  "1000\n",
+		"eth1":  "10000\n",
+		"wlan0": "-1\n",
+		"bad0":  "fast\n",
+	} {
+		dir := filepath.Join(root, "class", "net", name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "speed"), []byte(speed), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		want int
+	}{
+		{"eth0", 1000},
+		{"eth1", 10000},
+		{"wlan0", 0},
+		{"bad0", 0},
+		{"missing0", 0},
+	}
+	for _, tt := range tests {
+		if got := sysLinkSpeed(root, tt.name); got != tt.want {
+			t.Errorf("sysLinkSpeed(%q) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIfconfigLinkSpeed(t *testing.T) {
+	tests := []struct {
+		name, output string
+		want         int
+	}{
+		{"gigabit", "en0: flags=8863<UP>\n\tmedia: autoselect (1000baseT <full-duplex>)\n\tstatus: active\n", 1000},
+		{"10G", "en1: flags=8863<UP>\n\tmedia: autoselect (10Gbase-T <full-duplex>)\n", 10000},
+		{"wifi", "en0: flags=8863<UP>\n\tmedia: autoselect\n\tstatus: active\n", 0},
+		{"no media", "lo0: flags=8049<UP,LOOPBACK>\n", 0},
+	}
+	for _, tt := range tests {
+		if got := ifconfigLinkSpeed(tt.output); got != tt.want {
+			t.Errorf("%s: ifconfigLinkSpeed() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestInterfaceFor_Loopback(t *testing.T) {
+	name, _, err := InterfaceFor("127.0.0.1")
+	if err != nil {
+		t.Skipf("no loopback interface: %v", err)
+	}
+	if name == "" {
+		t.Error("InterfaceFor(127.0.0.1) returned an empty name")
+	}
+	if _, _, err := InterfaceFor("not-an-ip"); err == nil {
+		t.Error("expected error for an invalid IP")
+	}
+}
@@ -0,0 +1,21 @@
+package netutil
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// ProbePort checks that something accepts TCP connections on host:port. It is
+// a cheap preflight before a full iperf2 run, so an absent server fails fast
+// with a clear message instead of iperf2's own connect error.
+func ProbePort(host string, port int, timeout time.Duration) error {
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return fmt.Errorf("cannot reach iperf2 server at %s (is it running?): %w", addr, err)
+	}
+	conn.Close()
+	return nil
+}
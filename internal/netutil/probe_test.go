@@ -0,0 +1,47 @@
+package netutil
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProbePort_Open(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	if err := ProbePort("127.0.0.1", port, time.Second); err != nil {
+		t.Errorf("ProbePort() error: %v", err)
+	}
+}
+
+func TestProbePort_Closed(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close() // nothing listens here any more
+
+	err = ProbePort("127.0.0.1", port, 500*time.Millisecond)
+	if err == nil {
+		t.Fatal("expected error for a closed port")
+	}
+	if !strings.Contains(err.Error(), "is it running?") {
+		t.Errorf("error = %q, want the reachability hint", err)
+	}
+}
@@ -0,0 +1,49 @@
+package netutil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+// resolveTimeout bounds the DNS lookup in Resolve.
+const resolveTimeout = 2 * time.Second
+
+// Resolve returns the IP address host resolves to, preferring IPv4 as
+// iperf2 does without -V. An IP literal is returned unchanged.
+func Resolve(host string) (string, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", host, err)
+	}
+	if len(addrs) == 0 {
+		return "", fmt.Errorf("resolve %s: no addresses", host)
+	}
+	for _, a := range addrs {
+		if a.IP.To4() != nil {
+			return a.IP.String(), nil
+		}
+	}
+	return addrs[0].IP.String(), nil
+}
+
+// RecordServer stores the server as given and the IP it resolved to on
+// result. An address already parsed from iperf2's connection line wins over
+// a fresh lookup, since that is the one the test actually used.
+func RecordServer(result *model.TestResult, host string) {
+	result.ServerHostname = host
+	if result.ServerResolvedIP != "" {
+		return
+	}
+	if ip, err := Resolve(host); err == nil {
+		result.ServerResolvedIP = ip
+	}
+}
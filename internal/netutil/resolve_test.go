@@ -0,0 +1,38 @@
+package netutil
+
+import (
+	"net"
+	"testing"
+
+	"iperf-tool/internal/model"
+)
+
+func TestResolve_Localhost(t *testing.T) {
+	ip, err := Resolve("localhost")
+	if err != nil {
+		t.Skipf("no resolver for localhost: %v", err)
+	}
+	if parsed := net.ParseIP(ip); parsed == nil || !parsed.IsLoopback() {
+		t.Errorf("Resolve(localhost) = %q, want a loopback address", ip)
+	}
+}
+
+func TestResolve_Literal(t *testing.T) {
+	if ip, err := Resolve("10.0.0.1"); err != nil || ip != "10.0.0.1" {
+		t.Errorf("Resolve(10.0.0.1) = %q, %v", ip, err)
+	}
+}
+
+func TestRecordServer(t *testing.T) {
+	r := &model.TestResult{}
+	RecordServer(r, "127.0.0.1")
+	if r.ServerHostname != "127.0.0.1" || r.ServerResolvedIP != "127.0.0.1" {
+		t.Errorf("RecordServer() = %q/%q", r.ServerHostname, r.ServerResolvedIP)
+	}
+
+	parsed := &model.TestResult{ServerResolvedIP: "10.0.0.9"}
+	RecordServer(parsed, "localhost")
+	if parsed.ServerResolvedIP != "10.0.0.9" {
+		t.Errorf("parsed peer should win, got %q", parsed.ServerResolvedIP)
+	}
+}
@@ -0,0 +1,51 @@
+package netutil
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"iperf-tool/internal/model"
+)
+
+// tcpConnectTimeout bounds each dial made by TCPConnectTime.
+var tcpConnectTimeout = 2 * time.Second
+
+// TCPConnectTime measures the TCP handshake time to host:port by dialling it
+// samples times. Unlike ICMP ping it works wherever the iperf port is
+// reachable. Failed dials count as lost samples; it is an error only when
+// none succeed.
+func TCPConnectTime(host string, port, samples int) (*model.PingResult, error) {
+	if samples < 1 {
+		samples = 1
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	r := &model.PingResult{PacketsSent: samples}
+	var sum float64
+	var lastErr error
+	for i := 0; i < samples; i++ {
+		start := time.Now()
+		conn, err := net.DialTimeout("tcp", addr, tcpConnectTimeout)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		ms := float64(time.Since(start).Microseconds()) / 1000
+		conn.Close()
+		if r.PacketsRecv == 0 || ms < r.MinMs {
+			r.MinMs = ms
+		}
+		if ms > r.MaxMs {
+			r.MaxMs = ms
+		}
+		sum += ms
+		r.PacketsRecv++
+	}
+	if r.PacketsRecv == 0 {
+		return nil, fmt.Errorf("tcp connect to %s: %w", addr, lastErr)
+	}
+	r.AvgMs = sum / float64(r.PacketsRecv)
+	r.PacketLoss = float64(samples-r.PacketsRecv) / float64(samples) * 100
+	return r, nil
+}
@@ -0,0 +1,53 @@
+package netutil
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTCPConnectTime_LocalListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	r, err := TCPConnectTime("127.0.0.1", port, 3)
+	if err != nil {
+		t.Fatalf("TCPConnectTime() error: %v", err)
+	}
+	if r.PacketsSent != 3 || r.PacketsRecv != 3 || r.PacketLoss != 0 {
+		t.Errorf("sent/recv/loss = %d/%d/%.0f, want 3/3/0", r.PacketsSent, r.PacketsRecv, r.PacketLoss)
+	}
+	if r.MinMs > r.AvgMs || r.AvgMs > r.MaxMs {
+		t.Errorf("min/avg/max out of order: %.3f/%.3f/%.3f", r.MinMs, r.AvgMs, r.MaxMs)
+	}
+}
+
+func TestTCPConnectTime_Refused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close() // nothing listens here any more
+
+	orig := tcpConnectTimeout
+	tcpConnectTimeout = 500 * time.Millisecond
+	defer func() { tcpConnectTimeout = orig }()
+
+	if _, err := TCPConnectTime("127.0.0.1", port, 2); err == nil {
+		t.Error("expected error when every dial fails")
+	}
+}
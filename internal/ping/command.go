@@ -0,0 +1,54 @@
+package ping
+
+import (
+	"context"
+	"net/netip"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// command builds the ping invocation for host on this OS. count > 0 sends
+// that many echoes; count <= 0 pings until the context is cancelled.
+func command(ctx context.Context, host string, count int) *exec.Cmd {
+	name, args := commandArgs(runtime.GOOS, host, count)
+	return exec.CommandContext(ctx, name, args...)
+}
+
+// commandArgs returns the ping binary and arguments for goos. IPv6 literals
+// get "ping -6" on Linux and Windows and "ping6" on macOS and the BSDs,
+// whose ping only speaks IPv4; hostnames are left to ping's own resolver.
+func commandArgs(goos, host string, count int) (string, []string) {
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	v6 := isIPv6(host)
+
+	name, args := "ping", []string(nil)
+	switch {
+	case goos == "windows":
+		if v6 {
+			args = append(args, "-6")
+		}
+		if count > 0 {
+			args = append(args, "-n", strconv.Itoa(count))
+		} else {
+			args = append(args, "-t")
+		}
+		return name, append(args, host)
+	case v6 && goos == "linux":
+		args = append(args, "-6")
+	case v6:
+		name = "ping6"
+	}
+	if count > 0 {
+		args = append(args, "-c", strconv.Itoa(count))
+	}
+	return name, append(args, host)
+}
+
+// isIPv6 reports whether host is an IPv6 address literal, with or without a
+// zone ("fe80::1%eth0"). IPv4-mapped addresses count as IPv4.
+func isIPv6(host string) bool {
+	addr, err := netip.ParseAddr(host)
+	return err == nil && addr.Is6() && !addr.Is4In6()
+}
@@ -0,0 +1,33 @@
+package ping
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommandArgs(t *testing.T) {
+	tests := []struct {
+		goos, host string
+		count      int
+		want       string
+	}{
+		{"linux", "192.168.1.1", 4, "ping -c 4 192.168.1.1"},
+		{"linux", "2001:db8::1", 4, "ping -6 -c 4 2001:db8::1"},
+		{"linux", "[2001:db8::1]", 0, "ping -6 2001:db8::1"},
+		{"linux", "fe80::1%eth0", 3, "ping -6 -c 3 fe80::1%eth0"},
+		{"linux", "::ffff:10.0.0.1", 4, "ping -c 4 ::ffff:10.0.0.1"},
+		{"linux", "server.example.com", 4, "ping -c 4 server.example.com"},
+		{"darwin", "10.0.0.1", 4, "ping -c 4 10.0.0.1"},
+		{"darwin", "2001:db8::1", 4, "ping6 -c 4 2001:db8::1"},
+		{"freebsd", "2001:db8::1", 0, "ping6 2001:db8::1"},
+		{"windows", "10.0.0.1", 4, "ping -n 4 10.0.0.1"},
+		{"windows", "2001:db8::1", 4, "ping -6 -n 4 2001:db8::1"},
+		{"windows", "2001:db8::1", 0, "ping -6 -t 2001:db8::1"},
+	}
+	for _, tt := range tests {
+		name, args := commandArgs(tt.goos, tt.host, tt.count)
+		if got := strings.Join(append([]string{name}, args...), " "); got != tt.want {
+			t.Errorf("commandArgs(%q, %q, %d) = %q, want %q", tt.goos, tt.host, tt.count, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package ping
 
 import (
+	"math"
+	"sort"
+
 	"iperf-tool/internal/model"
 )
 
@@ -12,6 +15,22 @@ type Result struct {
 	MinMs       float64
 	AvgMs       float64
 	MaxMs       float64
+	StdDevMs    float64
+	P50Ms       float64
+	P95Ms       float64
+	P99Ms       float64
+}
+
+// DefaultCount is the number of baseline ping packets when none is configured.
+const DefaultCount = 4
+
+// BlockedNotice is logged once when the baseline ping suggests ICMP is filtered.
+const BlockedNotice = "ICMP appears blocked; skipping latency measurement"
+
+// Blocked reports whether a baseline ping means latency cannot be measured:
+// ping failed outright or not a single reply came back (100% loss).
+func Blocked(r *Result, err error) bool {
+	return err != nil || r == nil || r.PacketsRecv == 0
 }
 
 // ToModel converts a ping Result to the model representation.
@@ -26,5 +45,31 @@ func (r *Result) ToModel() *model.PingResult {
 		MinMs:       r.MinMs,
 		AvgMs:       r.AvgMs,
 		MaxMs:       r.MaxMs,
+		StdDevMs:    r.StdDevMs,
+		P50Ms:       r.P50Ms,
+		P95Ms:       r.P95Ms,
+		P99Ms:       r.P99Ms,
+	}
+}
+
+// setPercentiles fills P50/P95/P99 from individual RTT samples.
+// Leaves them zero when there are no samples.
+func (r *Result) setPercentiles(samples []float64) {
+	if len(samples) == 0 {
+		return
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	r.P50Ms = percentile(sorted, 50)
+	r.P95Ms = percentile(sorted, 95)
+	r.P99Ms = percentile(sorted, 99)
+}
+
+// percentile returns the nearest-rank percentile p of an ascending slice.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
 	}
+	return sorted[rank-1]
 }
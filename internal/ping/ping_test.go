@@ -3,6 +3,7 @@
 package ping
 
 import (
+	"errors"
 	"math"
 	"testing"
 )
@@ -72,6 +73,9 @@ func TestParseOutput_MacOS(t *testing.T) {
 	if !almostEqual(r.MaxMs, 2.012) {
 		t.Errorf("MaxMs = %f, want 2.012", r.MaxMs)
 	}
+	if !almostEqual(r.StdDevMs, 0.295) {
+		t.Errorf("StdDevMs = %f, want 0.295", r.StdDevMs)
+	}
 }
 
 func TestParseOutput_Linux(t *testing.T) {
@@ -95,6 +99,9 @@ func TestParseOutput_Linux(t *testing.T) {
 	if !almostEqual(r.MaxMs, 0.621) {
 		t.Errorf("MaxMs = %f, want 0.621", r.MaxMs)
 	}
+	if !almostEqual(r.StdDevMs, 0.029) {
+		t.Errorf("StdDevMs (mdev) = %f, want 0.029", r.StdDevMs)
+	}
 }
 
 func TestParseOutput_PartialLoss(t *testing.T) {
@@ -138,3 +145,85 @@ func TestParseOutput_InvalidInput(t *testing.T) {
 		t.Error("expected error for invalid ping output")
 	}
 }
+
+func TestParseOutput_Percentiles(t *testing.T) {
+	r, err := ParseOutput(macOSOutput)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	// Samples: 1.234, 1.456, 1.789, 2.012 (nearest-rank)
+	if !almostEqual(r.P50Ms, 1.456) {
+		t.Errorf("P50Ms = %f, want 1.456", r.P50Ms)
+	}
+	if !almostEqual(r.P95Ms, 2.012) {
+		t.Errorf("P95Ms = %f, want 2.012", r.P95Ms)
+	}
+	if !almostEqual(r.P99Ms, 2.012) {
+		t.Errorf("P99Ms = %f, want 2.012", r.P99Ms)
+	}
+
+	m := r.ToModel()
+	if m.P50Ms != r.P50Ms || m.P95Ms != r.P95Ms || m.P99Ms != r.P99Ms {
+		t.Errorf("ToModel() did not copy percentiles: %+v", m)
+	}
+}
+
+func TestParseOutput_PercentilesSummaryOnly(t *testing.T) {
+	summaryOnly := `--- 10.0.0.1 ping statistics ---
+4 packets transmitted, 4 received, 0% packet loss, time 3004ms
+rtt min/avg/max/mdev = 0.543/0.594/0.621/0.029 ms
+`
+	r, err := ParseOutput(summaryOnly)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	if r.P50Ms != 0 || r.P95Ms != 0 || r.P99Ms != 0 {
+		t.Errorf("percentiles should stay zero without samples, got %f/%f/%f", r.P50Ms, r.P95Ms, r.P99Ms)
+	}
+	if !almostEqual(r.AvgMs, 0.594) {
+		t.Errorf("AvgMs = %f, want 0.594", r.AvgMs)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]float64, 100)
+	for i := range sorted {
+		sorted[i] = float64(i + 1)
+	}
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{50, 50},
+		{95, 95},
+		{99, 99},
+		{100, 100},
+		{0, 1},
+	}
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestBlocked(t *testing.T) {
+	lost, err := ParseOutput(totalLossOutput)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	if !Blocked(lost, nil) {
+		t.Error("100% loss should count as blocked")
+	}
+	if !Blocked(nil, errors.New("ping failed: exit status 2")) {
+		t.Error("a ping error should count as blocked")
+	}
+
+	partial, err := ParseOutput(partialLossOutput)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	if Blocked(partial, nil) {
+		t.Error("partial loss is not blocked")
+	}
+}
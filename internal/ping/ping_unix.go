@@ -6,7 +6,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
@@ -15,16 +14,20 @@ import (
 // statsRe matches the rtt summary line from ping output on macOS and Linux.
 // Example: "round-trip min/avg/max/stddev = 1.234/5.678/9.012/1.234 ms"
 // Example: "rtt min/avg/max/mdev = 1.234/5.678/9.012/1.234 ms"
-var statsRe = regexp.MustCompile(`(?:round-trip|rtt)\s+min/avg/max/(?:std|m)dev\s*=\s*([\d.]+)/([\d.]+)/([\d.]+)`)
+var statsRe = regexp.MustCompile(`(?:round-trip|rtt)\s+min/avg/max/(?:std|m)dev\s*=\s*([\d.]+)/([\d.]+)/([\d.]+)/([\d.]+)`)
 
 // lossRe matches the packet loss summary line.
 // Example: "4 packets transmitted, 4 received, 0% packet loss"
 // Example: "4 packets transmitted, 4 packets received, 0.0% packet loss"
 var lossRe = regexp.MustCompile(`(\d+)\s+packets?\s+transmitted,\s+(\d+)\s+(?:packets?\s+)?received,\s+([\d.]+)%\s+packet loss`)
 
+// sampleRe matches the RTT of a single echo reply.
+// Example: "64 bytes from 10.0.0.1: icmp_seq=1 ttl=64 time=0.543 ms"
+var sampleRe = regexp.MustCompile(`time=([\d.]+)\s*ms`)
+
 // Run executes ping with a fixed count and returns the parsed result.
 func Run(ctx context.Context, host string, count int) (*Result, error) {
-	cmd := exec.CommandContext(ctx, "ping", "-c", strconv.Itoa(count), host)
+	cmd := command(ctx, host, count)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -41,7 +44,7 @@ func Run(ctx context.Context, host string, count int) (*Result, error) {
 // RunUntilCancel runs ping continuously until the context is cancelled.
 // On cancellation it sends SIGINT so ping prints its summary, then parses output.
 func RunUntilCancel(ctx context.Context, host string) (*Result, error) {
-	cmd := exec.CommandContext(ctx, "ping", host)
+	cmd := command(ctx, host, 0)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -85,6 +88,19 @@ func ParseOutput(output string) (*Result, error) {
 	r.MinMs, _ = strconv.ParseFloat(sm[1], 64)
 	r.AvgMs, _ = strconv.ParseFloat(sm[2], 64)
 	r.MaxMs, _ = strconv.ParseFloat(sm[3], 64)
+	r.StdDevMs, _ = strconv.ParseFloat(sm[4], 64)
+	r.setPercentiles(parseSamples(output))
 
 	return r, nil
 }
+
+// parseSamples extracts the RTT of every echo reply line.
+func parseSamples(output string) []float64 {
+	var samples []float64
+	for _, m := range sampleRe.FindAllStringSubmatch(output, -1) {
+		if v, err := strconv.ParseFloat(m[1], 64); err == nil {
+			samples = append(samples, v)
+		}
+	}
+	return samples
+}
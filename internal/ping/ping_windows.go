@@ -6,7 +6,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
@@ -20,9 +19,13 @@ var statsRe = regexp.MustCompile(`Minimum\s*=\s*(\d+)\s*ms,\s*Maximum\s*=\s*(\d+
 // Example: "    Packets: Sent = 4, Received = 4, Lost = 0 (0% loss),"
 var lossRe = regexp.MustCompile(`Sent\s*=\s*(\d+),\s*Received\s*=\s*(\d+),\s*Lost\s*=\s*\d+\s*\((\d+)%\s*loss\)`)
 
+// sampleRe matches the RTT of a single echo reply ("time<1ms" counts as 0).
+// Example: "Reply from 10.0.0.1: bytes=32 time=3ms TTL=64"
+var sampleRe = regexp.MustCompile(`time([=<])(\d+)ms`)
+
 // Run executes ping with a fixed count and returns the parsed result.
 func Run(ctx context.Context, host string, count int) (*Result, error) {
-	cmd := exec.CommandContext(ctx, "ping", "-n", strconv.Itoa(count), host)
+	cmd := command(ctx, host, count)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -38,7 +41,7 @@ func Run(ctx context.Context, host string, count int) (*Result, error) {
 // RunUntilCancel runs ping continuously until the context is cancelled.
 // Uses -t flag for continuous ping on Windows.
 func RunUntilCancel(ctx context.Context, host string) (*Result, error) {
-	cmd := exec.CommandContext(ctx, "ping", "-t", host)
+	cmd := command(ctx, host, 0)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -79,6 +82,22 @@ func ParseOutput(output string) (*Result, error) {
 	r.MinMs, _ = strconv.ParseFloat(sm[1], 64)
 	r.AvgMs, _ = strconv.ParseFloat(sm[2], 64)
 	r.MaxMs, _ = strconv.ParseFloat(sm[3], 64)
+	r.setPercentiles(parseSamples(output))
 
 	return r, nil
 }
+
+// parseSamples extracts the RTT of every reply line.
+func parseSamples(output string) []float64 {
+	var samples []float64
+	for _, m := range sampleRe.FindAllStringSubmatch(output, -1) {
+		if m[1] == "<" {
+			samples = append(samples, 0)
+			continue
+		}
+		if v, err := strconv.ParseFloat(m[2], 64); err == nil {
+			samples = append(samples, v)
+		}
+	}
+	return samples
+}
@@ -114,3 +114,29 @@ func TestParseOutput_InvalidInput(t *testing.T) {
 		t.Error("expected error for invalid ping output")
 	}
 }
+
+func TestParseOutput_WindowsPercentiles(t *testing.T) {
+	out := `
+Pinging 10.0.0.1 with 32 bytes of data:
+Reply from 10.0.0.1: bytes=32 time<1ms TTL=64
+Reply from 10.0.0.1: bytes=32 time=2ms TTL=64
+Reply from 10.0.0.1: bytes=32 time=9ms TTL=64
+Reply from 10.0.0.1: bytes=32 time=1ms TTL=64
+
+Ping statistics for 10.0.0.1:
+    Packets: Sent = 4, Received = 4, Lost = 0 (0% loss),
+Approximate round trip times in milli-seconds:
+    Minimum = 0ms, Maximum = 9ms, Average = 3ms
+`
+	r, err := ParseOutput(out)
+	if err != nil {
+		t.Fatalf("ParseOutput() error: %v", err)
+	}
+	// Samples sorted: 0, 1, 2, 9 (nearest-rank)
+	if !almostEqual(r.P50Ms, 1.0) {
+		t.Errorf("P50Ms = %f, want 1.0", r.P50Ms)
+	}
+	if !almostEqual(r.P99Ms, 9.0) {
+		t.Errorf("P99Ms = %f, want 9.0", r.P99Ms)
+	}
+}
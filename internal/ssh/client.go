@@ -4,11 +4,13 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/ssh"
@@ -18,9 +20,12 @@ import (
 
 // Client wraps an SSH connection.
 type Client struct {
-	conn *ssh.Client
-	host string
-	user string
+	mu    sync.Mutex // guards conn and jumps, swapped by Reconnect
+	conn  *ssh.Client
+	jumps []*ssh.Client // jump host connections carrying conn, outermost first
+	host  string
+	user  string
+	cfg   ConnectConfig // original settings, reused by Reconnect
 }
 
 // ConnectConfig holds SSH connection parameters.
@@ -31,6 +36,34 @@ type ConnectConfig struct {
 	KeyPath            string // path to private key file
 	Password           string // fallback if KeyPath is empty
 	InsecureSkipVerify bool   // disables host key checking; use only where known_hosts is unavailable
+	JumpHost           string // ProxyJump spec "[user@]host[:port][,...]"; empty = ProxyJump from ~/.ssh/config
+	Passphrase         string // decrypts a passphrase-protected KeyPath that is not in the agent
+
+	// PassphrasePrompt is asked for the passphrase of keyPath when the key
+	// is encrypted and Passphrase is empty; the CLI reads the terminal, the
+	// GUI shows a dialog. nil = fail with a hint instead. The answer is
+	// remembered for Reconnect.
+	PassphrasePrompt func(keyPath string) (string, error)
+}
+
+// parseKey parses a private key, decrypting it with cfg.Passphrase or the
+// answer of cfg.PassphrasePrompt when it is passphrase-protected.
+func parseKey(key []byte, keyPath string, cfg ConnectConfig) (ssh.Signer, error) {
+	signer, err := ssh.ParsePrivateKey(key)
+	var missing *ssh.PassphraseMissingError
+	if !errors.As(err, &missing) {
+		return signer, err
+	}
+	passphrase := cfg.Passphrase
+	if passphrase == "" && cfg.PassphrasePrompt != nil {
+		if passphrase, err = cfg.PassphrasePrompt(keyPath); err != nil {
+			return nil, fmt.Errorf("read passphrase for %q: %w", keyPath, err)
+		}
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("key %q is passphrase-protected: add it to ssh-agent or enter its passphrase", keyPath)
+	}
+	return ssh.ParsePrivateKeyWithPassphrase(key, []byte(passphrase))
 }
 
 // DefaultKeyPaths returns common SSH private key paths that exist on disk.
@@ -56,8 +89,30 @@ func DefaultKeyPaths() []string {
 // Connect establishes an SSH connection using key auth (preferred) or password.
 // If no KeyPath or Password is provided, it tries the SSH agent and then
 // auto-discovers keys from default locations. It also honors ProxyCommand
-// from ~/.ssh/config.
+// and ProxyJump from ~/.ssh/config; an explicit JumpHost takes precedence.
 func Connect(cfg ConnectConfig) (*Client, error) {
+	if prompt := cfg.PassphrasePrompt; prompt != nil {
+		asked := map[string]string{}
+		cfg.PassphrasePrompt = func(keyPath string) (string, error) {
+			if p, ok := asked[keyPath]; ok {
+				return p, nil
+			}
+			p, err := prompt(keyPath)
+			if err == nil {
+				asked[keyPath] = p
+			}
+			return p, err
+		}
+	}
+	c, err := connect(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.cfg = cfg
+	return c, nil
+}
+
+func connect(cfg ConnectConfig) (*Client, error) {
 	if cfg.Port == 0 {
 		cfg.Port = 22
 	}
@@ -75,21 +130,26 @@ func Connect(cfg ConnectConfig) (*Client, error) {
 		if err != nil {
 			return nil, fmt.Errorf("read SSH key %q: %w", cfg.KeyPath, err)
 		}
-		signer, err := ssh.ParsePrivateKey(key)
+		signer, err := parseKey(key, cfg.KeyPath, cfg)
 		if err != nil {
 			return nil, fmt.Errorf("parse SSH key: %w", err)
 		}
 		signers = append(signers, signer)
 	}
 
-	// Auto-discover default SSH keys if no explicit key/password was provided
+	// Auto-discover default SSH keys if no explicit key/password was provided.
+	// Encrypted ones only prompt when the agent offered nothing.
 	if cfg.KeyPath == "" {
+		discover := cfg
+		if len(signers) > 0 {
+			discover.PassphrasePrompt = nil
+		}
 		for _, keyPath := range DefaultKeyPaths() {
 			key, err := os.ReadFile(keyPath)
 			if err != nil {
 				continue
 			}
-			signer, err := ssh.ParsePrivateKey(key)
+			signer, err := parseKey(key, keyPath, discover)
 			if err != nil {
 				continue
 			}
@@ -123,6 +183,10 @@ func Connect(cfg ConnectConfig) (*Client, error) {
 
 	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
 
+	if cfg.JumpHost != "" {
+		return dialViaJump(cfg, sshConfig, addr)
+	}
+
 	// Check ~/.ssh/config for ProxyCommand
 	if proxyCmd := lookupProxyCommand(cfg.Host); proxyCmd != "" {
 		proxyCmd = strings.ReplaceAll(proxyCmd, "%h", cfg.Host)
@@ -135,6 +199,11 @@ func Connect(cfg ConnectConfig) (*Client, error) {
 		return &Client{conn: conn, host: cfg.Host, user: cfg.User}, nil
 	}
 
+	if jump := lookupProxyJump(cfg.Host); jump != "" {
+		cfg.JumpHost = jump
+		return dialViaJump(cfg, sshConfig, addr)
+	}
+
 	conn, err := ssh.Dial("tcp", addr, sshConfig)
 	if err != nil {
 		return nil, fmt.Errorf("SSH connect to %s: %w", addr, err)
@@ -143,9 +212,17 @@ func Connect(cfg ConnectConfig) (*Client, error) {
 	return &Client{conn: conn, host: cfg.Host, user: cfg.User}, nil
 }
 
+// current returns the live connection; sessions opened on it before a
+// Reconnect keep the old one until they finish.
+func (c *Client) current() *ssh.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn
+}
+
 // RunCommand executes a command on the remote host and returns its output.
 func (c *Client) RunCommand(cmd string) (string, error) {
-	session, err := c.conn.NewSession()
+	session, err := c.current().NewSession()
 	if err != nil {
 		return "", fmt.Errorf("create SSH session: %w", err)
 	}
@@ -162,7 +239,7 @@ func (c *Client) RunCommand(cmd string) (string, error) {
 // for each stdout line as it arrives. Stderr is captured and returned along
 // with the full combined output. Useful for live progress streaming.
 func (c *Client) RunCommandStream(cmd string, onLine func(string)) (string, error) {
-	session, err := c.conn.NewSession()
+	session, err := c.current().NewSession()
 	if err != nil {
 		return "", fmt.Errorf("create SSH session: %w", err)
 	}
@@ -213,21 +290,106 @@ func (c *Client) RunCommandStream(cmd string, onLine func(string)) (string, erro
 
 // Close closes the SSH connection.
 func (c *Client) Close() error {
-	if c.conn != nil {
-		return c.conn.Close()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return closeConns(c.conn, c.jumps)
+}
+
+// closeConns closes conn and then the jump hosts carrying it, innermost first.
+func closeConns(conn *ssh.Client, jumps []*ssh.Client) error {
+	var err error
+	if conn != nil {
+		err = conn.Close()
+	}
+	for i := len(jumps) - 1; i >= 0; i-- {
+		jumps[i].Close()
+	}
+	return err
+}
+
+// keepaliveTimeout bounds how long IsAlive waits for a keepalive reply; a
+// half-open link (NAT timeout, suspended peer) never answers at all.
+var keepaliveTimeout = 5 * time.Second
+
+// IsAlive reports whether the connection still answers a keepalive request.
+// Servers reject the request type itself, which still proves the
+// connection is up; only a transport error or no reply within
+// keepaliveTimeout means it has dropped.
+func (c *Client) IsAlive() bool {
+	conn := c.current()
+	if conn == nil {
+		return false
+	}
+	return keepalive(func() error {
+		_, _, err := conn.SendRequest("keepalive@openssh.com", true, nil)
+		return err
+	}, keepaliveTimeout)
+}
+
+// keepalive runs send in the background and reports whether it succeeded
+// within timeout. A send still blocked afterwards returns once the dead
+// connection is closed.
+func keepalive(send func() error, timeout time.Duration) bool {
+	done := make(chan error, 1)
+	go func() { done <- send() }()
+	select {
+	case err := <-done:
+		return err == nil
+	case <-time.After(timeout):
+		return false
 	}
+}
+
+// Reconnect replaces a dropped connection with a fresh one built from the
+// original ConnectConfig. The Client value stays the same, so runners and
+// panels holding it keep working after a reconnect.
+func (c *Client) Reconnect() error {
+	fresh, err := connect(c.cfg)
+	if err != nil {
+		return fmt.Errorf("SSH reconnect to %s: %w", c.host, err)
+	}
+	c.mu.Lock()
+	old, oldJumps := c.conn, c.jumps
+	c.conn, c.jumps = fresh.conn, fresh.jumps
+	c.mu.Unlock()
+	closeConns(old, oldJumps)
 	return nil
 }
 
+// reconnector is the part of Client WithReconnect needs; tests fake it.
+type reconnector interface {
+	IsAlive() bool
+	Reconnect() error
+}
+
+// WithReconnect runs op and, if it fails because the SSH connection has
+// dropped (a long repeat session outliving the link), reconnects once and
+// runs op again. Failures on a live connection are returned unchanged.
+func (c *Client) WithReconnect(op func() error) error {
+	return withReconnect(c, op)
+}
+
+func withReconnect(c reconnector, op func() error) error {
+	err := op()
+	if err == nil || c.IsAlive() {
+		return err
+	}
+	if rerr := c.Reconnect(); rerr != nil {
+		return fmt.Errorf("%w (connection lost, %v)", err, rerr)
+	}
+	return op()
+}
+
 // LocalAddr returns the local network address used for the SSH connection.
 // It returns an empty string if the connection is not active or if the
 // local address cannot be determined.
 func (c *Client) LocalAddr() string {
-	if c.conn == nil {
+	conn := c.current()
+	if conn == nil {
 		return ""
 	}
 	
-	addr := c.conn.LocalAddr()
+	addr := conn.LocalAddr()
 	if addr == nil {
 		return ""
 	}
@@ -260,30 +422,46 @@ func sshAgentSigners() []ssh.Signer {
 // lookupProxyCommand does a minimal parse of ~/.ssh/config to find a
 // ProxyCommand that applies to the given host.
 func lookupProxyCommand(host string) string {
+	return lookupSSHConfig(host, "proxycommand")
+}
+
+// lookupProxyJump returns the ProxyJump from ~/.ssh/config that applies to
+// the given host, or "".
+func lookupProxyJump(host string) string {
+	return lookupSSHConfig(host, "proxyjump")
+}
+
+// lookupSSHConfig returns the first value of key (lower case) in
+// ~/.ssh/config for the given host.
+func lookupSSHConfig(host, key string) string {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return ""
 	}
-	path := filepath.Join(home, ".ssh", "config")
-	f, err := os.Open(path)
+	f, err := os.Open(filepath.Join(home, ".ssh", "config"))
 	if err != nil {
 		return ""
 	}
 	defer f.Close()
+	return findSSHConfigValue(f, host, key)
+}
 
+// findSSHConfigValue scans ssh_config text for the first key that applies
+// to host. "none" disables the option, as in OpenSSH.
+func findSSHConfigValue(r io.Reader, host, key string) string {
 	var currentHosts []string
-	scanner := bufio.NewScanner(f)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
-		key, val := parseSSHConfigLine(line)
-		key = strings.ToLower(key)
+		k, val := parseSSHConfigLine(line)
+		k = strings.ToLower(k)
 
-		if key == "host" {
+		if k == "host" {
 			currentHosts = strings.Fields(val)
-		} else if key == "proxycommand" && matchesHost(host, currentHosts) {
+		} else if k == key && matchesHost(host, currentHosts) {
 			if strings.ToLower(val) == "none" {
 				return ""
 			}
@@ -293,6 +471,70 @@ func lookupProxyCommand(host string) string {
 	return ""
 }
 
+// parseJumpHost splits one ProxyJump hop "[user@]host[:port]" into a user
+// (defaultUser when absent) and a dialable host:port address.
+func parseJumpHost(spec, defaultUser string) (user, addr string) {
+	user = defaultUser
+	if i := strings.LastIndex(spec, "@"); i >= 0 {
+		user, spec = spec[:i], spec[i+1:]
+	}
+	if host, port, err := net.SplitHostPort(spec); err == nil {
+		return user, net.JoinHostPort(host, port)
+	}
+	return user, net.JoinHostPort(strings.Trim(spec, "[]"), "22")
+}
+
+// dialViaJump connects to addr through the comma-separated chain of jump
+// hosts in cfg.JumpHost, tunnelling each hop over the previous one. Jump
+// hosts use the same auth methods and host key policy as the target.
+func dialViaJump(cfg ConnectConfig, config *ssh.ClientConfig, addr string) (*Client, error) {
+	var jumps []*ssh.Client
+	closeJumps := func() {
+		for i := len(jumps) - 1; i >= 0; i-- {
+			jumps[i].Close()
+		}
+	}
+
+	var via *ssh.Client
+	for _, hop := range strings.Split(cfg.JumpHost, ",") {
+		user, hopAddr := parseJumpHost(strings.TrimSpace(hop), config.User)
+		hopConfig := *config
+		hopConfig.User = user
+		c, err := dialSSH(via, hopAddr, &hopConfig)
+		if err != nil {
+			closeJumps()
+			return nil, fmt.Errorf("SSH connect to jump host %s: %w", hopAddr, err)
+		}
+		jumps = append(jumps, c)
+		via = c
+	}
+
+	conn, err := dialSSH(via, addr, config)
+	if err != nil {
+		closeJumps()
+		return nil, fmt.Errorf("SSH connect to %s via %s: %w", addr, cfg.JumpHost, err)
+	}
+	return &Client{conn: conn, jumps: jumps, host: cfg.Host, user: cfg.User}, nil
+}
+
+// dialSSH opens an SSH connection to addr, directly when via is nil or
+// through a TCP channel of the via connection otherwise.
+func dialSSH(via *ssh.Client, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	if via == nil {
+		return ssh.Dial("tcp", addr, config)
+	}
+	conn, err := via.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
 func parseSSHConfigLine(line string) (key, value string) {
 	// Handle both "Key=Value" and "Key Value"
 	if idx := strings.IndexByte(line, '='); idx != -1 {
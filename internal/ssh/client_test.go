@@ -3,11 +3,13 @@ package ssh
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"errors"
 	"net"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	gossh "golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/knownhosts"
@@ -160,3 +162,169 @@ func TestBuildHostKeyCallback_MissingKnownHostsCreatesFile(t *testing.T) {
 		t.Errorf("expected nil for TOFU on fresh known_hosts, got: %v", err)
 	}
 }
+
+func TestFindSSHConfigValue_ProxyJump(t *testing.T) {
+	conf := `# lab hosts
+Host bastion
+    HostName 203.0.113.10
+
+Host 10.0.* iperf-*
+    User ops
+    ProxyJump admin@bastion.example.com:2222
+
+Host direct
+    ProxyJump none
+
+Host *
+    ProxyJump=fallback
+`
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"10.0.0.5", "admin@bastion.example.com:2222"},
+		{"iperf-east", "admin@bastion.example.com:2222"},
+		{"direct", ""},
+		{"other", "fallback"},
+	}
+	for _, tt := range tests {
+		if got := findSSHConfigValue(strings.NewReader(conf), tt.host, "proxyjump"); got != tt.want {
+			t.Errorf("ProxyJump for %q = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+	if got := findSSHConfigValue(strings.NewReader(conf), "10.0.0.5", "proxycommand"); got != "" {
+		t.Errorf("ProxyCommand = %q, want empty", got)
+	}
+}
+
+func TestParseJumpHost(t *testing.T) {
+	tests := []struct {
+		spec, user, wantUser, wantAddr string
+	}{
+		{"bastion", "me", "me", "bastion:22"},
+		{"admin@bastion:2222", "me", "admin", "bastion:2222"},
+		{"10.0.0.1:2200", "", "", "10.0.0.1:2200"},
+		{"ops@[2001:db8::1]:22", "me", "ops", "[2001:db8::1]:22"},
+		{"[2001:db8::1]", "me", "me", "[2001:db8::1]:22"},
+	}
+	for _, tt := range tests {
+		user, addr := parseJumpHost(tt.spec, tt.user)
+		if user != tt.wantUser || addr != tt.wantAddr {
+			t.Errorf("parseJumpHost(%q) = %q, %q; want %q, %q", tt.spec, user, addr, tt.wantUser, tt.wantAddr)
+		}
+	}
+}
+
+// fakeReconnector is a connection that is dead until Reconnect is called.
+type fakeReconnector struct {
+	alive      bool
+	reconnects int
+	failDial   bool
+}
+
+func (f *fakeReconnector) IsAlive() bool { return f.alive }
+
+func (f *fakeReconnector) Reconnect() error {
+	f.reconnects++
+	if f.failDial {
+		return errors.New("dial tcp: connection refused")
+	}
+	f.alive = true
+	return nil
+}
+
+func TestWithReconnect_DeadConnection(t *testing.T) {
+	conn := &fakeReconnector{}
+	calls := 0
+	err := withReconnect(conn, func() error {
+		calls++
+		if !conn.alive {
+			return errors.New("create SSH session: EOF")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("withReconnect() error: %v", err)
+	}
+	if calls != 2 || conn.reconnects != 1 {
+		t.Errorf("calls = %d, reconnects = %d; want 2 and 1", calls, conn.reconnects)
+	}
+}
+
+func TestWithReconnect_LiveConnectionError(t *testing.T) {
+	conn := &fakeReconnector{alive: true}
+	want := errors.New("remote command: exit status 1")
+	calls := 0
+	err := withReconnect(conn, func() error { calls++; return want })
+	if !errors.Is(err, want) || calls != 1 || conn.reconnects != 0 {
+		t.Errorf("err = %v, calls = %d, reconnects = %d; a live connection must not be redialled", err, calls, conn.reconnects)
+	}
+}
+
+func TestWithReconnect_ReconnectFails(t *testing.T) {
+	conn := &fakeReconnector{failDial: true}
+	opErr := errors.New("create SSH session: EOF")
+	err := withReconnect(conn, func() error { return opErr })
+	if !errors.Is(err, opErr) || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("err = %v, want the op error with the reconnect failure", err)
+	}
+}
+
+func TestKeepalive(t *testing.T) {
+	if !keepalive(func() error { return nil }, time.Second) {
+		t.Error("answered keepalive reported dead")
+	}
+	if keepalive(func() error { return errors.New("EOF") }, time.Second) {
+		t.Error("failed keepalive reported alive")
+	}
+
+	// A half-open link never replies; IsAlive must give up, not hang.
+	block := make(chan struct{})
+	defer close(block)
+	start := time.Now()
+	if keepalive(func() error { <-block; return nil }, 50*time.Millisecond) {
+		t.Error("unanswered keepalive reported alive")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("keepalive waited %s, want about the 50ms timeout", d)
+	}
+}
+
+func TestParseKey_Passphrase(t *testing.T) {
+	const keyPath = "testdata/id_ed25519_encrypted"
+	key, err := os.ReadFile(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := parseKey(key, keyPath, ConnectConfig{Passphrase: "correct horse"})
+	if err != nil {
+		t.Fatalf("parseKey() with passphrase: %v", err)
+	}
+	if signer.PublicKey().Type() != gossh.KeyAlgoED25519 {
+		t.Errorf("key type = %s, want ed25519", signer.PublicKey().Type())
+	}
+
+	var asked []string
+	prompt := func(p string) (string, error) {
+		asked = append(asked, p)
+		return "correct horse", nil
+	}
+	if _, err := parseKey(key, keyPath, ConnectConfig{PassphrasePrompt: prompt}); err != nil {
+		t.Errorf("parseKey() with prompt: %v", err)
+	}
+	if len(asked) != 1 || asked[0] != keyPath {
+		t.Errorf("prompt asked for %v, want [%s]", asked, keyPath)
+	}
+
+	if _, err := parseKey(key, keyPath, ConnectConfig{}); err == nil || !strings.Contains(err.Error(), "passphrase-protected") {
+		t.Errorf("parseKey() without passphrase = %v, want passphrase-protected hint", err)
+	}
+	if _, err := parseKey(key, keyPath, ConnectConfig{Passphrase: "wrong"}); err == nil {
+		t.Error("parseKey() with wrong passphrase should fail")
+	}
+	cancelled := func(string) (string, error) { return "", errors.New("cancelled") }
+	if _, err := parseKey(key, keyPath, ConnectConfig{PassphrasePrompt: cancelled}); err == nil {
+		t.Error("parseKey() should fail when the prompt is cancelled")
+	}
+}
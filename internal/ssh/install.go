@@ -40,6 +40,10 @@ func (c *Client) DetectOS() (OSType, error) {
 
 // CheckIperfInstalled checks if iperf2 is available on the remote system.
 func (c *Client) CheckIperfInstalled() (bool, error) {
+	return checkIperfInstalled(c)
+}
+
+func checkIperfInstalled(c commandRunner) (bool, error) {
 	// Linux/Mac check
 	_, err := c.RunCommand("which iperf")
 	if err == nil {
@@ -105,6 +109,9 @@ func (c *Client) InstallIperf() error {
 
 	// Verify installation
 	if installed, err := c.CheckIperfInstalled(); err != nil || !installed {
+		if _, err := c.RunCommand("which iperf2"); err == nil {
+			return fmt.Errorf("iperf2 was installed as \"iperf2\", not \"iperf\"; use --remote-binary iperf2")
+		}
 		return fmt.Errorf("iperf2 installation verification failed")
 	}
 
@@ -129,27 +136,62 @@ func (c *Client) hasSudoPrivilege(osType OSType) (bool, error) {
 	return err == nil, nil
 }
 
+// linuxManager describes how to detect a package manager, probe for a
+// package name and install it non-interactively.
+type linuxManager struct {
+	name    string
+	check   string // succeeds when the manager is present
+	probe   string // succeeds when package %s exists in the repos; empty = no probe
+	install string // installs package %s
+}
+
+// linuxManagers is checked in order. dnf comes before yum, where yum is
+// often a dnf alias, and apt-get before apt, whose CLI is not meant for
+// scripts.
+var linuxManagers = []linuxManager{
+	{"apt-get", "which apt-get", "apt-cache show %s", "sudo apt-get update && sudo apt-get install -y %s"},
+	{"apt", "which apt", "apt-cache show %s", "sudo apt update && sudo apt install -y %s"},
+	{"dnf", "which dnf", "dnf info %s", "sudo dnf install -y %s"},
+	{"yum", "which yum", "yum info %s", "sudo yum install -y %s"},
+	{"zypper", "which zypper", "zypper --non-interactive search -x %s", "sudo zypper --non-interactive install %s"},
+	{"apk", "which apk", "", "sudo apk add %s"},
+	{"pacman", "which pacman", "pacman -Si %s", "sudo pacman -S --noconfirm %s"},
+	{"xbps", "which xbps-install", "xbps-query -R %s", "sudo xbps-install -Sy %s"},
+}
+
+// iperf2Packages are candidate package names, most specific first. The
+// "iperf3" package is a different, incompatible tool and is never chosen.
+var iperf2Packages = []string{"iperf2", "iperf"}
+
 // installLinux returns the command to install iperf2 on Linux.
-// Detects the package manager (apt, yum, dnf, apk, pacman).
 func (c *Client) installLinux() (string, error) {
-	managers := []struct {
-		check   string
-		install string
-	}{
-		{"which apt-get", "sudo apt-get update && sudo apt-get install -y iperf"},
-		{"which yum", "sudo yum install -y iperf"},
-		{"which dnf", "sudo dnf install -y iperf"},
-		{"which apk", "sudo apk add iperf"},
-		{"which pacman", "sudo pacman -S --noconfirm iperf"},
-	}
+	return linuxInstallCommand(c)
+}
 
-	for _, mgr := range managers {
-		if _, err := c.RunCommand(mgr.check); err == nil {
-			return mgr.install, nil
+// linuxInstallCommand detects the package manager and probes which iperf2
+// package name its repositories use, falling back to "iperf".
+func linuxInstallCommand(c commandRunner) (string, error) {
+	for _, mgr := range linuxManagers {
+		if _, err := c.RunCommand(mgr.check); err != nil {
+			continue
+		}
+		pkg := iperf2Packages[len(iperf2Packages)-1]
+		if mgr.probe != "" {
+			for _, name := range iperf2Packages {
+				if _, err := c.RunCommand(fmt.Sprintf(mgr.probe, name)); err == nil {
+					pkg = name
+					break
+				}
+			}
 		}
+		return fmt.Sprintf(mgr.install, pkg), nil
 	}
 
-	return "", fmt.Errorf("no supported package manager found (apt, yum, dnf, apk, pacman)")
+	names := make([]string, len(linuxManagers))
+	for i, mgr := range linuxManagers {
+		names[i] = mgr.name
+	}
+	return "", fmt.Errorf("no supported package manager found (%s)", strings.Join(names, ", "))
 }
 
 // installMacOS returns the command to install iperf2 on macOS.
@@ -1,6 +1,7 @@
 package ssh
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -55,25 +56,51 @@ func (me *mockError) Error() string {
 }
 
 func TestLinuxInstallCommandSelection(t *testing.T) {
-	mc := NewMockClient()
-	// Simulate apt-get being available
-	mc.commands["which apt-get"] = nil
-
 	tests := []struct {
 		name      string
-		checkCmd  string
+		available []string // commands that succeed on the fake host
 		expectCmd string
 	}{
-		{"apt-get", "which apt-get", "sudo apt-get update && sudo apt-get install -y iperf"},
-		{"yum", "which yum", "sudo yum install -y iperf"},
-		{"dnf", "which dnf", "sudo dnf install -y iperf"},
+		{"apt-get", []string{"which apt-get", "apt-cache show iperf"},
+			"sudo apt-get update && sudo apt-get install -y iperf"},
+		{"apt only", []string{"which apt", "apt-cache show iperf"},
+			"sudo apt update && sudo apt install -y iperf"},
+		{"dnf preferred over yum", []string{"which yum", "which dnf", "dnf info iperf"},
+			"sudo dnf install -y iperf"},
+		{"yum", []string{"which yum", "yum info iperf"}, "sudo yum install -y iperf"},
+		{"zypper iperf2 package", []string{"which zypper", "zypper --non-interactive search -x iperf2", "zypper --non-interactive search -x iperf"},
+			"sudo zypper --non-interactive install iperf2"},
+		{"apk no probe", []string{"which apk"}, "sudo apk add iperf"},
+		{"pacman", []string{"which pacman", "pacman -Si iperf"}, "sudo pacman -S --noconfirm iperf"},
+		{"xbps", []string{"which xbps-install", "xbps-query -R iperf"}, "sudo xbps-install -Sy iperf"},
+		{"probe finds nothing", []string{"which dnf"}, "sudo dnf install -y iperf"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// This test is illustrative; actual testing would require
-			// mocking the full Client interface
-			_ = tt.expectCmd
+			mc := NewMockClient()
+			for _, cmd := range tt.available {
+				mc.commands[cmd] = nil
+			}
+			got, err := linuxInstallCommand(mc)
+			if err != nil {
+				t.Fatalf("linuxInstallCommand() error: %v", err)
+			}
+			if got != tt.expectCmd {
+				t.Errorf("install command = %q, want %q", got, tt.expectCmd)
+			}
 		})
 	}
 }
+
+func TestLinuxInstallCommand_NoManager(t *testing.T) {
+	_, err := linuxInstallCommand(NewMockClient())
+	if err == nil {
+		t.Fatal("expected error with no package manager")
+	}
+	for _, name := range []string{"zypper", "xbps"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not list %s", err, name)
+		}
+	}
+}
@@ -7,8 +7,27 @@ import (
 	"time"
 )
 
+// commandRunner is the part of Client the server manager needs; tests
+// substitute a fake that records the issued commands.
+type commandRunner interface {
+	RunCommand(cmd string) (string, error)
+}
+
+// streamRunner can also run a long-lived command and stream its output.
+type streamRunner interface {
+	commandRunner
+	RunCommandStream(cmd string, onLine func(string)) (string, error)
+}
+
+// sleep is replaced in tests to skip the settle delays.
+var sleep = time.Sleep
+
 // ServerManager tracks and controls a remote iperf2 server process.
 type ServerManager struct {
+	// IperfPath is the remote iperf2 binary (e.g. "/opt/iperf2/bin/iperf").
+	// Empty means "iperf" ("iperf.exe" on Windows) from the remote PATH.
+	IperfPath string
+
 	mu           sync.Mutex
 	running      bool
 	port         int
@@ -38,15 +57,134 @@ func (m *ServerManager) StartServer(client *Client, port int) error {
 	return m.start(client, port, 2)
 }
 
+// StartServerStreaming starts a single foreground iperf2 TCP server (no -D)
+// on a long-lived SSH session and passes each line it prints, stderr
+// included, to onLine until the server is stopped. Use it to see why a
+// server refuses connections; StartServer's daemon mode stays the default.
+// A final "iperf2 server exited" line is sent when the session ends.
+func (m *ServerManager) StartServerStreaming(client *Client, port int, onLine func(string)) error {
+	return m.startStreaming(client, port, onLine)
+}
+
+func (m *ServerManager) startStreaming(client streamRunner, port int, onLine func(string)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.running {
+		return fmt.Errorf("iperf2 server already running on port %d", m.port)
+	}
+	if !m.checkInstalled(client) {
+		if m.IperfPath != "" {
+			return fmt.Errorf("iperf2 binary %q not found on the remote host", m.IperfPath)
+		}
+		return fmt.Errorf("iperf2 is not installed on the remote host")
+	}
+
+	bin := m.binary()
+	if _, err := client.RunCommand("cmd.exe /c echo %OS%"); err == nil {
+		bin = m.winBinary()
+	}
+	cmd := fmt.Sprintf("%s -s -p %d 2>&1", bin, port)
+
+	exited := make(chan error, 1)
+	go func() {
+		_, err := client.RunCommandStream(cmd, onLine)
+		exited <- err
+	}()
+
+	sleep(500 * time.Millisecond)
+	select {
+	case err := <-exited:
+		if err != nil {
+			return fmt.Errorf("remote iperf2 server exited on start: %w", err)
+		}
+		return fmt.Errorf("remote iperf2 server exited on start")
+	default:
+	}
+	if err := isListening(client, port); err != nil {
+		return fmt.Errorf("iperf2 server started but not listening on port %d: %w", port, err)
+	}
+
+	m.running = true
+	m.port = port
+	m.numInstances = 1
+
+	go func() {
+		err := <-exited
+		m.mu.Lock()
+		if m.port == port {
+			m.running = false
+		}
+		m.mu.Unlock()
+		if onLine == nil {
+			return
+		}
+		if err != nil {
+			onLine(fmt.Sprintf("iperf2 server exited: %v", err))
+		} else {
+			onLine("iperf2 server exited")
+		}
+	}()
+	return nil
+}
+
+// binary returns the remote Unix command used to launch iperf2.
+func (m *ServerManager) binary() string {
+	if m.IperfPath != "" {
+		return m.IperfPath
+	}
+	return "iperf"
+}
+
+// winBinary returns the remote Windows executable used to launch iperf2.
+func (m *ServerManager) winBinary() string {
+	if m.IperfPath != "" {
+		return m.IperfPath
+	}
+	return "iperf.exe"
+}
+
+// baseName returns the configured binary's file name, which is what
+// pkill/pgrep/killall see in the process list.
+func (m *ServerManager) baseName() string {
+	b := m.binary()
+	if i := strings.LastIndexAny(b, `/\`); i >= 0 {
+		b = b[i+1:]
+	}
+	return strings.TrimSuffix(b, ".exe")
+}
+
+// imageName returns the Windows image name for taskkill/tasklist.
+func (m *ServerManager) imageName() string {
+	return m.baseName() + ".exe"
+}
+
+// checkInstalled reports whether the configured iperf2 binary exists remotely.
+func (m *ServerManager) checkInstalled(client commandRunner) bool {
+	if m.IperfPath == "" {
+		installed, _ := checkIperfInstalled(client)
+		return installed
+	}
+	if _, err := client.RunCommand(fmt.Sprintf("command -v '%s'", m.IperfPath)); err == nil {
+		return true
+	}
+	winCheckCmd := fmt.Sprintf(`powershell -Command "if (Test-Path \"%s\") { exit 0 } else { exit 1 }"`, m.IperfPath)
+	_, err := client.RunCommand(winCheckCmd)
+	return err == nil
+}
+
 // start does the actual work; must be called with m.mu held.
 // It starts numInstances iperf2 instances on consecutive ports starting at port.
-func (m *ServerManager) start(client *Client, port, numInstances int) error {
+func (m *ServerManager) start(client commandRunner, port, numInstances int) error {
 	if numInstances < 1 {
 		numInstances = 2
 	}
 
 	// Pre-check: bail out early with a clear message if iperf is missing.
-	if installed, _ := client.CheckIperfInstalled(); !installed {
+	if !m.checkInstalled(client) {
+		if m.IperfPath != "" {
+			return fmt.Errorf("iperf2 binary %q not found on the remote host", m.IperfPath)
+		}
 		return fmt.Errorf("iperf2 is not installed on the remote host")
 	}
 
@@ -78,14 +216,14 @@ func (m *ServerManager) start(client *Client, port, numInstances int) error {
 
 	// 2. Unix daemon mode — start both TCP and UDP listeners.
 	for _, p := range ports {
-		if _, err := client.RunCommand(fmt.Sprintf("iperf -s -p %d -D", p)); err != nil {
+		if _, err := client.RunCommand(fmt.Sprintf("%s -s -p %d -D", m.binary(), p)); err != nil {
 			return fmt.Errorf("start remote iperf2 TCP server on port %d: %w", p, err)
 		}
-		if _, err := client.RunCommand(fmt.Sprintf("iperf -s -u -p %d -D", p)); err != nil {
+		if _, err := client.RunCommand(fmt.Sprintf("%s -s -u -p %d -D", m.binary(), p)); err != nil {
 			return fmt.Errorf("start remote iperf2 UDP server on port %d: %w", p, err)
 		}
 	}
-	time.Sleep(500 * time.Millisecond)
+	sleep(500 * time.Millisecond)
 	for _, p := range ports {
 		if err := isListening(client, p); err != nil {
 			return fmt.Errorf("iperf2 server started but not listening on port %d: %w", p, err)
@@ -101,16 +239,16 @@ func (m *ServerManager) start(client *Client, port, numInstances int) error {
 // startWindows starts a single iperf2 instance on the given port via WMI.
 // WMI is used because schtasks creates tasks in "Interactive only" logon mode
 // which does not run under non-interactive SSH sessions.
-func (m *ServerManager) startWindows(client *Client, port int, taskName string, extraFlags string) error {
+func (m *ServerManager) startWindows(client commandRunner, port int, taskName string, extraFlags string) error {
 	flags := fmt.Sprintf("-s -p %d", port)
 	if extraFlags != "" {
 		flags += " " + extraFlags
 	}
-	wmiCmd := fmt.Sprintf(`powershell -Command "Invoke-WmiMethod -Class Win32_Process -Name Create -ArgumentList 'iperf.exe %s'"`, flags)
+	wmiCmd := fmt.Sprintf(`powershell -Command "Invoke-WmiMethod -Class Win32_Process -Name Create -ArgumentList '%s %s'"`, m.winBinary(), flags)
 	if _, err := client.RunCommand(wmiCmd); err != nil {
 		return fmt.Errorf("start remote iperf2 server on port %d: %w", port, err)
 	}
-	time.Sleep(1 * time.Second)
+	sleep(1 * time.Second)
 	if err := isListening(client, port); err != nil {
 		return fmt.Errorf("iperf2 server started but not listening on port %d: %w", port, err)
 	}
@@ -124,7 +262,10 @@ func (m *ServerManager) StopServer(client *Client) error {
 	if client == nil {
 		return fmt.Errorf("SSH client is required to stop the server")
 	}
+	return m.stop(client)
+}
 
+func (m *ServerManager) stop(client commandRunner) error {
 	m.mu.Lock()
 	port := m.port
 	n := m.numInstances
@@ -137,10 +278,10 @@ func (m *ServerManager) StopServer(client *Client) error {
 	m.mu.Unlock()
 
 	// Try Unix kill first
-	if _, err := client.RunCommand("pkill -f 'iperf -s'"); err != nil {
-		if _, err2 := client.RunCommand("killall iperf"); err2 != nil {
+	if _, err := client.RunCommand(fmt.Sprintf("pkill -f '%s -s'", m.baseName())); err != nil {
+		if _, err2 := client.RunCommand("killall " + m.baseName()); err2 != nil {
 			// Windows
-			client.RunCommand("taskkill /IM iperf.exe /F")
+			client.RunCommand(fmt.Sprintf("taskkill /IM %s /F", m.imageName()))
 			ports := make([]int, n)
 			for i := range ports {
 				ports[i] = port + i
@@ -154,6 +295,10 @@ func (m *ServerManager) StopServer(client *Client) error {
 
 // CheckStatus checks whether iperf2 is actually listening on the remote host.
 func (m *ServerManager) CheckStatus(client *Client) (bool, error) {
+	return m.checkStatus(client)
+}
+
+func (m *ServerManager) checkStatus(client commandRunner) (bool, error) {
 	m.mu.Lock()
 	port := m.port
 	m.mu.Unlock()
@@ -183,9 +328,9 @@ func (m *ServerManager) CheckStatus(client *Client) (bool, error) {
 	}
 
 	// netstat unavailable: fall back to process list
-	out, err := client.RunCommand("pgrep -f 'iperf -s'")
+	out, err := client.RunCommand(fmt.Sprintf("pgrep -f '%s -s'", m.baseName()))
 	if err != nil {
-		outWin, errWin := client.RunCommand("tasklist | findstr iperf.exe")
+		outWin, errWin := client.RunCommand("tasklist | findstr " + m.imageName())
 		if errWin != nil || strings.TrimSpace(outWin) == "" {
 			m.mu.Lock()
 			m.running = false
@@ -206,6 +351,10 @@ func (m *ServerManager) CheckStatus(client *Client) (bool, error) {
 // numInstances controls how many iperf2 server instances to start on consecutive
 // ports. Pass 0 to use the default (2).
 func (m *ServerManager) RestartServer(client *Client, port, numInstances int) error {
+	return m.restart(client, port, numInstances)
+}
+
+func (m *ServerManager) restart(client commandRunner, port, numInstances int) error {
 	if numInstances < 1 {
 		numInstances = 2
 	}
@@ -221,8 +370,8 @@ func (m *ServerManager) RestartServer(client *Client, port, numInstances int) er
 	m.mu.Unlock()
 
 	// Force-kill any stale processes and clean up old firewall rules.
-	client.RunCommand("pkill -f 'iperf -s'")
-	client.RunCommand("taskkill /IM iperf.exe /F")
+	client.RunCommand(fmt.Sprintf("pkill -f '%s -s'", m.baseName()))
+	client.RunCommand(fmt.Sprintf("taskkill /IM %s /F", m.imageName()))
 	cleanN := oldN
 	if numInstances > cleanN {
 		cleanN = numInstances
@@ -232,7 +381,7 @@ func (m *ServerManager) RestartServer(client *Client, port, numInstances int) er
 		oldPorts[i] = port + i
 	}
 	removeWindowsFirewallRules(client, oldPorts...)
-	time.Sleep(300 * time.Millisecond)
+	sleep(300 * time.Millisecond)
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -248,7 +397,7 @@ func (m *ServerManager) IsRunning() bool {
 
 // addWindowsFirewallRules opens TCP+UDP inbound for the given ports.
 // Errors are ignored — the rules are best-effort (requires admin privileges).
-func addWindowsFirewallRules(client *Client, ports ...int) {
+func addWindowsFirewallRules(client commandRunner, ports ...int) {
 	for _, p := range ports {
 		rule := fmt.Sprintf("iperf2-%d", p)
 		client.RunCommand(fmt.Sprintf(
@@ -259,7 +408,7 @@ func addWindowsFirewallRules(client *Client, ports ...int) {
 }
 
 // removeWindowsFirewallRules removes the firewall rules created by addWindowsFirewallRules.
-func removeWindowsFirewallRules(client *Client, ports ...int) {
+func removeWindowsFirewallRules(client commandRunner, ports ...int) {
 	for _, p := range ports {
 		rule := fmt.Sprintf("iperf2-%d", p)
 		client.RunCommand(fmt.Sprintf(
@@ -269,7 +418,7 @@ func removeWindowsFirewallRules(client *Client, ports ...int) {
 
 // isListening returns nil if something is listening on port (netstat works on
 // both Unix and Windows).
-func isListening(client *Client, port int) error {
+func isListening(client commandRunner, port int) error {
 	out, _ := client.RunCommand("netstat -an")
 	needle := fmt.Sprintf(":%d", port)
 	for _, line := range strings.Split(out, "\n") {
@@ -0,0 +1,231 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRunner records commands and answers them from fixed rules.
+type fakeRunner struct {
+	cmds      []string
+	listening []int  // ports reported as LISTEN by netstat
+	fail      string // commands with this prefix fail
+}
+
+func (f *fakeRunner) RunCommand(cmd string) (string, error) {
+	f.cmds = append(f.cmds, cmd)
+	switch {
+	case f.fail != "" && strings.HasPrefix(cmd, f.fail):
+		return "", errors.New("exit status 1")
+	case strings.HasPrefix(cmd, "cmd.exe"), strings.HasPrefix(cmd, "powershell"):
+		return "", errors.New("not windows")
+	case cmd == "netstat -an":
+		var b strings.Builder
+		for _, p := range f.listening {
+			fmt.Fprintf(&b, "tcp  0  0 0.0.0.0:%d  0.0.0.0:*  LISTEN\n", p)
+		}
+		return b.String(), nil
+	}
+	return "", nil
+}
+
+func (f *fakeRunner) issued(cmd string) bool {
+	for _, c := range f.cmds {
+		if c == cmd {
+			return true
+		}
+	}
+	return false
+}
+
+func noSleep(t *testing.T) {
+	t.Helper()
+	orig := sleep
+	sleep = func(time.Duration) {}
+	t.Cleanup(func() { sleep = orig })
+}
+
+func TestServerManager_StartCustomBinary(t *testing.T) {
+	noSleep(t)
+	f := &fakeRunner{listening: []int{5201, 5202}}
+	m := NewServerManager()
+	m.IperfPath = "/opt/iperf2/bin/iperf2"
+
+	if err := m.start(f, 5201, 2); err != nil {
+		t.Fatalf("start() error: %v", err)
+	}
+	for _, want := range []string{
+		"command -v '/opt/iperf2/bin/iperf2'",
+		"/opt/iperf2/bin/iperf2 -s -p 5201 -D",
+		"/opt/iperf2/bin/iperf2 -s -u -p 5201 -D",
+		"/opt/iperf2/bin/iperf2 -s -p 5202 -D",
+	} {
+		if !f.issued(want) {
+			t.Errorf("command %q not issued; got %q", want, f.cmds)
+		}
+	}
+	if f.issued("which iperf") {
+		t.Error("default PATH check used despite IperfPath")
+	}
+}
+
+func TestServerManager_StartDefaultBinary(t *testing.T) {
+	noSleep(t)
+	f := &fakeRunner{listening: []int{5201, 5202}}
+	m := NewServerManager()
+
+	if err := m.start(f, 5201, 2); err != nil {
+		t.Fatalf("start() error: %v", err)
+	}
+	if !f.issued("which iperf") || !f.issued("iperf -s -p 5201 -D") {
+		t.Errorf("default commands not issued; got %q", f.cmds)
+	}
+}
+
+func TestServerManager_StartCustomBinaryMissing(t *testing.T) {
+	noSleep(t)
+	f := &fakeRunner{fail: "command -v"}
+	m := NewServerManager()
+	m.IperfPath = "/opt/iperf2/bin/iperf"
+
+	err := m.start(f, 5201, 2)
+	if err == nil || !strings.Contains(err.Error(), "/opt/iperf2/bin/iperf") {
+		t.Fatalf("start() error = %v, want missing-binary error naming the path", err)
+	}
+}
+
+func TestServerManager_StopAndRestartMatchBasename(t *testing.T) {
+	noSleep(t)
+	f := &fakeRunner{listening: []int{5201, 5202}}
+	m := NewServerManager()
+	m.IperfPath = "/opt/iperf2/bin/iperf2"
+
+	if err := m.stop(f); err != nil {
+		t.Fatalf("stop() error: %v", err)
+	}
+	if !f.issued("pkill -f 'iperf2 -s'") {
+		t.Errorf("pkill pattern not based on basename; got %q", f.cmds)
+	}
+
+	f.cmds = nil
+	if err := m.restart(f, 5201, 2); err != nil {
+		t.Fatalf("restart() error: %v", err)
+	}
+	for _, want := range []string{"pkill -f 'iperf2 -s'", "taskkill /IM iperf2.exe /F"} {
+		if !f.issued(want) {
+			t.Errorf("command %q not issued; got %q", want, f.cmds)
+		}
+	}
+}
+
+func TestServerManager_StopFallsBackToKillall(t *testing.T) {
+	f := &fakeRunner{fail: "pkill"}
+	m := NewServerManager()
+	m.IperfPath = "/usr/local/bin/iperf2"
+
+	if err := m.stop(f); err != nil {
+		t.Fatalf("stop() error: %v", err)
+	}
+	if !f.issued("killall iperf2") {
+		t.Errorf("killall not issued with basename; got %q", f.cmds)
+	}
+}
+
+func TestServerManager_CheckStatusPgrepPattern(t *testing.T) {
+	f := &fakeRunner{fail: "netstat"}
+	m := NewServerManager()
+	m.IperfPath = `C:\tools\iperf2.exe`
+
+	if _, err := m.checkStatus(f); err != nil {
+		t.Fatalf("checkStatus() error: %v", err)
+	}
+	if !f.issued("pgrep -f 'iperf2 -s'") {
+		t.Errorf("pgrep pattern not based on basename; got %q", f.cmds)
+	}
+}
+
+// fakeStreamRunner emits lines from a foreground command and blocks until
+// release is closed, like a server session that runs until killed.
+type fakeStreamRunner struct {
+	fakeRunner
+	lines     []string
+	release   chan struct{}
+	streamCmd string
+}
+
+func (f *fakeStreamRunner) RunCommandStream(cmd string, onLine func(string)) (string, error) {
+	f.streamCmd = cmd
+	for _, l := range f.lines {
+		onLine(l)
+	}
+	<-f.release
+	return "", nil
+}
+
+func TestServerManager_StartServerStreaming(t *testing.T) {
+	noSleep(t)
+	f := &fakeStreamRunner{
+		fakeRunner: fakeRunner{listening: []int{5201}},
+		lines: []string{
+			"------------------------------------------------------------",
+			"Server listening on TCP port 5201",
+		},
+		release: make(chan struct{}),
+	}
+	m := NewServerManager()
+
+	got := make(chan string, 8)
+	if err := m.startStreaming(f, 5201, func(l string) { got <- l }); err != nil {
+		t.Fatalf("startStreaming() error: %v", err)
+	}
+	if !m.IsRunning() {
+		t.Error("IsRunning() = false after streaming start")
+	}
+	for _, want := range f.lines {
+		if l := <-got; l != want {
+			t.Errorf("line = %q, want %q", l, want)
+		}
+	}
+	if f.streamCmd != "iperf -s -p 5201 2>&1" {
+		t.Errorf("stream command = %q, want foreground server without -D", f.streamCmd)
+	}
+
+	close(f.release)
+	if l := <-got; l != "iperf2 server exited" {
+		t.Errorf("final line = %q, want exit notice", l)
+	}
+	deadline := time.Now().Add(time.Second)
+	for m.IsRunning() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if m.IsRunning() {
+		t.Error("IsRunning() = true after the server session ended")
+	}
+}
+
+func TestServerManager_StartServerStreamingExitsEarly(t *testing.T) {
+	noSleep(t)
+	f := &fakeStreamRunner{
+		fakeRunner: fakeRunner{},
+		lines:      []string{"bind failed: Address already in use"},
+		release:    make(chan struct{}),
+	}
+	close(f.release)
+	m := NewServerManager()
+
+	var lines []string
+	sleep = func(time.Duration) { time.Sleep(10 * time.Millisecond) } // let the session end
+	err := m.startStreaming(f, 5201, func(l string) { lines = append(lines, l) })
+	if err == nil || !strings.Contains(err.Error(), "exited on start") {
+		t.Fatalf("startStreaming() error = %v, want exited-on-start", err)
+	}
+	if len(lines) != 1 || !strings.Contains(lines[0], "Address already in use") {
+		t.Errorf("lines = %q, want the server's bind error", lines)
+	}
+	if m.IsRunning() {
+		t.Error("IsRunning() = true after failed start")
+	}
+}
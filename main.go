@@ -5,11 +5,15 @@ import (
 	"os"
 	"os/signal"
 	"sync/atomic"
+	"time"
 
 	"fyne.io/fyne/v2/app"
 
 	"iperf-tool/internal/cli"
+	"iperf-tool/internal/export"
+	"iperf-tool/internal/format"
 	"iperf-tool/internal/iperf"
+	"iperf-tool/internal/model"
 	"iperf-tool/ui"
 )
 
@@ -29,33 +33,135 @@ func main() {
 
 	// CLI mode
 	if err := runCLI(cfg); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if cfg.ErrorJSON {
+			cli.WriteErrorJSON(os.Stdout, *cfg, err, time.Now())
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
 		os.Exit(1)
 	}
 }
 
 func runCLI(cfg *cli.RunnerConfig) error {
+	format.Units = cfg.UnitSystem()
+
+	// Compare: diff two saved result files (no iperf, no ping)
+	if len(cfg.Compare) == 2 {
+		out, err := cli.CompareFiles(cfg.Compare[0], cfg.Compare[1])
+		if err != nil {
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	}
+
+	// Doctor: report whether this machine is ready to run tests
+	if cfg.Doctor {
+		report, ready := cli.Doctor(*cfg)
+		fmt.Print(report)
+		if !ready {
+			return fmt.Errorf("environment not ready")
+		}
+		return nil
+	}
+
+	// Import: format a saved iperf2 output file (no iperf, no ping)
+	if cfg.Import != "" {
+		result, err := cli.ImportResult(*cfg)
+		if err != nil {
+			return err
+		}
+		cli.PrintResult(result, *cfg)
+		return nil
+	}
+
+	// MTU check: report UDP fragmentation for the configured -l and exit
+	if cfg.MTUCheck > 0 {
+		return cli.MTUCheck(*cfg, os.Stdout)
+	}
+
+	// Dry run: print the commands and touch nothing (no SSH, no iperf)
+	if cfg.DryRun {
+		for _, sc := range cfg.PerServer() {
+			if err := cli.DryRun(sc, os.Stdout); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Local listen-mode server
+	if cfg.Listen {
+		return cli.RunLocalServer(*cfg)
+	}
+
 	// Handle remote server operations (connect SSH first, then optionally test)
 	if cfg.SSHHost != "" {
 		return runRemoteServer(cfg)
 	}
 
+	// UDP bitrate sweep (local only)
+	if len(cfg.Sweep) > 0 {
+		results, err := cli.BitrateSweep(*cfg, cfg.Sweep, cfg.LossThreshold)
+		fmt.Fprintln(cfg.Stdout())
+		fmt.Fprint(cfg.Stdout(), cli.SweepSummary(cfg.Sweep, results, cfg.LossThreshold))
+		return err
+	}
+
 	// Handle repeat mode (local only)
 	if cfg.Repeat {
 		return runCLIRepeat(cfg)
 	}
 
-	// Handle local test
-	result, err := cli.LocalTestRunner(*cfg)
-	if err != nil {
+	// Scheduled runs at wall-clock ticks
+	if cfg.Every > 0 {
+		return cli.RunScheduled(*cfg, cfg.Every, cfg.ScheduleDeadline(time.Now()))
+	}
+
+	// Concurrent A/B test against all servers at once
+	if cfg.Concurrent {
+		_, err := cli.RunConcurrent(cfg.PerServer())
 		return err
 	}
 
-	cli.PrintResult(result)
+	// Handle local test
+	servers := cfg.PerServer()
+	failed := 0
+	for i, sc := range servers {
+		if len(servers) > 1 {
+			if i > 0 {
+				fmt.Fprintln(cfg.Stdout())
+			}
+			fmt.Fprintln(cfg.Stdout(), cli.ServerHeader(i+1, len(servers), sc.ServerAddr))
+		}
+		result, err := runTest(sc)
+		if err != nil {
+			if len(servers) == 1 {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Server %s error: %v\n", sc.ServerAddr, err)
+			failed++
+			continue
+		}
+		cli.PrintResult(result, *cfg)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d server(s) failed", failed, len(servers))
+	}
 	return nil
 }
 
+func runTest(cfg cli.RunnerConfig) (*model.TestResult, error) {
+	if cfg.Average > 1 {
+		return cli.RunAveraged(cfg, cfg.Average)
+	}
+	return cli.LocalTestRunner(cfg)
+}
+
 func runCLIRepeat(cfg *cli.RunnerConfig) error {
+	// One interval CSV for the whole session, even across midnight
+	cfg.PinIntervalLog(time.Now())
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt)
 
@@ -63,11 +169,14 @@ func runCLIRepeat(cfg *cli.RunnerConfig) error {
 
 	go func() {
 		<-sigCh
-		fmt.Println("\nStop requested — finishing current measurement...")
+		fmt.Fprintln(cfg.Stdout(), "\nStop requested — finishing current measurement...")
 		atomic.StoreInt32(&stopped, 1)
 	}()
 
 	totalRuns := 0
+	errored := 0
+	var totals cli.RunTotals
+	var results []model.TestResult
 	for runNum := 1; ; runNum++ {
 		if atomic.LoadInt32(&stopped) == 1 {
 			break
@@ -77,24 +186,46 @@ func runCLIRepeat(cfg *cli.RunnerConfig) error {
 		}
 
 		if runNum > 1 {
-			fmt.Printf("\n--- Repeat run %d", runNum)
+			fmt.Fprintf(cfg.Stdout(), "\n--- Repeat run %d", runNum)
 			if cfg.RepeatCount > 0 {
-				fmt.Printf(" of %d", cfg.RepeatCount)
+				fmt.Fprintf(cfg.Stdout(), " of %d", cfg.RepeatCount)
 			}
-			fmt.Println(" ---")
+			fmt.Fprintln(cfg.Stdout(), " ---")
 		}
 
-		result, err := cli.LocalTestRunner(*cfg)
-		totalRuns++
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Run %d error: %v\n", runNum, err)
-			// Continue on transient errors (good for long-term monitoring)
-			continue
+		servers := cfg.PerServer()
+		for i, sc := range servers {
+			if i > 0 && atomic.LoadInt32(&stopped) == 1 {
+				break
+			}
+			if len(servers) > 1 {
+				fmt.Fprintln(cfg.Stdout(), cli.ServerHeader(i+1, len(servers), sc.ServerAddr))
+			}
+
+			result, err := cli.LocalTestRunner(sc)
+			totalRuns++
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Run %d error: %v\n", runNum, err)
+				errored++
+				// Continue on transient errors (good for long-term monitoring)
+				continue
+			}
+			cli.PrintResult(result, *cfg)
+			results = append(results, *result)
+			totals.Add(result)
+			if sc.PromPath != "" {
+				if err := export.WritePromMetrics(sc.PromPath, result); err != nil {
+					fmt.Fprintf(os.Stderr, "Write metrics error: %v\n", err)
+				}
+			}
 		}
-		cli.PrintResult(result)
 	}
 
-	fmt.Printf("\nCompleted %d run(s).\n", totalRuns)
+	fmt.Fprintf(cfg.Stdout(), "\n%s\n", totals.Completed(totalRuns, "run(s)"))
+	if totalRuns > 1 {
+		fmt.Fprintln(cfg.Stdout())
+		fmt.Fprint(cfg.Stdout(), cli.SummarizeRuns(results, errored))
+	}
 	return nil
 }
 
@@ -131,6 +262,11 @@ func runRemoteServer(cfg *cli.RunnerConfig) error {
 	if cfg.ServerAddr != "" {
 		cfg.SSHClient = runner.Client()
 		cfg.IsWindows = runner.IsWindows()
+		if v, err := runner.CheckRemoteVersion(); err == nil {
+			cfg.RemoteIperfVersion = v
+		} else if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Remote iperf2 version check: %v\n", err)
+		}
 		// For reverse/bidir the remote client needs our local IP
 		if (cfg.Reverse || cfg.Bidir) && cfg.SSHClient != nil {
 			if lap, ok := cfg.SSHClient.(iperf.LocalAddrProvider); ok {
@@ -141,12 +277,15 @@ func runRemoteServer(cfg *cli.RunnerConfig) error {
 		if cfg.Repeat {
 			return runCLIRepeat(cfg)
 		}
+		if cfg.Every > 0 {
+			return cli.RunScheduled(*cfg, cfg.Every, cfg.ScheduleDeadline(time.Now()))
+		}
 
-		result, err := cli.LocalTestRunner(*cfg)
+		result, err := runTest(*cfg)
 		if err != nil {
 			return err
 		}
-		cli.PrintResult(result)
+		cli.PrintResult(result, *cfg)
 	}
 
 	return nil
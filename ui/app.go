@@ -1,15 +1,23 @@
 package ui
 
 import (
+	"fmt"
+	"path/filepath"
 	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
+
+	"iperf-tool/internal/export"
+	"iperf-tool/internal/model"
 )
 
 const windowsHostsPrefKey = "remote.known_windows_hosts"
 
+// historyFileName is the result store file in the app storage directory.
+const historyFileName = "history.json"
+
 // BuildMainWindow creates and configures the main application window.
 func BuildMainWindow(app fyne.App) fyne.Window {
 	win := app.NewWindow("iperf2 Test Tool")
@@ -17,8 +25,11 @@ func BuildMainWindow(app fyne.App) fyne.Window {
 	configForm := NewConfigForm()
 	remotePanel := NewRemotePanel(win)
 	outputView := NewOutputView()
+	chart := NewThroughputChart()
+	historyView := NewHistoryView(win)
+	localServer := NewLocalServerPanel(configForm, outputView)
 	savedFilesList := NewSavedFilesList()
-	controls := NewControls(configForm, outputView, savedFilesList, remotePanel, win)
+	controls := NewControls(configForm, outputView, chart, savedFilesList, remotePanel, win)
 
 	prefs := app.Preferences()
 
@@ -65,6 +76,25 @@ func BuildMainWindow(app fyne.App) fyne.Window {
 	remotePanel.LoadPreferences(prefs)
 	controls.LoadPreferences(prefs)
 
+	// Keep recent measurements across restarts in the app storage directory.
+	store := model.NewResultStore(prefs.IntWithFallback("history.max_results", model.DefaultStoreCap))
+	historyPath := filepath.Join(app.Storage().RootURI().Path(), historyFileName)
+	if err := store.Load(historyPath); err != nil {
+		outputView.AppendLine(fmt.Sprintf("History load error: %v", err))
+	}
+	if store.Len() > 0 {
+		historyView.SetResults(store.Recent(0), "Recent measurements")
+	}
+	controls.ResultStore = store
+	controls.OnResultStored = func(r model.TestResult) {
+		if err := export.EnsureDir(historyPath); err != nil {
+			outputView.AppendLine(fmt.Sprintf("History save error: %v", err))
+		} else if err := store.Save(historyPath); err != nil {
+			outputView.AppendLine(fmt.Sprintf("History save error: %v", err))
+		}
+		historyView.AddResult(r)
+	}
+
 	leftPanel := container.NewVBox(
 		configForm.Container(),
 		widget.NewSeparator(),
@@ -72,6 +102,7 @@ func BuildMainWindow(app fyne.App) fyne.Window {
 	)
 	centerPanel := container.NewVBox(
 		remotePanel.Container(),
+		localServer.Container(),
 	)
 	rightPanel := container.NewStack(
 		savedFilesList.Container(),
@@ -108,8 +139,14 @@ func BuildMainWindow(app fyne.App) fyne.Window {
 
 	topBar := container.NewBorder(nil, nil, nil, container.NewHBox(filesBtn))
 	upper := container.NewBorder(topBar, nil, nil, nil, mainArea)
-	content := container.NewVSplit(upper, outputView.Container())
-	content.SetOffset(MainSplitRatio)
+	lower := container.NewBorder(chart, nil, nil, nil, outputView.Container())
+	testTab := container.NewVSplit(upper, lower)
+	testTab.SetOffset(MainSplitRatio)
+
+	content := container.NewAppTabs(
+		container.NewTabItem("Test", testTab),
+		container.NewTabItem("History", historyView.Container()),
+	)
 
 	// Restore saved window size or use defaults
 	w := prefs.FloatWithFallback("ui.window_width", float64(WindowWidth))
@@ -119,6 +156,7 @@ func BuildMainWindow(app fyne.App) fyne.Window {
 	win.SetContent(content)
 
 	win.SetCloseIntercept(func() {
+		localServer.Stop()
 		configForm.SavePreferences(prefs)
 		remotePanel.SavePreferences(prefs)
 		controls.SavePreferences(prefs)
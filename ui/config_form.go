@@ -3,6 +3,7 @@ package ui
 import (
 	"runtime"
 	"strconv"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -18,13 +19,30 @@ type ConfigForm struct {
 	parallelEntry    *widget.Select
 	intervalEntry    *widget.Entry
 	durationEntry    *widget.Entry
+	bytesLimitEntry  *widget.Entry
+	blocksLimitEntry *widget.Entry
+	omitEntry        *widget.Entry
 	protocolRadio    *widget.RadioGroup
 	directionRadio   *widget.RadioGroup
+	bidirModeRadio   *widget.RadioGroup
 	blockSizeEntry   *widget.Entry
+	blockSizeItem    *widget.FormItem
+	perfForm         *widget.Form
 	bandwidthEntry   *widget.Entry
+	windowEntry      *widget.Entry
+	mssEntry         *widget.Entry
+	connTimeoutEntry *widget.Entry
+	dscpEntry        *widget.Entry
+	bindEntry        *widget.Entry
+	pingCountEntry   *widget.Entry
 	measurePingCheck *widget.Check
-	ipv6Check        *widget.Check
+	noDelayCheck     *widget.Check
+	noSrvOutCheck    *widget.Check
+	requireVerCheck  *widget.Check
+	familyRadio      *widget.RadioGroup
 	binaryEntry      *widget.Entry
+	labelEntry       *widget.Entry
+	extraArgsEntry   *widget.Entry
 	form             *fyne.Container
 
 	// OnProtocolChange is called when the protocol radio selection changes.
@@ -50,11 +68,22 @@ func NewConfigForm() *ConfigForm {
 
 	cf.intervalEntry = widget.NewEntry()
 	cf.intervalEntry.SetText("1")
+	cf.intervalEntry.SetPlaceHolder("1, 0.5")
 
 	cf.durationEntry = widget.NewEntry()
 	cf.durationEntry.SetText("10")
 
+	cf.bytesLimitEntry = widget.NewEntry()
+	cf.bytesLimitEntry.SetPlaceHolder("1G (instead of duration)")
+
+	cf.blocksLimitEntry = widget.NewEntry()
+	cf.blocksLimitEntry.SetPlaceHolder("10000 (instead of duration)")
+
+	cf.omitEntry = widget.NewEntry()
+	cf.omitEntry.SetPlaceHolder("0")
+
 	cf.protocolRadio = widget.NewRadioGroup([]string{"TCP", "UDP"}, func(selected string) {
+		cf.updateBlockSizeLabel(selected)
 		if cf.OnProtocolChange != nil {
 			cf.OnProtocolChange(selected)
 		}
@@ -66,14 +95,48 @@ func NewConfigForm() *ConfigForm {
 	cf.directionRadio.SetSelected("Normal")
 	cf.directionRadio.Horizontal = true
 
+	// Auto runs dualtest (-d) without SSH and split mode with it
+	cf.bidirModeRadio = widget.NewRadioGroup([]string{"Auto", "Dualtest", "Split"}, nil)
+	cf.bidirModeRadio.SetSelected("Auto")
+	cf.bidirModeRadio.Horizontal = true
+
 	cf.blockSizeEntry = widget.NewEntry()
 	cf.blockSizeEntry.SetPlaceHolder("default")
 
 	cf.bandwidthEntry = widget.NewEntry()
 	cf.bandwidthEntry.SetPlaceHolder("100M, 1G")
 
+	cf.windowEntry = widget.NewEntry()
+	cf.windowEntry.SetPlaceHolder("256K, 2M")
+
+	cf.mssEntry = widget.NewEntry()
+	cf.mssEntry.SetPlaceHolder("default, 1400")
+
+	cf.connTimeoutEntry = widget.NewEntry()
+	cf.connTimeoutEntry.SetPlaceHolder("default, 3000")
+
+	cf.dscpEntry = widget.NewEntry()
+	cf.dscpEntry.SetPlaceHolder("ef, af41, 184")
+
+	cf.bindEntry = widget.NewEntry()
+	cf.bindEntry.SetPlaceHolder("auto")
+
+	cf.labelEntry = widget.NewEntry()
+	cf.labelEntry.SetPlaceHolder("office-wifi, vpn-on")
+
+	cf.extraArgsEntry = widget.NewEntry()
+	cf.extraArgsEntry.SetPlaceHolder("--trip-times (passed to iperf2 as-is)")
+
+	cf.pingCountEntry = widget.NewEntry()
+	cf.pingCountEntry.SetText("4")
+
 	cf.measurePingCheck = widget.NewCheck("Measure Ping", nil)
-	cf.ipv6Check = widget.NewCheck("IPv6", nil)
+	cf.noDelayCheck = widget.NewCheck("TCP no-delay (-N)", nil)
+	cf.noSrvOutCheck = widget.NewCheck("No server output log (SSH)", nil)
+	cf.requireVerCheck = widget.NewCheck("Require iperf2 "+iperf.MinVersion+" or newer", nil)
+	cf.familyRadio = widget.NewRadioGroup([]string{"Auto", "IPv4", "IPv6"}, nil)
+	cf.familyRadio.SetSelected("Auto")
+	cf.familyRadio.Horizontal = true
 
 	cf.binaryEntry = widget.NewEntry()
 	if runtime.GOOS == "windows" {
@@ -89,26 +152,44 @@ func NewConfigForm() *ConfigForm {
 			widget.NewFormItem("Server", cf.serverEntry),
 			widget.NewFormItem("Port", cf.portEntry),
 			widget.NewFormItem("Protocol", cf.protocolRadio),
+			widget.NewFormItem("IP Version", cf.familyRadio),
+			widget.NewFormItem("Bind IP", cf.bindEntry),
 		),
-		cf.ipv6Check,
 	)
 
 	testParams := container.NewVBox(
 		widget.NewForm(
 			widget.NewFormItem("Duration", cf.durationEntry),
+			widget.NewFormItem("Data (-n)", cf.bytesLimitEntry),
+			widget.NewFormItem("Blocks (-k)", cf.blocksLimitEntry),
 			widget.NewFormItem("Interval", cf.intervalEntry),
+			widget.NewFormItem("Omit (s)", cf.omitEntry),
 			widget.NewFormItem("Direction", cf.directionRadio),
+			widget.NewFormItem("Bidir mode", cf.bidirModeRadio),
+			widget.NewFormItem("Ping count", cf.pingCountEntry),
+			widget.NewFormItem("Label", cf.labelEntry),
+			widget.NewFormItem("Extra args", cf.extraArgsEntry),
 		),
 		cf.measurePingCheck,
+		cf.noSrvOutCheck,
 	)
 
+	cf.blockSizeItem = widget.NewFormItem("Block Size", cf.blockSizeEntry)
+	cf.perfForm = widget.NewForm(
+		widget.NewFormItem("Streams", cf.parallelEntry),
+		widget.NewFormItem("Bandwidth", cf.bandwidthEntry),
+		cf.blockSizeItem,
+		widget.NewFormItem("Window", cf.windowEntry),
+		widget.NewFormItem("MSS (TCP)", cf.mssEntry),
+		widget.NewFormItem("Connect timeout (ms)", cf.connTimeoutEntry),
+		widget.NewFormItem("DSCP/ToS", cf.dscpEntry),
+		widget.NewFormItem("iperf path", cf.binaryEntry),
+	)
+	cf.updateBlockSizeLabel(cf.protocolRadio.Selected)
 	performance := container.NewVBox(
-		widget.NewForm(
-			widget.NewFormItem("Streams", cf.parallelEntry),
-			widget.NewFormItem("Bandwidth", cf.bandwidthEntry),
-			widget.NewFormItem("Block Size", cf.blockSizeEntry),
-			widget.NewFormItem("iperf path", cf.binaryEntry),
-		),
+		cf.perfForm,
+		cf.noDelayCheck,
+		cf.requireVerCheck,
 	)
 
 	accordion := widget.NewAccordion(
@@ -125,6 +206,22 @@ func NewConfigForm() *ConfigForm {
 	return cf
 }
 
+// updateBlockSizeLabel names the -l field for the selected protocol: a
+// send buffer for TCP, a datagram size for UDP.
+func (cf *ConfigForm) updateBlockSizeLabel(protocol string) {
+	if cf.blockSizeItem == nil {
+		return // radio fired before the form was built
+	}
+	if protocol == "UDP" {
+		cf.blockSizeItem.Text = "Datagram size"
+		cf.blockSizeEntry.SetPlaceHolder("1470 (max 1472 at 1500 MTU)")
+	} else {
+		cf.blockSizeItem.Text = "Block Size"
+		cf.blockSizeEntry.SetPlaceHolder("default")
+	}
+	cf.perfForm.Refresh()
+}
+
 // Container returns the form's Fyne container.
 func (cf *ConfigForm) Container() *fyne.Container {
 	return cf.form
@@ -154,20 +251,63 @@ func (cf *ConfigForm) LoadPreferences(prefs fyne.Preferences) {
 	if v := prefs.String("config.duration"); v != "" {
 		cf.durationEntry.SetText(v)
 	}
+	if v := prefs.String("config.bytes_limit"); v != "" {
+		cf.bytesLimitEntry.SetText(v)
+	}
+	if v := prefs.String("config.blocks_limit"); v != "" {
+		cf.blocksLimitEntry.SetText(v)
+	}
+	if v := prefs.String("config.omit"); v != "" {
+		cf.omitEntry.SetText(v)
+	}
 	if v := prefs.String("config.protocol"); v != "" {
 		cf.protocolRadio.SetSelected(v)
 	}
 	if v := prefs.String("config.direction"); v != "" {
 		cf.directionRadio.SetSelected(v)
 	}
+	if v := prefs.String("config.bidir_mode"); v != "" {
+		cf.bidirModeRadio.SetSelected(v)
+	}
 	if v := prefs.String("config.block_size"); v != "" {
 		cf.blockSizeEntry.SetText(v)
 	}
 	if v := prefs.String("config.bandwidth"); v != "" {
 		cf.bandwidthEntry.SetText(v)
 	}
+	if v := prefs.String("config.window"); v != "" {
+		cf.windowEntry.SetText(v)
+	}
+	if v := prefs.String("config.mss"); v != "" {
+		cf.mssEntry.SetText(v)
+	}
+	if v := prefs.String("config.connect_timeout"); v != "" {
+		cf.connTimeoutEntry.SetText(v)
+	}
+	if v := prefs.String("config.dscp"); v != "" {
+		cf.dscpEntry.SetText(v)
+	}
+	if v := prefs.String("config.bind_addr"); v != "" {
+		cf.bindEntry.SetText(v)
+	}
+	if v := prefs.String("config.label"); v != "" {
+		cf.labelEntry.SetText(v)
+	}
+	if v := prefs.String("config.extra_args"); v != "" {
+		cf.extraArgsEntry.SetText(v)
+	}
+	if v := prefs.String("config.ping_count"); v != "" {
+		cf.pingCountEntry.SetText(v)
+	}
 	cf.measurePingCheck.SetChecked(prefs.Bool("config.measure_ping"))
-	cf.ipv6Check.SetChecked(prefs.Bool("config.ipv6"))
+	cf.noDelayCheck.SetChecked(prefs.Bool("config.no_delay"))
+	cf.noSrvOutCheck.SetChecked(prefs.Bool("config.no_server_output"))
+	cf.requireVerCheck.SetChecked(prefs.Bool("config.require_version"))
+	if v := prefs.String("config.addr_family"); v != "" {
+		cf.familyRadio.SetSelected(v)
+	} else if prefs.Bool("config.ipv6") {
+		cf.familyRadio.SetSelected("IPv6") // legacy checkbox preference
+	}
 	if v := prefs.String("config.binary"); v != "" {
 		cf.binaryEntry.SetText(v)
 	}
@@ -180,12 +320,27 @@ func (cf *ConfigForm) SavePreferences(prefs fyne.Preferences) {
 	prefs.SetString("config.parallel", cf.parallelEntry.Selected)
 	prefs.SetString("config.interval", cf.intervalEntry.Text)
 	prefs.SetString("config.duration", cf.durationEntry.Text)
+	prefs.SetString("config.bytes_limit", cf.bytesLimitEntry.Text)
+	prefs.SetString("config.blocks_limit", cf.blocksLimitEntry.Text)
+	prefs.SetString("config.omit", cf.omitEntry.Text)
 	prefs.SetString("config.protocol", cf.protocolRadio.Selected)
 	prefs.SetString("config.direction", cf.directionRadio.Selected)
+	prefs.SetString("config.bidir_mode", cf.bidirModeRadio.Selected)
 	prefs.SetString("config.block_size", cf.blockSizeEntry.Text)
 	prefs.SetString("config.bandwidth", cf.bandwidthEntry.Text)
+	prefs.SetString("config.window", cf.windowEntry.Text)
+	prefs.SetString("config.mss", cf.mssEntry.Text)
+	prefs.SetString("config.connect_timeout", cf.connTimeoutEntry.Text)
+	prefs.SetString("config.dscp", cf.dscpEntry.Text)
+	prefs.SetString("config.bind_addr", cf.bindEntry.Text)
+	prefs.SetString("config.ping_count", cf.pingCountEntry.Text)
+	prefs.SetString("config.label", cf.labelEntry.Text)
+	prefs.SetString("config.extra_args", cf.extraArgsEntry.Text)
 	prefs.SetBool("config.measure_ping", cf.measurePingCheck.Checked)
-	prefs.SetBool("config.ipv6", cf.ipv6Check.Checked)
+	prefs.SetBool("config.no_delay", cf.noDelayCheck.Checked)
+	prefs.SetBool("config.no_server_output", cf.noSrvOutCheck.Checked)
+	prefs.SetBool("config.require_version", cf.requireVerCheck.Checked)
+	prefs.SetString("config.addr_family", cf.familyRadio.Selected)
 	prefs.SetString("config.binary", cf.binaryEntry.Text)
 }
 
@@ -194,9 +349,17 @@ func (cf *ConfigForm) SavePreferences(prefs fyne.Preferences) {
 func (cf *ConfigForm) Config() iperf.IperfConfig {
 	port := parsePort(cf.portEntry.Text, 5201)
 	parallel := parseIntOrDefault(cf.parallelEntry.Selected, 1)
-	interval := parseIntOrDefault(cf.intervalEntry.Text, 1)
+	interval := parseFloatOrDefault(cf.intervalEntry.Text, 1)
 	duration := parseIntOrDefault(cf.durationEntry.Text, 10)
+	omit := parseIntOrDefault(cf.omitEntry.Text, 0)
+	blocksLimit := parseIntOrDefault(cf.blocksLimitEntry.Text, 0)
 	blockSize := parseIntOrDefault(cf.blockSizeEntry.Text, 0)
+	mss := parseIntOrDefault(cf.mssEntry.Text, 0)
+	connTimeout := parseIntOrDefault(cf.connTimeoutEntry.Text, 0)
+	pingCount := parseIntOrDefault(cf.pingCountEntry.Text, 4)
+	if pingCount < 1 {
+		pingCount = 4
+	}
 
 	protocol := "tcp"
 	if cf.protocolRadio.Selected == "UDP" {
@@ -206,20 +369,44 @@ func (cf *ConfigForm) Config() iperf.IperfConfig {
 	reverse := cf.directionRadio.Selected == "Reverse"
 	bidir := cf.directionRadio.Selected == "Bidir"
 
+	family := ""
+	switch cf.familyRadio.Selected {
+	case "IPv4":
+		family = "4"
+	case "IPv6":
+		family = "6"
+	}
+
 	return iperf.IperfConfig{
 		BinaryPath:  cf.binaryEntry.Text,
 		ServerAddr:  cf.serverEntry.Text,
 		Port:        port,
 		Parallel:    parallel,
 		Duration:    duration,
-		Interval:    interval,
+		BytesLimit:  strings.ToUpper(strings.TrimSpace(cf.bytesLimitEntry.Text)),
+		BlocksLimit: blocksLimit,
+		IntervalSec: interval,
 		Protocol:    protocol,
 		BlockSize:   blockSize,
 		Reverse:     reverse,
 		Bidir:       bidir,
+		BidirMode:   strings.ToLower(cf.bidirModeRadio.Selected),
 		Bandwidth:   cf.bandwidthEntry.Text,
 		MeasurePing: cf.measurePingCheck.Checked,
-		IPv6:        cf.ipv6Check.Checked,
+		PingCount:   pingCount,
+		AddrFamily:  family,
 		Enhanced:    true,
+		OmitSeconds: omit,
+		DSCP:        strings.TrimSpace(cf.dscpEntry.Text),
+		WindowSize:  strings.TrimSpace(cf.windowEntry.Text),
+		BindAddr:    strings.TrimSpace(cf.bindEntry.Text),
+		NoDelay:     cf.noDelayCheck.Checked,
+		MSS:         mss,
+		Label:       strings.TrimSpace(cf.labelEntry.Text),
+		ExtraArgs:   strings.Fields(cf.extraArgsEntry.Text),
+
+		ConnectTimeoutMs: connTimeout,
+		NoServerOutput:   cf.noSrvOutCheck.Checked,
+		RequireVersion:   cf.requireVerCheck.Checked,
 	}
 }
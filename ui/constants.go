@@ -19,6 +19,12 @@ const (
 	OutputViewMinHeight = 100
 )
 
+// ThroughputChart dimensions
+const (
+	ChartMinWidth  = 200
+	ChartMinHeight = 80
+)
+
 // NewWindowSize returns the default window size
 func NewWindowSize() fyne.Size {
 	return fyne.NewSize(WindowWidth, WindowHeight)
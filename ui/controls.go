@@ -42,9 +42,12 @@ type Controls struct {
 	stopBtn       *StyledButton
 	repeatBtn     *StyledButton
 	fileNameEntry *widget.Entry
+	jsonCheck     *widget.Check
+	csvSepSelect  *widget.Select
 
 	configForm     *ConfigForm
 	outputView     *OutputView
+	chart          *ThroughputChart
 	savedFilesList *SavedFilesList
 	remotePanel    *RemotePanel
 	runner         *iperf.Runner
@@ -57,13 +60,18 @@ type Controls struct {
 	// it only fires for confirmed Windows targets.
 	IsHostKnownWindows func(host string) bool
 
+	// ResultStore, when set, receives every auto-saved result; OnResultStored
+	// is called after each one is added so the caller can persist the store.
+	ResultStore    *model.ResultStore
+	OnResultStored func(r model.TestResult)
+
 	container *fyne.Container
 }
 
 // NewControls creates the control buttons wired to the given views.
 // Set IPERF_DEBUG=1 in the environment to enable raw stream logging to
 // /tmp/iperf-debug.log.
-func NewControls(cf *ConfigForm, ov *OutputView, sfl *SavedFilesList, rp *RemotePanel, win fyne.Window) *Controls {
+func NewControls(cf *ConfigForm, ov *OutputView, tc *ThroughputChart, sfl *SavedFilesList, rp *RemotePanel, win fyne.Window) *Controls {
 	runner := iperf.NewRunner()
 	if os.Getenv("IPERF_DEBUG") == "1" {
 		runner = iperf.NewDebugRunner()
@@ -71,6 +79,7 @@ func NewControls(cf *ConfigForm, ov *OutputView, sfl *SavedFilesList, rp *Remote
 	c := &Controls{
 		configForm:     cf,
 		outputView:     ov,
+		chart:          tc,
 		savedFilesList: sfl,
 		remotePanel:    rp,
 		runner:         runner,
@@ -90,12 +99,19 @@ func NewControls(cf *ConfigForm, ov *OutputView, sfl *SavedFilesList, rp *Remote
 	c.fileNameEntry = widget.NewEntry()
 	c.fileNameEntry.SetPlaceHolder("results/results")
 
+	c.jsonCheck = widget.NewCheck("Also save JSON", nil)
+
+	c.csvSepSelect = widget.NewSelect([]string{csvSepSemicolon, csvSepComma, csvSepTab}, nil)
+	c.csvSepSelect.SetSelected(csvSepSemicolon)
+
 	c.container = container.NewVBox(
 		c.startBtn,
 		c.stopBtn,
 		c.repeatBtn,
 		widget.NewLabel("Output File Path and Name"),
 		c.fileNameEntry,
+		c.jsonCheck,
+		container.NewHBox(widget.NewLabel("CSV separator"), c.csvSepSelect),
 	)
 	return c
 }
@@ -128,12 +144,18 @@ func (c *Controls) LoadPreferences(prefs fyne.Preferences) {
 	if v := prefs.String("controls.output_path"); v != "" {
 		c.fileNameEntry.SetText(v)
 	}
+	c.jsonCheck.SetChecked(prefs.Bool("controls.save_json"))
+	if v := prefs.String("controls.csv_sep"); v != "" {
+		c.csvSepSelect.SetSelected(v)
+	}
 }
 
 // SavePreferences persists control state.
 func (c *Controls) SavePreferences(prefs fyne.Preferences) {
 	prefs.SetBool("controls.repeat", c.repeatOn)
 	prefs.SetString("controls.output_path", c.fileNameEntry.Text)
+	prefs.SetBool("controls.save_json", c.jsonCheck.Checked)
+	prefs.SetString("controls.csv_sep", c.csvSepSelect.Selected)
 }
 
 func (c *Controls) onStart() {
@@ -182,7 +204,7 @@ func (c *Controls) wireSSHConfig(cfg *iperf.IperfConfig) {
 		cfg.LocalAddr = c.remotePanel.LocalAddr()
 	}
 	cfg.IsWindows = c.remotePanel.IsWindows()
-	if cfg.RemoteOutputFile == "" {
+	if cfg.RemoteOutputFile == "" && !cfg.NoServerOutput {
 		if cfg.IsWindows {
 			cfg.RemoteOutputFile = `C:\iperf2_server_output.txt`
 		} else {
@@ -252,6 +274,12 @@ func (c *Controls) connectSSHThenStart(cfg iperf.IperfConfig) {
 // proceedWithTest runs the iperf test with the given config.
 func (c *Controls) proceedWithTest(cfg iperf.IperfConfig) {
 	c.outputView.Clear()
+	if msg, ok := iperf.AdviseParallel(cfg.Parallel); ok {
+		c.outputView.AppendLine(msg)
+	}
+	if msg, ok := cfg.AdviseDatagram(); ok {
+		c.outputView.AppendLine(msg)
+	}
 
 	go func() {
 		defer c.resetState()
@@ -271,26 +299,67 @@ func (c *Controls) proceedWithTest(cfg iperf.IperfConfig) {
 func (c *Controls) runOnce(cfg iperf.IperfConfig) bool {
 	c.outputView.AppendLine(fmt.Sprintf("Starting iperf2 test to %s:%d ...", cfg.ServerAddr, cfg.Port))
 
+	iperfVersion, verErr := iperf.CheckVersion(cfg.BinaryPath)
+	if cfg.RequireVersion {
+		if verErr == nil {
+			verErr = iperf.RequireMinVersion(iperfVersion)
+		}
+		if verErr != nil {
+			c.outputView.AppendLine(fmt.Sprintf("Error: %v", verErr))
+			return false
+		}
+	}
+
+	// Preflight: fail fast when nothing listens on the server port. With SSH
+	// connected the run below starts the remote server on demand instead.
+	if cfg.Protocol != "udp" && !cfg.Reverse && !c.remotePanel.IsConnected() {
+		if err := netutil.ProbePort(cfg.ServerAddr, cfg.Port, 2*time.Second); err != nil {
+			c.outputView.AppendLine(fmt.Sprintf("Error: %v", err))
+			return false
+		}
+	}
+
 	ctx := context.Background()
 
-	// Phase 1: baseline ping
+	// Phase 1: baseline ping; when ICMP is filtered, skip the loaded ping and
+	// fall back to TCP connect time for TCP tests
 	var baseline *ping.Result
+	var tcpBaseline *model.PingResult
+	pingSkipped := false
+	latencyMethod := ""
+	pingCount := cfg.PingCount
+	if pingCount < 1 {
+		pingCount = ping.DefaultCount
+	}
 	if cfg.MeasurePing {
-		c.outputView.AppendLine("Running baseline ping (4 packets)...")
+		c.outputView.AppendLine(fmt.Sprintf("Running baseline ping (%d packets)...", pingCount))
+		var err error
+		baseline, err = ping.Run(ctx, cfg.ServerAddr, pingCount)
+		if ping.Blocked(baseline, err) {
+			c.outputView.AppendLine(ping.BlockedNotice)
+			baseline = nil
+			pingSkipped = true
+		} else {
+			latencyMethod = model.LatencyICMP
+			c.outputView.AppendLine("Baseline latency: " + format.PingStats(baseline.ToModel()))
+		}
+	}
+	if pingSkipped && cfg.Protocol != "udp" {
 		var err error
-		baseline, err = ping.Run(ctx, cfg.ServerAddr, 4)
+		tcpBaseline, err = netutil.TCPConnectTime(cfg.ServerAddr, cfg.Port, pingCount)
 		if err != nil {
-			c.outputView.AppendLine(fmt.Sprintf("Baseline ping failed: %v", err))
+			c.outputView.AppendLine(fmt.Sprintf("TCP connect time failed: %v", err))
 		} else {
-			c.outputView.AppendLine(fmt.Sprintf("Baseline latency: min/avg/max = %.2f / %.2f / %.2f ms",
-				baseline.MinMs, baseline.AvgMs, baseline.MaxMs))
+			latencyMethod = model.LatencyTCPConnect
+			c.outputView.AppendLine(fmt.Sprintf("TCP connect time: min/avg/max = %.2f / %.2f / %.2f ms",
+				tcpBaseline.MinMs, tcpBaseline.AvgMs, tcpBaseline.MaxMs))
 		}
 	}
 
 	// Phase 2: start background ping during iperf
 	var loadedCh chan *ping.Result
 	var pingCancel context.CancelFunc
-	if cfg.MeasurePing {
+	if cfg.MeasurePing && !pingSkipped {
 		var pingCtx context.Context
 		pingCtx, pingCancel = context.WithCancel(ctx)
 		loadedCh = make(chan *ping.Result, 1)
@@ -304,19 +373,20 @@ func (c *Controls) runOnce(cfg iperf.IperfConfig) bool {
 		}()
 	}
 
-	iperfVersion, _ := iperf.CheckVersion(cfg.BinaryPath)
-
 	// Print interval header
 	isUDP := strings.EqualFold(cfg.Protocol, "udp")
+	tsLayout := format.IntervalTimeLayout(cfg.IntervalSeconds())
+	timeCol := format.IntervalTimeColumn(tsLayout)
 	var header string
 	if cfg.Bidir {
-		header = "Time      " + format.FormatBidirIntervalHeader(isUDP)
+		header = timeCol + format.FormatBidirIntervalHeader(isUDP)
 	} else {
-		header = "Time      " + format.FormatIntervalHeader(isUDP)
+		header = timeCol + format.FormatIntervalHeader(isUDP)
 	}
 	c.outputView.AppendLine("")
 	c.outputView.AppendLine(header)
 	c.outputView.AppendLine(strings.Repeat("-", len(header)))
+	c.chart.Reset()
 
 	testStart := time.Now()
 	emit := func(fwd, rev *model.IntervalResult) {
@@ -327,7 +397,15 @@ func (c *Controls) runOnce(cfg iperf.IperfConfig) bool {
 		if ref == nil {
 			ref = rev
 		}
-		ts := testStart.Add(time.Duration(ref.TimeStart * float64(time.Second))).Format("15:04:05")
+		var fwdMbps, revMbps float64
+		if fwd != nil {
+			fwdMbps = fwd.BandwidthMbps()
+		}
+		if rev != nil {
+			revMbps = rev.BandwidthMbps()
+		}
+		c.chart.AddPoint(ref.TimeEnd, fwdMbps, revMbps)
+		ts := testStart.Add(time.Duration(ref.TimeStart * float64(time.Second))).Format(tsLayout)
 		if cfg.Bidir {
 			c.outputView.AppendLine(ts + "  " + format.FormatBidirInterval(fwd, rev, isUDP))
 		} else if fwd != nil {
@@ -344,6 +422,7 @@ func (c *Controls) runOnce(cfg iperf.IperfConfig) bool {
 
 	// Get SSH client from remote panel (may be nil if not connected)
 	sshCli := c.remotePanel.Client()
+	dualtest, modeErr := cfg.UseDualtest(sshCli != nil)
 
 	// Route runner status messages to the GUI output view
 	c.runner.SetStatusCallback(func(msg string) {
@@ -354,8 +433,11 @@ func (c *Controls) runOnce(cfg iperf.IperfConfig) bool {
 	// Dispatch based on direction
 	var result *model.TestResult
 	var err error
+	firstStart := time.Now()
 	if cfg.Bidir {
-		if sshCli == nil {
+		if modeErr != nil {
+			err = modeErr
+		} else if dualtest {
 			result, err = c.runner.RunBidirDualtest(ctx, cfg, onInterval)
 		} else {
 			result, err = c.runner.RunBidir(ctx, cfg, sshCli, onInterval)
@@ -382,13 +464,16 @@ func (c *Controls) runOnce(cfg iperf.IperfConfig) bool {
 				numInstances = 2
 			}
 			c.outputView.AppendLine(fmt.Sprintf("Server not responding, starting remote iperf2 (%d instances)...", numInstances))
+			if !c.remotePanel.IsAlive() {
+				c.outputView.AppendLine("SSH connection lost, reconnecting...")
+			}
 			if restartErr := c.remotePanel.RestartServer(numInstances); restartErr != nil {
 				c.outputView.AppendLine(fmt.Sprintf("Start failed: %v", restartErr))
 			} else {
 				c.outputView.AppendLine("Server started, retrying test...")
 				time.Sleep(time.Second)
 				if cfg.Bidir {
-					if sshCli == nil {
+					if dualtest {
 						result, err = c.runner.RunBidirDualtest(ctx, cfg, onInterval)
 					} else {
 						result, err = c.runner.RunBidir(ctx, cfg, sshCli, onInterval)
@@ -401,6 +486,9 @@ func (c *Controls) runOnce(cfg iperf.IperfConfig) bool {
 				if flushIntervals != nil {
 					flushIntervals()
 				}
+				if result != nil {
+					result.StartedAt = firstStart // wall clock includes the failed attempt
+				}
 			}
 		} else {
 			c.outputView.AppendLine("Tip: connect via SSH in the Remote panel, then retry — the server will be started automatically.")
@@ -410,6 +498,9 @@ func (c *Controls) runOnce(cfg iperf.IperfConfig) bool {
 	// Stop background ping and collect results
 	hostname, _ := os.Hostname()
 	localIP := netutil.OutboundIP()
+	if cfg.BindAddr != "" {
+		localIP = cfg.BindAddr // the test was pinned to this address
+	}
 	var pingBaseline, pingLoaded *model.PingResult
 	if cfg.MeasurePing && pingCancel != nil {
 		pingCancel()
@@ -417,6 +508,9 @@ func (c *Controls) runOnce(cfg iperf.IperfConfig) bool {
 		pingBaseline = baseline.ToModel()
 		pingLoaded = loaded.ToModel()
 	}
+	if tcpBaseline != nil {
+		pingBaseline = tcpBaseline
+	}
 
 	if err != nil {
 		c.outputView.AppendLine(fmt.Sprintf("Error: %v", err))
@@ -435,6 +529,8 @@ func (c *Controls) runOnce(cfg iperf.IperfConfig) bool {
 			IperfVersion:  iperfVersion,
 			PingBaseline:  pingBaseline,
 			PingLoaded:    pingLoaded,
+			PingSkipped:   pingSkipped,
+			LatencyMethod: latencyMethod,
 		}
 		if cfg.Bidir {
 			errResult.Direction = "Bidirectional"
@@ -450,13 +546,19 @@ func (c *Controls) runOnce(cfg iperf.IperfConfig) bool {
 
 	// Set config echo fields on the successful result.
 	cfg.ApplyToResult(result, "GUI")
+	netutil.RecordServer(result, cfg.ServerAddr)
+	_, result.CPULimitedWarning = iperf.AdviseParallel(cfg.Parallel)
 	result.LocalHostname = hostname
 	result.LocalIP = localIP
+	netutil.RecordInterface(result)
 	result.IperfVersion = iperfVersion
 	result.PingBaseline = pingBaseline
 	result.PingLoaded = pingLoaded
+	result.PingSkipped = pingSkipped
+	result.LatencyMethod = latencyMethod
 	if c.remotePanel.IsConnected() {
 		result.SSHRemoteHost = c.remotePanel.Host()
+		result.RemoteIperfVersion = c.remotePanel.RemoteVersion()
 	}
 	result.MeasurementID = export.NextMeasurementID(result.Timestamp)
 
@@ -528,9 +630,17 @@ func (c *Controls) autoSave(result *model.TestResult) {
 		baseName = filepath.Join("results", baseName)
 	}
 
+	if c.ResultStore != nil {
+		c.ResultStore.Add(result)
+		if c.OnResultStored != nil {
+			c.OnResultStored(*result)
+		}
+	}
+
 	dir := filepath.Dir(baseName)
 	fyne.Do(func() {
 		c.savedFilesList.SetDir(dir)
+		c.savedFilesList.SetResult(result)
 	})
 
 	if err := export.EnsureDir(baseName + ".csv"); err != nil {
@@ -538,12 +648,13 @@ func (c *Controls) autoSave(result *model.TestResult) {
 		return
 	}
 
+	delim := csvSepDelimiter(c.csvSepSelect.Selected)
 	date := result.Timestamp
 	logPath := export.BuildLogPath(baseName, "_log", ".csv")
 	csvPath := export.BuildPath(baseName, "", ".csv", date)
 	txtPath := export.BuildPath(baseName, "", ".txt", date)
 
-	if err := export.WriteCSV(logPath, []model.TestResult{*result}); err != nil {
+	if err := export.WriteCSVWithDelimiter(logPath, []model.TestResult{*result}, delim); err != nil {
 		c.outputView.AppendLine(fmt.Sprintf("Auto-save CSV error: %v", err))
 	}
 
@@ -551,8 +662,15 @@ func (c *Controls) autoSave(result *model.TestResult) {
 		c.outputView.AppendLine(fmt.Sprintf("Auto-save TXT error: %v", err))
 	}
 
+	if c.jsonCheck.Checked {
+		jsonPath := export.BuildPath(baseName, "", ".json", date)
+		if err := export.WriteJSON(jsonPath, []model.TestResult{*result}); err != nil {
+			c.outputView.AppendLine(fmt.Sprintf("Auto-save JSON error: %v", err))
+		}
+	}
+
 	if len(result.Intervals) > 0 {
-		if err := export.WriteIntervalLog(csvPath, result); err != nil {
+		if err := export.WriteIntervalLogWithDelimiter(csvPath, result, delim); err != nil {
 			c.outputView.AppendLine(fmt.Sprintf("Auto-save interval log error: %v", err))
 		}
 	}
@@ -575,3 +693,21 @@ func (c *Controls) resetState() {
 		c.stopBtn.Disable()
 	})
 }
+
+// CSV separator choices shown in the controls dropdown.
+const (
+	csvSepSemicolon = "Semicolon (;)"
+	csvSepComma     = "Comma (,)"
+	csvSepTab       = "Tab"
+)
+
+// csvSepDelimiter maps a dropdown choice to its delimiter rune.
+func csvSepDelimiter(choice string) rune {
+	switch choice {
+	case csvSepComma:
+		return ','
+	case csvSepTab:
+		return '\t'
+	}
+	return export.DefaultDelimiter
+}
@@ -2,27 +2,38 @@ package ui
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/widget"
 
+	"iperf-tool/internal/export"
+	"iperf-tool/internal/format"
 	"iperf-tool/internal/model"
 )
 
-var historyColumns = []string{"Time", "Server", "Sent (Mbps)", "Recv (Mbps)", "Duration (s)", "Status"}
+var historyColumns = []string{"Time", "Server", "Fwd (Mbps)", "Rev (Mbps)", "Duration (s)", "Status"}
 
-// HistoryView displays a table of past test results.
+// HistoryView displays past test results loaded from a _log.csv file,
+// newest first, with a detail pane for the selected row.
 type HistoryView struct {
 	mu      sync.Mutex
 	results []model.TestResult
 	table   *widget.Table
+	detail  *widget.Entry
+	source  *widget.Label
+	win     fyne.Window
+
+	container *fyne.Container
 }
 
 // NewHistoryView creates a new history table view.
-func NewHistoryView() *HistoryView {
-	hv := &HistoryView{}
+func NewHistoryView(win fyne.Window) *HistoryView {
+	hv := &HistoryView{win: win}
 
 	hv.table = widget.NewTable(
 		hv.tableSize,
@@ -33,22 +44,41 @@ func NewHistoryView() *HistoryView {
 	hv.table.SetColumnWidth(0, 160)
 	hv.table.SetColumnWidth(1, 140)
 	hv.table.SetColumnWidth(2, 100)
-	hv.table.SetColumnWidth(3, 120)
-	hv.table.SetColumnWidth(4, 80)
+	hv.table.SetColumnWidth(3, 100)
+	hv.table.SetColumnWidth(4, 100)
 	hv.table.SetColumnWidth(5, 120)
 
+	hv.table.OnSelected = hv.onSelected
+
+	hv.detail = widget.NewMultiLineEntry()
+	hv.detail.TextStyle = fyne.TextStyle{Monospace: true}
+	hv.detail.Wrapping = fyne.TextWrapOff
+	hv.detail.SetPlaceHolder("Select a measurement to see details")
+
+	hv.source = widget.NewLabel("No log loaded")
+	openBtn := widget.NewButton("Open log...", hv.showOpenDialog)
+
+	split := container.NewHSplit(hv.table, hv.detail)
+	split.SetOffset(0.6)
+
+	hv.container = container.NewBorder(
+		container.NewBorder(nil, nil, openBtn, nil, hv.source),
+		nil, nil, nil,
+		split,
+	)
 	return hv
 }
 
-// Container returns the table widget wrapped in a container.
+// Container returns the history view's Fyne container.
 func (hv *HistoryView) Container() *fyne.Container {
-	return container.NewMax(hv.table)
+	return hv.container
 }
 
 // AddResult appends a test result to the history, safe to call from any goroutine.
 func (hv *HistoryView) AddResult(r model.TestResult) {
 	hv.mu.Lock()
 	hv.results = append(hv.results, r)
+	sortNewestFirst(hv.results)
 	hv.mu.Unlock()
 	fyne.Do(func() {
 		hv.table.Refresh()
@@ -64,6 +94,77 @@ func (hv *HistoryView) Results() []model.TestResult {
 	return out
 }
 
+// LoadFile replaces the history with the results stored in a CSV log.
+func (hv *HistoryView) LoadFile(path string) error {
+	results, err := export.ReadCSVLog(path)
+	if err != nil {
+		return err
+	}
+	hv.SetResults(results, path)
+	return nil
+}
+
+// SetResults replaces the history with results, labelled by source.
+func (hv *HistoryView) SetResults(results []model.TestResult, source string) {
+	sortNewestFirst(results)
+
+	hv.mu.Lock()
+	hv.results = results
+	hv.mu.Unlock()
+
+	fyne.Do(func() {
+		hv.source.SetText(fmt.Sprintf("%s (%d measurements)", source, len(results)))
+		hv.detail.SetText("")
+		hv.table.UnselectAll()
+		hv.table.Refresh()
+	})
+}
+
+func (hv *HistoryView) showOpenDialog() {
+	d := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, hv.win)
+			return
+		}
+		if rc == nil {
+			return // cancelled
+		}
+		path := rc.URI().Path()
+		rc.Close()
+		if err := hv.LoadFile(path); err != nil {
+			dialog.ShowError(err, hv.win)
+		}
+	}, hv.win)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{".csv"}))
+	if lister, err := storage.ListerForURI(storage.NewFileURI("results")); err == nil {
+		d.SetLocation(lister)
+	}
+	d.Show()
+}
+
+func (hv *HistoryView) onSelected(id widget.TableCellID) {
+	if id.Row == 0 {
+		return
+	}
+	hv.mu.Lock()
+	idx := id.Row - 1
+	if idx >= len(hv.results) {
+		hv.mu.Unlock()
+		return
+	}
+	r := hv.results[idx]
+	hv.mu.Unlock()
+
+	hv.detail.SetText(format.FormatResult(&r))
+}
+
+// sortNewestFirst orders results by timestamp, most recent first.
+func sortNewestFirst(results []model.TestResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Timestamp.After(results[j].Timestamp)
+	})
+}
+
 func (hv *HistoryView) tableSize() (rows int, cols int) {
 	hv.mu.Lock()
 	defer hv.mu.Unlock()
@@ -101,9 +202,9 @@ func (hv *HistoryView) updateCell(id widget.TableCellID, obj fyne.CanvasObject)
 	case 1:
 		label.SetText(r.ServerAddr)
 	case 2:
-		label.SetText(fmt.Sprintf("%.2f", r.SentMbps()))
+		label.SetText(fmt.Sprintf("%.2f", r.FwdActualMbps()))
 	case 3:
-		label.SetText(fmt.Sprintf("%.2f", r.ReceivedMbps()))
+		label.SetText(fmt.Sprintf("%.2f", r.ReverseActualMbps()))
 	case 4:
 		label.SetText(fmt.Sprintf("%d", r.Duration))
 	case 5:
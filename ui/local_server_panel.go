@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"iperf-tool/internal/iperf"
+)
+
+// LocalServerPanel starts and stops an iperf2 server on this machine, using
+// the port, protocol and binary from the configuration form.
+type LocalServerPanel struct {
+	configForm *ConfigForm
+	outputView *OutputView
+
+	startBtn  *widget.Button
+	stopBtn   *widget.Button
+	status    *widget.Label
+	container *fyne.Container
+
+	runner *iperf.Runner
+	cancel context.CancelFunc
+}
+
+// NewLocalServerPanel creates the local listen-mode server panel.
+func NewLocalServerPanel(cf *ConfigForm, ov *OutputView) *LocalServerPanel {
+	lp := &LocalServerPanel{
+		configForm: cf,
+		outputView: ov,
+		runner:     iperf.NewRunner(),
+	}
+
+	lp.startBtn = widget.NewButton("Start Local Server", lp.onStart)
+	lp.stopBtn = widget.NewButton("Stop Local Server", lp.onStop)
+	lp.stopBtn.Disable()
+	lp.status = widget.NewLabel("Local server stopped")
+
+	accordion := widget.NewAccordion(
+		widget.NewAccordionItem("Local Server", container.NewVBox(
+			container.NewHBox(lp.startBtn, lp.stopBtn),
+			lp.status,
+		)),
+	)
+	lp.container = container.NewVBox(accordion)
+	return lp
+}
+
+// Container returns the panel's container.
+func (lp *LocalServerPanel) Container() *fyne.Container {
+	return lp.container
+}
+
+func (lp *LocalServerPanel) onStart() {
+	cfg := lp.configForm.Config()
+	if err := cfg.ValidateListen(); err != nil {
+		lp.status.SetText(fmt.Sprintf("Error: %v", err))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lp.cancel = cancel
+	lp.startBtn.Disable()
+	lp.stopBtn.Enable()
+	lp.status.SetText(fmt.Sprintf("Listening on %s port %s", cfg.Protocol, cfg.PortRangeStr(0)))
+	lp.outputView.AppendLine("Starting local server: " + cfg.ListenCommand(false))
+
+	go func() {
+		err := lp.runner.RunServer(ctx, cfg, false, func(line string) {
+			lp.outputView.AppendLine(line)
+		})
+		cancel()
+		fyne.Do(func() {
+			lp.startBtn.Enable()
+			lp.stopBtn.Disable()
+			if err != nil {
+				lp.status.SetText(fmt.Sprintf("Error: %v", err))
+			} else {
+				lp.status.SetText("Local server stopped")
+			}
+		})
+	}()
+}
+
+func (lp *LocalServerPanel) onStop() {
+	if lp.cancel != nil {
+		lp.cancel()
+	}
+}
+
+// Stop shuts the local server down, e.g. when the window closes.
+func (lp *LocalServerPanel) Stop() {
+	lp.onStop()
+}
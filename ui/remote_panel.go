@@ -23,6 +23,8 @@ type RemotePanel struct {
 	keyPathEntry  *widget.Entry
 	passwordEntry *widget.Entry
 	portEntry     *widget.Entry
+	binaryEntry   *widget.Entry
+	jumpEntry     *widget.Entry
 
 	connectBtn        *widget.Button
 	disconnectBtn     *widget.Button
@@ -32,9 +34,12 @@ type RemotePanel struct {
 	setupLocalSSHBtn  *widget.Button // visible only on Windows
 	setupRemoteSSHBtn *widget.Button // visible after connect when remote is Windows
 	statusEntry       *ReadOnlyEntry
+	foregroundCheck   *widget.Check  // start the server without -D and stream its log
+	serverLog         *ReadOnlyEntry // live output of a foreground server
 
 	client    *internalssh.Client
 	srvMgr    *internalssh.ServerManager
+	remoteVer string // iperf2 version on the connected host; empty = unknown
 	container *fyne.Container
 	win       fyne.Window // needed for dialogs
 
@@ -71,13 +76,24 @@ func NewRemotePanel(win fyne.Window) *RemotePanel {
 	rp.passwordEntry = widget.NewPasswordEntry()
 	rp.passwordEntry.SetPlaceHolder("Optional")
 
+	rp.jumpEntry = widget.NewEntry()
+	rp.jumpEntry.SetPlaceHolder("Optional: user@bastion:22")
+
 	rp.portEntry = widget.NewEntry()
 	rp.portEntry.SetText("5201")
 
+	rp.binaryEntry = widget.NewEntry()
+	rp.binaryEntry.SetPlaceHolder("iperf (on PATH)")
+
 	rp.statusEntry = NewReadOnlyEntry()
 	rp.statusEntry.MultiLine = false
 	rp.statusEntry.SetText("Disconnected")
 
+	rp.foregroundCheck = widget.NewCheck("Foreground (live server log)", nil)
+	rp.serverLog = NewReadOnlyEntry()
+	rp.serverLog.SetMinRowsVisible(6)
+	rp.serverLog.Hide()
+
 	rp.connectBtn = widget.NewButton("Connect SSH", rp.onConnect)
 	rp.disconnectBtn = widget.NewButton("Disconnect SSH", rp.onDisconnect)
 	rp.disconnectBtn.Disable()
@@ -103,14 +119,18 @@ func NewRemotePanel(win fyne.Window) *RemotePanel {
 		widget.NewLabel("Username"), rp.userEntry,
 		widget.NewLabel("SSH Key Path"), rp.keyPathEntry,
 		widget.NewLabel("Password"), rp.passwordEntry,
+		widget.NewLabel("Jump Host"), rp.jumpEntry,
 		container.NewHBox(rp.connectBtn, rp.disconnectBtn),
 		rp.setupLocalSSHBtn,
 	)
 
 	control := container.NewVBox(
 		widget.NewLabel("Server Port"), rp.portEntry,
+		widget.NewLabel("Remote iperf path"), rp.binaryEntry,
 		rp.installBtn,
+		rp.foregroundCheck,
 		container.NewHBox(rp.startSrvBtn, rp.stopSrvBtn),
+		rp.serverLog,
 		rp.setupRemoteSSHBtn,
 	)
 
@@ -147,6 +167,12 @@ func (rp *RemotePanel) LoadPreferences(prefs fyne.Preferences) {
 	if v := prefs.String("remote.port"); v != "" {
 		rp.portEntry.SetText(v)
 	}
+	if v := prefs.String("remote.jump"); v != "" {
+		rp.jumpEntry.SetText(v)
+	}
+	if v := prefs.String("remote.binary"); v != "" {
+		rp.binaryEntry.SetText(v)
+	}
 }
 
 // SavePreferences persists panel values to preferences (excluding password).
@@ -155,6 +181,8 @@ func (rp *RemotePanel) SavePreferences(prefs fyne.Preferences) {
 	prefs.SetString("remote.user", rp.userEntry.Text)
 	prefs.SetString("remote.key_path", rp.keyPathEntry.Text)
 	prefs.SetString("remote.port", rp.portEntry.Text)
+	prefs.SetString("remote.jump", rp.jumpEntry.Text)
+	prefs.SetString("remote.binary", rp.binaryEntry.Text)
 }
 
 func (rp *RemotePanel) onConnect() {
@@ -170,6 +198,9 @@ func (rp *RemotePanel) ConnectAsync(onDone func(err error)) {
 		User:     rp.userEntry.Text,
 		KeyPath:  rp.keyPathEntry.Text,
 		Password: rp.passwordEntry.Text,
+		JumpHost: strings.TrimSpace(rp.jumpEntry.Text),
+
+		PassphrasePrompt: rp.askPassphrase,
 	}
 
 	rp.connectBtn.Disable()
@@ -188,18 +219,27 @@ func (rp *RemotePanel) ConnectAsync(onDone func(err error)) {
 			return
 		}
 
-		// Check if iperf2 is already installed
-		installed, _ := client.CheckIperfInstalled()
-
-		// Read the configured port on the UI thread, then start the server.
+		// Read the configured port and iperf path on the UI thread, then start the server.
 		portCh := make(chan int, 1)
-		fyne.Do(func() { portCh <- rp.getPort() })
+		mgrCh := make(chan *internalssh.ServerManager, 1)
+		fyne.Do(func() {
+			portCh <- rp.getPort()
+			mgrCh <- rp.serverManager()
+		})
 		port := <-portCh
+		mgr := <-mgrCh
+
+		// Check if iperf2 is already installed; a custom path is verified by
+		// the server start itself.
+		installed := mgr.IperfPath != ""
+		if !installed {
+			installed, _ = client.CheckIperfInstalled()
+		}
 
 		// Only attempt server start if iperf2 is installed.
 		var startErr error
 		if installed {
-			startErr = rp.srvMgr.RestartServer(client, port, 0)
+			startErr = mgr.RestartServer(client, port, 0)
 		}
 
 		// Detect remote OS for Windows-specific UI
@@ -207,9 +247,11 @@ func (rp *RemotePanel) ConnectAsync(onDone func(err error)) {
 		if osType, osErr := client.DetectOS(); osErr == nil && osType == internalssh.OSWindows {
 			isWin = true
 		}
+		remoteVer, _ := iperf.RemoteVersion(client, mgr.IperfPath)
 
 		fyne.Do(func() {
 			rp.client = client
+			rp.remoteVer = remoteVer
 			rp.disconnectBtn.Enable()
 
 			if installed {
@@ -266,7 +308,8 @@ func (rp *RemotePanel) RestartServer(numInstances ...int) error {
 		n = numInstances[0]
 	}
 
-	if err := rp.srvMgr.RestartServer(rp.client, port, n); err != nil {
+	mgr := rp.serverManager()
+	if err := rp.client.WithReconnect(func() error { return mgr.RestartServer(rp.client, port, n) }); err != nil {
 		return err
 	}
 
@@ -282,6 +325,12 @@ func (rp *RemotePanel) RestartServer(numInstances ...int) error {
 	return nil
 }
 
+// IsAlive reports whether the SSH connection still responds; a dropped
+// connection is redialled by RestartServer.
+func (rp *RemotePanel) IsAlive() bool {
+	return rp.client != nil && rp.client.IsAlive()
+}
+
 // IsConnected returns whether an SSH connection is active.
 func (rp *RemotePanel) IsConnected() bool {
 	return rp.client != nil
@@ -300,6 +349,14 @@ func (rp *RemotePanel) Host() string {
 	return rp.hostEntry.Text
 }
 
+// RemoteVersion returns the iperf2 version found on the connected host, or "".
+func (rp *RemotePanel) RemoteVersion() string {
+	if rp.client == nil {
+		return ""
+	}
+	return rp.remoteVer
+}
+
 // IsWindows returns true if the connected remote host was detected as Windows.
 // Returns false if not connected or OS detection fails.
 func (rp *RemotePanel) IsWindows() bool {
@@ -322,6 +379,12 @@ func (rp *RemotePanel) LocalAddr() string {
 	return rp.client.LocalAddr()
 }
 
+// serverManager returns the server manager set to the current remote iperf path.
+func (rp *RemotePanel) serverManager() *internalssh.ServerManager {
+	rp.srvMgr.IperfPath = strings.TrimSpace(rp.binaryEntry.Text)
+	return rp.srvMgr
+}
+
 // getPort returns the configured iperf2 server port, or 5201 if invalid.
 func (rp *RemotePanel) getPort() int {
 	return parsePort(rp.portEntry.Text, 5201)
@@ -351,8 +414,13 @@ func (rp *RemotePanel) onStartServer() {
 	rp.stopSrvBtn.Disable()
 	rp.statusEntry.SetText("Starting server...")
 
+	mgr := rp.serverManager()
+	if rp.foregroundCheck.Checked {
+		rp.startStreamingServer(mgr, port)
+		return
+	}
 	go func() {
-		err := rp.srvMgr.RestartServer(rp.client, port, 0)
+		err := mgr.RestartServer(rp.client, port, 0)
 		fyne.Do(func() {
 			if err != nil {
 				rp.statusEntry.SetText(fmt.Sprintf("Error: %v", err))
@@ -365,6 +433,30 @@ func (rp *RemotePanel) onStartServer() {
 	}()
 }
 
+// startStreamingServer starts a single foreground server and appends its
+// output to the server log panel.
+func (rp *RemotePanel) startStreamingServer(mgr *internalssh.ServerManager, port int) {
+	rp.serverLog.SetText("")
+	rp.serverLog.Show()
+	client := rp.client
+	go func() {
+		err := mgr.StartServerStreaming(client, port, func(line string) {
+			fyne.Do(func() {
+				rp.serverLog.Append(line + "\n")
+			})
+		})
+		fyne.Do(func() {
+			if err != nil {
+				rp.statusEntry.SetText(fmt.Sprintf("Error: %v", err))
+				rp.startSrvBtn.Enable()
+				return
+			}
+			rp.statusEntry.SetText(fmt.Sprintf("Server running on port %d (foreground)", port))
+			rp.stopSrvBtn.Enable()
+		})
+	}()
+}
+
 func (rp *RemotePanel) onStopServer() {
 	if rp.client == nil {
 		return
@@ -373,8 +465,9 @@ func (rp *RemotePanel) onStopServer() {
 	rp.stopSrvBtn.Disable()
 	rp.statusEntry.SetText("Stopping server...")
 
+	mgr := rp.serverManager()
 	go func() {
-		err := rp.srvMgr.StopServer(rp.client)
+		err := mgr.StopServer(rp.client)
 		fyne.Do(func() {
 			if err != nil {
 				rp.statusEntry.SetText(fmt.Sprintf("Error: %v", err))
@@ -413,6 +506,31 @@ func (rp *RemotePanel) onInstall() {
 	}()
 }
 
+// askPassphrase shows a password dialog for an encrypted SSH key and blocks
+// the calling (connect) goroutine until it is answered.
+func (rp *RemotePanel) askPassphrase(keyPath string) (string, error) {
+	type answer struct {
+		passphrase string
+		ok         bool
+	}
+	ch := make(chan answer, 1)
+	fyne.Do(func() {
+		entry := widget.NewPasswordEntry()
+		items := []*widget.FormItem{widget.NewFormItem("Passphrase", entry)}
+		d := dialog.NewForm("Key passphrase", "Unlock", "Cancel", items, func(ok bool) {
+			ch <- answer{entry.Text, ok}
+		}, rp.win)
+		d.Resize(fyne.NewSize(420, d.MinSize().Height))
+		d.Show()
+		rp.statusEntry.SetText("Waiting for passphrase of " + filepath.Base(keyPath) + "...")
+	})
+	a := <-ch
+	if !a.ok {
+		return "", fmt.Errorf("passphrase entry cancelled")
+	}
+	return a.passphrase, nil
+}
+
 func (rp *RemotePanel) onSetupLocalSSH() {
 	keyEntry := widget.NewEntry()
 	keyEntry.SetPlaceHolder("ssh-ed25519 AAAA... (optional)")
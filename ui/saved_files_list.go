@@ -15,6 +15,9 @@ import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
+
+	"iperf-tool/internal/export"
+	"iperf-tool/internal/model"
 )
 
 // SavedFilesList displays a list of saved result files from disk
@@ -24,6 +27,8 @@ type SavedFilesList struct {
 	files     []FileInfo
 	list      *widget.List
 	container *fyne.Container
+	exportBtn *widget.Button
+	result    *model.TestResult // latest result, exported by "Export HTML"
 }
 
 // FileInfo holds metadata about a saved file
@@ -83,8 +88,11 @@ func NewSavedFilesList() *SavedFilesList {
 	header := widget.NewLabel("Saved Results")
 	header.TextStyle = fyne.TextStyle{Bold: true}
 
+	sfl.exportBtn = widget.NewButton("Export HTML", sfl.exportHTML)
+	sfl.exportBtn.Disable()
+
 	sfl.container = container.NewBorder(
-		container.NewVBox(header, widget.NewSeparator()),
+		container.NewVBox(container.NewBorder(nil, nil, nil, sfl.exportBtn, header), widget.NewSeparator()),
 		nil, nil, nil,
 		sfl.list,
 	)
@@ -108,6 +116,38 @@ func (sfl *SavedFilesList) SetDir(dir string) {
 	sfl.Refresh()
 }
 
+// SetResult records the latest result for "Export HTML" and enables the button.
+// Must be called on the UI thread.
+func (sfl *SavedFilesList) SetResult(r *model.TestResult) {
+	sfl.mu.Lock()
+	sfl.result = r
+	sfl.mu.Unlock()
+	sfl.exportBtn.Enable()
+}
+
+// exportHTML writes the latest result as an HTML report into the scanned
+// directory and opens it.
+func (sfl *SavedFilesList) exportHTML() {
+	sfl.mu.Lock()
+	r, dir := sfl.result, sfl.dir
+	sfl.mu.Unlock()
+	if r == nil {
+		return
+	}
+
+	path := export.HTMLPath(filepath.Join(dir, "report"), r)
+	if err := export.EnsureDir(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Error exporting HTML: %v\n", err)
+		return
+	}
+	if err := export.WriteHTML(path, r); err != nil {
+		fmt.Fprintf(os.Stderr, "Error exporting HTML: %v\n", err)
+		return
+	}
+	sfl.Refresh()
+	go sfl.openFile(path)
+}
+
 // Refresh rescans the directory and updates the file list
 func (sfl *SavedFilesList) Refresh() {
 	files, err := sfl.scanFiles()
@@ -126,7 +166,7 @@ func (sfl *SavedFilesList) Refresh() {
 	sfl.list.Refresh()
 }
 
-// scanFiles discovers all CSV and TXT result files under the configured directory (recursive).
+// scanFiles discovers all CSV, TXT, JSON, Markdown and HTML result files under the configured directory (recursive).
 func (sfl *SavedFilesList) scanFiles() ([]FileInfo, error) {
 	sfl.mu.Lock()
 	dir := sfl.dir
@@ -142,7 +182,9 @@ func (sfl *SavedFilesList) scanFiles() ([]FileInfo, error) {
 			return nil
 		}
 		ext := strings.ToLower(filepath.Ext(path))
-		if ext != ".csv" && ext != ".txt" {
+		switch ext {
+		case ".csv", ".txt", ".json", ".md", ".html":
+		default:
 			return nil
 		}
 		info, err := d.Info()
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"image/color"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+var (
+	chartFwdColor = color.NRGBA{R: 40, G: 167, B: 69, A: 255}
+	chartRevColor = color.NRGBA{R: 204, G: 122, B: 0, A: 255}
+)
+
+type chartPoint struct {
+	t, fwd, rev float64
+}
+
+// ThroughputChart draws forward and reverse Mbps over time as polylines.
+// The vertical scale grows with the largest value seen so far.
+type ThroughputChart struct {
+	widget.BaseWidget
+
+	mu     sync.Mutex
+	points []chartPoint
+}
+
+// NewThroughputChart creates an empty chart.
+func NewThroughputChart() *ThroughputChart {
+	c := &ThroughputChart{}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// AddPoint appends one interval sample, safe to call from any goroutine.
+// Pass 0 for revMbps when there is no reverse direction.
+func (c *ThroughputChart) AddPoint(tSec, fwdMbps, revMbps float64) {
+	c.mu.Lock()
+	c.points = append(c.points, chartPoint{t: tSec, fwd: fwdMbps, rev: revMbps})
+	c.mu.Unlock()
+	fyne.Do(c.Refresh)
+}
+
+// Reset clears all points, safe to call from any goroutine.
+func (c *ThroughputChart) Reset() {
+	c.mu.Lock()
+	c.points = nil
+	c.mu.Unlock()
+	fyne.Do(c.Refresh)
+}
+
+func (c *ThroughputChart) snapshot() []chartPoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]chartPoint(nil), c.points...)
+}
+
+// CreateRenderer returns the chart renderer.
+func (c *ThroughputChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &chartRenderer{
+		chart: c,
+		bg:    canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground)),
+	}
+	r.rebuild()
+	return r
+}
+
+type chartRenderer struct {
+	chart   *ThroughputChart
+	bg      *canvas.Rectangle
+	size    fyne.Size
+	objects []fyne.CanvasObject
+}
+
+func (r *chartRenderer) Layout(size fyne.Size) {
+	r.size = size
+	r.rebuild()
+}
+
+func (r *chartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(ChartMinWidth, ChartMinHeight)
+}
+
+func (r *chartRenderer) Refresh() {
+	r.rebuild()
+	canvas.Refresh(r.chart)
+}
+
+func (r *chartRenderer) Objects() []fyne.CanvasObject { return r.objects }
+func (r *chartRenderer) Destroy()                     {}
+
+// rebuild regenerates the line segments for the current size and points.
+func (r *chartRenderer) rebuild() {
+	r.bg.FillColor = theme.Color(theme.ColorNameInputBackground)
+	r.bg.Resize(r.size)
+	r.objects = []fyne.CanvasObject{r.bg}
+
+	points := r.chart.snapshot()
+	if len(points) < 2 || r.size.Width <= 0 || r.size.Height <= 0 {
+		return
+	}
+
+	maxT := points[len(points)-1].t
+	maxY := 0.0
+	hasRev := false
+	for _, p := range points {
+		maxY = max(maxY, p.fwd, p.rev)
+		if p.rev > 0 {
+			hasRev = true
+		}
+	}
+	if maxT <= 0 {
+		maxT = 1
+	}
+	if maxY <= 0 {
+		maxY = 1
+	}
+	maxY *= 1.1 // headroom so the peak does not touch the top edge
+
+	pos := func(t, v float64) fyne.Position {
+		pad := theme.Padding()
+		w := r.size.Width - 2*pad
+		h := r.size.Height - 2*pad
+		return fyne.NewPos(pad+float32(t/maxT)*w, pad+h-float32(v/maxY)*h)
+	}
+	addLine := func(a, b fyne.Position, col color.Color) {
+		l := canvas.NewLine(col)
+		l.StrokeWidth = 2
+		l.Position1 = a
+		l.Position2 = b
+		r.objects = append(r.objects, l)
+	}
+
+	for i := 1; i < len(points); i++ {
+		prev, cur := points[i-1], points[i]
+		addLine(pos(prev.t, prev.fwd), pos(cur.t, cur.fwd), chartFwdColor)
+		if hasRev {
+			addLine(pos(prev.t, prev.rev), pos(cur.t, cur.rev), chartRevColor)
+		}
+	}
+}
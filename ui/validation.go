@@ -18,6 +18,19 @@ func parseIntOrDefault(s string, defaultValue int) int {
 	return val
 }
 
+// parseFloatOrDefault attempts to parse a string as a float.
+// Returns the parsed value or defaultValue if parsing fails.
+func parseFloatOrDefault(s string, defaultValue float64) float64 {
+	if s == "" {
+		return defaultValue
+	}
+	val, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return val
+}
+
 // parseIntInRange parses a string as an integer and validates it's within the given range.
 // Returns the parsed value, or an error if parsing fails or value is out of range.
 func parseIntInRange(s string, min, max int, fieldName string) (int, error) {